@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"the-ark/internal/core"
+	monitor "the-ark/internal/features/uptime/services"
 	"the-ark/internal/server"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +21,15 @@ func main() {
 	// Create logger
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
+	// "the-ark probe" runs a probe agent checking websites for another Ark
+	if len(os.Args) > 1 && os.Args[1] == "probe" {
+		if err := runProbe(logger); err != nil {
+			logger.Error("Probe failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create and start server
 	srv := server.New(logger)
 
@@ -23,3 +38,26 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runProbe runs a probe agent until it is interrupted
+func runProbe(logger *slog.Logger) error {
+	config, err := core.LoadProbeConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	agent := monitor.NewAgent(logger, monitor.AgentConfig{
+		Server:       config.Server,
+		Token:        config.Token,
+		Name:         config.Name,
+		SyncInterval: time.Duration(config.SyncInterval) * time.Second,
+		MaxWorkers:   config.MaxConcurrentChecks,
+		CheckTimeout: time.Duration(config.CheckTimeout) * time.Second,
+	})
+
+	logger.Info("Starting probe", "name", config.Name, "server", config.Server)
+	return agent.Run(ctx)
+}
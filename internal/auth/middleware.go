@@ -175,6 +175,13 @@ func RequireAuthentication(next http.Handler) http.Handler {
 		user := GetUserFromContext(r)
 
 		if user.IsAnonymous() {
+			// API clients get an error they can act on
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				core.WriteErrorResponse(w, http.StatusUnauthorized, core.NewAppError(
+					core.ErrCodeUnauthorized, "Authentication required", nil))
+				return
+			}
+
 			// Redirect to login page for web requests
 			http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
 			return
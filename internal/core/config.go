@@ -44,11 +44,26 @@ type FeatureConfig struct {
 
 // UptimeConfig contains uptime monitoring configuration
 type UptimeConfig struct {
-	Enabled        bool   `json:"enabled"`
-	CheckInterval  int    `json:"check_interval"`
-	SMTP2GOAPIKey  string `json:"smtp2go_api_key"`
-	SMTP2GOSender  string `json:"smtp2go_sender"`
-	AlertRecipient string `json:"alert_recipient"`
+	Enabled             bool    `json:"enabled"`
+	CheckInterval       int     `json:"check_interval"`
+	MaxConcurrentChecks int     `json:"max_concurrent_checks"`
+	CheckTimeout        int     `json:"check_timeout"`
+	RawRetentionDays    int     `json:"raw_retention_days"`
+	MaxWebsites         int     `json:"max_websites"`
+	SLATarget           float64 `json:"sla_target"`
+	SMTP2GOAPIKey       string  `json:"smtp2go_api_key"`
+	SMTP2GOSender       string  `json:"smtp2go_sender"`
+	AlertRecipient      string  `json:"alert_recipient"`
+	ReportRecipient     string  `json:"report_recipient"`
+	ProbeToken          string  `json:"-"`
+	ProbeQuorum         int     `json:"probe_quorum"`
+
+	// RDAPURL is the RDAP service domain registrations are looked up through
+	RDAPURL string `json:"rdap_url"`
+
+	// DomainExpiryThresholds is a comma separated list of days before a
+	// domain's registration expires to send warnings at
+	DomainExpiryThresholds string `json:"domain_expiry_thresholds"`
 }
 
 // ServerMonitoringConfig contains server monitoring configuration
@@ -58,7 +73,10 @@ type ServerMonitoringConfig struct {
 
 // SSLConfig contains SSL certificate tracking configuration
 type SSLConfig struct {
-	Enabled bool `json:"enabled"`
+	Enabled          bool   `json:"enabled"`
+	CheckInterval    int    `json:"check_interval"`
+	CheckTimeout     int    `json:"check_timeout"`
+	ExpiryThresholds string `json:"expiry_thresholds"`
 }
 
 // LogViewerConfig contains log viewer configuration
@@ -77,6 +95,45 @@ type RSSConfig struct {
 	MaxConcurrentFetches int    `json:"max_concurrent_fetches"`
 }
 
+// ProbeConfig contains the configuration of a probe agent, the same binary
+// run with the probe command to check websites from another location
+type ProbeConfig struct {
+	Server              string `json:"server"`
+	Token               string `json:"-"`
+	Name                string `json:"name"`
+	SyncInterval        int    `json:"sync_interval"`
+	MaxConcurrentChecks int    `json:"max_concurrent_checks"`
+	CheckTimeout        int    `json:"check_timeout"`
+}
+
+// LoadProbeConfig loads the probe agent configuration from environment
+// variables. A probe needs none of the main Ark's settings. Its name
+// defaults to the host name.
+func LoadProbeConfig() (*ProbeConfig, error) {
+	hostname, _ := os.Hostname()
+
+	config := &ProbeConfig{
+		Server:              getEnvOrDefault("ARK_PROBE_SERVER", ""),
+		Token:               getEnvOrDefault("ARK_PROBE_TOKEN", ""),
+		Name:                getEnvOrDefault("ARK_PROBE_NAME", hostname),
+		SyncInterval:        getEnvAsInt("ARK_PROBE_SYNC_INTERVAL", 60),
+		MaxConcurrentChecks: getEnvAsInt("ARK_PROBE_MAX_CONCURRENT_CHECKS", 5),
+		CheckTimeout:        getEnvAsInt("ARK_PROBE_CHECK_TIMEOUT", 10),
+	}
+
+	if config.Server == "" {
+		return nil, fmt.Errorf("ARK_PROBE_SERVER is required to run a probe")
+	}
+	if config.Token == "" {
+		return nil, fmt.Errorf("ARK_PROBE_TOKEN is required to run a probe")
+	}
+	if config.Name == "" {
+		return nil, fmt.Errorf("ARK_PROBE_NAME is required to run a probe")
+	}
+
+	return config, nil
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	config := &Config{
@@ -94,17 +151,30 @@ func LoadConfig() (*Config, error) {
 		},
 		Features: FeatureConfig{
 			Uptime: UptimeConfig{
-				Enabled:        getEnvAsBool("ARK_ENABLE_UPTIME", true),
-				CheckInterval:  getEnvAsInt("ARK_UPTIME_CHECK_INTERVAL", 300),
-				SMTP2GOAPIKey:  getEnvOrDefault("ARK_SMTP2GO_API_KEY", ""),
-				SMTP2GOSender:  getEnvOrDefault("ARK_SMTP2GO_SENDER", "The Ark <ark@alexbates.dev>"),
-				AlertRecipient: getEnvOrDefault("ARK_ALERT_RECIPIENT", "ajbates93@gmail.com"),
+				Enabled:                getEnvAsBool("ARK_ENABLE_UPTIME", true),
+				CheckInterval:          getEnvAsInt("ARK_UPTIME_CHECK_INTERVAL", 300),
+				MaxConcurrentChecks:    getEnvAsInt("ARK_UPTIME_MAX_CONCURRENT_CHECKS", 5),
+				CheckTimeout:           getEnvAsInt("ARK_UPTIME_CHECK_TIMEOUT", 10),
+				RawRetentionDays:       getEnvAsInt("ARK_UPTIME_RAW_RETENTION_DAYS", 30),
+				MaxWebsites:            getEnvAsInt("ARK_UPTIME_MAX_WEBSITES", 0),
+				SLATarget:              getEnvAsFloat("ARK_UPTIME_SLA_TARGET", 99.9),
+				SMTP2GOAPIKey:          getEnvOrDefault("ARK_SMTP2GO_API_KEY", ""),
+				SMTP2GOSender:          getEnvOrDefault("ARK_SMTP2GO_SENDER", "The Ark <ark@alexbates.dev>"),
+				AlertRecipient:         getEnvOrDefault("ARK_ALERT_RECIPIENT", "ajbates93@gmail.com"),
+				ReportRecipient:        getEnvOrDefault("ARK_UPTIME_REPORT_RECIPIENT", ""),
+				ProbeToken:             getEnvOrDefault("ARK_UPTIME_PROBE_TOKEN", ""),
+				ProbeQuorum:            getEnvAsInt("ARK_UPTIME_PROBE_QUORUM", 2),
+				RDAPURL:                getEnvOrDefault("ARK_UPTIME_RDAP_URL", ""),
+				DomainExpiryThresholds: getEnvOrDefault("ARK_UPTIME_DOMAIN_EXPIRY_THRESHOLDS", ""),
 			},
 			Server: ServerMonitoringConfig{
 				Enabled: getEnvAsBool("ARK_ENABLE_SERVER_MONITORING", false),
 			},
 			SSL: SSLConfig{
-				Enabled: getEnvAsBool("ARK_ENABLE_SSL_TRACKER", false),
+				Enabled:          getEnvAsBool("ARK_ENABLE_SSL_TRACKER", false),
+				CheckInterval:    getEnvAsInt("ARK_SSL_CHECK_INTERVAL", 43200),
+				CheckTimeout:     getEnvAsInt("ARK_SSL_CHECK_TIMEOUT", 10),
+				ExpiryThresholds: getEnvOrDefault("ARK_SSL_EXPIRY_THRESHOLDS", "30,14,7,1"),
 			},
 			Logs: LogViewerConfig{
 				Enabled: getEnvAsBool("ARK_ENABLE_LOG_VIEWER", false),
@@ -156,6 +226,9 @@ func (c *Config) Validate() error {
 		if c.Features.Uptime.SMTP2GOAPIKey == "" {
 			return fmt.Errorf("SMTP2GO API key is required when uptime monitoring is enabled")
 		}
+		if err := validateDayList(c.Features.Uptime.DomainExpiryThresholds); err != nil {
+			return fmt.Errorf("invalid ARK_UPTIME_DOMAIN_EXPIRY_THRESHOLDS: %w", err)
+		}
 	}
 
 	return nil
@@ -197,6 +270,30 @@ func (c *Config) IsFeatureEnabled(featureName string) bool {
 	}
 }
 
+// validateDayList checks a comma separated list of days between 1 and 365.
+// An empty list is valid and leaves the feature's defaults in place.
+func validateDayList(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	count := 0
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if days, err := strconv.Atoi(part); err != nil || days <= 0 || days > 365 {
+			return fmt.Errorf("%q is not a number of days between 1 and 365", part)
+		}
+		count++
+	}
+	if count == 0 {
+		return fmt.Errorf("at least one number of days is required")
+	}
+	return nil
+}
+
 // Helper functions for environment variable parsing
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -214,6 +311,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		switch strings.ToLower(value) {
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// eventBufferSize is how many events a subscriber may fall behind by before
+// further events are dropped for it
+const eventBufferSize = 64
+
+// eventKeepAlive is how often an idle event stream sends a comment, so
+// proxies don't close the connection
+const eventKeepAlive = 30 * time.Second
+
+// Event is a message a feature publishes for live views, e.g. a website's
+// new check result. Topics are dot-separated, starting with the feature
+// name, e.g. uptime.check.
+type Event struct {
+	Topic string    `json:"topic"`
+	Data  any       `json:"data"`
+	Time  time.Time `json:"time"`
+}
+
+// EventBus fans events out from features to subscribers, such as browsers
+// connected to the event stream. Publishing never blocks: a subscriber that
+// falls too far behind misses events rather than stalling the publisher.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscription]struct{}
+}
+
+// subscription is a subscriber's event channel and topic filter
+type subscription struct {
+	topics []string
+	events chan Event
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[*subscription]struct{})}
+}
+
+// Publish sends an event to every subscriber of its topic. Publishing on a
+// nil bus does nothing, so features work without one.
+func (b *EventBus) Publish(topic string, data any) {
+	if b == nil {
+		return
+	}
+
+	event := Event{Topic: topic, Data: data, Time: time.Now()}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if !sub.matches(topic) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving events for the given topics, and a
+// function ending the subscription. A topic also matches the topics below
+// it, so "uptime" receives uptime.check. Without topics every event is
+// received.
+func (b *EventBus) Subscribe(topics ...string) (<-chan Event, func()) {
+	sub := &subscription{topics: topics, events: make(chan Event, eventBufferSize)}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+		})
+	}
+	return sub.events, unsubscribe
+}
+
+// matches reports whether the subscription receives events for topic
+func (s *subscription) matches(topic string) bool {
+	if len(s.topics) == 0 {
+		return true
+	}
+	for _, t := range s.topics {
+		if topic == t || strings.HasPrefix(topic, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP streams events to the client as server-sent events until it
+// disconnects. The topic query parameter, which may be repeated, limits the
+// stream to those topics.
+func (b *EventBus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	events, unsubscribe := b.Subscribe(r.URL.Query()["topic"]...)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Ask browsers to wait a few seconds before reconnecting
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-events:
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Topic, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventBusTopics(t *testing.T) {
+	bus := NewEventBus()
+	uptime, unsubscribe := bus.Subscribe("uptime")
+	all, _ := bus.Subscribe()
+
+	bus.Publish("uptime.check", 1)
+	bus.Publish("uptimer.check", 2)
+	bus.Publish("rss.articles", 3)
+
+	if event := <-uptime; event.Topic != "uptime.check" || event.Data != 1 {
+		t.Errorf("Expected the uptime.check event, got %+v", event)
+	}
+	select {
+	case event := <-uptime:
+		t.Errorf("Expected other topics filtered out, got %+v", event)
+	default:
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected a subscriber without topics to receive every event, got %d", len(all))
+	}
+
+	// A subscriber that stops reading misses events instead of blocking
+	for i := 0; i < eventBufferSize*2; i++ {
+		bus.Publish("uptime.check", i)
+	}
+	if len(uptime) != eventBufferSize {
+		t.Errorf("Expected %d buffered events, got %d", eventBufferSize, len(uptime))
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if len(bus.subscribers) != 1 {
+		t.Errorf("Expected one subscriber left, got %d", len(bus.subscribers))
+	}
+
+	var nilBus *EventBus
+	nilBus.Publish("uptime.check", 1)
+}
+
+func TestEventBusStream(t *testing.T) {
+	bus := NewEventBus()
+	server := httptest.NewServer(bus)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?topic=rss")
+	if err != nil {
+		t.Fatalf("Failed to connect to event stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", contentType)
+	}
+
+	// Wait for the handler to subscribe before publishing
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); line != "retry: 5000\n" {
+		t.Fatalf("Expected the retry interval first, got %q", line)
+	}
+
+	bus.Publish("uptime.check", map[string]int{"website_id": 1})
+	bus.Publish("rss.articles", map[string]int{"feed_id": 2, "count": 3})
+
+	done := make(chan string)
+	go func() {
+		var lines []string
+		for len(lines) < 2 {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		done <- strings.Join(lines, "\n")
+	}()
+
+	select {
+	case got := <-done:
+		expected := "event: rss.articles\ndata: {\"count\":3,\"feed_id\":2}"
+		if got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the event")
+	}
+}
@@ -31,6 +31,10 @@ type Route struct {
 	Method  string
 	Path    string
 	Handler http.HandlerFunc
+
+	// Public routes are served without authentication. They must do their
+	// own access control, e.g. with a secret token in the path.
+	Public bool
 }
 
 // BaseFeature provides common functionality for all features
@@ -53,7 +53,12 @@ func (m *MigrationService) InitMigrations(ctx context.Context) error {
 func (m *MigrationService) GetAppliedMigrations(ctx context.Context) ([]Migration, error) {
 	query := `SELECT version, name, description, applied_at FROM migrations ORDER BY version`
 
-	rows, err := m.db.QueryWithTimeout(ctx, query)
+	// The rows are read here, so the timeout is cancelled once they are
+	// rather than when the query returns
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(queryCtx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query migrations: %w", err)
 	}
@@ -76,8 +81,11 @@ func (m *MigrationService) GetAppliedMigrations(ctx context.Context) ([]Migratio
 func (m *MigrationService) IsMigrationApplied(ctx context.Context, version int) (bool, error) {
 	query := `SELECT COUNT(*) FROM migrations WHERE version = ?`
 
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
 	var count int
-	err := m.db.QueryRowWithTimeout(ctx, query, version).Scan(&count)
+	err := m.db.QueryRowContext(queryCtx, query, version).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check migration status: %w", err)
 	}
@@ -24,7 +24,7 @@ type Feature struct {
 }
 
 // NewFeature creates a new RSS feature
-func NewFeature(logger *core.Logger, db *core.Database, config *Config) *Feature {
+func NewFeature(logger *core.Logger, db *core.Database, events *core.EventBus, config *Config) *Feature {
 	// Create migration manager
 	migrationMgr := migrations.NewManager(db, logger)
 
@@ -43,7 +43,7 @@ func NewFeature(logger *core.Logger, db *core.Database, config *Config) *Feature
 	// Create scheduler service
 	schedulerConfig := models.DefaultSchedulerConfig()
 	schedulerConfig.UpdateInterval = time.Duration(config.FetchInterval) * time.Second
-	schedulerService := services.NewSchedulerService(feedService, articleService, fetcherService, logger, schedulerConfig)
+	schedulerService := services.NewSchedulerService(feedService, articleService, fetcherService, logger, schedulerConfig, events)
 
     // Create handlers
     handlers := handlers.NewHandlers(logger, feedService, articleService, schedulerService)
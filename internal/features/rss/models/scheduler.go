@@ -21,3 +21,14 @@ func DefaultSchedulerConfig() *SchedulerConfig {
 		RetryDelay:     5 * time.Minute, // Wait 5 minutes between retries
 	}
 }
+
+// EventArticles is the topic the scheduler publishes when a feed update
+// adds new articles
+const EventArticles = "rss.articles"
+
+// ArticlesEvent describes the new articles of a feed update
+type ArticlesEvent struct {
+	FeedID    int    `json:"feed_id"`
+	FeedTitle string `json:"feed_title"`
+	Count     int    `json:"count"`
+}
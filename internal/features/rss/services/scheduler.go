@@ -16,6 +16,7 @@ type SchedulerService struct {
 	fetcherService *FetcherService
 	logger         *core.Logger
 	config         *models.SchedulerConfig
+	events         *core.EventBus
 	stopChan       chan struct{}
 	wg             sync.WaitGroup
 }
@@ -27,6 +28,7 @@ func NewSchedulerService(
 	fetcherService *FetcherService,
 	logger *core.Logger,
 	config *models.SchedulerConfig,
+	events *core.EventBus,
 ) *SchedulerService {
 	return &SchedulerService{
 		feedService:    feedService,
@@ -34,6 +36,7 @@ func NewSchedulerService(
 		fetcherService: fetcherService,
 		logger:         logger,
 		config:         config,
+		events:         events,
 		stopChan:       make(chan struct{}),
 	}
 }
@@ -215,6 +218,13 @@ func (s *SchedulerService) updateFeed(ctx context.Context, feed *models.Feed) er
 	}
 
 	s.logger.Info("Feed update completed", "feed_id", feed.ID, "articles_added", articlesAdded)
+	if articlesAdded > 0 {
+		s.events.Publish(models.EventArticles, models.ArticlesEvent{
+			FeedID:    feed.ID,
+			FeedTitle: feed.Title,
+			Count:     articlesAdded,
+		})
+	}
 	return nil
 }
 
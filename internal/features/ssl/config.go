@@ -0,0 +1,59 @@
+package ssl
+
+import (
+	"fmt"
+	"the-ark/internal/core"
+	"the-ark/internal/features/ssl/models"
+)
+
+// Config represents SSL tracker feature configuration
+type Config struct {
+	Enabled       bool
+	CheckInterval int
+	CheckTimeout  int
+
+	// ExpiryThresholds is a comma separated list of days before expiry to
+	// send warnings at
+	ExpiryThresholds string
+
+	// AlertRecipient receives expiry warnings, shared with uptime alerts
+	AlertRecipient string
+}
+
+// NewConfig creates SSL tracker config from core config
+func NewConfig(coreConfig *core.Config) *Config {
+	return &Config{
+		Enabled:          coreConfig.Features.SSL.Enabled,
+		CheckInterval:    coreConfig.Features.SSL.CheckInterval,
+		CheckTimeout:     coreConfig.Features.SSL.CheckTimeout,
+		ExpiryThresholds: coreConfig.Features.SSL.ExpiryThresholds,
+		AlertRecipient:   coreConfig.Features.Uptime.AlertRecipient,
+	}
+}
+
+// Validate validates the SSL tracker configuration
+func (c *Config) Validate() error {
+	if c.CheckInterval < 300 || c.CheckInterval > 604800 {
+		return fmt.Errorf("SSL check interval must be between 300 and 604800 seconds")
+	}
+
+	if c.CheckTimeout < 1 || c.CheckTimeout > 60 {
+		return fmt.Errorf("SSL check timeout must be between 1 and 60 seconds")
+	}
+
+	if _, err := models.ParseThresholds(c.ExpiryThresholds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Thresholds returns the parsed expiry thresholds, falling back to the
+// defaults when they are invalid
+func (c *Config) Thresholds() []int {
+	thresholds, err := models.ParseThresholds(c.ExpiryThresholds)
+	if err != nil {
+		return models.DefaultExpiryThresholds
+	}
+	return thresholds
+}
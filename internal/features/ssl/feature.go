@@ -0,0 +1,93 @@
+package ssl
+
+import (
+	"context"
+	"fmt"
+	"the-ark/internal/core"
+	"the-ark/internal/features/ssl/handlers"
+	"the-ark/internal/features/ssl/migrations"
+	"the-ark/internal/features/ssl/services"
+	"time"
+)
+
+// Feature represents the SSL certificate tracker feature
+type Feature struct {
+	*core.BaseFeature
+	config             *Config
+	migrationMgr       *migrations.Manager
+	certificateService *services.CertificateService
+	trackerService     *services.TrackerService
+	handlers           *handlers.Handlers
+}
+
+// NewFeature creates a new SSL tracker feature
+func NewFeature(logger *core.Logger, db *core.Database, mailer services.Mailer, config *Config) *Feature {
+	migrationMgr := migrations.NewManager(db, logger)
+
+	certificateService := services.NewCertificateService(db, logger)
+	checker := services.NewChecker(time.Duration(config.CheckTimeout) * time.Second)
+	trackerService := services.NewTrackerService(certificateService, checker, mailer, logger, services.TrackerConfig{
+		CheckInterval:    time.Duration(config.CheckInterval) * time.Second,
+		ExpiryThresholds: config.Thresholds(),
+		AlertRecipient:   config.AlertRecipient,
+	})
+
+	return &Feature{
+		BaseFeature:        core.NewBaseFeature("ssl", "SSL Certificate Tracker", config.Enabled, logger, db, config),
+		config:             config,
+		migrationMgr:       migrationMgr,
+		certificateService: certificateService,
+		trackerService:     trackerService,
+		handlers:           handlers.NewHandlers(logger, certificateService, trackerService, config.Thresholds()),
+	}
+}
+
+// Init initializes the SSL tracker feature
+func (f *Feature) Init(ctx context.Context) error {
+	if err := f.BaseFeature.Init(ctx); err != nil {
+		return err
+	}
+
+	// Validate configuration
+	if err := f.config.Validate(); err != nil {
+		return err
+	}
+
+	// Run migrations
+	if err := f.migrationMgr.Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := f.trackerService.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start SSL tracker: %w", err)
+	}
+
+	f.Logger().Info("SSL tracker feature initialized successfully")
+	return nil
+}
+
+// Routes returns the HTTP routes for the SSL tracker feature
+func (f *Feature) Routes() []core.Route {
+	return []core.Route{
+		// Web interface routes
+		{Method: "GET", Path: "/ssl", Handler: f.handlers.Dashboard},
+
+		// API routes
+		{Method: "GET", Path: "/ssl/api/hosts", Handler: f.handlers.ListHosts},
+		{Method: "POST", Path: "/ssl/api/hosts", Handler: f.handlers.CreateHost},
+		{Method: "GET", Path: "/ssl/api/hosts/{id}", Handler: f.handlers.GetHost},
+		{Method: "DELETE", Path: "/ssl/api/hosts/{id}", Handler: f.handlers.DeleteHost},
+		{Method: "POST", Path: "/ssl/api/hosts/{id}/check", Handler: f.handlers.CheckHost},
+	}
+}
+
+// Shutdown gracefully shuts down the SSL tracker feature
+func (f *Feature) Shutdown(ctx context.Context) error {
+	f.Logger().Info("Shutting down SSL tracker feature")
+
+	if err := f.trackerService.Stop(ctx); err != nil {
+		f.Logger().Error("Failed to stop SSL tracker", "error", err)
+	}
+
+	return f.BaseFeature.Shutdown(ctx)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"the-ark/internal/auth"
+	"the-ark/internal/core"
+	"the-ark/internal/features/ssl/models"
+	"the-ark/internal/features/ssl/services"
+	viewssl "the-ark/views/ssl"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handlers contains all SSL tracker HTTP handlers
+type Handlers struct {
+	logger       *core.Logger
+	certificates *services.CertificateService
+	tracker      *services.TrackerService
+
+	// thresholds are the expiry warning thresholds, the largest of which
+	// marks certificates as expiring soon on the dashboard
+	thresholds []int
+}
+
+// NewHandlers creates a new handlers instance
+func NewHandlers(logger *core.Logger, certificates *services.CertificateService, tracker *services.TrackerService, thresholds []int) *Handlers {
+	return &Handlers{
+		logger:       logger,
+		certificates: certificates,
+		tracker:      tracker,
+		thresholds:   thresholds,
+	}
+}
+
+// Dashboard renders the SSL tracker page
+func (h *Handlers) Dashboard(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromContext(r)
+	if user == nil {
+		h.logger.Error("User is nil in SSL dashboard handler")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	hosts, err := h.certificates.ListHosts(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list SSL hosts", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := viewssl.Dashboard(user, hosts, h.thresholds, time.Now())
+	component.Render(r.Context(), w)
+}
+
+// ListHosts returns every tracked host with its last certificate
+func (h *Handlers) ListHosts(w http.ResponseWriter, r *http.Request) {
+	hosts, err := h.certificates.ListHosts(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list SSL hosts", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"hosts": hosts})
+}
+
+// GetHost returns a tracked host with its last certificate
+func (h *Handlers) GetHost(w http.ResponseWriter, r *http.Request) {
+	host, ok := h.host(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(host)
+}
+
+// CreateHost starts tracking a host from the add host form and checks it
+// straight away
+func (h *Handlers) CreateHost(w http.ResponseWriter, r *http.Request) {
+	hostname, port, err := models.ParseHost(r.FormValue("host"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	host := models.Host{
+		Name:     strings.TrimSpace(r.FormValue("name")),
+		Hostname: hostname,
+		Port:     port,
+	}
+	if host.Name == "" {
+		host.Name = hostname
+	}
+	if err := host.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.certificates.CreateHost(r.Context(), &host); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			http.Error(w, "That host is already being tracked", http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to create SSL host", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// A host that can't be reached yet is still tracked, the error shows on
+	// the dashboard
+	if _, err := h.tracker.CheckHost(r.Context(), host); err != nil {
+		h.logger.Warn("Initial SSL check failed", "host", host.Address(), "error", err)
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.renderHostList(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "id": host.ID})
+}
+
+// DeleteHost stops tracking a host
+func (h *Handlers) DeleteHost(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid host ID", http.StatusBadRequest)
+		return
+	}
+
+	// Hosts synced from uptime websites would only come back on the next
+	// sync, they stop being tracked when their website is removed
+	host, err := h.certificates.GetHost(r.Context(), id)
+	if err == nil && host.WebsiteID != nil {
+		http.Error(w, "Host is tracked from an uptime website, remove the website instead", http.StatusConflict)
+		return
+	}
+	if err == nil {
+		err = h.certificates.DeleteHost(r.Context(), id)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Host not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete SSL host", "host_id", id, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"success": true, "message": "Host deleted successfully"}`))
+}
+
+// CheckHost reads a host's certificate now
+func (h *Handlers) CheckHost(w http.ResponseWriter, r *http.Request) {
+	host, ok := h.host(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.tracker.CheckHost(r.Context(), *host); err != nil {
+		h.logger.Warn("SSL check failed", "host", host.Address(), "error", err)
+	}
+
+	// Reload so the response reflects the stored result either way
+	host, ok = h.host(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		component := viewssl.HostRow(*host, h.thresholds, time.Now())
+		component.Render(r.Context(), w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(host)
+}
+
+// host loads the host named in the URL, writing an error response if it
+// can't
+func (h *Handlers) host(w http.ResponseWriter, r *http.Request) (*models.Host, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid host ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	host, err := h.certificates.GetHost(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Host not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("Failed to get SSL host", "host_id", id, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return host, true
+}
+
+// renderHostList renders the dashboard's host list
+func (h *Handlers) renderHostList(w http.ResponseWriter, r *http.Request) {
+	hosts, err := h.certificates.ListHosts(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list SSL hosts", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := viewssl.HostList(hosts, h.thresholds, time.Now())
+	component.Render(r.Context(), w)
+}
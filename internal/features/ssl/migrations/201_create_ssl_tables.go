@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration201CreateSSLTables creates the SSL certificate tracker tables
+var Migration201CreateSSLTables = core.Migration{
+	Version:     201,
+	Name:        "create_ssl_tables",
+	Description: "Create SSL certificate tracker tables",
+	UpSQL: `
+		-- Hosts whose certificates are tracked
+		CREATE TABLE IF NOT EXISTS ssl_hosts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			hostname TEXT NOT NULL,
+			port INTEGER NOT NULL DEFAULT 443,
+			last_checked_at DATETIME,
+			last_error TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (hostname, port)
+		);
+
+		-- The last certificate read from each host
+		CREATE TABLE IF NOT EXISTS ssl_certificates (
+			host_id INTEGER PRIMARY KEY REFERENCES ssl_hosts(id) ON DELETE CASCADE,
+			subject TEXT NOT NULL,
+			issuer TEXT NOT NULL,
+			sans TEXT NOT NULL DEFAULT '[]',
+			serial_number TEXT NOT NULL,
+			not_before DATETIME NOT NULL,
+			not_after DATETIME NOT NULL,
+			fingerprint TEXT NOT NULL,
+			key_algorithm TEXT NOT NULL,
+			key_bits INTEGER NOT NULL DEFAULT 0,
+			signature_algorithm TEXT NOT NULL,
+			chain_length INTEGER NOT NULL DEFAULT 1,
+			problems TEXT NOT NULL DEFAULT '[]',
+			checked_at DATETIME NOT NULL
+		);
+
+		-- Expiry warnings sent, so each threshold is sent once per certificate
+		CREATE TABLE IF NOT EXISTS ssl_expiry_alerts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			host_id INTEGER NOT NULL REFERENCES ssl_hosts(id) ON DELETE CASCADE,
+			fingerprint TEXT NOT NULL,
+			threshold INTEGER NOT NULL,
+			sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (host_id, fingerprint, threshold)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_ssl_certificates_not_after ON ssl_certificates(not_after);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_ssl_certificates_not_after;
+		DROP TABLE IF EXISTS ssl_expiry_alerts;
+		DROP TABLE IF EXISTS ssl_certificates;
+		DROP TABLE IF EXISTS ssl_hosts;
+	`,
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration202LinkHostsToWebsites links hosts to the uptime website they
+// are tracked from, so HTTPS and TLS websites don't have to be added twice
+var Migration202LinkHostsToWebsites = core.Migration{
+	Version:     202,
+	Name:        "link_ssl_hosts_to_websites",
+	Description: "Link SSL hosts to the uptime websites they are tracked from",
+	UpSQL: `
+		-- Set for hosts synced from uptime websites, NULL for hosts added by hand
+		ALTER TABLE ssl_hosts ADD COLUMN website_id INTEGER;
+
+		CREATE INDEX IF NOT EXISTS idx_ssl_hosts_website_id ON ssl_hosts(website_id);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_ssl_hosts_website_id;
+		ALTER TABLE ssl_hosts DROP COLUMN website_id;
+	`,
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"the-ark/internal/core"
+)
+
+// Manager handles SSL tracker feature migrations
+type Manager struct {
+	migrationService *core.MigrationService
+	logger           *core.Logger
+}
+
+// NewManager creates a new SSL tracker migration manager
+func NewManager(db *core.Database, logger *core.Logger) *Manager {
+	migrationService := core.NewMigrationService(db, logger)
+	return &Manager{
+		migrationService: migrationService,
+		logger:           logger,
+	}
+}
+
+// Migrations returns all SSL tracker migrations in order. They are numbered
+// from 201 so they don't collide with other features sharing the migrations
+// table.
+func (m *Manager) Migrations() []core.Migration {
+	return []core.Migration{
+		Migration201CreateSSLTables,
+		Migration202LinkHostsToWebsites,
+	}
+}
+
+// Migrate applies all pending SSL tracker migrations
+func (m *Manager) Migrate(ctx context.Context) error {
+	// Initialize migrations table if it doesn't exist
+	if err := m.migrationService.InitMigrations(ctx); err != nil {
+		return fmt.Errorf("failed to initialize migrations: %w", err)
+	}
+
+	migrations := m.Migrations()
+	m.logger.Info("Starting SSL tracker migrations", "count", len(migrations))
+
+	for _, migration := range migrations {
+		if err := m.migrationService.ApplyMigration(ctx, migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	m.logger.Info("SSL tracker migrations completed successfully")
+	return nil
+}
+
+// Status returns the current migration status
+func (m *Manager) Status(ctx context.Context) (*core.MigrationStatus, error) {
+	return m.migrationService.GetMigrationStatus(ctx)
+}
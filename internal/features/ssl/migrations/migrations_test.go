@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"the-ark/internal/core"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestSSLMigrations(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	manager := NewManager(core.NewDatabase(db, core.NewLogger()), core.NewLogger())
+
+	ctx := context.Background()
+	if err := manager.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	// Migrations must be idempotent
+	if err := manager.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to re-apply migrations: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM migrations").Scan(&count); err != nil {
+		t.Fatalf("Failed to query migrations table: %v", err)
+	}
+	if count != len(manager.Migrations()) {
+		t.Errorf("Expected %d migrations, got %d", len(manager.Migrations()), count)
+	}
+
+	// Every SSL migration must fit in the SSL version range
+	for _, migration := range manager.Migrations() {
+		if migration.Version <= 200 || migration.Version >= 300 {
+			t.Errorf("Migration %s has version %d outside the SSL range", migration.Name, migration.Version)
+		}
+	}
+
+	for _, table := range []string{"ssl_hosts", "ssl_certificates", "ssl_expiry_alerts"} {
+		var found int
+		err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?", table).Scan(&found)
+		if err != nil {
+			t.Fatalf("Failed to check table %s: %v", table, err)
+		}
+		if found != 1 {
+			t.Errorf("Table %s was not created", table)
+		}
+	}
+}
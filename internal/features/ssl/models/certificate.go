@@ -0,0 +1,221 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPort is the port hosts are checked on when none is given
+const DefaultPort = 443
+
+// Host is a TLS endpoint whose certificate is tracked
+type Host struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	Port     int    `json:"port"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+
+	// LastError is set when the last check couldn't read a certificate
+	LastError string `json:"last_error,omitempty"`
+
+	// Certificate is the last certificate read from the host, if any
+	Certificate *Certificate `json:"certificate,omitempty"`
+
+	// WebsiteID is the uptime website the host is synced from, nil for
+	// hosts added by hand
+	WebsiteID *int `json:"website_id,omitempty"`
+}
+
+// Address returns the host and port to dial
+func (h Host) Address() string {
+	return net.JoinHostPort(h.Hostname, strconv.Itoa(h.Port))
+}
+
+// Validate checks the host can be dialled
+func (h Host) Validate() error {
+	if h.Hostname == "" {
+		return fmt.Errorf("hostname is required")
+	}
+	if h.Port <= 0 || h.Port > 65535 {
+		return fmt.Errorf("invalid port %d", h.Port)
+	}
+	return nil
+}
+
+// WebsiteHost returns the host whose certificate an uptime website serves,
+// reporting false for websites that don't serve one. HTTP checks of https
+// URLs and TLS checks do.
+func WebsiteHost(rawURL, checkType string) (string, int, bool) {
+	switch checkType {
+	case "", "http":
+		if !strings.HasPrefix(strings.ToLower(rawURL), "https://") {
+			return "", 0, false
+		}
+	case "tls":
+	default:
+		return "", 0, false
+	}
+
+	hostname, port, err := ParseHost(rawURL)
+	return hostname, port, err == nil
+}
+
+// ParseHost reads a hostname and port from a bare host, a host:port pair or
+// an https URL
+func ParseHost(raw string) (string, int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", 0, fmt.Errorf("hostname is required")
+	}
+
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return "", 0, fmt.Errorf("invalid URL %q", raw)
+		}
+		raw = u.Host
+	}
+
+	host, port := raw, DefaultPort
+	if h, p, err := net.SplitHostPort(raw); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 || n > 65535 {
+			return "", 0, fmt.Errorf("invalid port %q", p)
+		}
+		host, port = h, n
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", 0, fmt.Errorf("invalid hostname %q", raw)
+	}
+	return host, port, nil
+}
+
+// ProblemKind identifies something wrong with a certificate
+type ProblemKind string
+
+const (
+	ProblemExpired          ProblemKind = "expired"
+	ProblemNotYetValid      ProblemKind = "not_yet_valid"
+	ProblemHostnameMismatch ProblemKind = "hostname_mismatch"
+	ProblemWeakKey          ProblemKind = "weak_key"
+	ProblemWeakSignature    ProblemKind = "weak_signature"
+	ProblemChain            ProblemKind = "chain"
+)
+
+// Problem is an issue found when inspecting a certificate
+type Problem struct {
+	Kind    ProblemKind `json:"kind"`
+	Message string      `json:"message"`
+}
+
+// Certificate describes the leaf certificate served by a host, along with
+// anything wrong with it or the chain it was served with
+type Certificate struct {
+	HostID int `json:"host_id"`
+
+	Subject      string   `json:"subject"`
+	Issuer       string   `json:"issuer"`
+	SANs         []string `json:"sans"`
+	SerialNumber string   `json:"serial_number"`
+
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+
+	// Fingerprint is the SHA-256 hash of the DER certificate, as colon
+	// separated hex
+	Fingerprint string `json:"fingerprint"`
+
+	KeyAlgorithm       string `json:"key_algorithm"`
+	KeyBits            int    `json:"key_bits"`
+	SignatureAlgorithm string `json:"signature_algorithm"`
+
+	// ChainLength counts the certificates served, including the leaf
+	ChainLength int `json:"chain_length"`
+
+	Problems  []Problem `json:"problems,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// DaysLeft returns the number of whole days until the certificate expires,
+// negative once it has
+func (c Certificate) DaysLeft(now time.Time) int {
+	left := c.NotAfter.Sub(now)
+	days := int(left / (24 * time.Hour))
+	if left < 0 {
+		days--
+	}
+	return days
+}
+
+// IsExpired reports whether the certificate has expired at now
+func (c Certificate) IsExpired(now time.Time) bool {
+	return now.After(c.NotAfter)
+}
+
+// HasProblem reports whether the certificate has a problem of the given kind
+func (c Certificate) HasProblem(kind ProblemKind) bool {
+	for _, problem := range c.Problems {
+		if problem.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultExpiryThresholds are the days before expiry warnings are sent at
+var DefaultExpiryThresholds = []int{30, 14, 7, 1}
+
+// ParseThresholds reads a comma separated list of days, returned in
+// descending order
+func ParseThresholds(raw string) ([]int, error) {
+	var thresholds []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		days, err := strconv.Atoi(part)
+		if err != nil || days <= 0 || days > 365 {
+			return nil, fmt.Errorf("invalid expiry threshold %q, expected days between 1 and 365", part)
+		}
+		if !seen[days] {
+			seen[days] = true
+			thresholds = append(thresholds, days)
+		}
+	}
+	if len(thresholds) == 0 {
+		return nil, fmt.Errorf("at least one expiry threshold is required")
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(thresholds)))
+	return thresholds, nil
+}
+
+// DueThreshold returns the most urgent threshold the certificate has crossed
+// at now, or zero once it has expired. It reports false while no threshold
+// has been crossed.
+func DueThreshold(cert Certificate, thresholds []int, now time.Time) (int, bool) {
+	if cert.IsExpired(now) {
+		return 0, true
+	}
+
+	daysLeft := cert.DaysLeft(now)
+	due, ok := 0, false
+	for _, threshold := range thresholds {
+		if daysLeft <= threshold && (!ok || threshold < due) {
+			due, ok = threshold, true
+		}
+	}
+	return due, ok
+}
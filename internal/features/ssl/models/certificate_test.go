@@ -0,0 +1,107 @@
+package models
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseHost(t *testing.T) {
+	tests := []struct {
+		raw      string
+		hostname string
+		port     int
+		wantErr  bool
+	}{
+		{"example.com", "example.com", 443, false},
+		{"Example.COM.", "example.com", 443, false},
+		{"example.com:8443", "example.com", 8443, false},
+		{"https://example.com/login", "example.com", 443, false},
+		{"https://example.com:9443", "example.com", 9443, false},
+		{"[::1]:8443", "::1", 8443, false},
+		{"", "", 0, true},
+		{"example.com:99999", "", 0, true},
+		{"bad host", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		hostname, port, err := ParseHost(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseHost(%q) error = %v, want error %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if hostname != tt.hostname || port != tt.port {
+			t.Errorf("ParseHost(%q) = %q, %d, want %q, %d", tt.raw, hostname, port, tt.hostname, tt.port)
+		}
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := ParseThresholds(" 7, 30,1,14,7 ")
+	if err != nil {
+		t.Fatalf("ParseThresholds failed: %v", err)
+	}
+	if !slices.Equal(thresholds, []int{30, 14, 7, 1}) {
+		t.Errorf("Expected thresholds sorted descending without duplicates, got %v", thresholds)
+	}
+
+	for _, raw := range []string{"", "0", "30,soon", "400"} {
+		if _, err := ParseThresholds(raw); err == nil {
+			t.Errorf("Expected ParseThresholds(%q) to fail", raw)
+		}
+	}
+}
+
+func TestDueThreshold(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	thresholds := []int{30, 14, 7, 1}
+
+	tests := []struct {
+		name     string
+		notAfter time.Time
+		want     int
+		due      bool
+	}{
+		{"plenty of time", now.AddDate(0, 0, 60), 0, false},
+		{"within 30 days", now.AddDate(0, 0, 30), 30, true},
+		{"within 7 days", now.Add(5*24*time.Hour + time.Hour), 7, true},
+		{"last day", now.Add(12 * time.Hour), 1, true},
+		{"expired", now.Add(-time.Minute), 0, true},
+	}
+
+	for _, tt := range tests {
+		got, due := DueThreshold(Certificate{NotAfter: tt.notAfter}, thresholds, now)
+		if got != tt.want || due != tt.due {
+			t.Errorf("%s: DueThreshold = %d, %v, want %d, %v", tt.name, got, due, tt.want, tt.due)
+		}
+	}
+}
+
+func TestWebsiteHost(t *testing.T) {
+	tests := []struct {
+		url       string
+		checkType string
+		address   string
+		ok        bool
+	}{
+		{"https://example.com/health", "http", "example.com:443", true},
+		{"https://example.com:8443", "", "example.com:8443", true},
+		{"http://example.com", "http", "", false},
+		{"example.com", "tls", "example.com:443", true},
+		{"example.com:993", "tls", "example.com:993", true},
+		{"example.com:22", "tcp", "", false},
+	}
+
+	for _, tt := range tests {
+		hostname, port, ok := WebsiteHost(tt.url, tt.checkType)
+		if ok != tt.ok {
+			t.Errorf("WebsiteHost(%q, %q) ok = %v, want %v", tt.url, tt.checkType, ok, tt.ok)
+			continue
+		}
+		if ok && net.JoinHostPort(hostname, strconv.Itoa(port)) != tt.address {
+			t.Errorf("WebsiteHost(%q, %q) = %s:%d, want %s", tt.url, tt.checkType, hostname, port, tt.address)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"the-ark/internal/core"
+	"the-ark/internal/features/ssl/models"
+	"time"
+)
+
+// CertificateService stores tracked hosts and the certificates read from them
+type CertificateService struct {
+	db     *core.Database
+	logger *core.Logger
+}
+
+// NewCertificateService creates a new certificate service
+func NewCertificateService(db *core.Database, logger *core.Logger) *CertificateService {
+	return &CertificateService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// hostColumns lists the ssl_hosts and ssl_certificates columns read by
+// scanHost, with hosts joined to their certificate
+const hostColumns = `h.id, h.name, h.hostname, h.port, h.last_checked_at, h.last_error, h.created_at, h.website_id,
+	c.subject, c.issuer, c.sans, c.serial_number, c.not_before, c.not_after, c.fingerprint,
+	c.key_algorithm, c.key_bits, c.signature_algorithm, c.chain_length, c.problems, c.checked_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanHost scans a row selected with hostColumns
+func scanHost(row rowScanner) (*models.Host, error) {
+	var host models.Host
+	var lastChecked sql.NullTime
+	var lastError sql.NullString
+	var websiteID sql.NullInt64
+
+	var subject, issuer, sans, serial, fingerprint, keyAlgorithm, signatureAlgorithm, problems sql.NullString
+	var notBefore, notAfter, checkedAt sql.NullTime
+	var keyBits, chainLength sql.NullInt64
+
+	err := row.Scan(
+		&host.ID, &host.Name, &host.Hostname, &host.Port, &lastChecked, &lastError, &host.CreatedAt, &websiteID,
+		&subject, &issuer, &sans, &serial, &notBefore, &notAfter, &fingerprint,
+		&keyAlgorithm, &keyBits, &signatureAlgorithm, &chainLength, &problems, &checkedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastChecked.Valid {
+		host.LastCheckedAt = &lastChecked.Time
+	}
+	host.LastError = lastError.String
+	if websiteID.Valid {
+		id := int(websiteID.Int64)
+		host.WebsiteID = &id
+	}
+
+	if fingerprint.Valid {
+		cert := &models.Certificate{
+			HostID:             host.ID,
+			Subject:            subject.String,
+			Issuer:             issuer.String,
+			SerialNumber:       serial.String,
+			NotBefore:          notBefore.Time,
+			NotAfter:           notAfter.Time,
+			Fingerprint:        fingerprint.String,
+			KeyAlgorithm:       keyAlgorithm.String,
+			KeyBits:            int(keyBits.Int64),
+			SignatureAlgorithm: signatureAlgorithm.String,
+			ChainLength:        int(chainLength.Int64),
+			CheckedAt:          checkedAt.Time,
+		}
+		if err := json.Unmarshal([]byte(sans.String), &cert.SANs); err != nil {
+			return nil, fmt.Errorf("failed to decode SANs: %w", err)
+		}
+		if err := json.Unmarshal([]byte(problems.String), &cert.Problems); err != nil {
+			return nil, fmt.Errorf("failed to decode problems: %w", err)
+		}
+		host.Certificate = cert
+	}
+
+	return &host, nil
+}
+
+// ListHosts returns every tracked host with its last certificate, soonest to
+// expire first
+func (s *CertificateService) ListHosts(ctx context.Context) ([]models.Host, error) {
+	query := `
+		SELECT ` + hostColumns + `
+		FROM ssl_hosts h
+		LEFT JOIN ssl_certificates c ON c.host_id = h.id
+		ORDER BY c.not_after IS NULL, c.not_after, h.name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	defer rows.Close()
+
+	var hosts []models.Host
+	for rows.Next() {
+		host, err := scanHost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan host: %w", err)
+		}
+		hosts = append(hosts, *host)
+	}
+	return hosts, rows.Err()
+}
+
+// GetHost returns a tracked host with its last certificate, or sql.ErrNoRows
+func (s *CertificateService) GetHost(ctx context.Context, id int) (*models.Host, error) {
+	query := `
+		SELECT ` + hostColumns + `
+		FROM ssl_hosts h
+		LEFT JOIN ssl_certificates c ON c.host_id = h.id
+		WHERE h.id = ?
+	`
+	return scanHost(s.db.QueryRowContext(ctx, query, id))
+}
+
+// CreateHost starts tracking a host, setting its ID and creation time
+func (s *CertificateService) CreateHost(ctx context.Context, host *models.Host) error {
+	query := `
+		INSERT INTO ssl_hosts (name, hostname, port, created_at)
+		VALUES (?, ?, ?, ?)
+		RETURNING id, created_at
+	`
+
+	err := s.db.QueryRowContext(ctx, query, host.Name, host.Hostname, host.Port, time.Now()).
+		Scan(&host.ID, &host.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create host: %w", err)
+	}
+	return nil
+}
+
+// SyncWebsiteHosts tracks the hosts of the HTTPS and TLS uptime websites,
+// so they don't have to be added twice. Hosts of websites that were removed
+// or no longer serve a certificate stop being tracked, while hosts added by
+// hand are left alone and take precedence over a website on the same host.
+// Nothing is synced while the uptime feature's tables don't exist.
+func (s *CertificateService) SyncWebsiteHosts(ctx context.Context) error {
+	var tables int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'uptime_websites'`).Scan(&tables)
+	if err != nil || tables == 0 {
+		return err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, url, check_type FROM uptime_websites ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to list uptime websites: %w", err)
+	}
+	defer rows.Close()
+
+	type websiteHost struct {
+		id       int
+		name     string
+		hostname string
+		port     int
+	}
+	var wanted []websiteHost
+	for rows.Next() {
+		var website websiteHost
+		var rawURL, checkType string
+		if err := rows.Scan(&website.id, &website.name, &rawURL, &checkType); err != nil {
+			return fmt.Errorf("failed to scan uptime website: %w", err)
+		}
+		hostname, port, ok := models.WebsiteHost(rawURL, checkType)
+		if !ok {
+			continue
+		}
+		website.hostname, website.port = hostname, port
+		wanted = append(wanted, website)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	return s.db.Transaction(ctx, func(tx *sql.Tx) error {
+		synced, err := tx.QueryContext(ctx, `SELECT id, website_id, hostname, port FROM ssl_hosts WHERE website_id IS NOT NULL`)
+		if err != nil {
+			return err
+		}
+		var stale []int
+		for synced.Next() {
+			var id, websiteID, port int
+			var hostname string
+			if err := synced.Scan(&id, &websiteID, &hostname, &port); err != nil {
+				synced.Close()
+				return err
+			}
+			if !slices.ContainsFunc(wanted, func(w websiteHost) bool {
+				return w.id == websiteID && w.hostname == hostname && w.port == port
+			}) {
+				stale = append(stale, id)
+			}
+		}
+		synced.Close()
+		if err := synced.Err(); err != nil {
+			return err
+		}
+
+		for _, id := range stale {
+			if err := deleteHost(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
+		// A host already tracked by hand or for another website is left
+		// as it is
+		for _, website := range wanted {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO ssl_hosts (name, hostname, port, website_id, created_at)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (hostname, port) DO UPDATE SET name = excluded.name
+				WHERE ssl_hosts.website_id = excluded.website_id
+			`, website.name, website.hostname, website.port, website.id, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to sync host of website %d: %w", website.id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteHost stops tracking a host, returning sql.ErrNoRows if it doesn't
+// exist
+func (s *CertificateService) DeleteHost(ctx context.Context, id int) error {
+	return s.db.Transaction(ctx, func(tx *sql.Tx) error {
+		return deleteHost(ctx, tx, id)
+	})
+}
+
+// deleteHost deletes a host with its certificate and expiry alerts,
+// returning sql.ErrNoRows if it doesn't exist
+func deleteHost(ctx context.Context, tx *sql.Tx, id int) error {
+	// Delete dependents explicitly, foreign keys may not be enforced
+	if _, err := tx.ExecContext(ctx, `DELETE FROM ssl_expiry_alerts WHERE host_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM ssl_certificates WHERE host_id = ?`, id); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM ssl_hosts WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SaveCertificate stores the certificate read from a host, replacing the
+// previous one and clearing any check error
+func (s *CertificateService) SaveCertificate(ctx context.Context, cert *models.Certificate) error {
+	sans, err := json.Marshal(nonNil(cert.SANs))
+	if err != nil {
+		return err
+	}
+	problems, err := json.Marshal(nonNil(cert.Problems))
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT OR REPLACE INTO ssl_certificates (
+				host_id, subject, issuer, sans, serial_number, not_before, not_after, fingerprint,
+				key_algorithm, key_bits, signature_algorithm, chain_length, problems, checked_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			cert.HostID, cert.Subject, cert.Issuer, string(sans), cert.SerialNumber,
+			cert.NotBefore, cert.NotAfter, cert.Fingerprint, cert.KeyAlgorithm, cert.KeyBits,
+			cert.SignatureAlgorithm, cert.ChainLength, string(problems), cert.CheckedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save certificate: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE ssl_hosts SET last_checked_at = ?, last_error = NULL WHERE id = ?`,
+			cert.CheckedAt, cert.HostID)
+		return err
+	})
+}
+
+// RecordCheckError records that a host's certificate couldn't be read. The
+// last certificate read is kept.
+func (s *CertificateService) RecordCheckError(ctx context.Context, hostID int, message string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE ssl_hosts SET last_checked_at = ?, last_error = ? WHERE id = ?`,
+		at, message, hostID)
+	return err
+}
+
+// HasExpiryAlert reports whether the warning for a threshold was already sent
+// for a certificate
+func (s *CertificateService) HasExpiryAlert(ctx context.Context, hostID int, fingerprint string, threshold int) (bool, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM ssl_expiry_alerts WHERE host_id = ? AND fingerprint = ? AND threshold = ?`,
+		hostID, fingerprint, threshold).Scan(&count)
+	return count > 0, err
+}
+
+// RecordExpiryAlert records that the warning for a threshold was sent for a
+// certificate
+func (s *CertificateService) RecordExpiryAlert(ctx context.Context, hostID int, fingerprint string, threshold int, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO ssl_expiry_alerts (host_id, fingerprint, threshold, sent_at)
+		VALUES (?, ?, ?, ?)
+	`, hostID, fingerprint, threshold, at)
+	return err
+}
+
+// nonNil keeps empty lists encoding as [] rather than null
+func nonNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
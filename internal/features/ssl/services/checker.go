@@ -0,0 +1,262 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"the-ark/internal/features/ssl/models"
+	"time"
+)
+
+// Minimum key sizes below which a key is reported as weak
+const (
+	minRSABits   = 2048
+	minECDSABits = 256
+)
+
+// Checker reads the certificate chain served by a host
+type Checker struct {
+	dialer *net.Dialer
+
+	// rootCAs overrides the system roots when verifying chains, used by tests
+	rootCAs *x509.CertPool
+}
+
+// NewChecker creates a checker giving up on hosts after timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{dialer: &net.Dialer{Timeout: timeout}}
+}
+
+// Inspect connects to a host and describes the certificate it serves. Errors
+// are only returned when no certificate could be read; problems with the
+// certificate itself are reported on the result.
+func (c *Checker) Inspect(ctx context.Context, host models.Host, now time.Time) (*models.Certificate, error) {
+	// Verification is done by Analyze so a bad certificate can still be
+	// described rather than failing the handshake
+	dialer := &tls.Dialer{
+		NetDialer: c.dialer,
+		Config: &tls.Config{
+			ServerName:         host.Hostname,
+			InsecureSkipVerify: true,
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", host.Address())
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	chain := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificate served")
+	}
+
+	cert := Analyze(host.Hostname, chain, c.rootCAs, now)
+	cert.HostID = host.ID
+	return cert, nil
+}
+
+// Analyze describes the leaf of a served chain and flags expiry, hostname
+// mismatches, weak keys and signatures and chain problems. A nil roots pool
+// verifies against the system roots.
+func Analyze(hostname string, chain []*x509.Certificate, roots *x509.CertPool, now time.Time) *models.Certificate {
+	leaf := chain[0]
+	fingerprint := sha256.Sum256(leaf.Raw)
+	algorithm, bits := publicKeyInfo(leaf)
+
+	cert := &models.Certificate{
+		Subject:            nameOrString(leaf.Subject.CommonName, leaf.Subject.String()),
+		Issuer:             nameOrString(leaf.Issuer.CommonName, leaf.Issuer.String()),
+		SANs:               subjectAltNames(leaf),
+		SerialNumber:       leaf.SerialNumber.Text(16),
+		NotBefore:          leaf.NotBefore,
+		NotAfter:           leaf.NotAfter,
+		Fingerprint:        formatFingerprint(fingerprint[:]),
+		KeyAlgorithm:       algorithm,
+		KeyBits:            bits,
+		SignatureAlgorithm: leaf.SignatureAlgorithm.String(),
+		ChainLength:        len(chain),
+		CheckedAt:          now,
+	}
+
+	switch {
+	case now.After(leaf.NotAfter):
+		cert.Problems = append(cert.Problems, models.Problem{
+			Kind:    models.ProblemExpired,
+			Message: fmt.Sprintf("Certificate expired on %s", leaf.NotAfter.Format("Jan 2, 2006")),
+		})
+	case now.Before(leaf.NotBefore):
+		cert.Problems = append(cert.Problems, models.Problem{
+			Kind:    models.ProblemNotYetValid,
+			Message: fmt.Sprintf("Certificate is not valid until %s", leaf.NotBefore.Format("Jan 2, 2006")),
+		})
+	}
+
+	if err := leaf.VerifyHostname(hostname); err != nil {
+		cert.Problems = append(cert.Problems, models.Problem{
+			Kind:    models.ProblemHostnameMismatch,
+			Message: fmt.Sprintf("Certificate is not valid for %s", hostname),
+		})
+	}
+
+	for i, c := range chain {
+		if weak := weakKey(c); weak != "" {
+			cert.Problems = append(cert.Problems, models.Problem{
+				Kind:    models.ProblemWeakKey,
+				Message: describeChainPosition(i) + " " + weak,
+			})
+		}
+		if weakSignature(c) {
+			cert.Problems = append(cert.Problems, models.Problem{
+				Kind:    models.ProblemWeakSignature,
+				Message: fmt.Sprintf("%s is signed with %s", describeChainPosition(i), c.SignatureAlgorithm),
+			})
+		}
+	}
+
+	cert.Problems = append(cert.Problems, chainProblems(chain, roots, now)...)
+	return cert
+}
+
+// chainProblems verifies the served chain against roots and checks it is
+// served in order
+func chainProblems(chain []*x509.Certificate, roots *x509.CertPool, now time.Time) []models.Problem {
+	var problems []models.Problem
+
+	for i := 0; i+1 < len(chain); i++ {
+		if chain[i].CheckSignatureFrom(chain[i+1]) != nil {
+			problems = append(problems, models.Problem{
+				Kind:    models.ProblemChain,
+				Message: fmt.Sprintf("%s is not signed by the next certificate served; the chain is out of order or contains extra certificates", describeChainPosition(i)),
+			})
+			break
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	if err == nil {
+		return problems
+	}
+
+	// An expired or not yet valid leaf is already reported
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) && invalid.Reason == x509.Expired && invalid.Cert == chain[0] {
+		return problems
+	}
+
+	var unknown x509.UnknownAuthorityError
+	message := err.Error()
+	switch {
+	case errors.As(err, &unknown) && len(chain) == 1 && isSelfSigned(chain[0]):
+		message = "Certificate is self-signed"
+	case errors.As(err, &unknown):
+		message = "Certificate chain is incomplete or signed by an untrusted authority"
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		message = "An intermediate certificate in the chain has expired"
+	}
+	return append(problems, models.Problem{Kind: models.ProblemChain, Message: message})
+}
+
+// publicKeyInfo returns the name and size of a certificate's public key
+func publicKeyInfo(c *x509.Certificate) (string, int) {
+	switch key := c.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return c.PublicKeyAlgorithm.String(), 0
+	}
+}
+
+// weakKey describes why a certificate's key is weak, or returns an empty
+// string when it isn't
+func weakKey(c *x509.Certificate) string {
+	algorithm, bits := publicKeyInfo(c)
+	switch algorithm {
+	case "RSA":
+		if bits < minRSABits {
+			return fmt.Sprintf("has a %d-bit RSA key, below the %d-bit minimum", bits, minRSABits)
+		}
+	case "ECDSA":
+		if bits < minECDSABits {
+			return fmt.Sprintf("has a %d-bit ECDSA key, below the %d-bit minimum", bits, minECDSABits)
+		}
+	}
+	if c.PublicKeyAlgorithm == x509.DSA {
+		return "has a DSA key, which is no longer considered secure"
+	}
+	return ""
+}
+
+// weakSignature reports whether a certificate is signed with a broken hash.
+// Self-signed roots are trusted by their presence in the root store, so
+// their signatures don't matter.
+func weakSignature(c *x509.Certificate) bool {
+	if isSelfSigned(c) && c.IsCA {
+		return false
+	}
+	switch c.SignatureAlgorithm {
+	case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return true
+	}
+	return false
+}
+
+// isSelfSigned reports whether a certificate is signed by its own key. Unlike
+// CheckSignatureFrom this doesn't require the certificate to be a CA.
+func isSelfSigned(c *x509.Certificate) bool {
+	return bytes.Equal(c.RawIssuer, c.RawSubject) &&
+		c.CheckSignature(c.SignatureAlgorithm, c.RawTBSCertificate, c.Signature) == nil
+}
+
+func describeChainPosition(i int) string {
+	if i == 0 {
+		return "Certificate"
+	}
+	return fmt.Sprintf("Chain certificate %d", i)
+}
+
+// subjectAltNames lists a certificate's DNS and IP subject alternative names
+func subjectAltNames(c *x509.Certificate) []string {
+	names := append([]string{}, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+func nameOrString(name, fallback string) string {
+	if name != "" {
+		return name
+	}
+	return fallback
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"the-ark/internal/features/ssl/models"
+	"time"
+)
+
+// testCert is a generated certificate and its key
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// certOptions describes a certificate to generate
+type certOptions struct {
+	commonName string
+	dnsNames   []string
+	notBefore  time.Time
+	notAfter   time.Time
+	isCA       bool
+	rsaBits    int
+}
+
+// newTestCert generates a certificate signed by parent, or self-signed when
+// parent is nil. Keys are ECDSA P-256 unless rsaBits is set.
+func newTestCert(t *testing.T, opts certOptions, parent *testCert) *testCert {
+	t.Helper()
+
+	var key crypto.Signer
+	var err error
+	if opts.rsaBits > 0 {
+		key, err = rsa.GenerateKey(rand.Reader, opts.rsaBits)
+	} else {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	if opts.notBefore.IsZero() {
+		opts.notBefore = time.Now().Add(-time.Hour)
+	}
+	if opts.notAfter.IsZero() {
+		opts.notAfter = time.Now().Add(90 * 24 * time.Hour)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		t.Fatalf("Failed to generate serial number: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: opts.commonName},
+		DNSNames:              opts.dnsNames,
+		NotBefore:             opts.notBefore,
+		NotAfter:              opts.notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  opts.isCA,
+	}
+	if opts.isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+		template.ExtKeyUsage = nil
+	}
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, key.Public(), signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// newTestCA generates a root and an intermediate CA
+func newTestCA(t *testing.T) (root, intermediate *testCert) {
+	root = newTestCert(t, certOptions{commonName: "Test Root", isCA: true, notAfter: time.Now().AddDate(10, 0, 0)}, nil)
+	intermediate = newTestCert(t, certOptions{commonName: "Test Intermediate", isCA: true, notAfter: time.Now().AddDate(5, 0, 0)}, root)
+	return root, intermediate
+}
+
+func rootPool(root *testCert) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(root.cert)
+	return pool
+}
+
+// newTLSHost starts a local TLS listener serving chain and returns the host
+// to check it as
+func newTLSHost(t *testing.T, chain []*testCert) models.Host {
+	t.Helper()
+
+	certificate := tls.Certificate{PrivateKey: chain[0].key}
+	for _, c := range chain {
+		certificate.Certificate = append(certificate.Certificate, c.cert.Raw)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to split listener address: %v", err)
+	}
+	n, _ := strconv.Atoi(port)
+	return models.Host{ID: 1, Name: "Local", Hostname: "localhost", Port: n}
+}
+
+func TestInspectReadsServedCertificate(t *testing.T) {
+	root, intermediate := newTestCA(t)
+	leaf := newTestCert(t, certOptions{commonName: "localhost", dnsNames: []string{"localhost", "www.localhost"}}, intermediate)
+	host := newTLSHost(t, []*testCert{leaf, intermediate})
+
+	checker := NewChecker(5 * time.Second)
+	checker.rootCAs = rootPool(root)
+
+	cert, err := checker.Inspect(t.Context(), host, time.Now())
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+
+	if len(cert.Problems) != 0 {
+		t.Errorf("Expected no problems, got %+v", cert.Problems)
+	}
+	if cert.Subject != "localhost" || cert.Issuer != "Test Intermediate" {
+		t.Errorf("Unexpected subject %q and issuer %q", cert.Subject, cert.Issuer)
+	}
+	if len(cert.SANs) != 2 || cert.SANs[1] != "www.localhost" {
+		t.Errorf("Unexpected SANs %v", cert.SANs)
+	}
+	if !cert.NotAfter.Equal(leaf.cert.NotAfter) || !cert.NotBefore.Equal(leaf.cert.NotBefore) {
+		t.Errorf("Unexpected validity %s to %s", cert.NotBefore, cert.NotAfter)
+	}
+	sum := sha256.Sum256(leaf.cert.Raw)
+	if cert.Fingerprint != formatFingerprint(sum[:]) {
+		t.Errorf("Unexpected fingerprint %s", cert.Fingerprint)
+	}
+	if cert.KeyAlgorithm != "ECDSA" || cert.KeyBits != 256 {
+		t.Errorf("Unexpected key %s %d", cert.KeyAlgorithm, cert.KeyBits)
+	}
+	if cert.ChainLength != 2 || cert.HostID != host.ID {
+		t.Errorf("Unexpected chain length %d or host %d", cert.ChainLength, cert.HostID)
+	}
+}
+
+func TestInspectUnreachableHost(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	checker := NewChecker(time.Second)
+	if _, err := checker.Inspect(t.Context(), models.Host{Hostname: "127.0.0.1", Port: port}, time.Now()); err == nil {
+		t.Error("Expected an error for a closed port")
+	}
+}
+
+func TestAnalyzeFlagsProblems(t *testing.T) {
+	root, intermediate := newTestCA(t)
+	now := time.Now()
+
+	valid := newTestCert(t, certOptions{commonName: "example.com", dnsNames: []string{"example.com"}}, intermediate)
+	selfSigned := newTestCert(t, certOptions{commonName: "example.com", dnsNames: []string{"example.com"}}, nil)
+	weak := newTestCert(t, certOptions{commonName: "example.com", dnsNames: []string{"example.com"}, rsaBits: 1024}, intermediate)
+	expired := newTestCert(t, certOptions{
+		commonName: "example.com",
+		dnsNames:   []string{"example.com"},
+		notBefore:  now.AddDate(0, -3, 0),
+		notAfter:   now.Add(-time.Hour),
+	}, intermediate)
+
+	tests := []struct {
+		name     string
+		hostname string
+		chain    []*testCert
+		want     []models.ProblemKind
+	}{
+		{"valid chain", "example.com", []*testCert{valid, intermediate}, nil},
+		{"hostname mismatch", "other.example", []*testCert{valid, intermediate}, []models.ProblemKind{models.ProblemHostnameMismatch}},
+		{"missing intermediate", "example.com", []*testCert{valid}, []models.ProblemKind{models.ProblemChain}},
+		{"out of order chain", "example.com", []*testCert{valid, root, intermediate}, []models.ProblemKind{models.ProblemChain}},
+		{"self-signed", "example.com", []*testCert{selfSigned}, []models.ProblemKind{models.ProblemChain}},
+		{"weak key", "example.com", []*testCert{weak, intermediate}, []models.ProblemKind{models.ProblemWeakKey}},
+		{"expired", "example.com", []*testCert{expired, intermediate}, []models.ProblemKind{models.ProblemExpired}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := make([]*x509.Certificate, len(tt.chain))
+			for i, c := range tt.chain {
+				chain[i] = c.cert
+			}
+
+			cert := Analyze(tt.hostname, chain, rootPool(root), now)
+
+			var got []models.ProblemKind
+			for _, problem := range cert.Problems {
+				got = append(got, problem.Kind)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected problems %v, got %+v", tt.want, cert.Problems)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected problems %v, got %+v", tt.want, cert.Problems)
+				}
+			}
+		})
+	}
+}
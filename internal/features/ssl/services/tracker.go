@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"the-ark/internal/core"
+	"the-ark/internal/features/ssl/models"
+	"time"
+)
+
+// expiryTemplate is the mailer template used for expiry warnings
+const expiryTemplate = "ssl_expiry_warning.tmpl"
+
+// Mailer sends templated emails
+type Mailer interface {
+	Send(recipient, templateFile string, data any) error
+}
+
+// TrackerConfig configures the certificate tracker
+type TrackerConfig struct {
+	// CheckInterval is how often every host is checked
+	CheckInterval time.Duration
+
+	// ExpiryThresholds are the days before expiry warnings are sent at
+	ExpiryThresholds []int
+
+	// AlertRecipient receives expiry warnings
+	AlertRecipient string
+}
+
+// ExpiryWarning is the data rendered into expiry warning emails
+type ExpiryWarning struct {
+	Name        string
+	Hostname    string
+	Port        int
+	Issuer      string
+	Fingerprint string
+	NotAfter    string
+	DaysLeft    int
+	Expired     bool
+	Problems    []models.Problem
+	Timestamp   string
+}
+
+// TrackerService periodically checks every tracked host and sends expiry
+// warnings
+type TrackerService struct {
+	certificates *CertificateService
+	checker      *Checker
+	mailer       Mailer
+	logger       *core.Logger
+	config       TrackerConfig
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewTrackerService creates a new tracker service
+func NewTrackerService(certificates *CertificateService, checker *Checker, mailer Mailer, logger *core.Logger, config TrackerConfig) *TrackerService {
+	if len(config.ExpiryThresholds) == 0 {
+		config.ExpiryThresholds = models.DefaultExpiryThresholds
+	}
+	return &TrackerService{
+		certificates: certificates,
+		checker:      checker,
+		mailer:       mailer,
+		logger:       logger,
+		config:       config,
+	}
+}
+
+// Start begins checking hosts in the background
+func (s *TrackerService) Start(ctx context.Context) error {
+	s.logger.Info("Starting SSL certificate tracker", "interval", s.config.CheckInterval)
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.wg.Add(1)
+	go s.checkLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the tracker, waiting for a running check to finish. It is safe
+// to call more than once.
+func (s *TrackerService) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.logger.Info("Stopping SSL certificate tracker")
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *TrackerService) checkLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	s.CheckAll(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckAll(ctx)
+		}
+	}
+}
+
+// CheckAll syncs the hosts of uptime websites and checks every tracked host
+func (s *TrackerService) CheckAll(ctx context.Context) {
+	if err := s.certificates.SyncWebsiteHosts(ctx); err != nil {
+		s.logger.Error("Failed to sync SSL hosts from uptime websites", "error", err)
+	}
+
+	hosts, err := s.certificates.ListHosts(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get hosts for SSL check", "error", err)
+		return
+	}
+
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.CheckHost(ctx, host); err != nil {
+			s.logger.Warn("SSL check failed", "host", host.Address(), "error", err)
+		}
+	}
+}
+
+// CheckHost reads a host's certificate, stores it and sends an expiry warning
+// if one is due. The returned error is set when no certificate could be read.
+func (s *TrackerService) CheckHost(ctx context.Context, host models.Host) (*models.Certificate, error) {
+	now := time.Now()
+
+	cert, err := s.checker.Inspect(ctx, host, now)
+	if err != nil {
+		if recordErr := s.certificates.RecordCheckError(ctx, host.ID, err.Error(), now); recordErr != nil {
+			s.logger.Error("Failed to record SSL check error", "host_id", host.ID, "error", recordErr)
+		}
+		return nil, err
+	}
+
+	if err := s.certificates.SaveCertificate(ctx, cert); err != nil {
+		return cert, err
+	}
+
+	s.warnExpiry(ctx, host, *cert, now)
+	return cert, nil
+}
+
+// warnExpiry emails a warning when a certificate crosses an expiry threshold.
+// Each threshold is sent once per certificate, so a renewed certificate
+// starts over, and only the most urgent threshold crossed is sent.
+func (s *TrackerService) warnExpiry(ctx context.Context, host models.Host, cert models.Certificate, now time.Time) {
+	threshold, due := models.DueThreshold(cert, s.config.ExpiryThresholds, now)
+	if !due || s.config.AlertRecipient == "" {
+		return
+	}
+
+	sent, err := s.certificates.HasExpiryAlert(ctx, host.ID, cert.Fingerprint, threshold)
+	if err != nil {
+		s.logger.Error("Failed to check SSL expiry alerts", "host_id", host.ID, "error", err)
+		return
+	}
+	if sent {
+		return
+	}
+
+	warning := ExpiryWarning{
+		Name:        host.Name,
+		Hostname:    host.Hostname,
+		Port:        host.Port,
+		Issuer:      cert.Issuer,
+		Fingerprint: cert.Fingerprint,
+		NotAfter:    cert.NotAfter.UTC().Format("Jan 2, 2006 15:04 MST"),
+		DaysLeft:    cert.DaysLeft(now),
+		Expired:     cert.IsExpired(now),
+		Problems:    cert.Problems,
+		Timestamp:   now.Format(time.RFC1123),
+	}
+	if err := s.mailer.Send(s.config.AlertRecipient, expiryTemplate, warning); err != nil {
+		s.logger.Error("Failed to send SSL expiry warning", "host", host.Address(), "error", err)
+		return
+	}
+
+	if err := s.certificates.RecordExpiryAlert(ctx, host.ID, cert.Fingerprint, threshold, now); err != nil {
+		s.logger.Error("Failed to record SSL expiry alert", "host_id", host.ID, "error", err)
+	}
+	s.logger.Info("Sent SSL expiry warning", "host", host.Address(), "threshold", describeThreshold(threshold))
+}
+
+func describeThreshold(threshold int) string {
+	if threshold == 0 {
+		return "expired"
+	}
+	return fmt.Sprintf("%d days", threshold)
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"the-ark/internal/core"
+	"the-ark/internal/features/ssl/migrations"
+	"the-ark/internal/features/ssl/models"
+	"the-ark/internal/server/services/mailer"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// newTestCertificateService returns a certificate service backed by a
+// migrated in-memory database
+func newTestCertificateService(t *testing.T) *CertificateService {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	coreDB := core.NewDatabase(db, core.NewLogger())
+	if err := migrations.NewManager(coreDB, core.NewLogger()).Migrate(t.Context()); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+	return NewCertificateService(coreDB, core.NewLogger())
+}
+
+// newMailStandIn starts an SMTP2GO stand-in recording the emails sent to it
+func newMailStandIn(t *testing.T) (mailer.Mailer, func() []mailer.SMTP2GORequest) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var sent []mailer.SMTP2GORequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request mailer.SMTP2GORequest
+		json.Unmarshal(body, &request)
+		mu.Lock()
+		sent = append(sent, request)
+		mu.Unlock()
+		io.WriteString(w, `{"request_id": "1", "data": {"email_id": "1"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return mailer.New("key", "ssl@example.com").WithEndpoint(server.URL), func() []mailer.SMTP2GORequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]mailer.SMTP2GORequest(nil), sent...)
+	}
+}
+
+func TestTrackerStoresCertificateAndWarnsOnce(t *testing.T) {
+	root, intermediate := newTestCA(t)
+	leaf := newTestCert(t, certOptions{
+		commonName: "localhost",
+		dnsNames:   []string{"localhost"},
+		notAfter:   time.Now().Add(5*24*time.Hour + time.Hour),
+	}, intermediate)
+	host := newTLSHost(t, []*testCert{leaf, intermediate})
+
+	certificates := newTestCertificateService(t)
+	if err := certificates.CreateHost(t.Context(), &host); err != nil {
+		t.Fatalf("Failed to create host: %v", err)
+	}
+
+	checker := NewChecker(5 * time.Second)
+	checker.rootCAs = rootPool(root)
+	m, sent := newMailStandIn(t)
+	tracker := NewTrackerService(certificates, checker, m, core.NewLogger(), TrackerConfig{
+		CheckInterval:    time.Hour,
+		ExpiryThresholds: []int{30, 14, 7, 1},
+		AlertRecipient:   "ops@example.com",
+	})
+
+	for range 2 {
+		if _, err := tracker.CheckHost(t.Context(), host); err != nil {
+			t.Fatalf("CheckHost failed: %v", err)
+		}
+	}
+
+	emails := sent()
+	if len(emails) != 1 {
+		t.Fatalf("Expected one expiry warning, got %d", len(emails))
+	}
+	if emails[0].To[0] != "ops@example.com" || !strings.Contains(emails[0].Subject, "[EXPIRING] localhost") {
+		t.Errorf("Unexpected warning %q to %v", emails[0].Subject, emails[0].To)
+	}
+	if !strings.Contains(emails[0].TextBody, "in 5 day(s)") {
+		t.Errorf("Expected warning to give the days left, got %q", emails[0].TextBody)
+	}
+
+	// Only the most urgent threshold crossed is recorded
+	sum := sha256.Sum256(leaf.cert.Raw)
+	for threshold, want := range map[int]bool{7: true, 14: false} {
+		got, err := certificates.HasExpiryAlert(t.Context(), host.ID, formatFingerprint(sum[:]), threshold)
+		if err != nil {
+			t.Fatalf("HasExpiryAlert failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected %d day alert recorded to be %v", threshold, want)
+		}
+	}
+
+	stored, err := certificates.GetHost(t.Context(), host.ID)
+	if err != nil {
+		t.Fatalf("GetHost failed: %v", err)
+	}
+	if stored.Certificate == nil || stored.Certificate.Issuer != "Test Intermediate" || stored.LastCheckedAt == nil {
+		t.Fatalf("Expected the certificate to be stored, got %+v", stored)
+	}
+	if len(stored.Certificate.SANs) != 1 || stored.Certificate.SANs[0] != "localhost" {
+		t.Errorf("Unexpected stored SANs %v", stored.Certificate.SANs)
+	}
+}
+
+func TestTrackerRecordsCheckErrors(t *testing.T) {
+	certificates := newTestCertificateService(t)
+	host := models.Host{Name: "Closed", Hostname: "127.0.0.1", Port: 1}
+	if err := certificates.CreateHost(t.Context(), &host); err != nil {
+		t.Fatalf("Failed to create host: %v", err)
+	}
+
+	m, sent := newMailStandIn(t)
+	tracker := NewTrackerService(certificates, NewChecker(time.Second), m, core.NewLogger(), TrackerConfig{
+		CheckInterval:  time.Hour,
+		AlertRecipient: "ops@example.com",
+	})
+
+	if _, err := tracker.CheckHost(t.Context(), host); err == nil {
+		t.Fatal("Expected the check to fail")
+	}
+
+	stored, err := certificates.GetHost(t.Context(), host.ID)
+	if err != nil {
+		t.Fatalf("GetHost failed: %v", err)
+	}
+	if stored.LastError == "" || stored.Certificate != nil {
+		t.Errorf("Expected a check error and no certificate, got %+v", stored)
+	}
+	if len(sent()) != 0 {
+		t.Error("Expected no email for an unreachable host")
+	}
+}
+
+func TestTrackerStopsOnce(t *testing.T) {
+	tracker := NewTrackerService(newTestCertificateService(t), NewChecker(time.Second), mailer.Mailer{}, core.NewLogger(), TrackerConfig{
+		CheckInterval: time.Hour,
+	})
+	if err := tracker.Stop(t.Context()); err != nil {
+		t.Fatalf("Stopping a tracker that never started failed: %v", err)
+	}
+
+	if err := tracker.Start(t.Context()); err != nil {
+		t.Fatalf("Failed to start tracker: %v", err)
+	}
+	for range 2 {
+		if err := tracker.Stop(t.Context()); err != nil {
+			t.Fatalf("Failed to stop tracker: %v", err)
+		}
+	}
+}
+
+func TestSyncWebsiteHosts(t *testing.T) {
+	certificates := newTestCertificateService(t)
+	ctx := t.Context()
+
+	// Without the uptime feature's tables there is nothing to sync
+	if err := certificates.SyncWebsiteHosts(ctx); err != nil {
+		t.Fatalf("Failed to sync without uptime websites: %v", err)
+	}
+
+	_, err := certificates.db.ExecContext(ctx, `
+		CREATE TABLE uptime_websites (id INTEGER PRIMARY KEY, name TEXT, url TEXT, check_type TEXT);
+		INSERT INTO uptime_websites (id, name, url, check_type) VALUES
+			(1, 'Shop', 'https://shop.example.com/health', 'http'),
+			(2, 'Blog', 'http://blog.example.com', 'http'),
+			(3, 'Mail', 'mail.example.com:993', 'tls'),
+			(4, 'Manual', 'https://manual.example.com', 'http'),
+			(5, 'SSH', 'shell.example.com:22', 'tcp');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create uptime websites: %v", err)
+	}
+	manual := models.Host{Name: "Added by hand", Hostname: "manual.example.com", Port: 443}
+	if err := certificates.CreateHost(ctx, &manual); err != nil {
+		t.Fatalf("Failed to create host: %v", err)
+	}
+
+	addresses := func() map[string]*int {
+		t.Helper()
+		if err := certificates.SyncWebsiteHosts(ctx); err != nil {
+			t.Fatalf("Failed to sync hosts: %v", err)
+		}
+		hosts, err := certificates.ListHosts(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list hosts: %v", err)
+		}
+		found := make(map[string]*int)
+		for _, host := range hosts {
+			found[host.Address()] = host.WebsiteID
+		}
+		return found
+	}
+
+	hosts := addresses()
+	if len(hosts) != 3 || hosts["shop.example.com:443"] == nil || hosts["mail.example.com:993"] == nil {
+		t.Fatalf("Expected the HTTPS and TLS websites' hosts, got %v", hosts)
+	}
+	if id, ok := hosts["manual.example.com:443"]; !ok || id != nil {
+		t.Errorf("Expected the host added by hand to be left alone, got %v", hosts)
+	}
+
+	// Syncing again changes nothing, and removed websites stop being tracked
+	if hosts := addresses(); len(hosts) != 3 {
+		t.Errorf("Expected syncing to be idempotent, got %v", hosts)
+	}
+	if _, err := certificates.db.ExecContext(ctx, `DELETE FROM uptime_websites WHERE id IN (1, 4)`); err != nil {
+		t.Fatalf("Failed to delete websites: %v", err)
+	}
+	hosts = addresses()
+	if _, ok := hosts["shop.example.com:443"]; ok || len(hosts) != 2 {
+		t.Errorf("Expected the removed website's host to stop being tracked, got %v", hosts)
+	}
+}
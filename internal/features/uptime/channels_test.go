@@ -0,0 +1,75 @@
+package uptime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+)
+
+func TestChannelURLsAreRedacted(t *testing.T) {
+	feature, server := newProbeTestServer(t, Config{})
+
+	secret := "https://hooks.slack.com/services/T000/B000/SLACKSECRET"
+	if err := feature.service.CreateNotificationChannel(models.NotificationChannel{
+		Name: "Ops",
+		Type: models.ChannelSlack,
+		URL:  secret,
+	}); err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	resp, err := http.Get(server.URL + "/uptime/api/channels")
+	if err != nil {
+		t.Fatalf("Failed to list channels: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if strings.Contains(string(body), "SLACKSECRET") {
+		t.Fatalf("Expected the channel list to leave out the webhook URL, got %s", body)
+	}
+
+	var list struct {
+		Channels []struct {
+			ID     int    `json:"id"`
+			URL    string `json:"url"`
+			Target string `json:"target"`
+		} `json:"channels"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil || len(list.Channels) != 1 {
+		t.Fatalf("Failed to decode channels %s: %v", body, err)
+	}
+	listed := list.Channels[0]
+	if listed.Target != "hooks.slack.com" {
+		t.Errorf("Expected the webhook host as the target, got %q", listed.Target)
+	}
+
+	// Sending the redacted URL back with an edit keeps the stored one
+	form := url.Values{
+		"name":         {"Operations"},
+		"channel_type": {models.ChannelSlack},
+		"channel_url":  {listed.URL},
+	}
+	req, _ := http.NewRequest(http.MethodPut, server.URL+fmt.Sprintf("/uptime/api/channels/%d", listed.ID), strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to update channel: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 from the update, got %d", resp.StatusCode)
+	}
+
+	channels, err := feature.service.GetNotificationChannels()
+	if err != nil || len(channels) != 1 {
+		t.Fatalf("Failed to get channels: %v", err)
+	}
+	if channels[0].Name != "Operations" || channels[0].URL != secret {
+		t.Errorf("Expected the renamed channel to keep its URL, got %+v", channels[0])
+	}
+}
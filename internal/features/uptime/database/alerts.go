@@ -0,0 +1,238 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// AlertHistoryLimit is how many alerts the website detail page shows
+const AlertHistoryLimit = 20
+
+// alertColumns lists the alert_history columns read by scanAlert
+const alertColumns = `id, website_id, incident_id, alert_type, channel_id, channel_name,
+	outcome, reason, error_message, sent_at`
+
+// scanAlert scans a row selected with alertColumns
+func scanAlert(row rowScanner) (*models.AlertRecord, error) {
+	var record models.AlertRecord
+	var incidentID, channelID sql.NullInt64
+	var channelName, reason, errorMessage sql.NullString
+
+	err := row.Scan(
+		&record.ID,
+		&record.WebsiteID,
+		&incidentID,
+		&record.Type,
+		&channelID,
+		&channelName,
+		&record.Outcome,
+		&reason,
+		&errorMessage,
+		&record.SentAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	record.IncidentID = int(incidentID.Int64)
+	record.ChannelID = int(channelID.Int64)
+	record.ChannelName = channelName.String
+	record.Reason = reason.String
+	record.Error = errorMessage.String
+	return &record, nil
+}
+
+// queryAlerts runs a query selecting alertColumns
+func (s *DatabaseService) queryAlerts(query string, args ...any) ([]models.AlertRecord, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []models.AlertRecord
+	for rows.Next() {
+		record, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
+}
+
+// RecordAlert records a delivery attempt of an alert in alert_history
+func (s *DatabaseService) RecordAlert(record models.AlertRecord) error {
+	query := `
+		INSERT INTO alert_history (
+			website_id, incident_id, alert_type, channel_id, channel_name,
+			outcome, reason, error_message, sent_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	sentAt := record.SentAt
+	if sentAt.IsZero() {
+		sentAt = time.Now()
+	}
+
+	_, err := s.db.Exec(query,
+		record.WebsiteID,
+		sql.NullInt64{Int64: int64(record.IncidentID), Valid: record.IncidentID > 0},
+		record.Type,
+		sql.NullInt64{Int64: int64(record.ChannelID), Valid: record.ChannelID > 0},
+		record.ChannelName,
+		record.Outcome,
+		nullString(record.Reason),
+		nullString(record.Error),
+		sentAt,
+	)
+	return err
+}
+
+// GetLastAlertSent returns when an alert of one of the given types was last
+// delivered for an incident, or nil if none was
+func (s *DatabaseService) GetLastAlertSent(incidentID int, alertTypes ...string) (*time.Time, error) {
+	if len(alertTypes) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT sent_at
+		FROM alert_history
+		WHERE incident_id = ? AND outcome IN (?, ?)
+		AND alert_type IN (?` + strings.Repeat(", ?", len(alertTypes)-1) + `)
+		ORDER BY sent_at DESC
+		LIMIT 1
+	`
+	args := []any{incidentID, models.AlertSent, models.AlertReleased}
+	for _, alertType := range alertTypes {
+		args = append(args, alertType)
+	}
+
+	var sentAt time.Time
+	err := s.db.QueryRow(query, args...).Scan(&sentAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sentAt, nil
+}
+
+// GetDeferredAlerts retrieves alerts held back by quiet hours, oldest first
+func (s *DatabaseService) GetDeferredAlerts() ([]models.AlertRecord, error) {
+	query := `
+		SELECT ` + alertColumns + `
+		FROM alert_history
+		WHERE outcome = ?
+		ORDER BY sent_at, id
+	`
+	return s.queryAlerts(query, models.AlertDeferred)
+}
+
+// discardDeferredAlerts marks the alerts deferred for the websites selected
+// by a query as discarded, so paused websites don't leave them pending
+func discardDeferredAlerts(tx *sql.Tx, websites string, args ...any) error {
+	query := `UPDATE alert_history SET outcome = ? WHERE outcome = ? AND website_id IN (` + websites + `)`
+	_, err := tx.Exec(query, append([]any{models.AlertDiscarded, models.AlertDeferred}, args...)...)
+	return err
+}
+
+// SetAlertOutcome updates the outcome of a recorded alert
+func (s *DatabaseService) SetAlertOutcome(alertID int, outcome string) error {
+	_, err := s.db.Exec(`UPDATE alert_history SET outcome = ? WHERE id = ?`, outcome, alertID)
+	return err
+}
+
+// GetAlertHistory retrieves a website's most recent delivery attempts
+func (s *DatabaseService) GetAlertHistory(websiteID int, limit int) ([]models.AlertRecord, error) {
+	query := `
+		SELECT ` + alertColumns + `
+		FROM alert_history
+		WHERE website_id = ?
+		ORDER BY sent_at DESC, id DESC
+		LIMIT ?
+	`
+	return s.queryAlerts(query, websiteID, limit)
+}
+
+// SetAlertPolicy replaces a website's alert policy, returning
+// models.ErrUnknownEscalationChannel when its escalation channel doesn't
+// exist
+func (s *DatabaseService) SetAlertPolicy(websiteID int, policy models.AlertPolicy) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setAlertPolicy(tx, websiteID, policy); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetWebsiteAlerts replaces the notification channels and alert policy of a
+// website together, so a rejected policy leaves the channels as they were
+func (s *DatabaseService) SetWebsiteAlerts(websiteID int, channelIDs []int, policy models.AlertPolicy) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setAlertPolicy(tx, websiteID, policy); err != nil {
+		return err
+	}
+	if err := setWebsiteChannels(tx, websiteID, channelIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func setAlertPolicy(tx *sql.Tx, websiteID int, policy models.AlertPolicy) error {
+	query := `
+		UPDATE uptime_websites
+		SET renotify_interval = ?, escalation_channel_id = ?, escalate_after = ?,
+			quiet_hours_start = ?, quiet_hours_end = ?, quiet_hours_timezone = ?,
+			latency_threshold = ?, latency_deviation = ?
+		WHERE id = ?
+	`
+
+	result, err := tx.Exec(query,
+		policy.RenotifyInterval,
+		sql.NullInt64{Int64: int64(policy.EscalationChannelID), Valid: policy.EscalationChannelID > 0},
+		policy.EscalateAfter,
+		nullString(policy.QuietHoursStart),
+		nullString(policy.QuietHoursEnd),
+		nullString(policy.QuietHoursTimezone),
+		policy.LatencyThreshold,
+		policy.LatencyDeviation,
+		websiteID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if policy.EscalationChannelID > 0 {
+		var exists bool
+		err := tx.QueryRow(
+			`SELECT EXISTS (SELECT 1 FROM uptime_notification_channels WHERE id = ?)`,
+			policy.EscalationChannelID,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrUnknownEscalationChannel
+		}
+	}
+	return nil
+}
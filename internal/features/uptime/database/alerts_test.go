@@ -0,0 +1,180 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestAlertHistory(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+	if err := s.OpenIncident(1, "HTTP 503"); err != nil {
+		t.Fatalf("Failed to open incident: %v", err)
+	}
+	incident, err := s.GetLatestIncident(1)
+	if err != nil || incident == nil {
+		t.Fatalf("Failed to get latest incident: %v", err)
+	}
+
+	if last, err := s.GetLastAlertSent(incident.ID, models.AlertDown); err != nil || last != nil {
+		t.Fatalf("Expected no alert sent yet, got %v, %v", last, err)
+	}
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	records := []models.AlertRecord{
+		{WebsiteID: 1, IncidentID: incident.ID, Type: models.AlertDown, ChannelName: "Slack", Outcome: models.AlertSent, SentAt: start},
+		{WebsiteID: 1, IncidentID: incident.ID, Type: models.AlertDown, ChannelName: "Webhook", Outcome: models.AlertFailed, Error: "HTTP 500", SentAt: start.Add(time.Minute)},
+		{WebsiteID: 1, IncidentID: incident.ID, Type: models.AlertReminder, ChannelName: "Slack", Outcome: models.AlertSent, SentAt: start.Add(30 * time.Minute)},
+		{WebsiteID: 1, IncidentID: incident.ID, Type: models.AlertRecovery, ChannelName: "Slack", Outcome: models.AlertDeferred, SentAt: start.Add(45 * time.Minute)},
+	}
+	for _, record := range records {
+		if err := s.RecordAlert(record); err != nil {
+			t.Fatalf("Failed to record alert: %v", err)
+		}
+	}
+
+	last, err := s.GetLastAlertSent(incident.ID, models.AlertDown, models.AlertReminder)
+	if err != nil || last == nil || !last.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("Expected the reminder to be the last alert sent, got %v, %v", last, err)
+	}
+	if last, _ := s.GetLastAlertSent(incident.ID, models.AlertRecovery); last != nil {
+		t.Errorf("Expected deferred alerts not to count as sent, got %v", last)
+	}
+
+	deferred, err := s.GetDeferredAlerts()
+	if err != nil || len(deferred) != 1 || deferred[0].Type != models.AlertRecovery {
+		t.Fatalf("Expected one deferred recovery alert, got %+v, %v", deferred, err)
+	}
+	if err := s.SetAlertOutcome(deferred[0].ID, models.AlertReleased); err != nil {
+		t.Fatalf("Failed to update alert outcome: %v", err)
+	}
+	if deferred, _ := s.GetDeferredAlerts(); len(deferred) != 0 {
+		t.Errorf("Expected no deferred alerts after release, got %+v", deferred)
+	}
+
+	history, err := s.GetAlertHistory(1, 10)
+	if err != nil || len(history) != 4 {
+		t.Fatalf("Expected four alerts in the history, got %+v, %v", history, err)
+	}
+	if history[0].Outcome != models.AlertReleased || history[2].Error != "HTTP 500" {
+		t.Errorf("Expected the history newest first with errors, got %+v", history)
+	}
+}
+
+func TestAlertPolicy(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	if err := s.CreateNotificationChannel(models.NotificationChannel{Name: "Manager", Type: models.ChannelEmail, Recipient: "manager@example.com"}); err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	policy := models.AlertPolicy{
+		RenotifyInterval:    30,
+		EscalationChannelID: 1,
+		EscalateAfter:       15,
+		QuietHoursStart:     "22:00",
+		QuietHoursEnd:       "07:00",
+		QuietHoursTimezone:  "Europe/London",
+	}
+	if err := s.SetAlertPolicy(1, policy); err != nil {
+		t.Fatalf("Failed to set alert policy: %v", err)
+	}
+
+	website, err := s.GetWebsiteByID(1)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if website.Alerts != policy {
+		t.Errorf("Expected policy %+v, got %+v", policy, website.Alerts)
+	}
+
+	// Deleting the escalation contact clears it from the policy
+	if err := s.DeleteNotificationChannel(1); err != nil {
+		t.Fatalf("Failed to delete channel: %v", err)
+	}
+	if website, _ := s.GetWebsiteByID(1); website.Alerts.EscalationChannelID != 0 {
+		t.Errorf("Expected the escalation channel to be cleared, got %d", website.Alerts.EscalationChannelID)
+	}
+
+	if err := s.SetAlertPolicy(99, policy); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing website, got %v", err)
+	}
+}
+
+func TestSetWebsiteAlerts(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	if err := s.CreateNotificationChannel(models.NotificationChannel{Name: "Ops", Type: models.ChannelEmail, Recipient: "ops@example.com"}); err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	policy := models.AlertPolicy{EscalationChannelID: 1, EscalateAfter: 15}
+	if err := s.SetWebsiteAlerts(1, []int{1}, policy); err != nil {
+		t.Fatalf("Failed to set website alerts: %v", err)
+	}
+
+	// An unknown escalation channel is rejected without touching the channels
+	policy.EscalationChannelID = 99
+	if err := s.SetWebsiteAlerts(1, nil, policy); !errors.Is(err, models.ErrUnknownEscalationChannel) {
+		t.Fatalf("Expected ErrUnknownEscalationChannel, got %v", err)
+	}
+	website, err := s.GetWebsiteByID(1)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if website.Alerts.EscalationChannelID != 1 {
+		t.Errorf("Expected the policy to be left as it was, got %+v", website.Alerts)
+	}
+	if channels, _ := s.GetWebsiteChannels(1); len(channels) != 1 {
+		t.Errorf("Expected the channels to be left as they were, got %+v", channels)
+	}
+}
+
+func TestPausingDiscardsDeferredAlerts(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	for _, name := range []string{"Paused", "Tagged", "Deleted"} {
+		website := models.Website{Name: name, URL: "https://" + strings.ToLower(name) + ".example.com"}
+		if name == "Tagged" {
+			website.Tags = []string{"prod"}
+		}
+		if _, err := s.CreateWebsite(website); err != nil {
+			t.Fatalf("Failed to create website: %v", err)
+		}
+	}
+	for websiteID := 1; websiteID <= 3; websiteID++ {
+		record := models.AlertRecord{WebsiteID: websiteID, Type: models.AlertRecovery, Outcome: models.AlertDeferred, SentAt: time.Now()}
+		if err := s.RecordAlert(record); err != nil {
+			t.Fatalf("Failed to record alert: %v", err)
+		}
+	}
+
+	if err := s.SetWebsiteActive(1, false); err != nil {
+		t.Fatalf("Failed to pause website: %v", err)
+	}
+	if _, err := s.SetTagActive("prod", false); err != nil {
+		t.Fatalf("Failed to pause tag: %v", err)
+	}
+	if err := s.DeleteWebsite(3); err != nil {
+		t.Fatalf("Failed to delete website: %v", err)
+	}
+
+	if deferred, err := s.GetDeferredAlerts(); err != nil || len(deferred) != 0 {
+		t.Fatalf("Expected no deferred alerts left pending, got %+v, %v", deferred, err)
+	}
+	for websiteID := 1; websiteID <= 2; websiteID++ {
+		history, err := s.GetAlertHistory(websiteID, 10)
+		if err != nil || len(history) != 1 || history[0].Outcome != models.AlertDiscarded {
+			t.Errorf("Expected the deferred alert of website %d discarded, got %+v, %v", websiteID, history, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"the-ark/internal/features/uptime/models"
+)
+
+// ContentSnapshotLimit is how many content snapshots the detail view shows
+const ContentSnapshotLimit = 10
+
+// snapshotColumns are the columns scanSnapshot reads, in order
+const snapshotColumns = `id, website_id, hash, content, diff, captured_at`
+
+// StoreContentSnapshot stores a snapshot of a website's content, deleting
+// its oldest snapshots beyond models.MaxContentSnapshots
+func (s *DatabaseService) StoreContentSnapshot(snapshot models.ContentSnapshot) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO uptime_content_snapshots (website_id, hash, content, diff, captured_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, snapshot.WebsiteID, snapshot.Hash, snapshot.Content, snapshot.Diff, snapshot.CapturedAt.Local())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM uptime_content_snapshots
+		WHERE website_id = ? AND id NOT IN (
+			SELECT id FROM uptime_content_snapshots WHERE website_id = ?
+			ORDER BY captured_at DESC, id DESC LIMIT ?
+		)
+	`, snapshot.WebsiteID, snapshot.WebsiteID, models.MaxContentSnapshots)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetLatestContentSnapshot retrieves a website's most recent content
+// snapshot, or nil if it has none
+func (s *DatabaseService) GetLatestContentSnapshot(websiteID int) (*models.ContentSnapshot, error) {
+	row := s.db.QueryRow(`
+		SELECT `+snapshotColumns+` FROM uptime_content_snapshots
+		WHERE website_id = ? ORDER BY captured_at DESC, id DESC LIMIT 1
+	`, websiteID)
+
+	snapshot, err := scanSnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// GetContentSnapshots retrieves a website's most recent content snapshots,
+// newest first
+func (s *DatabaseService) GetContentSnapshots(websiteID int, limit int) ([]models.ContentSnapshot, error) {
+	rows, err := s.db.Query(`
+		SELECT `+snapshotColumns+` FROM uptime_content_snapshots
+		WHERE website_id = ? ORDER BY captured_at DESC, id DESC LIMIT ?
+	`, websiteID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []models.ContentSnapshot
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// scanSnapshot scans a row selected with snapshotColumns
+func scanSnapshot(row rowScanner) (*models.ContentSnapshot, error) {
+	var snapshot models.ContentSnapshot
+	err := row.Scan(&snapshot.ID, &snapshot.WebsiteID, &snapshot.Hash, &snapshot.Content, &snapshot.Diff, &snapshot.CapturedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestContentSnapshots(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	if latest, err := s.GetLatestContentSnapshot(1); err != nil || latest != nil {
+		t.Fatalf("Expected no snapshot yet, got %+v, %v", latest, err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	for i := range models.MaxContentSnapshots + 5 {
+		content := fmt.Sprintf("version %d", i)
+		snapshot := models.ContentSnapshot{
+			WebsiteID:  1,
+			Hash:       models.ContentHash(content),
+			Content:    content,
+			Diff:       fmt.Sprintf("+ version %d", i),
+			CapturedAt: start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := s.StoreContentSnapshot(snapshot); err != nil {
+			t.Fatalf("Failed to store snapshot: %v", err)
+		}
+	}
+
+	latest, err := s.GetLatestContentSnapshot(1)
+	if err != nil || latest == nil {
+		t.Fatalf("Failed to get latest snapshot: %+v, %v", latest, err)
+	}
+	last := fmt.Sprintf("version %d", models.MaxContentSnapshots+4)
+	if latest.Content != last || latest.Hash != models.ContentHash(last) {
+		t.Errorf("Expected the newest snapshot, got %+v", latest)
+	}
+
+	// Only the newest snapshots are kept
+	snapshots, err := s.GetContentSnapshots(1, 100)
+	if err != nil {
+		t.Fatalf("Failed to get snapshots: %v", err)
+	}
+	if len(snapshots) != models.MaxContentSnapshots {
+		t.Fatalf("Expected %d snapshots kept, got %d", models.MaxContentSnapshots, len(snapshots))
+	}
+	if snapshots[0].Content != last || snapshots[len(snapshots)-1].Content != "version 5" {
+		t.Errorf("Expected snapshots newest first down to version 5, got %q to %q", snapshots[0].Content, snapshots[len(snapshots)-1].Content)
+	}
+
+	if err := s.DeleteWebsite(1); err != nil {
+		t.Fatalf("Failed to delete website: %v", err)
+	}
+	if snapshots, err := s.GetContentSnapshots(1, 100); err != nil || len(snapshots) != 0 {
+		t.Errorf("Expected snapshots deleted with the website, got %d, %v", len(snapshots), err)
+	}
+}
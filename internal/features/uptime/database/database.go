@@ -2,7 +2,9 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"the-ark/internal/features/uptime/models"
 	"time"
 )
@@ -17,15 +19,139 @@ func NewDatabaseService(db *sql.DB) *DatabaseService {
 	}
 }
 
-// GetActiveWebsites retrieves all active websites from the database
+// websiteColumns lists the uptime_websites columns read by scanWebsite
+const websiteColumns = `id, name, url, check_interval, created_at, assertions,
+	request_method, request_headers, request_body, auth_type, auth_username, auth_secret,
+	user_agent, follow_redirects, check_type, dns_record_type, dns_expected,
+	heartbeat_token, grace_period, last_ping_at,
+	retries, retry_delay, failure_threshold, recovery_threshold,
+	renotify_interval, escalation_channel_id, escalate_after,
+	quiet_hours_start, quiet_hours_end, quiet_hours_timezone,
+	latency_threshold, latency_deviation, degraded_since, is_active, updated_at,
+	content_options, resumed_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanWebsite scans a row selected with websiteColumns
+func scanWebsite(row rowScanner) (*models.Website, error) {
+	var website models.Website
+	var createdAt time.Time
+	var assertions, requestHeaders, requestBody, authUsername, authSecret, userAgent sql.NullString
+	var contentOptions sql.NullString
+	var dnsRecordType, dnsExpected, heartbeatToken sql.NullString
+	var lastPingAt, degradedSince, updatedAt, resumedAt sql.NullTime
+	var escalationChannelID sql.NullInt64
+	var quietHoursStart, quietHoursEnd, quietHoursTimezone sql.NullString
+
+	err := row.Scan(
+		&website.ID,
+		&website.Name,
+		&website.URL,
+		&website.CheckInterval,
+		&createdAt,
+		&assertions,
+		&website.Request.Method,
+		&requestHeaders,
+		&requestBody,
+		&website.Request.AuthType,
+		&authUsername,
+		&authSecret,
+		&userAgent,
+		&website.Request.FollowRedirects,
+		&website.CheckType,
+		&dnsRecordType,
+		&dnsExpected,
+		&heartbeatToken,
+		&website.GracePeriod,
+		&lastPingAt,
+		&website.Confirmation.Retries,
+		&website.Confirmation.RetryDelay,
+		&website.Confirmation.FailureThreshold,
+		&website.Confirmation.RecoveryThreshold,
+		&website.Alerts.RenotifyInterval,
+		&escalationChannelID,
+		&website.Alerts.EscalateAfter,
+		&quietHoursStart,
+		&quietHoursEnd,
+		&quietHoursTimezone,
+		&website.Alerts.LatencyThreshold,
+		&website.Alerts.LatencyDeviation,
+		&degradedSince,
+		&website.IsActive,
+		&updatedAt,
+		&contentOptions,
+		&resumedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if assertions.Valid && assertions.String != "" {
+		if err := json.Unmarshal([]byte(assertions.String), &website.Assertions); err != nil {
+			return nil, fmt.Errorf("failed to decode assertions for website %d: %w", website.ID, err)
+		}
+	}
+
+	if contentOptions.Valid && contentOptions.String != "" {
+		if err := json.Unmarshal([]byte(contentOptions.String), &website.Content); err != nil {
+			return nil, fmt.Errorf("failed to decode content options for website %d: %w", website.ID, err)
+		}
+	}
+
+	if requestHeaders.Valid && requestHeaders.String != "" {
+		if err := json.Unmarshal([]byte(requestHeaders.String), &website.Request.Headers); err != nil {
+			return nil, fmt.Errorf("failed to decode request headers for website %d: %w", website.ID, err)
+		}
+	}
+
+	website.Request.Body = requestBody.String
+	website.Request.AuthUsername = authUsername.String
+	website.Request.AuthSecret = authSecret.String
+	website.Request.UserAgent = userAgent.String
+	website.DNS.RecordType = dnsRecordType.String
+	website.DNS.Expected = dnsExpected.String
+	website.HeartbeatToken = heartbeatToken.String
+	if lastPingAt.Valid {
+		website.LastPingAt = &lastPingAt.Time
+	}
+	website.Alerts.EscalationChannelID = int(escalationChannelID.Int64)
+	website.Alerts.QuietHoursStart = quietHoursStart.String
+	website.Alerts.QuietHoursEnd = quietHoursEnd.String
+	website.Alerts.QuietHoursTimezone = quietHoursTimezone.String
+	if degradedSince.Valid {
+		website.DegradedSince = &degradedSince.Time
+	}
+	if resumedAt.Valid {
+		website.ResumedAt = &resumedAt.Time
+	}
+
+	website.CreatedAt = createdAt
+	website.UpdatedAt = createdAt
+	if updatedAt.Valid {
+		website.UpdatedAt = updatedAt.Time
+	}
+
+	return &website, nil
+}
+
+// GetWebsites retrieves every website with its tags, including paused ones
+func (s *DatabaseService) GetWebsites() ([]models.Website, error) {
+	return s.queryWebsites(`SELECT ` + websiteColumns + ` FROM uptime_websites ORDER BY name`)
+}
+
+// GetActiveWebsites retrieves the websites that aren't paused, with their
+// tags
 func (s *DatabaseService) GetActiveWebsites() ([]models.Website, error) {
-	query := `
-		SELECT id, name, url, check_interval, created_at
-		FROM uptime_websites
-		ORDER BY name
-	`
+	return s.queryWebsites(`SELECT ` + websiteColumns + ` FROM uptime_websites WHERE is_active = 1 ORDER BY name`)
+}
 
-	rows, err := s.db.Query(query)
+// queryWebsites retrieves the websites selected by a query of websiteColumns
+// and loads their tags
+func (s *DatabaseService) queryWebsites(query string, args ...any) ([]models.Website, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,63 +159,82 @@ func (s *DatabaseService) GetActiveWebsites() ([]models.Website, error) {
 
 	var websites []models.Website
 	for rows.Next() {
-		var website models.Website
-		var createdAt time.Time
-
-		err := rows.Scan(
-			&website.ID,
-			&website.Name,
-			&website.URL,
-			&website.CheckInterval,
-			&createdAt,
-		)
+		website, err := scanWebsite(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		website.CreatedAt = createdAt
-		website.UpdatedAt = createdAt // Use created_at for updated_at since it doesn't exist
-		website.IsActive = true       // All websites in uptime_websites are considered active
-
-		websites = append(websites, website)
+		websites = append(websites, *website)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
+	if err := s.loadTags(websites); err != nil {
+		return nil, err
+	}
 	return websites, nil
 }
 
 // GetWebsiteByID retrieves a specific website by ID
 func (s *DatabaseService) GetWebsiteByID(websiteID int) (*models.Website, error) {
 	query := `
-		SELECT id, name, url, check_interval, created_at
+		SELECT ` + websiteColumns + `
 		FROM uptime_websites
 		WHERE id = ?
 	`
 
-	var website models.Website
-	var createdAt time.Time
-
-	err := s.db.QueryRow(query, websiteID).Scan(
-		&website.ID,
-		&website.Name,
-		&website.URL,
-		&website.CheckInterval,
-		&createdAt,
-	)
+	website, err := scanWebsite(s.db.QueryRow(query, websiteID))
 	if err != nil {
 		return nil, err
 	}
 
-	website.CreatedAt = createdAt
-	website.UpdatedAt = createdAt // Use created_at for updated_at since it doesn't exist
-	website.IsActive = true
+	if website.ChannelIDs, err = s.getWebsiteChannelIDs(websiteID); err != nil {
+		return nil, err
+	}
+	if website.Tags, err = s.getWebsiteTags(websiteID); err != nil {
+		return nil, err
+	}
+	return website, nil
+}
 
-	return &website, nil
+// GetWebsiteByHeartbeatToken retrieves the heartbeat website a ping URL
+// belongs to
+func (s *DatabaseService) GetWebsiteByHeartbeatToken(token string) (*models.Website, error) {
+	query := `
+		SELECT ` + websiteColumns + `
+		FROM uptime_websites
+		WHERE heartbeat_token = ?
+	`
+
+	return scanWebsite(s.db.QueryRow(query, token))
+}
+
+// RecordHeartbeat stores the time a heartbeat website last pinged
+func (s *DatabaseService) RecordHeartbeat(websiteID int, at time.Time) error {
+	_, err := s.db.Exec(`UPDATE uptime_websites SET last_ping_at = ? WHERE id = ?`, at, websiteID)
+	return err
+}
+
+// GetLastHeartbeat returns when a heartbeat website last pinged, or nil if
+// it never has
+func (s *DatabaseService) GetLastHeartbeat(websiteID int) (*time.Time, error) {
+	var lastPingAt sql.NullTime
+	err := s.db.QueryRow(`SELECT last_ping_at FROM uptime_websites WHERE id = ?`, websiteID).Scan(&lastPingAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lastPingAt.Valid {
+		return nil, nil
+	}
+	return &lastPingAt.Time, nil
 }
 
 // GetLastWebsiteStatus retrieves the most recent status for a website
 func (s *DatabaseService) GetLastWebsiteStatus(websiteID int) (*models.WebsiteStatus, error) {
 	query := `
-		SELECT id, website_id, status, response_time, status_code, error_message, checked_at
+		SELECT id, website_id, status, response_time, status_code, COALESCE(error_message, ''), maintenance, checked_at, ` + timingColumns + `
 		FROM uptime_checks
 		WHERE website_id = ?
 		ORDER BY checked_at DESC
@@ -98,16 +243,18 @@ func (s *DatabaseService) GetLastWebsiteStatus(websiteID int) (*models.WebsiteSt
 
 	var status models.WebsiteStatus
 	var checkedAt time.Time
+	var timing timingScanner
 
-	err := s.db.QueryRow(query, websiteID).Scan(
+	err := s.db.QueryRow(query, websiteID).Scan(append([]any{
 		&status.ID,
 		&status.WebsiteID,
 		&status.Status,
 		&status.ResponseTime,
 		&status.StatusCode,
 		&status.Error,
+		&status.Maintenance,
 		&checkedAt,
-	)
+	}, timing.dest()...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No status found
@@ -116,52 +263,47 @@ func (s *DatabaseService) GetLastWebsiteStatus(websiteID int) (*models.WebsiteSt
 	}
 
 	status.CheckedAt = checkedAt
+	status.Timing = timing.timing()
 	return &status, nil
 }
 
-// StoreUptimeCheck stores a new uptime check result
-func (s *DatabaseService) StoreUptimeCheck(websiteID int, statusCode int, responseTime int64, isUp bool, errorMsg string) error {
-	status := "down"
-	if isUp {
-		status = "up"
-	}
-
+// StoreUptimeCheck stores a new uptime check result, tagged as maintenance
+// when taken during a maintenance window. Timing is nil for checks that
+// aren't HTTP.
+func (s *DatabaseService) StoreUptimeCheck(websiteID int, statusCode int, responseTime int64, status string, errorMsg string, maintenance bool, timing *models.CheckTiming) error {
 	query := `
-		INSERT INTO uptime_checks (website_id, status, response_time, status_code, error_message, checked_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO uptime_checks (website_id, status, response_time, status_code, error_message, maintenance, checked_at, ` + timingColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := s.db.Exec(query, websiteID, status, responseTime, statusCode, errorMsg, time.Now())
+	_, err := s.db.Exec(query, append([]any{websiteID, status, responseTime, statusCode, errorMsg, maintenance, time.Now()}, timingValues(timing)...)...)
 	return err
 }
 
-// ShouldSendAlert checks if an alert should be sent (prevents spam)
-func (s *DatabaseService) ShouldSendAlert(websiteID int, alertType string) (bool, error) {
-	query := `
-		SELECT COUNT(*) 
-		FROM alert_history 
-		WHERE website_id = ? AND alert_type = ? AND sent_at > datetime('now', '-1 hour')
-	`
-
-	var count int
-	err := s.db.QueryRow(query, websiteID, alertType).Scan(&count)
+// GetRecentStatuses returns the statuses of a website's most recent checks,
+// newest first. Maintenance checks are skipped so the state confirmed before
+// a maintenance window carries over it.
+func (s *DatabaseService) GetRecentStatuses(websiteID int, limit int) ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT status FROM uptime_checks
+		WHERE website_id = ? AND maintenance = 0
+		ORDER BY checked_at DESC, id DESC
+		LIMIT ?
+	`, websiteID, limit)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Send alert if no alert was sent in the last hour
-	return count == 0, nil
-}
-
-// RecordAlertSent records that an alert was sent
-func (s *DatabaseService) RecordAlertSent(websiteID int, alertType string) error {
-	query := `
-		INSERT INTO alert_history (website_id, alert_type, sent_at)
-		VALUES (?, ?, ?)
-	`
-
-	_, err := s.db.Exec(query, websiteID, alertType, time.Now())
-	return err
+	var statuses []string
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
 }
 
 // CheckWebsite performs a manual check of a website
@@ -172,44 +314,44 @@ func (s *DatabaseService) CheckWebsite(website models.Website) error {
 	return err
 }
 
-// GetUptimePercentage calculates the uptime percentage for a given time period
+// GetUptimePercentage calculates the uptime percentage for a given time
+// period from the check rollups. Checks taken during maintenance don't count.
 func (s *DatabaseService) GetUptimePercentage(websiteID int, hours int) (float64, int, int, error) {
-	query := `
-		SELECT 
-			COUNT(*) as total_checks,
-			SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) as up_checks
-		FROM uptime_checks 
-		WHERE website_id = ? 
-		AND checked_at >= datetime('now', '-' || ? || ' hours')
-	`
-
-	var totalChecks, upChecks int
-	err := s.db.QueryRow(query, websiteID, hours).Scan(&totalChecks, &upChecks)
+	now := time.Now()
+	summary, err := s.getCheckSummary(websiteID, now.Add(-time.Duration(hours)*time.Hour), time.Time{}, now)
 	if err != nil {
 		return 0, 0, 0, err
 	}
 
-	if totalChecks == 0 {
-		return 100.0, 0, 0, nil // No checks means 100% uptime
-	}
-
-	percentage := float64(upChecks) / float64(totalChecks) * 100
-	return percentage, upChecks, totalChecks - upChecks, nil
+	upChecks := summary.UpChecks + summary.DegradedChecks
+	return summary.Percentage(), upChecks, summary.DownChecks, nil
 }
 
-// GetUptimeHistory returns uptime checks for a website with pagination
+// GetUptimeHistory returns a website's most recent checks, newest first
 func (s *DatabaseService) GetUptimeHistory(websiteID int, limit int) ([]models.WebsiteStatus, error) {
+	checks, _, err := s.GetCheckPage(websiteID, limit, 0)
+	return checks, err
+}
+
+// GetCheckPage returns a page of a website's checks, newest first, with the
+// number of checks the website has
+func (s *DatabaseService) GetCheckPage(websiteID int, limit, offset int) ([]models.WebsiteStatus, int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM uptime_checks WHERE website_id = ?`, websiteID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
 	query := `
-		SELECT id, website_id, status, response_time, status_code, error_message, checked_at
+		SELECT id, website_id, status, response_time, status_code, COALESCE(error_message, ''), maintenance, checked_at, ` + timingColumns + `
 		FROM uptime_checks
 		WHERE website_id = ?
-		ORDER BY checked_at DESC
-		LIMIT ?
+		ORDER BY checked_at DESC, id DESC
+		LIMIT ? OFFSET ?
 	`
 
-	rows, err := s.db.Query(query, websiteID, limit)
+	rows, err := s.db.Query(query, websiteID, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -217,272 +359,224 @@ func (s *DatabaseService) GetUptimeHistory(websiteID int, limit int) ([]models.W
 	for rows.Next() {
 		var status models.WebsiteStatus
 		var checkedAt time.Time
+		var timing timingScanner
 
-		err := rows.Scan(
+		err := rows.Scan(append([]any{
 			&status.ID,
 			&status.WebsiteID,
 			&status.Status,
 			&status.ResponseTime,
 			&status.StatusCode,
 			&status.Error,
+			&status.Maintenance,
 			&checkedAt,
-		)
+		}, timing.dest()...)...)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		status.CheckedAt = checkedAt
+		status.Timing = timing.timing()
 		statuses = append(statuses, status)
 	}
 
-	return statuses, nil
+	return statuses, total, rows.Err()
 }
 
-// GetIncidents returns incidents (downtime periods) for a website
-func (s *DatabaseService) GetIncidents(websiteID int, limit int) ([]models.Incident, error) {
-	query := `
-		WITH status_changes AS (
-			SELECT 
-				status,
-				checked_at,
-				LAG(status) OVER (ORDER BY checked_at) as prev_status,
-				LAG(checked_at) OVER (ORDER BY checked_at) as prev_checked_at
-			FROM uptime_checks
-			WHERE website_id = ?
-			ORDER BY checked_at
-		)
-		SELECT 
-			prev_checked_at as started_at,
-			checked_at as resolved_at,
-			status as final_status
-		FROM status_changes
-		WHERE prev_status = 'up' AND status = 'down'
-		ORDER BY prev_checked_at DESC
-		LIMIT ?
-	`
-
-	rows, err := s.db.Query(query, websiteID, limit)
+// GetAverageResponseTime calculates the average response time for a given
+// period from the check rollups
+func (s *DatabaseService) GetAverageResponseTime(websiteID int, hours int) (float64, error) {
+	now := time.Now()
+	summary, err := s.getCheckSummary(websiteID, now.Add(-time.Duration(hours)*time.Hour), time.Time{}, now)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	defer rows.Close()
-
-	var incidents []models.Incident
-	for rows.Next() {
-		var incident models.Incident
-		var startedAtStr, resolvedAtStr sql.NullString
-		var finalStatus string
-
-		err := rows.Scan(&startedAtStr, &resolvedAtStr, &finalStatus)
-		if err != nil {
-			return nil, err
-		}
-
-		// Parse started_at
-		if startedAtStr.Valid {
-			startedAt, err := parseTimeFlexible(startedAtStr.String)
-			if err != nil {
-				return nil, err
-			}
-			incident.StartedAt = startedAt
-		}
-
-		// Parse resolved_at
-		if resolvedAtStr.Valid {
-			resolvedAt, err := parseTimeFlexible(resolvedAtStr.String)
-			if err != nil {
-				return nil, err
-			}
-			incident.ResolvedAt = &resolvedAt
-		}
+	return summary.AvgResponseTime, nil
+}
 
-		incident.Status = finalStatus
-		incident.WebsiteID = websiteID
+// websiteWriteColumns lists the uptime_websites columns set from a website's
+// configuration, in websiteValues order
+const websiteWriteColumns = `name, url, check_interval, assertions,
+	request_method, request_headers, request_body, auth_type, auth_username, auth_secret,
+	user_agent, follow_redirects, check_type, dns_record_type, dns_expected,
+	heartbeat_token, grace_period,
+	retries, retry_delay, failure_threshold, recovery_threshold,
+	renotify_interval, escalation_channel_id, escalate_after,
+	quiet_hours_start, quiet_hours_end, quiet_hours_timezone,
+	latency_threshold, latency_deviation, content_options`
+
+// websiteValues returns the values of websiteWriteColumns for a website
+func websiteValues(website models.Website) ([]any, error) {
+	assertions, err := json.Marshal(website.Assertions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode assertions: %w", err)
+	}
 
-		// Calculate duration
-		if incident.ResolvedAt != nil {
-			incident.Duration = incident.ResolvedAt.Sub(incident.StartedAt)
-		} else {
-			incident.Duration = time.Since(incident.StartedAt)
-		}
+	requestHeaders, err := json.Marshal(website.Request.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request headers: %w", err)
+	}
 
-		incidents = append(incidents, incident)
+	contentOptions, err := json.Marshal(website.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode content options: %w", err)
 	}
 
-	return incidents, nil
+	return []any{
+		website.Name,
+		website.URL,
+		website.CheckInterval,
+		string(assertions),
+		website.Request.RequestMethod(),
+		string(requestHeaders),
+		website.Request.Body,
+		website.Request.AuthType,
+		website.Request.AuthUsername,
+		website.Request.AuthSecret,
+		website.Request.UserAgent,
+		website.Request.FollowRedirects,
+		website.Type(),
+		website.DNS.RecordType,
+		website.DNS.Expected,
+		sql.NullString{String: website.HeartbeatToken, Valid: website.HeartbeatToken != ""},
+		website.GracePeriod,
+		website.Confirmation.Retries,
+		website.Confirmation.RetryDelay,
+		website.Confirmation.Threshold(true),
+		website.Confirmation.Threshold(false),
+		website.Alerts.RenotifyInterval,
+		sql.NullInt64{Int64: int64(website.Alerts.EscalationChannelID), Valid: website.Alerts.EscalationChannelID > 0},
+		website.Alerts.EscalateAfter,
+		nullString(website.Alerts.QuietHoursStart),
+		nullString(website.Alerts.QuietHoursEnd),
+		nullString(website.Alerts.QuietHoursTimezone),
+		website.Alerts.LatencyThreshold,
+		website.Alerts.LatencyDeviation,
+		string(contentOptions),
+	}, nil
 }
 
-// GetAverageResponseTime calculates the average response time for a given period
-func (s *DatabaseService) GetAverageResponseTime(websiteID int, hours int) (float64, error) {
+// CreateWebsite adds a new website to monitor, returning its ID
+func (s *DatabaseService) CreateWebsite(website models.Website) (int, error) {
+	values, err := websiteValues(website)
+	if err != nil {
+		return 0, err
+	}
+
 	query := `
-		SELECT AVG(response_time)
-		FROM uptime_checks 
-		WHERE website_id = ? 
-		AND checked_at >= datetime('now', '-' || ? || ' hours')
-		AND status = 'up'
+		INSERT INTO uptime_websites (created_at, updated_at, ` + websiteWriteColumns + `)
+		VALUES (?, ?` + strings.Repeat(", ?", len(values)) + `)
 	`
 
-	var avgResponseTime sql.NullFloat64
-	err := s.db.QueryRow(query, websiteID, hours).Scan(&avgResponseTime)
+	tx, err := s.db.Begin()
 	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
 
-	if avgResponseTime.Valid {
-		return avgResponseTime.Float64, nil
+	now := time.Now()
+	result, err := tx.Exec(query, append([]any{now, now}, values...)...)
+	if err != nil {
+		return 0, err
 	}
-	return 0, nil
-}
 
-// GetWebsiteDetailData retrieves all data needed for the detailed website view
-func (s *DatabaseService) GetWebsiteDetailData(websiteID int) (*models.WebsiteDetailData, error) {
-	// Get website
-	website, err := s.GetWebsiteByID(websiteID)
+	websiteID, err := result.LastInsertId()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	if err := setWebsiteChannels(tx, int(websiteID), website.ChannelIDs); err != nil {
+		return 0, err
+	}
+	if err := setWebsiteTags(tx, int(websiteID), website.Tags); err != nil {
+		return 0, err
+	}
+
+	return int(websiteID), tx.Commit()
+}
 
-	// Get last status
-	lastStatus, err := s.GetLastWebsiteStatus(websiteID)
+// UpdateWebsite replaces a website's configuration and channels, returning
+// sql.ErrNoRows for unknown websites. Check history is kept.
+func (s *DatabaseService) UpdateWebsite(website models.Website) error {
+	values, err := websiteValues(website)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Get uptime stats for different periods
-	uptimeStats, err := s.getUptimeStats(websiteID)
-	if err != nil {
-		return nil, err
+	var assignments []string
+	for _, column := range strings.Split(websiteWriteColumns, ",") {
+		assignments = append(assignments, strings.TrimSpace(column)+" = ?")
 	}
+	query := `UPDATE uptime_websites SET ` + strings.Join(assignments, ", ") + `, updated_at = ? WHERE id = ?`
 
-	// Get incidents
-	incidents, err := s.GetIncidents(websiteID, 10)
+	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer tx.Rollback()
 
-	// Get average response time
-	avgResponse, err := s.GetAverageResponseTime(websiteID, 24*30) // 30 days
+	result, err := tx.Exec(query, append(values, time.Now(), website.ID)...)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	return &models.WebsiteDetailData{
-		Website:     *website,
-		LastStatus:  lastStatus,
-		UptimeStats: uptimeStats,
-		Incidents:   incidents,
-		AvgResponse: avgResponse,
-	}, nil
-}
-
-// getUptimeStats calculates uptime statistics for different time periods
-func (s *DatabaseService) getUptimeStats(websiteID int) ([]models.UptimeStats, error) {
-	periods := []struct {
-		hours int
-		label string
-	}{
-		{24, "24h"},
-		{24 * 7, "7d"},
-		{24 * 30, "30d"},
-		{24 * 365, "365d"},
-	}
-
-	var stats []models.UptimeStats
-	for _, period := range periods {
-		percentage, upChecks, downChecks, err := s.GetUptimePercentage(websiteID, period.hours)
-		if err != nil {
-			return nil, err
-		}
-
-		// Get incident count for this period
-		incidents, err := s.GetIncidents(websiteID, 100) // Get more incidents to count
-		if err != nil {
-			return nil, err
-		}
-
-		// Count incidents in this period
-		incidentCount := 0
-		var totalDowntime time.Duration
-		for _, incident := range incidents {
-			if time.Since(incident.StartedAt) <= time.Duration(period.hours)*time.Hour {
-				incidentCount++
-				totalDowntime += incident.Duration
-			}
-		}
-
-		stats = append(stats, models.UptimeStats{
-			WebsiteID:     websiteID,
-			Period:        period.label,
-			Percentage:    percentage,
-			UpChecks:      upChecks,
-			DownChecks:    downChecks,
-			TotalChecks:   upChecks + downChecks,
-			IncidentCount: incidentCount,
-			Downtime:      formatDuration(totalDowntime),
-		})
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := setWebsiteChannels(tx, website.ID, website.ChannelIDs); err != nil {
+		return err
+	}
+	if err := setWebsiteTags(tx, website.ID, website.Tags); err != nil {
+		return err
 	}
 
-	return stats, nil
+	return tx.Commit()
 }
 
-// formatDuration formats a duration for display
-func formatDuration(d time.Duration) string {
-	if d < time.Minute {
-		return fmt.Sprintf("%.0fs", d.Seconds())
-	} else if d < time.Hour {
-		return fmt.Sprintf("%.0fm", d.Minutes())
-	} else if d < 24*time.Hour {
-		return fmt.Sprintf("%.0fh", d.Hours())
+// DeleteWebsite removes a website from monitoring, returning sql.ErrNoRows
+// for unknown websites
+func (s *DatabaseService) DeleteWebsite(websiteID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
 	}
-	return fmt.Sprintf("%.0fd", d.Hours()/24)
-}
-
-// CreateWebsite adds a new website to monitor
-func (s *DatabaseService) CreateWebsite(website models.Website) error {
-	query := `
-		INSERT INTO uptime_websites (name, url, check_interval, created_at)
-		VALUES (?, ?, ?, ?)
-	`
+	defer tx.Rollback()
 
-	_, err := s.db.Exec(query, website.Name, website.URL, website.CheckInterval, time.Now())
-	return err
+	var exists int
+	if err := tx.QueryRow(`SELECT 1 FROM uptime_websites WHERE id = ?`, websiteID).Scan(&exists); err != nil {
+		return err
+	}
+	if err := deleteWebsites(tx, []int{websiteID}); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// DeleteWebsite removes a website from monitoring
-func (s *DatabaseService) DeleteWebsite(websiteID int) error {
+// deleteWebsites removes websites with everything recorded about them, and
+// the tags no website has any more
+func deleteWebsites(tx *sql.Tx, websiteIDs []int) error {
 	// Delete related records first (due to foreign key constraints)
 	queries := []string{
 		"DELETE FROM alert_history WHERE website_id = ?",
+		"DELETE FROM uptime_incident_events WHERE incident_id IN (SELECT id FROM uptime_incidents WHERE website_id = ?)",
+		"DELETE FROM uptime_incidents WHERE website_id = ?",
+		"DELETE FROM uptime_website_channels WHERE website_id = ?",
+		"DELETE FROM uptime_website_tags WHERE website_id = ?",
+		"DELETE FROM uptime_probe_results WHERE website_id = ?",
+		"DELETE FROM uptime_content_snapshots WHERE website_id = ?",
+		"DELETE FROM uptime_maintenance_windows WHERE website_id = ?",
+		"DELETE FROM uptime_status_component_websites WHERE website_id = ?",
+		"DELETE FROM uptime_check_rollups WHERE website_id = ?",
 		"DELETE FROM uptime_checks WHERE website_id = ?",
 		"DELETE FROM uptime_websites WHERE id = ?",
 	}
 
-	for _, query := range queries {
-		_, err := s.db.Exec(query, websiteID)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// parseTimeFlexible tries to parse a datetime string using multiple common formats
-func parseTimeFlexible(timeStr string) (time.Time, error) {
-	formats := []string{
-		time.RFC3339Nano,                      // 2025-08-03T18:04:25.926402+01:00
-		"2006-01-02T15:04:05.999999999",       // 2025-08-03T18:04:25.926402
-		"2006-01-02 15:04:05.999999999-07:00", // 2025-08-03 17:46:37.91092+01:00
-		"2006-01-02 15:04:05.999999999",       // 2025-08-03 17:46:37.91092
-		"2006-01-02 15:04:05",                 // 2025-08-03 17:46:37
-		time.RFC3339,                          // 2025-08-03T18:04:25+01:00
-	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, timeStr); err == nil {
-			return t, nil
+	for _, websiteID := range websiteIDs {
+		for _, query := range queries {
+			if _, err := tx.Exec(query, websiteID); err != nil {
+				return err
+			}
 		}
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse time string: %s", timeStr)
+	_, err := tx.Exec(deleteUnusedTags)
+	return err
 }
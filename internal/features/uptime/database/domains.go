@@ -0,0 +1,142 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// domainColumns are the columns scanDomain reads, in order
+const domainColumns = `id, name, registrar, expires_at, checked_at, error_message`
+
+// GetDomains retrieves every tracked domain by name
+func (s *DatabaseService) GetDomains() ([]models.Domain, error) {
+	rows, err := s.db.Query(`SELECT ` + domainColumns + ` FROM uptime_domains ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domains []models.Domain
+	for rows.Next() {
+		domain, err := scanDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, *domain)
+	}
+	return domains, rows.Err()
+}
+
+// GetDomain retrieves a tracked domain by name, returning sql.ErrNoRows for
+// domains that haven't been looked up
+func (s *DatabaseService) GetDomain(name string) (*models.Domain, error) {
+	row := s.db.QueryRow(`SELECT `+domainColumns+` FROM uptime_domains WHERE name = ?`, name)
+	return scanDomain(row)
+}
+
+// SaveDomain stores the outcome of a domain lookup at its CheckedAt time.
+// A successful lookup replaces the registrar and expiry and clears the
+// error, while a failed one only records its Error so the registration
+// from before the failure is kept. The stored domain is returned.
+func (s *DatabaseService) SaveDomain(domain models.Domain) (*models.Domain, error) {
+	checkedAt := time.Now()
+	if domain.CheckedAt != nil {
+		checkedAt = *domain.CheckedAt
+	}
+
+	var err error
+	if domain.Error != "" {
+		_, err = s.db.Exec(`
+			INSERT INTO uptime_domains (name, checked_at, error_message) VALUES (?, ?, ?)
+			ON CONFLICT(name) DO UPDATE SET checked_at = excluded.checked_at, error_message = excluded.error_message
+		`, domain.Name, checkedAt.Local(), domain.Error)
+	} else {
+		var expiresAt any
+		if domain.ExpiresAt != nil {
+			expiresAt = domain.ExpiresAt.Local()
+		}
+		_, err = s.db.Exec(`
+			INSERT INTO uptime_domains (name, registrar, expires_at, checked_at, error_message) VALUES (?, ?, ?, ?, '')
+			ON CONFLICT(name) DO UPDATE SET
+				registrar = excluded.registrar,
+				expires_at = excluded.expires_at,
+				checked_at = excluded.checked_at,
+				error_message = ''
+		`, domain.Name, domain.Registrar, expiresAt, checkedAt.Local())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDomain(domain.Name)
+}
+
+// DeleteDomainsExcept removes the domains not among names, which no website
+// is on any more, with their expiry warnings. It returns how many domains
+// were removed.
+func (s *DatabaseService) DeleteDomainsExcept(names []string) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	where := `1 = 1`
+	args := make([]any, len(names))
+	if len(names) > 0 {
+		where = `name NOT IN (?` + strings.Repeat(`, ?`, len(names)-1) + `)`
+		for i, name := range names {
+			args[i] = name
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM uptime_domain_alerts WHERE domain_id IN (SELECT id FROM uptime_domains WHERE `+where+`)`, args...); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(`DELETE FROM uptime_domains WHERE `+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return deleted, tx.Commit()
+}
+
+// HasDomainAlert reports whether the warning for a threshold has been sent
+// for a domain's registration expiring on expiresOn
+func (s *DatabaseService) HasDomainAlert(domainID int, expiresOn string, threshold int) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM uptime_domain_alerts WHERE domain_id = ? AND expires_on = ? AND threshold = ?
+		)
+	`, domainID, expiresOn, threshold).Scan(&exists)
+	return exists, err
+}
+
+// RecordDomainAlert records that the warning for a threshold was sent for a
+// domain's registration expiring on expiresOn
+func (s *DatabaseService) RecordDomainAlert(domainID int, expiresOn string, threshold int, at time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO uptime_domain_alerts (domain_id, expires_on, threshold, sent_at) VALUES (?, ?, ?, ?)
+	`, domainID, expiresOn, threshold, at.Local())
+	return err
+}
+
+func scanDomain(row rowScanner) (*models.Domain, error) {
+	var domain models.Domain
+	var expiresAt, checkedAt sql.NullTime
+	if err := row.Scan(&domain.ID, &domain.Name, &domain.Registrar, &expiresAt, &checkedAt, &domain.Error); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		domain.ExpiresAt = &expiresAt.Time
+	}
+	if checkedAt.Valid {
+		domain.CheckedAt = &checkedAt.Time
+	}
+	return &domain, nil
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestDomains(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+
+	if _, err := s.GetDomain("example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected no domain yet, got %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	expiresAt := now.Add(20 * 24 * time.Hour)
+	domain, err := s.SaveDomain(models.Domain{Name: "example.com", Registrar: "Example Registrar", ExpiresAt: &expiresAt, CheckedAt: &now})
+	if err != nil {
+		t.Fatalf("Failed to save domain: %v", err)
+	}
+	if domain.Registrar != "Example Registrar" || domain.ExpiresAt == nil || !domain.ExpiresAt.Equal(expiresAt) || domain.Error != "" {
+		t.Fatalf("Unexpected domain %+v", domain)
+	}
+
+	// A failed lookup keeps the registration from before it
+	later := now.Add(24 * time.Hour)
+	failed, err := s.SaveDomain(models.Domain{Name: "example.com", Error: "RDAP lookup failed: HTTP 503", CheckedAt: &later})
+	if err != nil {
+		t.Fatalf("Failed to save lookup error: %v", err)
+	}
+	if failed.ID != domain.ID || failed.Error == "" || failed.ExpiresAt == nil || !failed.CheckedAt.Equal(later) {
+		t.Errorf("Expected the error recorded alongside the registration, got %+v", failed)
+	}
+
+	// The next successful lookup clears the error
+	renewed := expiresAt.AddDate(1, 0, 0)
+	if domain, err = s.SaveDomain(models.Domain{Name: "example.com", Registrar: "Example Registrar", ExpiresAt: &renewed, CheckedAt: &later}); err != nil {
+		t.Fatalf("Failed to save domain: %v", err)
+	}
+	if domain.Error != "" || !domain.ExpiresAt.Equal(renewed) {
+		t.Errorf("Expected the renewed registration without an error, got %+v", domain)
+	}
+
+	if _, err := s.SaveDomain(models.Domain{Name: "example.org", Error: "domain not found in RDAP"}); err != nil {
+		t.Fatalf("Failed to save domain: %v", err)
+	}
+
+	sent, err := s.HasDomainAlert(domain.ID, "2026-10-21", 30)
+	if err != nil || sent {
+		t.Fatalf("Expected no alert sent yet, got %v, %v", sent, err)
+	}
+	for range 2 {
+		if err := s.RecordDomainAlert(domain.ID, "2026-10-21", 30, now); err != nil {
+			t.Fatalf("Failed to record alert: %v", err)
+		}
+	}
+	if sent, err := s.HasDomainAlert(domain.ID, "2026-10-21", 30); err != nil || !sent {
+		t.Errorf("Expected the alert recorded, got %v, %v", sent, err)
+	}
+	if sent, err := s.HasDomainAlert(domain.ID, "2027-10-21", 30); err != nil || sent {
+		t.Errorf("Expected a renewed registration to be warned about again, got %v, %v", sent, err)
+	}
+
+	deleted, err := s.DeleteDomainsExcept([]string{"example.org"})
+	if err != nil || deleted != 1 {
+		t.Fatalf("Expected example.com deleted, got %d, %v", deleted, err)
+	}
+	domains, err := s.GetDomains()
+	if err != nil || len(domains) != 1 || domains[0].Name != "example.org" {
+		t.Fatalf("Expected only example.org left, got %+v, %v", domains, err)
+	}
+	if sent, err := s.HasDomainAlert(domain.ID, "2026-10-21", 30); err != nil || sent {
+		t.Errorf("Expected the deleted domain's alerts removed, got %v, %v", sent, err)
+	}
+
+	if deleted, err := s.DeleteDomainsExcept(nil); err != nil || deleted != 1 {
+		t.Errorf("Expected every domain deleted, got %d, %v", deleted, err)
+	}
+}
@@ -0,0 +1,328 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// incidentColumns lists the uptime_incidents columns read by scanIncident
+const incidentColumns = `id, website_id, status, cause, root_cause, started_at,
+	acknowledged_at, acknowledged_by, resolved_at`
+
+// scanIncident scans a row selected with incidentColumns
+func scanIncident(row rowScanner) (*models.Incident, error) {
+	var incident models.Incident
+	var cause, rootCause, acknowledgedBy sql.NullString
+	var acknowledgedAt, resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&incident.ID,
+		&incident.WebsiteID,
+		&incident.Status,
+		&cause,
+		&rootCause,
+		&incident.StartedAt,
+		&acknowledgedAt,
+		&acknowledgedBy,
+		&resolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	incident.Cause = cause.String
+	incident.RootCause = rootCause.String
+	incident.AcknowledgedBy = acknowledgedBy.String
+	if acknowledgedAt.Valid {
+		incident.AcknowledgedAt = &acknowledgedAt.Time
+	}
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+		incident.Duration = resolvedAt.Time.Sub(incident.StartedAt)
+	} else {
+		incident.Duration = time.Since(incident.StartedAt)
+	}
+
+	return &incident, nil
+}
+
+// OpenIncident opens an incident for a website that has gone down. It does
+// nothing if the website already has an unresolved incident.
+func (s *DatabaseService) OpenIncident(websiteID int, cause string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var open int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM uptime_incidents WHERE website_id = ? AND resolved_at IS NULL`, websiteID).Scan(&open)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		INSERT INTO uptime_incidents (website_id, status, cause, started_at)
+		VALUES (?, ?, ?, ?)
+	`, websiteID, models.IncidentOpen, cause, now)
+	if err != nil {
+		return err
+	}
+
+	incidentID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if err := insertIncidentEvent(tx, int(incidentID), models.IncidentEventOpened, cause, "", now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResolveIncident resolves a website's unresolved incident, if it has one
+func (s *DatabaseService) ResolveIncident(websiteID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var incidentID int
+	err = tx.QueryRow(`
+		SELECT id FROM uptime_incidents
+		WHERE website_id = ? AND resolved_at IS NULL
+		ORDER BY started_at DESC
+		LIMIT 1
+	`, websiteID).Scan(&incidentID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`UPDATE uptime_incidents SET status = ?, resolved_at = ? WHERE id = ?`, models.IncidentResolved, now, incidentID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertIncidentEvent(tx, incidentID, models.IncidentEventResolved, "", "", now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetIncidents returns a website's most recent incidents, newest first.
+// Timelines are not loaded.
+func (s *DatabaseService) GetIncidents(websiteID int, limit int) ([]models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM uptime_incidents
+		WHERE website_id = ?
+		ORDER BY started_at DESC
+		LIMIT ?
+	`
+
+	rows, err := s.db.Query(query, websiteID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []models.Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, *incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+// GetIncidentPage returns a page of a website's incidents, newest first,
+// with the number of incidents the website has. Timelines are not loaded.
+func (s *DatabaseService) GetIncidentPage(websiteID int, limit, offset int) ([]models.Incident, int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM uptime_incidents WHERE website_id = ?`, websiteID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM uptime_incidents
+		WHERE website_id = ?
+		ORDER BY started_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := s.db.Query(query, websiteID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var incidents []models.Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		incidents = append(incidents, *incident)
+	}
+
+	return incidents, total, rows.Err()
+}
+
+// GetLatestIncident retrieves a website's most recent incident, or nil if
+// it has never had one
+func (s *DatabaseService) GetLatestIncident(websiteID int) (*models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM uptime_incidents
+		WHERE website_id = ?
+		ORDER BY started_at DESC, id DESC
+		LIMIT 1
+	`
+
+	incident, err := scanIncident(s.db.QueryRow(query, websiteID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return incident, err
+}
+
+// GetIncident retrieves a single incident with its timeline
+func (s *DatabaseService) GetIncident(incidentID int) (*models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM uptime_incidents
+		WHERE id = ?
+	`
+
+	incident, err := scanIncident(s.db.QueryRow(query, incidentID))
+	if err != nil {
+		return nil, err
+	}
+
+	incident.Timeline, err = s.GetIncidentTimeline(incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// GetIncidentTimeline returns an incident's events, oldest first
+func (s *DatabaseService) GetIncidentTimeline(incidentID int) ([]models.IncidentEvent, error) {
+	query := `
+		SELECT id, incident_id, event_type, message, author, created_at
+		FROM uptime_incident_events
+		WHERE incident_id = ?
+		ORDER BY created_at, id
+	`
+
+	rows, err := s.db.Query(query, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.IncidentEvent
+	for rows.Next() {
+		var event models.IncidentEvent
+		var message, author sql.NullString
+		if err := rows.Scan(&event.ID, &event.IncidentID, &event.Type, &message, &author, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.Message = message.String
+		event.Author = author.String
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// AcknowledgeIncident marks an unresolved incident as being worked on
+func (s *DatabaseService) AcknowledgeIncident(incidentID int, author string) error {
+	return s.updateIncident(incidentID, func(tx *sql.Tx, incident *models.Incident, now time.Time) error {
+		if incident.IsResolved() || incident.AcknowledgedAt != nil {
+			return nil
+		}
+
+		_, err := tx.Exec(`
+			UPDATE uptime_incidents SET status = ?, acknowledged_at = ?, acknowledged_by = ? WHERE id = ?
+		`, models.IncidentAcknowledged, now, author, incidentID)
+		if err != nil {
+			return err
+		}
+
+		return insertIncidentEvent(tx, incidentID, models.IncidentEventAcknowledged, "", author, now)
+	})
+}
+
+// SetIncidentRootCause records what caused an incident
+func (s *DatabaseService) SetIncidentRootCause(incidentID int, rootCause, author string) error {
+	return s.updateIncident(incidentID, func(tx *sql.Tx, incident *models.Incident, now time.Time) error {
+		if _, err := tx.Exec(`UPDATE uptime_incidents SET root_cause = ? WHERE id = ?`, rootCause, incidentID); err != nil {
+			return err
+		}
+
+		return insertIncidentEvent(tx, incidentID, models.IncidentEventRootCause, rootCause, author, now)
+	})
+}
+
+// AddIncidentComment adds a comment to an incident's timeline
+func (s *DatabaseService) AddIncidentComment(incidentID int, message, author string) error {
+	return s.updateIncident(incidentID, func(tx *sql.Tx, incident *models.Incident, now time.Time) error {
+		return insertIncidentEvent(tx, incidentID, models.IncidentEventComment, message, author, now)
+	})
+}
+
+// updateIncident runs update in a transaction with the current incident,
+// returning sql.ErrNoRows if the incident doesn't exist
+func (s *DatabaseService) updateIncident(incidentID int, update func(tx *sql.Tx, incident *models.Incident, now time.Time) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	incident, err := scanIncident(tx.QueryRow(`SELECT `+incidentColumns+` FROM uptime_incidents WHERE id = ?`, incidentID))
+	if err != nil {
+		return err
+	}
+
+	if err := update(tx, incident, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertIncidentEvent(tx *sql.Tx, incidentID int, eventType, message, author string, at time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO uptime_incident_events (incident_id, event_type, message, author, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, incidentID, eventType, nullString(message), nullString(author), at)
+	if err != nil {
+		return fmt.Errorf("failed to record incident event: %w", err)
+	}
+	return nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"the-ark/internal/core"
+	"the-ark/internal/features/uptime/migrations"
+	"the-ark/internal/features/uptime/models"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// newTestDatabase returns a migrated in-memory database
+func newTestDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	manager := migrations.NewManager(core.NewDatabase(db, core.NewLogger()), core.NewLogger())
+	if err := manager.Migrate(context.Background()); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+	return db
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	if err := s.OpenIncident(1, "connection refused"); err != nil {
+		t.Fatalf("Failed to open incident: %v", err)
+	}
+	// Opening again while unresolved is a no-op
+	if err := s.OpenIncident(1, "timeout"); err != nil {
+		t.Fatalf("Failed to open incident: %v", err)
+	}
+
+	incidents, err := s.GetIncidents(1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get incidents: %v", err)
+	}
+	if len(incidents) != 1 || incidents[0].Status != models.IncidentOpen || incidents[0].Cause != "connection refused" {
+		t.Fatalf("Expected one open incident, got %+v", incidents)
+	}
+	incidentID := incidents[0].ID
+
+	if err := s.AcknowledgeIncident(incidentID, "Alex"); err != nil {
+		t.Fatalf("Failed to acknowledge incident: %v", err)
+	}
+	if err := s.AddIncidentComment(incidentID, "Looking into it", "Alex"); err != nil {
+		t.Fatalf("Failed to comment: %v", err)
+	}
+	if err := s.SetIncidentRootCause(incidentID, "Expired database password", "Alex"); err != nil {
+		t.Fatalf("Failed to set root cause: %v", err)
+	}
+	if err := s.ResolveIncident(1); err != nil {
+		t.Fatalf("Failed to resolve incident: %v", err)
+	}
+
+	incident, err := s.GetIncident(incidentID)
+	if err != nil {
+		t.Fatalf("Failed to get incident: %v", err)
+	}
+	if incident.Status != models.IncidentResolved || !incident.IsResolved() || incident.AcknowledgedBy != "Alex" {
+		t.Errorf("Expected resolved incident acknowledged by Alex, got %+v", incident)
+	}
+	if incident.RootCause != "Expired database password" {
+		t.Errorf("Expected root cause to be stored, got %q", incident.RootCause)
+	}
+
+	var types []string
+	for _, event := range incident.Timeline {
+		types = append(types, event.Type)
+	}
+	want := []string{
+		models.IncidentEventOpened,
+		models.IncidentEventAcknowledged,
+		models.IncidentEventComment,
+		models.IncidentEventRootCause,
+		models.IncidentEventResolved,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("Expected timeline %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("Expected timeline %v, got %v", want, types)
+		}
+	}
+
+	if err := s.AddIncidentComment(999, "missing", "Alex"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing incident, got %v", err)
+	}
+}
+
+func TestIncidentBackfill(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	coreDB := core.NewDatabase(db, core.NewLogger())
+	manager := migrations.NewManager(coreDB, core.NewLogger())
+	service := core.NewMigrationService(coreDB, core.NewLogger())
+	ctx := context.Background()
+
+	// Apply everything before the incidents table, then record history
+	if err := service.InitMigrations(ctx); err != nil {
+		t.Fatalf("Failed to init migrations: %v", err)
+	}
+	for _, migration := range manager.Migrations() {
+		if migration.Version >= migrations.Migration106CreateIncidents.Version {
+			break
+		}
+		if err := service.ApplyMigration(ctx, migration); err != nil {
+			t.Fatalf("Failed to apply migration %d: %v", migration.Version, err)
+		}
+	}
+
+	// Later migrations add columns CreateWebsite writes, so insert directly
+	if _, err := db.Exec(`INSERT INTO uptime_websites (name, url) VALUES ('Example', 'https://example.com')`); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	history := []string{"up", "down", "down", "up", "up", "down"}
+	for i, status := range history {
+		_, err := db.Exec(`INSERT INTO uptime_checks (website_id, status, error_message, checked_at) VALUES (1, ?, ?, ?)`,
+			status, "check "+status, start.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatalf("Failed to insert check: %v", err)
+		}
+	}
+
+	if err := manager.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	s := NewDatabaseService(db)
+	incidents, err := s.GetIncidents(1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get incidents: %v", err)
+	}
+	if len(incidents) != 2 {
+		t.Fatalf("Expected two backfilled incidents, got %+v", incidents)
+	}
+
+	// Newest first: the trailing down check is still ongoing
+	if incidents[0].IsResolved() || incidents[0].Status != models.IncidentOpen {
+		t.Errorf("Expected the latest incident to be open, got %+v", incidents[0])
+	}
+	resolved := incidents[1]
+	if !resolved.IsResolved() || resolved.Duration != 2*time.Minute {
+		t.Errorf("Expected a resolved incident lasting 2m, got %+v", resolved)
+	}
+	if resolved.Cause != "check down" {
+		t.Errorf("Expected cause from the first down check, got %q", resolved.Cause)
+	}
+
+	timeline, err := s.GetIncidentTimeline(resolved.ID)
+	if err != nil {
+		t.Fatalf("Failed to get timeline: %v", err)
+	}
+	if len(timeline) != 2 || timeline[0].Type != models.IncidentEventOpened || timeline[1].Type != models.IncidentEventResolved {
+		t.Errorf("Expected opened and resolved events, got %+v", timeline)
+	}
+}
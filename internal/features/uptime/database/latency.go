@@ -0,0 +1,64 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GetLatencyBaseline returns a website's median response time since a
+// time, along with the number of samples. Only checks that were up are
+// sampled, so degraded checks don't drag the baseline up while a website
+// is slow.
+func (s *DatabaseService) GetLatencyBaseline(websiteID int, since time.Time) (int64, int, error) {
+	const sampled = `
+		FROM uptime_checks
+		WHERE website_id = ?
+		AND status = 'up'
+		AND maintenance = 0
+		AND checked_at >= ?
+	`
+
+	var samples int
+	if err := s.db.QueryRow(`SELECT COUNT(*) `+sampled, websiteID, since.Local()).Scan(&samples); err != nil {
+		return 0, 0, err
+	}
+	if samples == 0 {
+		return 0, 0, nil
+	}
+
+	var median int64
+	err := s.db.QueryRow(
+		`SELECT response_time `+sampled+` ORDER BY response_time LIMIT 1 OFFSET ?`,
+		websiteID, since.Local(), (samples-1)/2,
+	).Scan(&median)
+	if err != nil {
+		return 0, 0, err
+	}
+	return median, samples, nil
+}
+
+// GetDegradedSince returns since when a website has been breaching its
+// latency limits, or nil if it isn't
+func (s *DatabaseService) GetDegradedSince(websiteID int) (*time.Time, error) {
+	var degradedSince sql.NullTime
+	err := s.db.QueryRow(`SELECT degraded_since FROM uptime_websites WHERE id = ?`, websiteID).Scan(&degradedSince)
+	if err != nil {
+		return nil, err
+	}
+
+	if !degradedSince.Valid {
+		return nil, nil
+	}
+	return &degradedSince.Time, nil
+}
+
+// SetDegradedSince records since when a website has been breaching its
+// latency limits. A nil time clears it.
+func (s *DatabaseService) SetDegradedSince(websiteID int, since *time.Time) error {
+	var value sql.NullTime
+	if since != nil {
+		value = sql.NullTime{Time: *since, Valid: true}
+	}
+	_, err := s.db.Exec(`UPDATE uptime_websites SET degraded_since = ? WHERE id = ?`, value, websiteID)
+	return err
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestLatencyBaselineAndDegradedState(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	website := models.Website{
+		Name:   "Example",
+		URL:    "https://example.com",
+		Alerts: models.AlertPolicy{LatencyThreshold: 1500, LatencyDeviation: 200},
+	}
+	if _, err := s.CreateWebsite(website); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	stored, err := s.GetWebsiteByID(1)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if stored.Alerts.LatencyThreshold != 1500 || stored.Alerts.LatencyDeviation != 200 || stored.DegradedSince != nil {
+		t.Errorf("Unexpected latency policy %+v, degraded since %v", stored.Alerts, stored.DegradedSince)
+	}
+
+	now := time.Now()
+	insertCheck(t, db, 1, models.StatusUp, 200, 100, now.Add(-3*time.Minute))
+	insertCheck(t, db, 1, models.StatusUp, 200, 300, now.Add(-2*time.Minute))
+	insertCheck(t, db, 1, models.StatusUp, 200, 200, now.Add(-time.Minute))
+	// Degraded and old checks aren't part of the baseline
+	insertCheck(t, db, 1, models.StatusDegraded, 200, 9000, now)
+	insertCheck(t, db, 1, models.StatusUp, 200, 5000, now.Add(-48*time.Hour))
+
+	median, samples, err := s.GetLatencyBaseline(1, now.Add(-models.LatencyBaselineWindow))
+	if err != nil {
+		t.Fatalf("Failed to get baseline: %v", err)
+	}
+	if median != 200 || samples != 3 {
+		t.Errorf("Expected a 200 ms median of 3 samples, got %d of %d", median, samples)
+	}
+
+	if err := s.SetDegradedSince(1, &now); err != nil {
+		t.Fatalf("Failed to set degraded state: %v", err)
+	}
+	if since, err := s.GetDegradedSince(1); err != nil || since == nil || !since.Equal(now) {
+		t.Errorf("Expected the website degraded since %v, got %v, %v", now, since, err)
+	}
+	if err := s.SetDegradedSince(1, nil); err != nil {
+		t.Fatalf("Failed to clear degraded state: %v", err)
+	}
+	if since, err := s.GetDegradedSince(1); err != nil || since != nil {
+		t.Errorf("Expected the degraded state cleared, got %v, %v", since, err)
+	}
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"database/sql"
+	"the-ark/internal/features/uptime/models"
+)
+
+// maintenanceColumns lists the uptime_maintenance_windows columns read by
+// scanMaintenanceWindow
+const maintenanceColumns = `id, website_id, name, starts_at, ends_at, schedule, duration,
+	timezone, created_at`
+
+// scanMaintenanceWindow scans a row selected with maintenanceColumns
+func scanMaintenanceWindow(row rowScanner) (*models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	var websiteID sql.NullInt64
+	var startsAt, endsAt sql.NullTime
+	var schedule, timezone sql.NullString
+
+	err := row.Scan(
+		&window.ID,
+		&websiteID,
+		&window.Name,
+		&startsAt,
+		&endsAt,
+		&schedule,
+		&window.Duration,
+		&timezone,
+		&window.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	window.WebsiteID = int(websiteID.Int64)
+	if startsAt.Valid {
+		window.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		window.EndsAt = &endsAt.Time
+	}
+	window.Schedule = schedule.String
+	window.Timezone = timezone.String
+	return &window, nil
+}
+
+// queryMaintenanceWindows runs a query selecting maintenanceColumns
+func (s *DatabaseService) queryMaintenanceWindows(query string, args ...any) ([]models.MaintenanceWindow, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []models.MaintenanceWindow
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, *window)
+	}
+	return windows, rows.Err()
+}
+
+// GetMaintenanceWindows retrieves every maintenance window, global windows
+// first
+func (s *DatabaseService) GetMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	query := `
+		SELECT ` + maintenanceColumns + `
+		FROM uptime_maintenance_windows
+		ORDER BY website_id IS NOT NULL, website_id, name
+	`
+	return s.queryMaintenanceWindows(query)
+}
+
+// GetWebsiteMaintenanceWindows retrieves the maintenance windows covering a
+// website, including global windows
+func (s *DatabaseService) GetWebsiteMaintenanceWindows(websiteID int) ([]models.MaintenanceWindow, error) {
+	query := `
+		SELECT ` + maintenanceColumns + `
+		FROM uptime_maintenance_windows
+		WHERE website_id = ? OR website_id IS NULL
+		ORDER BY website_id IS NOT NULL, name
+	`
+	return s.queryMaintenanceWindows(query, websiteID)
+}
+
+// CreateMaintenanceWindow stores a new maintenance window
+func (s *DatabaseService) CreateMaintenanceWindow(window models.MaintenanceWindow) error {
+	query := `
+		INSERT INTO uptime_maintenance_windows (website_id, name, starts_at, ends_at, schedule, duration, timezone)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var startsAt, endsAt sql.NullTime
+	if window.StartsAt != nil {
+		startsAt = sql.NullTime{Time: *window.StartsAt, Valid: true}
+	}
+	if window.EndsAt != nil {
+		endsAt = sql.NullTime{Time: *window.EndsAt, Valid: true}
+	}
+
+	_, err := s.db.Exec(query,
+		sql.NullInt64{Int64: int64(window.WebsiteID), Valid: window.WebsiteID > 0},
+		window.Name,
+		startsAt,
+		endsAt,
+		nullString(window.Schedule),
+		window.Duration,
+		nullString(window.Timezone),
+	)
+	return err
+}
+
+// DeleteMaintenanceWindow removes a maintenance window
+func (s *DatabaseService) DeleteMaintenanceWindow(windowID int) error {
+	result, err := s.db.Exec(`DELETE FROM uptime_maintenance_windows WHERE id = ?`, windowID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
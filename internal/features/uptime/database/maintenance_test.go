@@ -0,0 +1,70 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	for _, name := range []string{"example", "other"} {
+		if _, err := s.CreateWebsite(models.Website{Name: name, URL: "https://" + name + ".com"}); err != nil {
+			t.Fatalf("Failed to create website: %v", err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	end := start.Add(2 * time.Hour)
+	windows := []models.MaintenanceWindow{
+		{Name: "Nightly", Schedule: "0 2 * * *", Duration: 30, Timezone: "UTC"},
+		{WebsiteID: 1, Name: "Deploy", StartsAt: &start, EndsAt: &end},
+		{WebsiteID: 2, Name: "Migration", StartsAt: &start, EndsAt: &end},
+	}
+	for _, window := range windows {
+		if err := s.CreateMaintenanceWindow(window); err != nil {
+			t.Fatalf("Failed to create maintenance window: %v", err)
+		}
+	}
+
+	covering, err := s.GetWebsiteMaintenanceWindows(1)
+	if err != nil {
+		t.Fatalf("Failed to get maintenance windows: %v", err)
+	}
+	if len(covering) != 2 || !covering[0].IsGlobal() || covering[1].Name != "Deploy" {
+		t.Fatalf("Expected the global and website windows, got %+v", covering)
+	}
+	if !covering[1].StartsAt.Equal(start) || !covering[1].Active(time.Now()) {
+		t.Errorf("Expected the deploy window to be in progress, got %+v", covering[1])
+	}
+
+	// Maintenance checks don't count towards uptime or confirmation
+	if err := s.StoreUptimeCheck(1, 200, 50, models.StatusUp, "", false, nil); err != nil {
+		t.Fatalf("Failed to store check: %v", err)
+	}
+	if err := s.StoreUptimeCheck(1, 503, 50, models.StatusDown, "HTTP 503", true, nil); err != nil {
+		t.Fatalf("Failed to store check: %v", err)
+	}
+	percentage, up, down, err := s.GetUptimePercentage(1, 24)
+	if err != nil || percentage != 100 || up != 1 || down != 0 {
+		t.Errorf("Expected maintenance checks to be excluded, got %.2f%% (%d up, %d down), %v", percentage, up, down, err)
+	}
+	if statuses, _ := s.GetRecentStatuses(1, 5); len(statuses) != 1 || statuses[0] != models.StatusUp {
+		t.Errorf("Expected only the regular check, got %v", statuses)
+	}
+	if last, _ := s.GetLastWebsiteStatus(1); last == nil || !last.Maintenance {
+		t.Errorf("Expected the last check to be tagged as maintenance, got %+v", last)
+	}
+
+	if err := s.DeleteMaintenanceWindow(covering[1].ID); err != nil {
+		t.Fatalf("Failed to delete maintenance window: %v", err)
+	}
+	if err := s.DeleteMaintenanceWindow(covering[1].ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing window, got %v", err)
+	}
+	if all, _ := s.GetMaintenanceWindows(); len(all) != 2 {
+		t.Errorf("Expected two windows left, got %+v", all)
+	}
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"math"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// GetLatencyPercentiles computes exact nearest-rank percentiles of a
+// website's latency since a time, along with the number of samples. SQLite
+// has no percentile function, so each is read by offsetting into the
+// response times in order. Only up and degraded checks outside maintenance
+// are sampled, and only raw checks that haven't been pruned, so callers must
+// check the samples cover the period.
+func (s *DatabaseService) GetLatencyPercentiles(websiteID int, since time.Time, percentiles ...float64) ([]int64, int, error) {
+	const sampled = `
+		FROM uptime_checks
+		WHERE website_id = ?
+		AND status IN ('up', 'degraded')
+		AND maintenance = 0
+		AND checked_at >= ?
+	`
+
+	var samples int
+	if err := s.db.QueryRow(`SELECT COUNT(*) `+sampled, websiteID, since.Local()).Scan(&samples); err != nil {
+		return nil, 0, err
+	}
+
+	values := make([]int64, len(percentiles))
+	if samples == 0 {
+		return values, 0, nil
+	}
+	for i, p := range percentiles {
+		rank := int(math.Ceil(p / 100 * float64(samples)))
+		offset := min(max(rank, 1), samples) - 1
+
+		err := s.db.QueryRow(
+			`SELECT response_time `+sampled+` ORDER BY response_time LIMIT 1 OFFSET ?`,
+			websiteID, since.Local(), offset,
+		).Scan(&values[i])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return values, samples, nil
+}
+
+// GetWebsiteMetrics gathers the latency series, status code breakdown and
+// uptime heatmap the website detail charts show for a period
+func (s *DatabaseService) GetWebsiteMetrics(websiteID int, period models.MetricsPeriod, resolution string, now time.Time) (*models.WebsiteMetrics, error) {
+	since := now.Add(-period.Duration)
+
+	points, err := s.GetCheckRollups(websiteID, resolution, since)
+	if err != nil {
+		return nil, err
+	}
+	hourly := points
+	if resolution != models.ResolutionHour {
+		if hourly, err = s.GetCheckRollups(websiteID, models.ResolutionHour, since); err != nil {
+			return nil, err
+		}
+	}
+
+	var total models.CheckRollup
+	for _, point := range points {
+		total.Merge(point)
+	}
+
+	summary := models.LatencySummary{
+		Checks:          total.TotalChecks(),
+		Uptime:          total.Percentage(),
+		LatencySamples:  total.LatencySamples,
+		AvgResponseTime: total.AvgResponseTime,
+		P50ResponseTime: total.P50ResponseTime,
+		P95ResponseTime: total.P95ResponseTime,
+		P99ResponseTime: total.P99ResponseTime,
+	}
+	// Exact percentiles need every sample of the period. Once raw checks
+	// within it have been pruned, the raw samples no longer match the
+	// rollups' and the rollups' merged percentiles are used instead.
+	percentiles, samples, err := s.GetLatencyPercentiles(websiteID, models.TruncateBucket(since, resolution), 50, 95, 99)
+	if err != nil {
+		return nil, err
+	}
+	if samples > 0 && samples == total.LatencySamples {
+		summary.P50ResponseTime = percentiles[0]
+		summary.P95ResponseTime = percentiles[1]
+		summary.P99ResponseTime = percentiles[2]
+	} else {
+		summary.Approximate = total.LatencySamples > 0
+	}
+
+	timing, err := s.GetAverageTiming(websiteID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	if points == nil {
+		points = []models.CheckRollup{}
+	}
+	return &models.WebsiteMetrics{
+		WebsiteID:   websiteID,
+		Period:      period.Name,
+		Resolution:  resolution,
+		Since:       since,
+		Summary:     summary,
+		Points:      points,
+		StatusCodes: models.SortedStatusCodes(total.StatusCodes),
+		Heatmap:     models.NewHeatmap(hourly, since, now),
+		Timing:      timing,
+	}, nil
+}
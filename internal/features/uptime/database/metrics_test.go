@@ -0,0 +1,120 @@
+package database
+
+import (
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestGetLatencyPercentiles(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	for i := int64(1); i <= 100; i++ {
+		insertCheck(t, db, 1, models.StatusUp, 200, i*10, now.Add(-time.Duration(i)*time.Minute))
+	}
+	// Down checks and checks before the period aren't sampled
+	insertCheck(t, db, 1, models.StatusDown, 0, 10000, now.Add(-time.Minute))
+	insertCheck(t, db, 1, models.StatusUp, 200, 10000, now.Add(-48*time.Hour))
+
+	values, samples, err := s.GetLatencyPercentiles(1, now.Add(-24*time.Hour), 50, 95, 99)
+	if err != nil {
+		t.Fatalf("Failed to get percentiles: %v", err)
+	}
+	if samples != 100 || values[0] != 500 || values[1] != 950 || values[2] != 990 {
+		t.Errorf("Unexpected percentiles %v from %d samples", values, samples)
+	}
+
+	if values, samples, err := s.GetLatencyPercentiles(2, now.Add(-24*time.Hour), 50); err != nil || samples != 0 || values[0] != 0 {
+		t.Errorf("Expected no samples for an unknown website, got %v, %d, %v", values, samples, err)
+	}
+}
+
+func TestGetWebsiteMetrics(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	hour := models.TruncateBucket(now, models.ResolutionHour)
+	insertCheck(t, db, 1, models.StatusUp, 200, 100, hour.Add(-3*time.Hour+time.Minute))
+	insertCheck(t, db, 1, models.StatusDown, 503, 0, hour.Add(-3*time.Hour+2*time.Minute))
+	insertCheck(t, db, 1, models.StatusUp, 200, 300, now)
+	if _, err := s.RollUpChecks(models.ResolutionHour, now); err != nil {
+		t.Fatalf("Failed to roll up checks: %v", err)
+	}
+
+	period, err := models.GetMetricsPeriod("24h")
+	if err != nil {
+		t.Fatalf("Failed to get period: %v", err)
+	}
+	metrics, err := s.GetWebsiteMetrics(1, period, models.ResolutionHour, now)
+	if err != nil {
+		t.Fatalf("Failed to get metrics: %v", err)
+	}
+
+	if len(metrics.Points) != 2 || metrics.Points[0].DownChecks != 1 || metrics.Points[1].UpChecks != 1 {
+		t.Fatalf("Expected the rolled up hour and the current one, got %+v", metrics.Points)
+	}
+	if metrics.Summary.Checks != 3 || metrics.Summary.P50ResponseTime != 100 || metrics.Summary.P99ResponseTime != 300 {
+		t.Errorf("Unexpected summary %+v", metrics.Summary)
+	}
+	if len(metrics.StatusCodes) != 2 || metrics.StatusCodes[0].Code != 200 || metrics.StatusCodes[0].Count != 2 {
+		t.Errorf("Unexpected status codes %+v", metrics.StatusCodes)
+	}
+
+	last := metrics.Heatmap[len(metrics.Heatmap)-1]
+	if uptime := last.Hours[hour.Hour()]; uptime == nil || *uptime != 100 {
+		t.Errorf("Expected the current hour to be up in the heatmap, got %v", uptime)
+	}
+}
+
+func TestWebsiteMetricsAfterPruning(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	day := models.TruncateBucket(now, models.ResolutionDay).AddDate(0, 0, -3)
+	insertCheck(t, db, 1, models.StatusUp, 200, 100, day.Add(time.Hour))
+	insertCheck(t, db, 1, models.StatusUp, 200, 300, day.Add(2*time.Hour))
+	insertCheck(t, db, 1, models.StatusUp, 200, 200, now)
+
+	period, err := models.GetMetricsPeriod("7d")
+	if err != nil {
+		t.Fatalf("Failed to get period: %v", err)
+	}
+	metrics, err := s.GetWebsiteMetrics(1, period, models.ResolutionHour, now)
+	if err != nil {
+		t.Fatalf("Failed to get metrics: %v", err)
+	}
+	if summary := metrics.Summary; summary.Approximate || summary.P50ResponseTime != 200 || summary.P99ResponseTime != 300 {
+		t.Errorf("Expected exact percentiles from raw checks, got %+v", summary)
+	}
+
+	// Once raw checks within the period are pruned, the percentiles come
+	// from the rollups
+	for _, resolution := range []string{models.ResolutionHour, models.ResolutionDay} {
+		if _, err := s.RollUpChecks(resolution, now); err != nil {
+			t.Fatalf("Failed to roll up checks: %v", err)
+		}
+	}
+	if pruned, err := s.PruneChecks(now); err != nil || pruned == 0 {
+		t.Fatalf("Expected checks to be pruned, got %d: %v", pruned, err)
+	}
+	metrics, err = s.GetWebsiteMetrics(1, period, models.ResolutionHour, now)
+	if err != nil {
+		t.Fatalf("Failed to get metrics: %v", err)
+	}
+	if summary := metrics.Summary; !summary.Approximate || summary.LatencySamples != 3 || summary.P50ResponseTime == 0 {
+		t.Errorf("Expected approximate percentiles from the rollups, got %+v", summary)
+	}
+}
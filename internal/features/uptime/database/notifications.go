@@ -0,0 +1,181 @@
+package database
+
+import (
+	"database/sql"
+	"the-ark/internal/features/uptime/models"
+)
+
+// channelColumns lists the uptime_notification_channels columns read by
+// scanChannel
+const channelColumns = `id, name, channel_type, url, token, recipient, created_at`
+
+// scanChannel scans a row selected with channelColumns
+func scanChannel(row rowScanner) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	var url, token, recipient sql.NullString
+
+	err := row.Scan(
+		&channel.ID,
+		&channel.Name,
+		&channel.Type,
+		&url,
+		&token,
+		&recipient,
+		&channel.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	channel.URL = url.String
+	channel.Token = token.String
+	channel.Recipient = recipient.String
+	return &channel, nil
+}
+
+// queryChannels runs a query selecting channelColumns
+func (s *DatabaseService) queryChannels(query string, args ...any) ([]models.NotificationChannel, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []models.NotificationChannel
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *channel)
+	}
+	return channels, rows.Err()
+}
+
+// GetNotificationChannels retrieves every notification channel
+func (s *DatabaseService) GetNotificationChannels() ([]models.NotificationChannel, error) {
+	return s.queryChannels(`SELECT ` + channelColumns + ` FROM uptime_notification_channels ORDER BY name`)
+}
+
+// GetNotificationChannel retrieves a notification channel by ID
+func (s *DatabaseService) GetNotificationChannel(channelID int) (*models.NotificationChannel, error) {
+	query := `SELECT ` + channelColumns + ` FROM uptime_notification_channels WHERE id = ?`
+	return scanChannel(s.db.QueryRow(query, channelID))
+}
+
+// GetWebsiteChannels retrieves the notification channels a website alerts
+func (s *DatabaseService) GetWebsiteChannels(websiteID int) ([]models.NotificationChannel, error) {
+	query := `
+		SELECT ` + channelColumns + `
+		FROM uptime_notification_channels
+		WHERE id IN (SELECT channel_id FROM uptime_website_channels WHERE website_id = ?)
+		ORDER BY name
+	`
+	return s.queryChannels(query, websiteID)
+}
+
+// CreateNotificationChannel stores a new notification channel
+func (s *DatabaseService) CreateNotificationChannel(channel models.NotificationChannel) error {
+	query := `
+		INSERT INTO uptime_notification_channels (name, channel_type, url, token, recipient)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := s.db.Exec(query, channel.Name, channel.Type, nullString(channel.URL), nullString(channel.Token), nullString(channel.Recipient))
+	return err
+}
+
+// UpdateNotificationChannel replaces a notification channel's settings,
+// returning sql.ErrNoRows for unknown channels
+func (s *DatabaseService) UpdateNotificationChannel(channel models.NotificationChannel) error {
+	query := `
+		UPDATE uptime_notification_channels
+		SET name = ?, channel_type = ?, url = ?, token = ?, recipient = ?
+		WHERE id = ?
+	`
+	result, err := s.db.Exec(query, channel.Name, channel.Type, nullString(channel.URL), nullString(channel.Token), nullString(channel.Recipient), channel.ID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteNotificationChannel removes a notification channel and detaches it
+// from every website, including as an escalation contact
+func (s *DatabaseService) DeleteNotificationChannel(channelID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM uptime_website_channels WHERE channel_id = ?`, channelID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE uptime_websites SET escalation_channel_id = NULL WHERE escalation_channel_id = ?`, channelID); err != nil {
+		return err
+	}
+	result, err := tx.Exec(`DELETE FROM uptime_notification_channels WHERE id = ?`, channelID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// getWebsiteChannelIDs retrieves the IDs of the channels a website alerts
+func (s *DatabaseService) getWebsiteChannelIDs(websiteID int) ([]int, error) {
+	rows, err := s.db.Query(`SELECT channel_id FROM uptime_website_channels WHERE website_id = ? ORDER BY channel_id`, websiteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SetWebsiteChannels replaces the notification channels a website alerts
+func (s *DatabaseService) SetWebsiteChannels(websiteID int, channelIDs []int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setWebsiteChannels(tx, websiteID, channelIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setWebsiteChannels replaces a website's channels within a transaction.
+// Unknown channel IDs are ignored.
+func setWebsiteChannels(tx *sql.Tx, websiteID int, channelIDs []int) error {
+	if _, err := tx.Exec(`DELETE FROM uptime_website_channels WHERE website_id = ?`, websiteID); err != nil {
+		return err
+	}
+
+	for _, channelID := range channelIDs {
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO uptime_website_channels (website_id, channel_id)
+			SELECT ?, id FROM uptime_notification_channels WHERE id = ?
+		`, websiteID, channelID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+)
+
+func TestWebsiteChannels(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+
+	channels := []models.NotificationChannel{
+		{Name: "Ops email", Type: models.ChannelEmail, Recipient: "ops@example.com"},
+		{Name: "Slack", Type: models.ChannelSlack, URL: "https://hooks.slack.com/services/T000/B000/XXX"},
+		{Name: "Gotify", Type: models.ChannelGotify, URL: "https://gotify.example.com", Token: "app-token"},
+	}
+	for _, channel := range channels {
+		if err := s.CreateNotificationChannel(channel); err != nil {
+			t.Fatalf("Failed to create channel: %v", err)
+		}
+	}
+
+	// Unknown channel IDs are ignored
+	website := models.Website{Name: "Example", URL: "https://example.com", ChannelIDs: []int{2, 3, 99}}
+	if _, err := s.CreateWebsite(website); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	stored, err := s.GetWebsiteByID(1)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if len(stored.ChannelIDs) != 2 || !stored.HasChannel(2) || !stored.HasChannel(3) {
+		t.Fatalf("Expected channels 2 and 3, got %v", stored.ChannelIDs)
+	}
+
+	selected, err := s.GetWebsiteChannels(1)
+	if err != nil {
+		t.Fatalf("Failed to get website channels: %v", err)
+	}
+	if len(selected) != 2 || selected[0].Name != "Gotify" || selected[0].Token != "app-token" {
+		t.Fatalf("Expected Gotify and Slack channels, got %+v", selected)
+	}
+
+	if err := s.SetWebsiteChannels(1, []int{1}); err != nil {
+		t.Fatalf("Failed to set website channels: %v", err)
+	}
+	if err := s.DeleteNotificationChannel(1); err != nil {
+		t.Fatalf("Failed to delete channel: %v", err)
+	}
+	if selected, _ := s.GetWebsiteChannels(1); len(selected) != 0 {
+		t.Errorf("Expected deleted channel to be detached, got %+v", selected)
+	}
+
+	if err := s.DeleteNotificationChannel(1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing channel, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// probeColumns are the columns scanProbe reads, in order
+const probeColumns = `id, name, registered_at, last_seen_at`
+
+// RegisterProbe stores a probe under its location name with the hash of its
+// key. A probe registering again under its name replaces its key, but only
+// when it presents the hash of its current key, so the registration token
+// alone can't take over another probe. models.ErrProbeNameTaken is returned
+// otherwise.
+func (s *DatabaseService) RegisterProbe(name, keyHash, currentKeyHash string, at time.Time) (*models.Probe, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var storedHash string
+	err = tx.QueryRow(`SELECT key_hash FROM uptime_probes WHERE name = ?`, name).Scan(&storedHash)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.Exec(`INSERT INTO uptime_probes (name, key_hash, registered_at) VALUES (?, ?, ?)`, name, keyHash, at.Local())
+	case err != nil:
+		return nil, err
+	case currentKeyHash == "" || subtle.ConstantTimeCompare([]byte(currentKeyHash), []byte(storedHash)) != 1:
+		return nil, models.ErrProbeNameTaken
+	default:
+		_, err = tx.Exec(`UPDATE uptime_probes SET key_hash = ?, registered_at = ? WHERE name = ?`, keyHash, at.Local(), name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.getProbe(`SELECT `+probeColumns+` FROM uptime_probes WHERE name = ?`, name)
+}
+
+// GetProbeByKeyHash retrieves the probe a key belongs to, returning
+// sql.ErrNoRows for unknown keys
+func (s *DatabaseService) GetProbeByKeyHash(keyHash string) (*models.Probe, error) {
+	return s.getProbe(`SELECT `+probeColumns+` FROM uptime_probes WHERE key_hash = ?`, keyHash)
+}
+
+// GetProbes retrieves every registered probe by name
+func (s *DatabaseService) GetProbes() ([]models.Probe, error) {
+	rows, err := s.db.Query(`SELECT ` + probeColumns + ` FROM uptime_probes ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var probes []models.Probe
+	for rows.Next() {
+		probe, err := scanProbe(rows)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, *probe)
+	}
+	return probes, rows.Err()
+}
+
+// DeleteProbe removes a probe and its results, returning sql.ErrNoRows for
+// unknown probes
+func (s *DatabaseService) DeleteProbe(probeID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM uptime_probe_results WHERE probe_id = ?`, probeID); err != nil {
+		return err
+	}
+	result, err := tx.Exec(`DELETE FROM uptime_probes WHERE id = ?`, probeID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// StoreProbeResults replaces a probe's latest results for the websites it
+// reported on and marks the probe as seen. Results for websites that don't
+// exist any more are dropped, and the number of results kept is returned.
+// Results can't be dated after at, which guards against skewed clocks.
+func (s *DatabaseService) StoreProbeResults(probeID int, results []models.ProbeResult, at time.Time) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE uptime_probes SET last_seen_at = ? WHERE id = ?`, at.Local(), probeID); err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, result := range results {
+		checkedAt := result.CheckedAt
+		if checkedAt.IsZero() || checkedAt.After(at) {
+			checkedAt = at
+		}
+
+		res, err := tx.Exec(`
+			INSERT INTO uptime_probe_results (probe_id, website_id, is_up, status_code, response_time, error_message, checked_at)
+			SELECT ?, id, ?, ?, ?, ?, ? FROM uptime_websites WHERE id = ?
+			ON CONFLICT(probe_id, website_id) DO UPDATE SET
+				is_up = excluded.is_up,
+				status_code = excluded.status_code,
+				response_time = excluded.response_time,
+				error_message = excluded.error_message,
+				checked_at = excluded.checked_at
+		`, probeID, result.IsUp, result.StatusCode, result.ResponseTime, result.Error, checkedAt.Local(), result.WebsiteID)
+		if err != nil {
+			return 0, err
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			stored += int(n)
+		}
+	}
+
+	return stored, tx.Commit()
+}
+
+// GetProbeResults retrieves the latest result of each probe for a website,
+// leaving out results older than since
+func (s *DatabaseService) GetProbeResults(websiteID int, since time.Time) ([]models.ProbeResult, error) {
+	rows, err := s.db.Query(`
+		SELECT r.website_id, r.is_up, r.status_code, r.response_time, r.error_message, r.checked_at, p.name
+		FROM uptime_probe_results r
+		JOIN uptime_probes p ON p.id = r.probe_id
+		WHERE r.website_id = ? AND r.checked_at >= ?
+		ORDER BY p.name
+	`, websiteID, since.Local())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.ProbeResult
+	for rows.Next() {
+		var result models.ProbeResult
+		err := rows.Scan(&result.WebsiteID, &result.IsUp, &result.StatusCode, &result.ResponseTime, &result.Error, &result.CheckedAt, &result.Location)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// getProbe retrieves a single probe, returning sql.ErrNoRows if there is none
+func (s *DatabaseService) getProbe(query string, args ...any) (*models.Probe, error) {
+	return scanProbe(s.db.QueryRow(query, args...))
+}
+
+// scanProbe reads a row of probeColumns
+func scanProbe(row rowScanner) (*models.Probe, error) {
+	var probe models.Probe
+	var lastSeenAt sql.NullTime
+	if err := row.Scan(&probe.ID, &probe.Name, &probe.RegisteredAt, &lastSeenAt); err != nil {
+		return nil, err
+	}
+	if lastSeenAt.Valid {
+		probe.LastSeenAt = &lastSeenAt.Time
+	}
+	return &probe, nil
+}
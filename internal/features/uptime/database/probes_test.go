@@ -0,0 +1,92 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestProbeResults(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	probe, err := s.RegisterProbe("eu-west", models.HashProbeKey("first"), "", now)
+	if err != nil {
+		t.Fatalf("Failed to register probe: %v", err)
+	}
+	if probe.Name != "eu-west" || probe.LastSeenAt != nil {
+		t.Fatalf("Expected an unseen eu-west probe, got %+v", probe)
+	}
+
+	// Registering again under the same name needs the current key
+	for _, current := range []string{"", models.HashProbeKey("wrong")} {
+		if _, err := s.RegisterProbe("eu-west", models.HashProbeKey("stolen"), current, now); !errors.Is(err, models.ErrProbeNameTaken) {
+			t.Fatalf("Expected models.ErrProbeNameTaken without the current key, got %v", err)
+		}
+	}
+	if _, err := s.GetProbeByKeyHash(models.HashProbeKey("stolen")); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected a rejected registration not to change the key, got %v", err)
+	}
+
+	// With it, the key is replaced
+	again, err := s.RegisterProbe("eu-west", models.HashProbeKey("second"), models.HashProbeKey("first"), now)
+	if err != nil || again.ID != probe.ID {
+		t.Fatalf("Expected the same probe re-registered, got %+v, %v", again, err)
+	}
+	if _, err := s.GetProbeByKeyHash(models.HashProbeKey("first")); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected the old key to stop working, got %v", err)
+	}
+	if found, err := s.GetProbeByKeyHash(models.HashProbeKey("second")); err != nil || found.ID != probe.ID {
+		t.Fatalf("Expected the new key to find the probe, got %+v, %v", found, err)
+	}
+
+	results := []models.ProbeResult{
+		{WebsiteID: 1, IsUp: false, Error: "connection refused", CheckedAt: now.Add(-time.Second)},
+		{WebsiteID: 99, IsUp: true, CheckedAt: now},
+	}
+	stored, err := s.StoreProbeResults(probe.ID, results, now)
+	if err != nil || stored != 1 {
+		t.Fatalf("Expected only the known website's result stored, got %d, %v", stored, err)
+	}
+
+	// A newer result replaces the probe's previous one
+	results = []models.ProbeResult{{WebsiteID: 1, IsUp: true, StatusCode: 200, ResponseTime: 120, CheckedAt: now.Add(time.Hour)}}
+	if _, err := s.StoreProbeResults(probe.ID, results, now); err != nil {
+		t.Fatalf("Failed to store probe results: %v", err)
+	}
+
+	latest, err := s.GetProbeResults(1, now.Add(-time.Minute))
+	if err != nil || len(latest) != 1 {
+		t.Fatalf("Expected one result, got %+v, %v", latest, err)
+	}
+	if !latest[0].IsUp || latest[0].Location != "eu-west" || latest[0].ResponseTime != 120 {
+		t.Errorf("Expected the latest up result from eu-west, got %+v", latest[0])
+	}
+	// Results dated in the future are dated when they arrived
+	if latest[0].CheckedAt.After(now.Add(time.Second)) {
+		t.Errorf("Expected the result dated no later than it arrived, got %v", latest[0].CheckedAt)
+	}
+	if stale, _ := s.GetProbeResults(1, now.Add(time.Minute)); len(stale) != 0 {
+		t.Errorf("Expected results older than since left out, got %+v", stale)
+	}
+
+	probes, err := s.GetProbes()
+	if err != nil || len(probes) != 1 || probes[0].LastSeenAt == nil {
+		t.Fatalf("Expected the probe marked as seen, got %+v, %v", probes, err)
+	}
+
+	if err := s.DeleteProbe(probe.ID); err != nil {
+		t.Fatalf("Failed to delete probe: %v", err)
+	}
+	if remaining, _ := s.GetProbeResults(1, time.Time{}); len(remaining) != 0 {
+		t.Errorf("Expected the probe's results deleted with it, got %+v", remaining)
+	}
+	if err := s.DeleteProbe(probe.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting an unknown probe, got %v", err)
+	}
+}
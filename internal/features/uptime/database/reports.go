@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// GetSLAReport builds the SLA report of every website for the month
+// starting at month, as of now
+func (s *DatabaseService) GetSLAReport(month time.Time, target float64, now time.Time) (*models.SLAReport, error) {
+	month = models.ReportMonth(month)
+	end := month.AddDate(0, 1, 0)
+
+	websites, err := s.GetWebsites()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SLAReport{
+		Month:       month,
+		Target:      target,
+		GeneratedAt: now,
+		Partial:     now.Before(end),
+		Websites:    []models.WebsiteSLA{},
+	}
+	for _, website := range websites {
+		// Websites added after the month have nothing to report
+		if !website.CreatedAt.Before(end) {
+			continue
+		}
+
+		summary, err := s.getCheckSummary(website.ID, month, end, now)
+		if err != nil {
+			return nil, err
+		}
+		incidents, err := s.GetIncidentsBetween(website.ID, month, end)
+		if err != nil {
+			return nil, err
+		}
+		windows, err := s.GetWebsiteMaintenanceWindows(website.ID)
+		if err != nil {
+			return nil, err
+		}
+		report.Websites = append(report.Websites, models.NewWebsiteSLA(website, summary, incidents, windows, month, target, now))
+	}
+	return report, nil
+}
+
+// GetIncidentsBetween returns a website's incidents that were open at any
+// point in [from, to), oldest first. Timelines are not loaded.
+func (s *DatabaseService) GetIncidentsBetween(websiteID int, from, to time.Time) ([]models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM uptime_incidents
+		WHERE website_id = ?
+		AND started_at < ?
+		AND (resolved_at IS NULL OR resolved_at >= ?)
+		ORDER BY started_at
+	`
+
+	rows, err := s.db.Query(query, websiteID, to.Local(), from.Local())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []models.Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, *incident)
+	}
+	return incidents, rows.Err()
+}
+
+// GetReportSent returns when a month's SLA report was emailed, or nil if it
+// hasn't been
+func (s *DatabaseService) GetReportSent(month string) (*time.Time, error) {
+	var sentAt time.Time
+	err := s.db.QueryRow(`SELECT sent_at FROM uptime_report_deliveries WHERE month = ?`, month).Scan(&sentAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sentAt, nil
+}
+
+// RecordReportSent records that a month's SLA report was emailed
+func (s *DatabaseService) RecordReportSent(month, recipient string, at time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO uptime_report_deliveries (month, recipient, sent_at) VALUES (?, ?, ?)
+		ON CONFLICT (month) DO UPDATE SET recipient = excluded.recipient, sent_at = excluded.sent_at
+	`, month, recipient, at)
+	return err
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestGetSLAReport(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	for _, website := range []models.Website{
+		{Name: "Example", URL: "https://example.com"},
+		{Name: "Later", URL: "https://later.example.com"},
+	} {
+		if _, err := s.CreateWebsite(website); err != nil {
+			t.Fatalf("Failed to create website: %v", err)
+		}
+	}
+
+	now := time.Now()
+	month := models.ReportMonth(now).AddDate(0, -1, 0)
+	// Only the first website existed last month
+	if _, err := db.Exec(`UPDATE uptime_websites SET created_at = ? WHERE id = 1`, month.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("Failed to backdate website: %v", err)
+	}
+
+	insertCheck(t, db, 1, models.StatusUp, 200, 100, month.Add(time.Hour).Local())
+	insertCheck(t, db, 1, models.StatusUp, 200, 300, month.Add(2*time.Hour).Local())
+	insertCheck(t, db, 1, models.StatusDown, 503, 0, month.Add(3*time.Hour).Local())
+	insertCheck(t, db, 1, models.StatusUp, 200, 200, month.AddDate(0, 0, 1).Local())
+	// Checks either side of the month aren't counted
+	insertCheck(t, db, 1, models.StatusDown, 503, 0, month.Add(-time.Hour).Local())
+	insertCheck(t, db, 1, models.StatusDown, 503, 0, month.AddDate(0, 1, 0).Local())
+
+	started := month.Add(3 * time.Hour)
+	resolved := started.Add(10 * time.Minute)
+	for _, incident := range []struct {
+		started  time.Time
+		resolved time.Time
+	}{
+		{started, resolved},
+		// Resolved before the month started
+		{month.Add(-2 * time.Hour), month.Add(-time.Hour)},
+	} {
+		if _, err := db.Exec(
+			`INSERT INTO uptime_incidents (website_id, status, cause, started_at, resolved_at) VALUES (1, ?, 'timeout', ?, ?)`,
+			models.IncidentResolved, incident.started.Local(), incident.resolved.Local(),
+		); err != nil {
+			t.Fatalf("Failed to insert incident: %v", err)
+		}
+	}
+
+	report, err := s.GetSLAReport(month, 99.9, now)
+	if err != nil {
+		t.Fatalf("Failed to get SLA report: %v", err)
+	}
+	if report.Partial || report.Name() != month.Format(models.ReportMonthLayout) {
+		t.Errorf("Expected a complete report of last month, got %+v", report)
+	}
+	if len(report.Websites) != 1 {
+		t.Fatalf("Expected only the website that existed last month, got %+v", report.Websites)
+	}
+	sla := report.Websites[0]
+	if sla.Checks != 4 || sla.DownChecks != 1 || sla.Uptime != 75 || sla.MetTarget {
+		t.Errorf("Expected 3 of 4 checks up, got %+v", sla)
+	}
+	if sla.LatencySamples != 3 || sla.P50ResponseTime != 200 {
+		t.Errorf("Expected latency of the up checks, got %+v", sla)
+	}
+	if len(sla.Incidents) != 1 || sla.Incidents[0].DowntimeSeconds != 600 || sla.DowntimeSeconds != 600 {
+		t.Errorf("Expected one 10 minute incident, got %+v", sla.Incidents)
+	}
+
+	// Once rolled up and pruned, the month is read from hourly rollups
+	for _, resolution := range []string{models.ResolutionHour, models.ResolutionDay} {
+		if _, err := s.RollUpChecks(resolution, now); err != nil {
+			t.Fatalf("Failed to roll up checks: %v", err)
+		}
+	}
+	if pruned, err := s.PruneChecks(now); err != nil || pruned == 0 {
+		t.Fatalf("Expected checks to be pruned, got %d: %v", pruned, err)
+	}
+	report, err = s.GetSLAReport(month, 99.9, now)
+	if err != nil {
+		t.Fatalf("Failed to get SLA report: %v", err)
+	}
+	if sla := report.Websites[0]; sla.Checks != 4 || sla.DownChecks != 1 {
+		t.Errorf("Expected the same checks from rollups, got %+v", sla)
+	}
+}
+
+func TestReportDeliveries(t *testing.T) {
+	s := NewDatabaseService(newTestDatabase(t))
+
+	sent, err := s.GetReportSent("2026-09")
+	if err != nil || sent != nil {
+		t.Fatalf("Expected no delivery, got %v, %v", sent, err)
+	}
+
+	at := time.Date(2026, time.October, 1, 0, 5, 0, 0, time.UTC)
+	if err := s.RecordReportSent("2026-09", "ops@example.com", at); err != nil {
+		t.Fatalf("Failed to record delivery: %v", err)
+	}
+	sent, err = s.GetReportSent("2026-09")
+	if err != nil || sent == nil || !sent.Equal(at) {
+		t.Errorf("Expected delivery at %v, got %v, %v", at, sent, err)
+	}
+}
@@ -0,0 +1,362 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// rollupColumns lists the uptime_check_rollups columns read by scanRollup
+const rollupColumns = `website_id, resolution, bucket_start, up_checks, degraded_checks, down_checks,
+	latency_samples, min_response_time, avg_response_time, p50_response_time, p95_response_time,
+	p99_response_time, max_response_time, status_codes`
+
+// scanRollup scans a row selected with rollupColumns
+func scanRollup(row rowScanner) (*models.CheckRollup, error) {
+	var rollup models.CheckRollup
+	var minResponse, p50Response, p95Response, p99Response, maxResponse sql.NullInt64
+	var avgResponse sql.NullFloat64
+	var statusCodes string
+
+	err := row.Scan(
+		&rollup.WebsiteID, &rollup.Resolution, &rollup.BucketStart,
+		&rollup.UpChecks, &rollup.DegradedChecks, &rollup.DownChecks,
+		&rollup.LatencySamples, &minResponse, &avgResponse, &p50Response, &p95Response,
+		&p99Response, &maxResponse, &statusCodes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rollup.BucketStart = rollup.BucketStart.UTC()
+	rollup.MinResponseTime = minResponse.Int64
+	rollup.AvgResponseTime = avgResponse.Float64
+	rollup.P50ResponseTime = p50Response.Int64
+	rollup.P95ResponseTime = p95Response.Int64
+	rollup.P99ResponseTime = p99Response.Int64
+	rollup.MaxResponseTime = maxResponse.Int64
+	if err := json.Unmarshal([]byte(statusCodes), &rollup.StatusCodes); err != nil {
+		return nil, fmt.Errorf("failed to decode status codes: %w", err)
+	}
+	return &rollup, nil
+}
+
+// getRawChecks retrieves the checks taken in [from, to), oldest first, or
+// every check since from when to is zero. A websiteID of 0 retrieves every
+// website's checks. Check times are stored in the server's timezone, so the
+// bounds are converted to it for comparison.
+func (s *DatabaseService) getRawChecks(websiteID int, from, to time.Time) ([]models.WebsiteStatus, error) {
+	query := `
+		SELECT website_id, status, response_time, status_code, maintenance, checked_at
+		FROM uptime_checks
+		WHERE checked_at >= ?
+	`
+	args := []any{from.Local()}
+	if !to.IsZero() {
+		query += ` AND checked_at < ?`
+		args = append(args, to.Local())
+	}
+	if websiteID > 0 {
+		query += ` AND website_id = ?`
+		args = append(args, websiteID)
+	}
+	query += ` ORDER BY checked_at`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []models.WebsiteStatus
+	for rows.Next() {
+		var check models.WebsiteStatus
+		if err := rows.Scan(&check.WebsiteID, &check.Status, &check.ResponseTime, &check.StatusCode, &check.Maintenance, &check.CheckedAt); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
+
+// rollUp groups checks into rollups by website and bucket, ordered by
+// website then bucket
+func rollUp(checks []models.WebsiteStatus, resolution string) []models.CheckRollup {
+	type bucketKey struct {
+		websiteID int
+		start     time.Time
+	}
+
+	buckets := make(map[bucketKey][]models.WebsiteStatus)
+	var keys []bucketKey
+	for _, check := range checks {
+		key := bucketKey{check.WebsiteID, models.TruncateBucket(check.CheckedAt, resolution)}
+		if _, ok := buckets[key]; !ok {
+			keys = append(keys, key)
+		}
+		buckets[key] = append(buckets[key], check)
+	}
+
+	slices.SortFunc(keys, func(a, b bucketKey) int {
+		if a.websiteID != b.websiteID {
+			return a.websiteID - b.websiteID
+		}
+		return a.start.Compare(b.start)
+	})
+
+	rollups := make([]models.CheckRollup, len(keys))
+	for i, key := range keys {
+		rollups[i] = models.RollupChecks(key.websiteID, resolution, key.start, buckets[key])
+	}
+	return rollups
+}
+
+// getRollupProgress returns the time raw checks have been rolled up to at a
+// resolution, or nil before the first rollup
+func (s *DatabaseService) getRollupProgress(resolution string) (*time.Time, error) {
+	var rolledUpTo time.Time
+	err := s.db.QueryRow(`SELECT rolled_up_to FROM uptime_rollup_progress WHERE resolution = ?`, resolution).Scan(&rolledUpTo)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rolledUpTo = rolledUpTo.UTC()
+	return &rolledUpTo, nil
+}
+
+// RollUpChecks aggregates the raw checks of every complete bucket since the
+// last rollup, a day at a time, returning the number of rollups written.
+// Rollups and progress are written together, so an interrupted rollup is
+// picked up where it stopped.
+func (s *DatabaseService) RollUpChecks(resolution string, now time.Time) (int, error) {
+	end := models.TruncateBucket(now, resolution)
+
+	start, err := s.getRollupProgress(resolution)
+	if err != nil {
+		return 0, err
+	}
+	if start == nil {
+		var first time.Time
+		err := s.db.QueryRow(`SELECT checked_at FROM uptime_checks ORDER BY checked_at LIMIT 1`).Scan(&first)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		first = models.TruncateBucket(first, resolution)
+		start = &first
+	}
+
+	written := 0
+	for from := *start; from.Before(end); {
+		to := from.Add(24 * time.Hour)
+		if to.After(end) {
+			to = end
+		}
+
+		n, err := s.rollUpRange(resolution, from, to)
+		if err != nil {
+			return written, err
+		}
+		written += n
+		from = to
+	}
+	return written, nil
+}
+
+// rollUpRange writes the rollups of the checks in [from, to) and records the
+// progress
+func (s *DatabaseService) rollUpRange(resolution string, from, to time.Time) (int, error) {
+	checks, err := s.getRawChecks(0, from, to)
+	if err != nil {
+		return 0, err
+	}
+	rollups := rollUp(checks, resolution)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, rollup := range rollups {
+		statusCodes, err := json.Marshal(rollup.StatusCodes)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode status codes: %w", err)
+		}
+		latency := rollup.LatencySamples > 0
+
+		_, err = tx.Exec(`
+			INSERT OR REPLACE INTO uptime_check_rollups (`+rollupColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			rollup.WebsiteID, rollup.Resolution, rollup.BucketStart,
+			rollup.UpChecks, rollup.DegradedChecks, rollup.DownChecks, rollup.LatencySamples,
+			sql.NullInt64{Int64: rollup.MinResponseTime, Valid: latency},
+			sql.NullFloat64{Float64: rollup.AvgResponseTime, Valid: latency},
+			sql.NullInt64{Int64: rollup.P50ResponseTime, Valid: latency},
+			sql.NullInt64{Int64: rollup.P95ResponseTime, Valid: latency},
+			sql.NullInt64{Int64: rollup.P99ResponseTime, Valid: latency},
+			sql.NullInt64{Int64: rollup.MaxResponseTime, Valid: latency},
+			string(statusCodes),
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO uptime_rollup_progress (resolution, rolled_up_to) VALUES (?, ?)
+		ON CONFLICT (resolution) DO UPDATE SET rolled_up_to = excluded.rolled_up_to
+	`, resolution, to.UTC()); err != nil {
+		return 0, err
+	}
+	return len(rollups), tx.Commit()
+}
+
+// PruneChecks deletes raw checks taken before a time, returning how many
+// were deleted. Checks that haven't been rolled up at every resolution are
+// kept, as is each website's latest check so its current state is known.
+func (s *DatabaseService) PruneChecks(before time.Time) (int64, error) {
+	for _, resolution := range []string{models.ResolutionHour, models.ResolutionDay} {
+		progress, err := s.getRollupProgress(resolution)
+		if err != nil {
+			return 0, err
+		}
+		if progress == nil {
+			return 0, nil
+		}
+		if progress.Before(before) {
+			before = *progress
+		}
+	}
+
+	result, err := s.db.Exec(`
+		DELETE FROM uptime_checks
+		WHERE checked_at < ?
+		AND id NOT IN (SELECT MAX(id) FROM uptime_checks GROUP BY website_id)
+	`, before.Local())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PruneRollups deletes rollups at a resolution whose buckets start before a
+// time, returning how many were deleted
+func (s *DatabaseService) PruneRollups(resolution string, before time.Time) (int64, error) {
+	result, err := s.db.Exec(
+		`DELETE FROM uptime_check_rollups WHERE resolution = ? AND bucket_start < ?`,
+		resolution, before.UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// getStoredRollups retrieves a website's stored rollups with buckets
+// starting in [from, to), oldest first
+func (s *DatabaseService) getStoredRollups(websiteID int, resolution string, from, to time.Time) ([]models.CheckRollup, error) {
+	rows, err := s.db.Query(`
+		SELECT `+rollupColumns+`
+		FROM uptime_check_rollups
+		WHERE website_id = ? AND resolution = ?
+		AND bucket_start >= ? AND bucket_start < ?
+		ORDER BY bucket_start
+	`, websiteID, resolution, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []models.CheckRollup
+	for rows.Next() {
+		rollup, err := scanRollup(rows)
+		if err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, *rollup)
+	}
+	return rollups, rows.Err()
+}
+
+// GetCheckRollups returns a website's rollups at a resolution from the
+// bucket containing since onwards, oldest first. Buckets that haven't been
+// rolled up yet, including the current one, are aggregated from raw checks.
+func (s *DatabaseService) GetCheckRollups(websiteID int, resolution string, since time.Time) ([]models.CheckRollup, error) {
+	from := models.TruncateBucket(since, resolution)
+
+	progress, err := s.getRollupProgress(resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var rollups []models.CheckRollup
+	if progress != nil && progress.After(from) {
+		if rollups, err = s.getStoredRollups(websiteID, resolution, from, *progress); err != nil {
+			return nil, err
+		}
+		from = *progress
+	}
+
+	checks, err := s.getRawChecks(websiteID, from, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return append(rollups, rollUp(checks, resolution)...), nil
+}
+
+// getCheckSummary aggregates a website's checks in [from, to) into a single
+// rollup, with a zero to leaving the period open ended. The summary reads
+// daily rollups when the period reaches back further than hourly rollups
+// are kept at now, then hourly rollups, then raw checks for the rest of the
+// period.
+func (s *DatabaseService) getCheckSummary(websiteID int, from, to, now time.Time) (models.CheckRollup, error) {
+	summary := models.CheckRollup{WebsiteID: websiteID, BucketStart: from, StatusCodes: make(map[int]int)}
+	cursor := from
+
+	resolutions := []string{models.ResolutionHour}
+	if now.Sub(from) > models.HourlyRollupRetention {
+		resolutions = []string{models.ResolutionDay, models.ResolutionHour}
+	}
+	for _, resolution := range resolutions {
+		progress, err := s.getRollupProgress(resolution)
+		if err != nil {
+			return summary, err
+		}
+		if progress == nil || !progress.After(cursor) {
+			continue
+		}
+
+		until := *progress
+		if !to.IsZero() && to.Before(until) {
+			until = to
+		}
+		rollups, err := s.getStoredRollups(websiteID, resolution, models.TruncateBucket(cursor, resolution), until)
+		if err != nil {
+			return summary, err
+		}
+		for _, rollup := range rollups {
+			summary.Merge(rollup)
+		}
+		cursor = until
+	}
+
+	if !to.IsZero() && !cursor.Before(to) {
+		return summary, nil
+	}
+	checks, err := s.getRawChecks(websiteID, cursor, to)
+	if err != nil {
+		return summary, err
+	}
+	summary.Merge(models.RollupChecks(websiteID, "", cursor, checks))
+	return summary, nil
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// insertCheck stores a check taken at a given time
+func insertCheck(t *testing.T, db *sql.DB, websiteID int, status string, statusCode int, responseTime int64, at time.Time) {
+	t.Helper()
+
+	if _, err := db.Exec(
+		`INSERT INTO uptime_checks (website_id, status, response_time, status_code, maintenance, checked_at) VALUES (?, ?, ?, ?, 0, ?)`,
+		websiteID, status, responseTime, statusCode, at,
+	); err != nil {
+		t.Fatalf("Failed to insert check: %v", err)
+	}
+}
+
+func TestRollUpChecks(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	hour := models.TruncateBucket(now, models.ResolutionHour)
+	twoHoursAgo := hour.Add(-2 * time.Hour)
+	insertCheck(t, db, 1, models.StatusUp, 200, 100, twoHoursAgo.Add(time.Minute))
+	insertCheck(t, db, 1, models.StatusUp, 200, 300, twoHoursAgo.Add(2*time.Minute))
+	insertCheck(t, db, 1, models.StatusDown, 503, 50, twoHoursAgo.Add(3*time.Minute))
+	insertCheck(t, db, 1, models.StatusUp, 200, 200, now)
+
+	written, err := s.RollUpChecks(models.ResolutionHour, now)
+	if err != nil {
+		t.Fatalf("Failed to roll up checks: %v", err)
+	}
+	if written != 1 {
+		t.Errorf("Expected one hourly rollup, got %d", written)
+	}
+	// Rolling up again only covers new buckets
+	if written, err := s.RollUpChecks(models.ResolutionHour, now); err != nil || written != 0 {
+		t.Errorf("Expected nothing to roll up, got %d, %v", written, err)
+	}
+
+	rollups, err := s.GetCheckRollups(1, models.ResolutionHour, twoHoursAgo)
+	if err != nil {
+		t.Fatalf("Failed to get rollups: %v", err)
+	}
+	if len(rollups) != 2 {
+		t.Fatalf("Expected the stored rollup and the current hour, got %+v", rollups)
+	}
+	stored := rollups[0]
+	if !stored.BucketStart.Equal(twoHoursAgo) || stored.UpChecks != 2 || stored.DownChecks != 1 {
+		t.Errorf("Unexpected stored rollup %+v", stored)
+	}
+	if stored.MinResponseTime != 100 || stored.MaxResponseTime != 300 || stored.AvgResponseTime != 200 || stored.P95ResponseTime != 300 {
+		t.Errorf("Expected latency from up checks only, got %+v", stored)
+	}
+	if stored.StatusCodes[200] != 2 || stored.StatusCodes[503] != 1 {
+		t.Errorf("Unexpected status codes %v", stored.StatusCodes)
+	}
+	if rollups[1].UpChecks != 1 || !rollups[1].BucketStart.Equal(hour) {
+		t.Errorf("Expected the current hour from raw checks, got %+v", rollups[1])
+	}
+
+	// Raw checks aren't pruned until rolled up at every resolution
+	if pruned, err := s.PruneChecks(now); err != nil || pruned != 0 {
+		t.Errorf("Expected nothing pruned before the daily rollup, got %d, %v", pruned, err)
+	}
+	if _, err := s.RollUpChecks(models.ResolutionDay, now.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Failed to roll up days: %v", err)
+	}
+	if pruned, err := s.PruneChecks(now); err != nil || pruned != 3 {
+		t.Errorf("Expected the rolled up checks to be pruned, got %d, %v", pruned, err)
+	}
+
+	// Stats still count the pruned checks
+	percentage, up, down, err := s.GetUptimePercentage(1, 24)
+	if err != nil {
+		t.Fatalf("Failed to get uptime percentage: %v", err)
+	}
+	if up != 3 || down != 1 || percentage != 75 {
+		t.Errorf("Expected 75%% from rollups and raw checks, got %.2f%% (%d up, %d down)", percentage, up, down)
+	}
+	if avg, err := s.GetAverageResponseTime(1, 24); err != nil || avg != 200 {
+		t.Errorf("Expected a 200ms average, got %.2f, %v", avg, err)
+	}
+}
+
+func TestUptimeFromDailyRollups(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	insertCheck(t, db, 1, models.StatusDown, 500, 0, now.AddDate(0, 0, -200))
+	insertCheck(t, db, 1, models.StatusUp, 200, 100, now.AddDate(0, 0, -2))
+	insertCheck(t, db, 1, models.StatusUp, 200, 100, now)
+
+	for _, resolution := range []string{models.ResolutionHour, models.ResolutionDay} {
+		if _, err := s.RollUpChecks(resolution, now); err != nil {
+			t.Fatalf("Failed to roll up checks: %v", err)
+		}
+	}
+	if _, err := s.PruneRollups(models.ResolutionHour, now.Add(-models.HourlyRollupRetention)); err != nil {
+		t.Fatalf("Failed to prune hourly rollups: %v", err)
+	}
+	if _, err := s.PruneChecks(now.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("Failed to prune checks: %v", err)
+	}
+
+	// The year reads the daily rollups, the month the hourly ones
+	if _, up, down, err := s.GetUptimePercentage(1, 24*365); err != nil || up != 2 || down != 1 {
+		t.Errorf("Expected the old outage in the yearly stats, got %d up, %d down, %v", up, down, err)
+	}
+	if _, up, down, err := s.GetUptimePercentage(1, 24*30); err != nil || up != 2 || down != 0 {
+		t.Errorf("Expected two up checks in the monthly stats, got %d up, %d down, %v", up, down, err)
+	}
+
+	days, err := s.GetDailyUptime(1, 3, now)
+	if err != nil {
+		t.Fatalf("Failed to get daily uptime: %v", err)
+	}
+	if days[0].UpChecks != 1 || days[1].Checks != 0 || days[2].UpChecks != 1 {
+		t.Errorf("Expected daily uptime from rollups and today's raw checks, got %+v", days)
+	}
+}
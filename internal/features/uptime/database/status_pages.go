@@ -0,0 +1,346 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// statusIncidentDays is how far back resolved incidents are shown on status
+// pages
+const statusIncidentDays = 7
+
+// statusPageColumns lists the uptime_status_pages columns read by
+// scanStatusPage
+const statusPageColumns = `id, slug, title, description, created_at`
+
+// scanStatusPage scans a row selected with statusPageColumns
+func scanStatusPage(row rowScanner) (*models.StatusPage, error) {
+	var page models.StatusPage
+	var description sql.NullString
+
+	if err := row.Scan(&page.ID, &page.Slug, &page.Title, &description, &page.CreatedAt); err != nil {
+		return nil, err
+	}
+	page.Description = description.String
+	return &page, nil
+}
+
+// GetStatusPages retrieves every status page with its components
+func (s *DatabaseService) GetStatusPages() ([]models.StatusPage, error) {
+	rows, err := s.db.Query(`SELECT ` + statusPageColumns + ` FROM uptime_status_pages ORDER BY title`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pages []models.StatusPage
+	for rows.Next() {
+		page, err := scanStatusPage(rows)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, *page)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range pages {
+		if pages[i].Components, err = s.getStatusComponents(pages[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// GetStatusPageBySlug retrieves a status page with its components, or
+// sql.ErrNoRows
+func (s *DatabaseService) GetStatusPageBySlug(slug string) (*models.StatusPage, error) {
+	row := s.db.QueryRow(`SELECT `+statusPageColumns+` FROM uptime_status_pages WHERE slug = ?`, slug)
+	page, err := scanStatusPage(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Components, err = s.getStatusComponents(page.ID); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// getStatusComponents retrieves a page's components in order, with the
+// websites each shows
+func (s *DatabaseService) getStatusComponents(pageID int) ([]models.StatusComponent, error) {
+	rows, err := s.db.Query(`
+		SELECT c.id, c.page_id, c.name, c.position, cw.website_id
+		FROM uptime_status_components c
+		LEFT JOIN uptime_status_component_websites cw ON cw.component_id = c.id
+		WHERE c.page_id = ?
+		ORDER BY c.position, c.id, cw.website_id
+	`, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var components []models.StatusComponent
+	for rows.Next() {
+		var component models.StatusComponent
+		var websiteID sql.NullInt64
+		if err := rows.Scan(&component.ID, &component.PageID, &component.Name, &component.Position, &websiteID); err != nil {
+			return nil, err
+		}
+
+		if n := len(components); n == 0 || components[n-1].ID != component.ID {
+			components = append(components, component)
+		}
+		if websiteID.Valid {
+			last := &components[len(components)-1]
+			last.WebsiteIDs = append(last.WebsiteIDs, int(websiteID.Int64))
+		}
+	}
+	return components, rows.Err()
+}
+
+// CreateStatusPage stores a new status page without components
+func (s *DatabaseService) CreateStatusPage(page models.StatusPage) error {
+	_, err := s.db.Exec(
+		`INSERT INTO uptime_status_pages (slug, title, description) VALUES (?, ?, ?)`,
+		page.Slug, page.Title, nullString(page.Description),
+	)
+	return err
+}
+
+// DeleteStatusPage removes a status page and its components
+func (s *DatabaseService) DeleteStatusPage(pageID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		"DELETE FROM uptime_status_component_websites WHERE component_id IN (SELECT id FROM uptime_status_components WHERE page_id = ?)",
+		"DELETE FROM uptime_status_components WHERE page_id = ?",
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query, pageID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM uptime_status_pages WHERE id = ?`, pageID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// AddStatusComponent appends a component to the end of a status page,
+// returning sql.ErrNoRows if the page doesn't exist
+func (s *DatabaseService) AddStatusComponent(component models.StatusComponent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM uptime_status_pages WHERE id = ?`, component.PageID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return sql.ErrNoRows
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO uptime_status_components (page_id, name, position)
+		SELECT ?, ?, COALESCE(MAX(position), -1) + 1 FROM uptime_status_components WHERE page_id = ?
+	`, component.PageID, component.Name, component.PageID)
+	if err != nil {
+		return err
+	}
+	componentID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, websiteID := range component.WebsiteIDs {
+		if _, err := tx.Exec(
+			`INSERT OR IGNORE INTO uptime_status_component_websites (component_id, website_id) VALUES (?, ?)`,
+			componentID, websiteID,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteStatusComponent removes a component from its status page
+func (s *DatabaseService) DeleteStatusComponent(componentID int) error {
+	if _, err := s.db.Exec(`DELETE FROM uptime_status_component_websites WHERE component_id = ?`, componentID); err != nil {
+		return err
+	}
+	result, err := s.db.Exec(`DELETE FROM uptime_status_components WHERE id = ?`, componentID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetDailyUptime counts a website's checks per UTC day for the days days up
+// to and including now's, oldest first, from the daily rollups. Maintenance
+// and pending checks aren't counted.
+func (s *DatabaseService) GetDailyUptime(websiteID int, days int, now time.Time) ([]models.DailyUptime, error) {
+	first := models.TruncateBucket(now, models.ResolutionDay).AddDate(0, 0, -(days - 1))
+
+	rollups, err := s.GetCheckRollups(websiteID, models.ResolutionDay, first)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[time.Time]models.DailyUptime)
+	for _, rollup := range rollups {
+		counts[rollup.BucketStart] = models.DailyUptime{
+			Checks:   rollup.TotalChecks(),
+			UpChecks: rollup.UpChecks + rollup.DegradedChecks,
+		}
+	}
+
+	// Fill in days without checks
+	uptime := make([]models.DailyUptime, days)
+	for i := range uptime {
+		date := first.AddDate(0, 0, i)
+		uptime[i] = counts[date]
+		uptime[i].Date = date
+	}
+	return uptime, nil
+}
+
+// getStatusIncidents retrieves the open incidents of websites and those
+// started since a time, newest first
+func (s *DatabaseService) getStatusIncidents(websiteIDs []int, since time.Time) ([]models.Incident, error) {
+	if len(websiteIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(websiteIDs)+1)
+	for _, id := range websiteIDs {
+		args = append(args, id)
+	}
+	args = append(args, since)
+
+	rows, err := s.db.Query(`
+		SELECT `+incidentColumns+`
+		FROM uptime_incidents
+		WHERE website_id IN (?`+strings.Repeat(", ?", len(websiteIDs)-1)+`)
+		AND (resolved_at IS NULL OR started_at >= ?)
+		ORDER BY started_at DESC
+		LIMIT 20
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []models.Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, *incident)
+	}
+	return incidents, rows.Err()
+}
+
+// GetPublicStatusPage assembles what a status page shows: each component's
+// websites with their current state and daily uptime, and active or recent
+// incidents. It returns sql.ErrNoRows for unknown slugs.
+func (s *DatabaseService) GetPublicStatusPage(slug string, now time.Time) (*models.PublicStatusPage, error) {
+	page, err := s.GetStatusPageBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	public := &models.PublicStatusPage{
+		Title:       page.Title,
+		Description: page.Description,
+		Components:  []models.PublicComponent{},
+		Incidents:   []models.PublicIncident{},
+		GeneratedAt: now,
+	}
+
+	// Websites may appear in several components, incidents are listed once
+	websiteNames := make(map[int]string)
+	websiteComponents := make(map[int]string)
+	var websiteIDs []int
+
+	var componentStates []string
+	for _, component := range page.Components {
+		publicComponent := models.PublicComponent{Name: component.Name, Websites: []models.PublicWebsite{}}
+
+		var states []string
+		for _, websiteID := range component.WebsiteIDs {
+			website, err := s.GetWebsiteByID(websiteID)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			status, err := s.GetLastWebsiteStatus(websiteID)
+			if err != nil {
+				return nil, err
+			}
+			days, err := s.GetDailyUptime(websiteID, models.StatusPageDays, now)
+			if err != nil {
+				return nil, err
+			}
+
+			state := models.WebsiteState(status)
+			states = append(states, state)
+			publicComponent.Websites = append(publicComponent.Websites, models.PublicWebsite{
+				Name:   website.Name,
+				Status: state,
+				Uptime: models.OverallUptime(days),
+				Days:   days,
+			})
+
+			if _, seen := websiteNames[websiteID]; !seen {
+				websiteNames[websiteID] = website.Name
+				websiteComponents[websiteID] = component.Name
+				websiteIDs = append(websiteIDs, websiteID)
+			}
+		}
+
+		publicComponent.Status = models.CombineStates(states)
+		componentStates = append(componentStates, publicComponent.Status)
+		public.Components = append(public.Components, publicComponent)
+	}
+	public.Status = models.CombineStates(componentStates)
+
+	incidents, err := s.getStatusIncidents(websiteIDs, now.AddDate(0, 0, -statusIncidentDays))
+	if err != nil {
+		return nil, err
+	}
+	for _, incident := range incidents {
+		public.Incidents = append(public.Incidents, models.PublicIncident{
+			Website:    websiteNames[incident.WebsiteID],
+			Component:  websiteComponents[incident.WebsiteID],
+			Status:     incident.Status,
+			StartedAt:  incident.StartedAt,
+			ResolvedAt: incident.ResolvedAt,
+		})
+	}
+
+	return public, nil
+}
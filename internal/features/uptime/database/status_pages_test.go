@@ -0,0 +1,147 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestStatusPages(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	for _, name := range []string{"api", "web"} {
+		if _, err := s.CreateWebsite(models.Website{Name: name, URL: "https://" + name + ".example.com"}); err != nil {
+			t.Fatalf("Failed to create website: %v", err)
+		}
+	}
+
+	if err := s.CreateStatusPage(models.StatusPage{Slug: "acme", Title: "Acme"}); err != nil {
+		t.Fatalf("Failed to create status page: %v", err)
+	}
+	if err := s.CreateStatusPage(models.StatusPage{Slug: "acme", Title: "Acme again"}); err == nil {
+		t.Error("Expected a duplicate slug to be rejected")
+	}
+
+	page, err := s.GetStatusPageBySlug("acme")
+	if err != nil {
+		t.Fatalf("Failed to get status page: %v", err)
+	}
+	for _, component := range []models.StatusComponent{
+		{PageID: page.ID, Name: "API", WebsiteIDs: []int{1}},
+		{PageID: page.ID, Name: "Everything", WebsiteIDs: []int{1, 2}},
+	} {
+		if err := s.AddStatusComponent(component); err != nil {
+			t.Fatalf("Failed to add component: %v", err)
+		}
+	}
+	if err := s.AddStatusComponent(models.StatusComponent{PageID: 99, Name: "Missing"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing page, got %v", err)
+	}
+
+	// The API is down and has an open incident
+	if err := s.StoreUptimeCheck(1, 503, 50, models.StatusDown, "HTTP 503", false, nil); err != nil {
+		t.Fatalf("Failed to store check: %v", err)
+	}
+	if err := s.StoreUptimeCheck(2, 200, 50, models.StatusUp, "", false, nil); err != nil {
+		t.Fatalf("Failed to store check: %v", err)
+	}
+	if err := s.OpenIncident(1, "HTTP 503"); err != nil {
+		t.Fatalf("Failed to open incident: %v", err)
+	}
+
+	public, err := s.GetPublicStatusPage("acme", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to get public status page: %v", err)
+	}
+	if len(public.Components) != 2 || public.Components[0].Name != "API" || public.Components[1].Name != "Everything" {
+		t.Fatalf("Expected components in the order added, got %+v", public.Components)
+	}
+	if public.Components[0].Status != models.PageMajorOutage || public.Components[1].Status != models.PagePartialOutage {
+		t.Errorf("Unexpected component states %q and %q", public.Components[0].Status, public.Components[1].Status)
+	}
+	if public.Status != models.PagePartialOutage {
+		t.Errorf("Expected a partial outage overall, got %q", public.Status)
+	}
+	if days := public.Components[1].Websites[1].Days; len(days) != models.StatusPageDays || days[len(days)-1].UpChecks != 1 {
+		t.Errorf("Expected %d days ending with today's check, got %+v", models.StatusPageDays, days[len(days)-1])
+	}
+	// The API is in both components but its incident is listed once
+	if len(public.Incidents) != 1 || public.Incidents[0].Website != "api" || public.Incidents[0].Component != "API" {
+		t.Errorf("Expected the API incident once, got %+v", public.Incidents)
+	}
+
+	// Deleting a website removes it from its components
+	if err := s.DeleteWebsite(1); err != nil {
+		t.Fatalf("Failed to delete website: %v", err)
+	}
+	pages, err := s.GetStatusPages()
+	if err != nil {
+		t.Fatalf("Failed to get status pages: %v", err)
+	}
+	if len(pages) != 1 || len(pages[0].Components) != 2 || len(pages[0].Components[0].WebsiteIDs) != 0 {
+		t.Errorf("Expected the API component to be empty, got %+v", pages)
+	}
+
+	if err := s.DeleteStatusComponent(pages[0].Components[0].ID); err != nil {
+		t.Fatalf("Failed to delete component: %v", err)
+	}
+	if err := s.DeleteStatusPage(page.ID); err != nil {
+		t.Fatalf("Failed to delete status page: %v", err)
+	}
+	if _, err := s.GetPublicStatusPage("acme", time.Now()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a deleted page, got %v", err)
+	}
+	var remaining int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM uptime_status_components`).Scan(&remaining); err != nil || remaining != 0 {
+		t.Errorf("Expected components to be deleted with their page, got %d, %v", remaining, err)
+	}
+}
+
+func TestGetDailyUptime(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	checks := []struct {
+		daysAgo     int
+		status      string
+		maintenance bool
+	}{
+		{2, models.StatusUp, false},
+		{2, models.StatusDown, false},
+		{2, models.StatusDown, true},
+		{0, models.StatusUp, false},
+		{0, models.StatusPending, false},
+		{10, models.StatusDown, false},
+	}
+	for _, check := range checks {
+		if _, err := db.Exec(
+			`INSERT INTO uptime_checks (website_id, status, response_time, status_code, maintenance, checked_at) VALUES (1, ?, 50, 200, ?, ?)`,
+			check.status, check.maintenance, now.AddDate(0, 0, -check.daysAgo),
+		); err != nil {
+			t.Fatalf("Failed to insert check: %v", err)
+		}
+	}
+
+	days, err := s.GetDailyUptime(1, 3, now)
+	if err != nil {
+		t.Fatalf("Failed to get daily uptime: %v", err)
+	}
+	if len(days) != 3 {
+		t.Fatalf("Expected 3 days, got %+v", days)
+	}
+	if days[0].Checks != 2 || days[0].UpChecks != 1 || days[0].Status() != "down" {
+		t.Errorf("Expected a half-up day excluding maintenance, got %+v", days[0])
+	}
+	if days[1].Checks != 0 || days[1].Status() != "none" {
+		t.Errorf("Expected a day without checks, got %+v", days[1])
+	}
+	if days[2].Checks != 1 || days[2].Status() != "up" || days[2].Date.Day() != now.Day() {
+		t.Errorf("Expected today to exclude pending checks, got %+v", days[2])
+	}
+}
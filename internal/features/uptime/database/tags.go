@@ -0,0 +1,228 @@
+package database
+
+import (
+	"database/sql"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// deleteUnusedTags removes tags no website has any more
+const deleteUnusedTags = `DELETE FROM uptime_tags WHERE id NOT IN (SELECT tag_id FROM uptime_website_tags)`
+
+// taggedWebsites selects the IDs of the websites with a tag
+const taggedWebsites = `
+	SELECT wt.website_id FROM uptime_website_tags wt
+	JOIN uptime_tags t ON t.id = wt.tag_id
+	WHERE t.name = ?
+`
+
+// setResumedAt sets resumed_at when a paused website is resumed, given
+// whether it is being resumed and the time. It reads the previous is_active,
+// so it must be part of the UPDATE that changes it.
+const setResumedAt = `resumed_at = CASE WHEN ? AND is_active = 0 THEN ? ELSE resumed_at END`
+
+// GetTags retrieves every tag with how many websites have it
+func (s *DatabaseService) GetTags() ([]models.Tag, error) {
+	rows, err := s.db.Query(`
+		SELECT t.id, t.name, COUNT(wt.website_id)
+		FROM uptime_tags t
+		LEFT JOIN uptime_website_tags wt ON wt.tag_id = t.id
+		GROUP BY t.id, t.name
+		ORDER BY t.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.WebsiteCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetWebsitesByTag retrieves the websites with a tag, including paused ones
+func (s *DatabaseService) GetWebsitesByTag(tag string) ([]models.Website, error) {
+	query := `
+		SELECT ` + websiteColumns + `
+		FROM uptime_websites
+		WHERE id IN (` + taggedWebsites + `)
+		ORDER BY name
+	`
+	return s.queryWebsites(query, tag)
+}
+
+// SetWebsiteActive pauses or resumes monitoring a website, returning
+// sql.ErrNoRows for unknown websites. Its history is kept either way, but
+// alerts deferred by quiet hours are discarded on pause.
+func (s *DatabaseService) SetWebsiteActive(websiteID int, active bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`UPDATE uptime_websites SET is_active = ?, updated_at = ?, `+setResumedAt+` WHERE id = ?`, active, now, active, now, websiteID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	if !active {
+		if err := discardDeferredAlerts(tx, "?", websiteID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SetTagActive pauses or resumes monitoring every website with a tag,
+// returning how many websites it changed
+func (s *DatabaseService) SetTagActive(tag string, active bool) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	query := `UPDATE uptime_websites SET is_active = ?, updated_at = ?, ` + setResumedAt + ` WHERE is_active != ? AND id IN (` + taggedWebsites + `)`
+	result, err := tx.Exec(query, active, now, active, now, active, tag)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if !active {
+		if err := discardDeferredAlerts(tx, taggedWebsites, tag); err != nil {
+			return 0, err
+		}
+	}
+	return int(n), tx.Commit()
+}
+
+// DeleteTaggedWebsites removes every website with a tag in one
+// transaction, so a failure leaves the whole group in place, and returns
+// how many websites it removed
+func (s *DatabaseService) DeleteTaggedWebsites(tag string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(taggedWebsites, tag)
+	if err != nil {
+		return 0, err
+	}
+	var websiteIDs []int
+	for rows.Next() {
+		var websiteID int
+		if err := rows.Scan(&websiteID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		websiteIDs = append(websiteIDs, websiteID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if err := deleteWebsites(tx, websiteIDs); err != nil {
+		return 0, err
+	}
+	return len(websiteIDs), tx.Commit()
+}
+
+// getWebsiteTags retrieves the names of a website's tags in order
+func (s *DatabaseService) getWebsiteTags(websiteID int) ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT t.name FROM uptime_website_tags wt
+		JOIN uptime_tags t ON t.id = wt.tag_id
+		WHERE wt.website_id = ?
+		ORDER BY t.name
+	`, websiteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// loadTags fills in the tags of a list of websites with a single query
+func (s *DatabaseService) loadTags(websites []models.Website) error {
+	if len(websites) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT wt.website_id, t.name FROM uptime_website_tags wt
+		JOIN uptime_tags t ON t.id = wt.tag_id
+		ORDER BY t.name
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tags := make(map[int][]string)
+	for rows.Next() {
+		var websiteID int
+		var tag string
+		if err := rows.Scan(&websiteID, &tag); err != nil {
+			return err
+		}
+		tags[websiteID] = append(tags[websiteID], tag)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range websites {
+		websites[i].Tags = tags[websites[i].ID]
+	}
+	return nil
+}
+
+// setWebsiteTags replaces a website's tags, creating tags that don't exist
+// yet and removing tags no website has any more
+func setWebsiteTags(tx *sql.Tx, websiteID int, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM uptime_website_tags WHERE website_id = ?`, websiteID); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO uptime_tags (name) VALUES (?)`, tag); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO uptime_website_tags (website_id, tag_id)
+			SELECT ?, id FROM uptime_tags WHERE name = ?
+		`, websiteID, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(deleteUnusedTags)
+	return err
+}
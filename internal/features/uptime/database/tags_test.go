@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+)
+
+func TestWebsiteTags(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+
+	websites := []models.Website{
+		{Name: "API", URL: "https://api.example.com", Tags: []string{"client-x", "prod"}},
+		{Name: "Shop", URL: "https://shop.example.com", Tags: []string{"prod"}},
+		{Name: "Staging", URL: "https://staging.example.com", Tags: []string{"staging"}},
+	}
+	for _, website := range websites {
+		if _, err := s.CreateWebsite(website); err != nil {
+			t.Fatalf("Failed to create website: %v", err)
+		}
+	}
+
+	all, err := s.GetWebsites()
+	if err != nil {
+		t.Fatalf("Failed to get websites: %v", err)
+	}
+	if len(all) != 3 || !slices.Equal(all[0].Tags, []string{"client-x", "prod"}) || !all[0].IsActive {
+		t.Fatalf("Expected websites with their tags, got %+v", all)
+	}
+
+	tags, err := s.GetTags()
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, tag := range tags {
+		counts[tag.Name] = tag.WebsiteCount
+	}
+	if counts["prod"] != 2 || counts["client-x"] != 1 || counts["staging"] != 1 || len(counts) != 3 {
+		t.Errorf("Expected tag counts, got %v", counts)
+	}
+
+	prod, err := s.GetWebsitesByTag("prod")
+	if err != nil || len(prod) != 2 {
+		t.Fatalf("Expected 2 prod websites, got %d, %v", len(prod), err)
+	}
+
+	// Pausing a group keeps its websites but stops them being monitored
+	paused, err := s.SetTagActive("prod", false)
+	if err != nil || paused != 2 {
+		t.Fatalf("Expected 2 websites paused, got %d, %v", paused, err)
+	}
+	active, err := s.GetActiveWebsites()
+	if err != nil || len(active) != 1 || active[0].Name != "Staging" {
+		t.Errorf("Expected only the staging website active, got %+v, %v", active, err)
+	}
+	if paused, _ := s.SetTagActive("prod", false); paused != 0 {
+		t.Errorf("Expected already paused websites not to count, got %d", paused)
+	}
+	if err := s.SetWebsiteActive(1, true); err != nil {
+		t.Fatalf("Failed to resume website: %v", err)
+	}
+	if err := s.SetWebsiteActive(99, true); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for an unknown website, got %v", err)
+	}
+
+	// Tags no website has any more are removed
+	staging, err := s.GetWebsiteByID(3)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	staging.Tags = []string{"qa"}
+	if err := s.UpdateWebsite(*staging); err != nil {
+		t.Fatalf("Failed to update website: %v", err)
+	}
+	if err := s.DeleteWebsite(1); err != nil {
+		t.Fatalf("Failed to delete website: %v", err)
+	}
+
+	tags, err = s.GetTags()
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	if want := []string{"prod", "qa"}; !slices.Equal(names, want) {
+		t.Errorf("Expected tags %v, got %v", want, names)
+	}
+
+	// Deleting a group removes every website with the tag
+	deleted, err := s.DeleteTaggedWebsites("prod")
+	if err != nil || deleted != 1 {
+		t.Fatalf("Expected the remaining prod website deleted, got %d, %v", deleted, err)
+	}
+	remaining, err := s.GetWebsites()
+	if err != nil || len(remaining) != 1 || remaining[0].Name != "Staging" {
+		t.Errorf("Expected only the staging website left, got %+v, %v", remaining, err)
+	}
+	if deleted, err := s.DeleteTaggedWebsites("prod"); err != nil || deleted != 0 {
+		t.Errorf("Expected nothing left to delete, got %d, %v", deleted, err)
+	}
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"math"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// timingColumns are the uptime_checks columns holding a check's timing, in
+// CheckTiming order
+const timingColumns = "dns_time, connect_time, tls_time, ttfb_time, transfer_time"
+
+// timingValues returns the timing column values of a check, all NULL
+// without timing
+func timingValues(timing *models.CheckTiming) []any {
+	if timing == nil {
+		return []any{nil, nil, nil, nil, nil}
+	}
+	return []any{timing.DNS, timing.Connect, timing.TLS, timing.TTFB, timing.Transfer}
+}
+
+// timingScanner scans the nullable timing columns of a check
+type timingScanner struct {
+	dns, connect, tls, ttfb, transfer sql.NullInt64
+}
+
+func (s *timingScanner) dest() []any {
+	return []any{&s.dns, &s.connect, &s.tls, &s.ttfb, &s.transfer}
+}
+
+// timing returns the scanned timing, or nil for checks stored without one
+func (s *timingScanner) timing() *models.CheckTiming {
+	if !s.dns.Valid {
+		return nil
+	}
+	return &models.CheckTiming{
+		DNS:      s.dns.Int64,
+		Connect:  s.connect.Int64,
+		TLS:      s.tls.Int64,
+		TTFB:     s.ttfb.Int64,
+		Transfer: s.transfer.Int64,
+	}
+}
+
+// GetAverageTiming returns the average timing of a website's up and degraded
+// checks since a time, or nil when none were timed. Timings are only kept
+// with raw checks, so periods past the raw retention only cover what's left.
+func (s *DatabaseService) GetAverageTiming(websiteID int, since time.Time) (*models.CheckTiming, error) {
+	query := `
+		SELECT COUNT(*), AVG(dns_time), AVG(connect_time), AVG(tls_time), AVG(ttfb_time), AVG(transfer_time)
+		FROM uptime_checks
+		WHERE website_id = ? AND checked_at >= ? AND maintenance = 0
+		AND status IN (?, ?) AND dns_time IS NOT NULL
+	`
+
+	var count int
+	var dns, connect, tls, ttfb, transfer sql.NullFloat64
+	err := s.db.QueryRow(query, websiteID, since.Local(), models.StatusUp, models.StatusDegraded).Scan(&count, &dns, &connect, &tls, &ttfb, &transfer)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	round := func(value sql.NullFloat64) int64 {
+		return int64(math.Round(value.Float64))
+	}
+	return &models.CheckTiming{
+		DNS:      round(dns),
+		Connect:  round(connect),
+		TLS:      round(tls),
+		TTFB:     round(ttfb),
+		Transfer: round(transfer),
+	}, nil
+}
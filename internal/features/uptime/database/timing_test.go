@@ -0,0 +1,64 @@
+package database
+
+import (
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestCheckTiming(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	if average, err := s.GetAverageTiming(1, time.Now().Add(-time.Hour)); err != nil || average != nil {
+		t.Errorf("Expected no average without timed checks, got %+v, %v", average, err)
+	}
+
+	checks := []struct {
+		status string
+		timing *models.CheckTiming
+	}{
+		{models.StatusUp, &models.CheckTiming{DNS: 10, Connect: 20, TLS: 30, TTFB: 100, Transfer: 5}},
+		{models.StatusUp, &models.CheckTiming{DNS: 0, Connect: 0, TLS: 0, TTFB: 200, Transfer: 15}},
+		// Failed and untimed checks aren't averaged
+		{models.StatusDown, &models.CheckTiming{DNS: 5000}},
+		{models.StatusUp, nil},
+	}
+	for _, check := range checks {
+		if err := s.StoreUptimeCheck(1, 200, 150, check.status, "", false, check.timing); err != nil {
+			t.Fatalf("Failed to store check: %v", err)
+		}
+	}
+
+	history, err := s.GetUptimeHistory(1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(history) != 4 {
+		t.Fatalf("Expected 4 checks, got %d", len(history))
+	}
+	timed := 0
+	for _, check := range history {
+		if check.Timing != nil {
+			timed++
+			if check.Timing.TTFB == 100 && check.Timing.Total() != 165 {
+				t.Errorf("Unexpected stored timing %+v", check.Timing)
+			}
+		}
+	}
+	if timed != 3 {
+		t.Errorf("Expected 3 timed checks, got %d", timed)
+	}
+
+	average, err := s.GetAverageTiming(1, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get average timing: %v", err)
+	}
+	want := models.CheckTiming{DNS: 5, Connect: 10, TLS: 15, TTFB: 150, Transfer: 10}
+	if average == nil || *average != want {
+		t.Errorf("Expected average %+v, got %+v", want, average)
+	}
+}
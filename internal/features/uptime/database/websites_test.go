@@ -0,0 +1,153 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestUpdateWebsite(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+
+	websiteID, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com", CheckInterval: 300, Request: models.DefaultRequestOptions()})
+	if err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+	if websiteID != 1 {
+		t.Errorf("Expected the first website to get ID 1, got %d", websiteID)
+	}
+	insertCheck(t, db, websiteID, models.StatusUp, 200, 100, time.Now())
+
+	website, err := s.GetWebsiteByID(websiteID)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	website.Name = "Renamed"
+	website.CheckInterval = 60
+	website.Request.Method = "HEAD"
+	website.Alerts.LatencyThreshold = 500
+	if err := s.UpdateWebsite(*website); err != nil {
+		t.Fatalf("Failed to update website: %v", err)
+	}
+
+	updated, err := s.GetWebsiteByID(websiteID)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if updated.Name != "Renamed" || updated.CheckInterval != 60 || updated.Request.Method != "HEAD" || updated.Alerts.LatencyThreshold != 500 {
+		t.Errorf("Expected the update to be stored, got %+v", updated)
+	}
+	if !updated.CreatedAt.Equal(website.CreatedAt) {
+		t.Errorf("Expected the creation time kept, got %s", updated.CreatedAt)
+	}
+	if updated.UpdatedAt.Before(website.UpdatedAt) {
+		t.Errorf("Expected the update time to move on from %s, got %s", website.UpdatedAt, updated.UpdatedAt)
+	}
+	if checks, _ := s.GetUptimeHistory(websiteID, 10); len(checks) != 1 {
+		t.Errorf("Expected check history kept, got %d checks", len(checks))
+	}
+
+	website.ID = 99
+	if err := s.UpdateWebsite(*website); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for an unknown website, got %v", err)
+	}
+	if err := s.DeleteWebsite(99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting an unknown website, got %v", err)
+	}
+}
+
+func TestPauseWebsite(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+
+	websiteID, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+	insertCheck(t, db, websiteID, models.StatusUp, 200, 100, time.Now())
+
+	created, err := s.GetWebsiteByID(websiteID)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if !created.IsActive || created.UpdatedAt.IsZero() {
+		t.Fatalf("Expected a new website to be active with an update time, got %+v", created)
+	}
+
+	if err := s.SetWebsiteActive(websiteID, false); err != nil {
+		t.Fatalf("Failed to pause website: %v", err)
+	}
+	paused, err := s.GetWebsiteByID(websiteID)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if paused.IsActive || paused.UpdatedAt.Before(created.UpdatedAt) || paused.ResumedAt != nil {
+		t.Errorf("Expected the website paused with a later update time, got %+v", paused)
+	}
+	if active, _ := s.GetActiveWebsites(); len(active) != 0 {
+		t.Errorf("Expected paused websites not to be monitored, got %+v", active)
+	}
+	if all, _ := s.GetWebsites(); len(all) != 1 {
+		t.Errorf("Expected paused websites to still be listed, got %d", len(all))
+	}
+	if checks, _ := s.GetUptimeHistory(websiteID, 10); len(checks) != 1 {
+		t.Errorf("Expected check history kept while paused, got %d checks", len(checks))
+	}
+
+	if err := s.SetWebsiteActive(websiteID, true); err != nil {
+		t.Fatalf("Failed to resume website: %v", err)
+	}
+	active, _ := s.GetActiveWebsites()
+	if len(active) != 1 {
+		t.Fatalf("Expected the resumed website to be monitored, got %+v", active)
+	}
+	if active[0].ResumedAt == nil || active[0].ResumedAt.Before(paused.UpdatedAt) {
+		t.Errorf("Expected the resume time recorded, got %v", active[0].ResumedAt)
+	}
+
+	// Resuming an active website doesn't move the resume time
+	if err := s.SetWebsiteActive(websiteID, true); err != nil {
+		t.Fatalf("Failed to resume website: %v", err)
+	}
+	if again, _ := s.GetWebsiteByID(websiteID); !again.ResumedAt.Equal(*active[0].ResumedAt) {
+		t.Errorf("Expected the resume time kept, got %v", again.ResumedAt)
+	}
+}
+
+func TestCheckAndIncidentPages(t *testing.T) {
+	db := newTestDatabase(t)
+	s := NewDatabaseService(db)
+	if _, err := s.CreateWebsite(models.Website{Name: "Example", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	for i := range 5 {
+		insertCheck(t, db, 1, models.StatusUp, 200, int64(i), now.Add(time.Duration(i)*time.Minute))
+		if _, err := db.Exec(
+			`INSERT INTO uptime_incidents (website_id, status, started_at, resolved_at) VALUES (1, ?, ?, ?)`,
+			models.IncidentResolved, now.Add(time.Duration(i)*time.Hour), now.Add(time.Duration(i)*time.Hour+time.Minute),
+		); err != nil {
+			t.Fatalf("Failed to insert incident: %v", err)
+		}
+	}
+
+	checks, total, err := s.GetCheckPage(1, 2, 1)
+	if err != nil {
+		t.Fatalf("Failed to get checks: %v", err)
+	}
+	if total != 5 || len(checks) != 2 || checks[0].ResponseTime != 3 || checks[1].ResponseTime != 2 {
+		t.Errorf("Expected the second and third newest of 5 checks, got %d: %+v", total, checks)
+	}
+
+	incidents, total, err := s.GetIncidentPage(1, 10, 4)
+	if err != nil {
+		t.Fatalf("Failed to get incidents: %v", err)
+	}
+	if total != 5 || len(incidents) != 1 || !incidents[0].StartedAt.Equal(now) {
+		t.Errorf("Expected only the oldest of 5 incidents, got %d: %+v", total, incidents)
+	}
+}
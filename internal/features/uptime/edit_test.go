@@ -0,0 +1,63 @@
+package uptime
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+)
+
+func TestEditWebsiteContentOptions(t *testing.T) {
+	feature, server := newProbeTestServer(t, Config{})
+
+	websiteID, err := feature.service.CreateWebsite(models.Website{
+		Name:          "Example",
+		URL:           "https://example.com",
+		CheckInterval: 300,
+		IsActive:      true,
+		Content:       models.ContentOptions{Enabled: true, IgnoreSelectors: []string{"#clock"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	edit := func(form url.Values) int {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPatch, server.URL+fmt.Sprintf("/uptime/api/websites/%d", websiteID), strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to edit website: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Unchecking the checkbox leaves it out of the form
+	if status := edit(url.Values{
+		"name":                     {"Example"},
+		"content_ignore_selectors": {"#clock\n.ad"},
+		"content_ignore_patterns":  {`\d+ visitors`},
+	}); status != http.StatusOK {
+		t.Fatalf("Expected 200 from the edit, got %d", status)
+	}
+
+	website, err := feature.service.GetWebsiteByID(websiteID)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	content := website.Content
+	if content.Enabled || len(content.IgnoreSelectors) != 2 || len(content.IgnorePatterns) != 1 {
+		t.Errorf("Expected content monitoring off with the new ignore lists, got %+v", content)
+	}
+
+	if status := edit(url.Values{
+		"content_monitoring":       {"1"},
+		"content_ignore_selectors": {""},
+		"content_ignore_patterns":  {"(unclosed"},
+	}); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid pattern, got %d", status)
+	}
+}
@@ -3,6 +3,7 @@ package uptime
 import (
 	"context"
 	"the-ark/internal/core"
+	"the-ark/internal/features/uptime/migrations"
 	"the-ark/internal/server/services/mailer"
 
 	"log/slog"
@@ -10,24 +11,27 @@ import (
 
 type Feature struct {
 	*core.BaseFeature
-	service *Service
+	service      *Service
+	migrationMgr *migrations.Manager
 }
 
-func NewFeature(logger *slog.Logger, db *core.Database, mailer mailer.Mailer, config Config) *Feature {
-	service := NewService(logger, db.DB, mailer, config)
+func NewFeature(logger *slog.Logger, db *core.Database, mailer mailer.Mailer, events *core.EventBus, config Config) *Feature {
+	service := NewService(logger, db.DB, mailer, events, config)
 
+	coreLogger := core.NewLogger()
 	baseFeature := core.NewBaseFeature(
 		"uptime",
 		"Website uptime monitoring with alerts",
 		true, // Always enabled for now
-		core.NewLogger(),
+		coreLogger,
 		db,
 		config,
 	)
 
 	return &Feature{
-		BaseFeature: baseFeature,
-		service:     service,
+		BaseFeature:  baseFeature,
+		service:      service,
+		migrationMgr: migrations.NewManager(db, coreLogger),
 	}
 }
 
@@ -37,6 +41,11 @@ func (f *Feature) Init(ctx context.Context) error {
 		return err
 	}
 
+	// Run migrations
+	if err := f.migrationMgr.Migrate(ctx); err != nil {
+		return err
+	}
+
 	f.service.Start(ctx)
 	f.Logger().Info("Uptime feature initialized")
 	return nil
@@ -46,25 +55,100 @@ func (f *Feature) Init(ctx context.Context) error {
 func (f *Feature) Routes() []core.Route {
 	apiHandler := f.service.GetAPIHandler()
 	webHandler := f.service.GetWebHandler()
+	v1Handler := f.service.GetV1Handler()
 
 	return []core.Route{
 		// Web routes
 		{Method: "GET", Path: "/uptime", Handler: webHandler.Dashboard},
 		{Method: "GET", Path: "/uptime/website/{id}", Handler: webHandler.WebsiteDetail},
 		{Method: "GET", Path: "/uptime/add", Handler: webHandler.AddSiteModal},
+		{Method: "GET", Path: "/uptime/website/{id}/edit", Handler: webHandler.EditSiteModal},
+		{Method: "GET", Path: "/uptime/channels", Handler: webHandler.Channels},
+		{Method: "GET", Path: "/uptime/status-pages", Handler: webHandler.StatusPages},
+		{Method: "GET", Path: "/uptime/reports", Handler: webHandler.Reports},
 
 		// API routes
 		{Method: "GET", Path: "/uptime/api/websites", Handler: apiHandler.ListWebsites},
 		{Method: "POST", Path: "/uptime/api/websites", Handler: apiHandler.CreateWebsite},
 		{Method: "GET", Path: "/uptime/api/websites/{id}", Handler: apiHandler.GetWebsite},
+		{Method: "GET", Path: "/uptime/api/websites/{id}/card", Handler: apiHandler.GetWebsiteCard},
+		{Method: "PATCH", Path: "/uptime/api/websites/{id}", Handler: apiHandler.UpdateWebsite},
 		{Method: "DELETE", Path: "/uptime/api/websites/{id}", Handler: apiHandler.DeleteWebsite},
+		{Method: "POST", Path: "/uptime/api/websites/{id}/pause", Handler: apiHandler.PauseWebsite},
+		{Method: "POST", Path: "/uptime/api/websites/{id}/resume", Handler: apiHandler.ResumeWebsite},
 		{Method: "POST", Path: "/uptime/api/websites/{id}/check", Handler: apiHandler.CheckWebsite},
 		{Method: "GET", Path: "/uptime/api/dashboard", Handler: apiHandler.GetDashboard},
+		{Method: "POST", Path: "/uptime/api/groups/{tag}/{action}", Handler: apiHandler.RunGroupAction},
+		{Method: "GET", Path: "/uptime/api/websites/{id}/incidents", Handler: apiHandler.ListIncidents},
+		{Method: "GET", Path: "/uptime/api/incidents/{id}", Handler: apiHandler.GetIncident},
+		{Method: "POST", Path: "/uptime/api/incidents/{id}/acknowledge", Handler: apiHandler.AcknowledgeIncident},
+		{Method: "POST", Path: "/uptime/api/incidents/{id}/root-cause", Handler: apiHandler.SetIncidentRootCause},
+		{Method: "POST", Path: "/uptime/api/incidents/{id}/comments", Handler: apiHandler.AddIncidentComment},
+		{Method: "PUT", Path: "/uptime/api/websites/{id}/channels", Handler: apiHandler.SetWebsiteChannels},
+		{Method: "PUT", Path: "/uptime/api/websites/{id}/alerts", Handler: apiHandler.SetWebsiteAlerts},
+		{Method: "GET", Path: "/uptime/api/websites/{id}/alert-history", Handler: apiHandler.GetAlertHistory},
+		{Method: "GET", Path: "/uptime/api/websites/{id}/metrics", Handler: apiHandler.GetWebsiteMetrics},
+		{Method: "GET", Path: "/uptime/api/websites/{id}/checks", Handler: apiHandler.GetCheckHistory},
+		{Method: "GET", Path: "/uptime/api/websites/{id}/maintenance", Handler: apiHandler.ListWebsiteMaintenance},
+		{Method: "GET", Path: "/uptime/api/maintenance", Handler: apiHandler.ListMaintenance},
+		{Method: "POST", Path: "/uptime/api/maintenance", Handler: apiHandler.CreateMaintenance},
+		{Method: "DELETE", Path: "/uptime/api/maintenance/{id}", Handler: apiHandler.DeleteMaintenance},
+		{Method: "GET", Path: "/uptime/api/reports/sla", Handler: apiHandler.GetSLAReport},
+		{Method: "GET", Path: "/uptime/api/status-pages", Handler: apiHandler.ListStatusPages},
+		{Method: "POST", Path: "/uptime/api/status-pages", Handler: apiHandler.CreateStatusPage},
+		{Method: "DELETE", Path: "/uptime/api/status-pages/{id}", Handler: apiHandler.DeleteStatusPage},
+		{Method: "POST", Path: "/uptime/api/status-pages/{id}/components", Handler: apiHandler.AddStatusComponent},
+		{Method: "DELETE", Path: "/uptime/api/status-components/{id}", Handler: apiHandler.DeleteStatusComponent},
+		{Method: "GET", Path: "/uptime/api/channels", Handler: apiHandler.ListChannels},
+		{Method: "POST", Path: "/uptime/api/channels", Handler: apiHandler.CreateChannel},
+		{Method: "PUT", Path: "/uptime/api/channels/{id}", Handler: apiHandler.UpdateChannel},
+		{Method: "DELETE", Path: "/uptime/api/channels/{id}", Handler: apiHandler.DeleteChannel},
+		{Method: "POST", Path: "/uptime/api/channels/{id}/test", Handler: apiHandler.TestChannel},
+
+		// Versioned JSON API
+		{Method: "GET", Path: "/api/v1/uptime/websites", Handler: v1Handler.ListWebsites},
+		{Method: "POST", Path: "/api/v1/uptime/websites", Handler: v1Handler.CreateWebsite},
+		{Method: "GET", Path: "/api/v1/uptime/websites/{id}", Handler: v1Handler.GetWebsite},
+		{Method: "PUT", Path: "/api/v1/uptime/websites/{id}", Handler: v1Handler.ReplaceWebsite},
+		{Method: "PATCH", Path: "/api/v1/uptime/websites/{id}", Handler: v1Handler.PatchWebsite},
+		{Method: "DELETE", Path: "/api/v1/uptime/websites/{id}", Handler: v1Handler.DeleteWebsite},
+		{Method: "POST", Path: "/api/v1/uptime/websites/{id}/check", Handler: v1Handler.CheckWebsite},
+		{Method: "POST", Path: "/api/v1/uptime/websites/{id}/pause", Handler: v1Handler.PauseWebsite},
+		{Method: "POST", Path: "/api/v1/uptime/websites/{id}/resume", Handler: v1Handler.ResumeWebsite},
+		{Method: "GET", Path: "/api/v1/uptime/websites/{id}/checks", Handler: v1Handler.ListChecks},
+		{Method: "GET", Path: "/api/v1/uptime/websites/{id}/incidents", Handler: v1Handler.ListIncidents},
+		{Method: "GET", Path: "/api/v1/uptime/incidents/{id}", Handler: v1Handler.GetIncident},
+		{Method: "GET", Path: "/api/v1/uptime/tags", Handler: v1Handler.ListTags},
+		{Method: "POST", Path: "/api/v1/uptime/tags/{tag}/{action}", Handler: v1Handler.RunGroupAction},
+		{Method: "GET", Path: "/api/v1/uptime/probes", Handler: v1Handler.ListProbes},
+		{Method: "DELETE", Path: "/api/v1/uptime/probes/{id}", Handler: v1Handler.DeleteProbe},
+
+		// Heartbeat ping routes, authenticated by the secret token in the path
+		{Method: "GET", Path: "/uptime/ping/{token}", Handler: apiHandler.Ping, Public: true},
+		{Method: "HEAD", Path: "/uptime/ping/{token}", Handler: apiHandler.Ping, Public: true},
+		{Method: "POST", Path: "/uptime/ping/{token}", Handler: apiHandler.Ping, Public: true},
+		{Method: "GET", Path: "/uptime/ping/{token}/fail", Handler: apiHandler.PingFailure, Public: true},
+		{Method: "POST", Path: "/uptime/ping/{token}/fail", Handler: apiHandler.PingFailure, Public: true},
+
+		// Probe agent routes, authenticated by the registration token or the
+		// probe's key as a bearer token
+		{Method: "POST", Path: "/api/v1/uptime/probes/register", Handler: v1Handler.RegisterProbe, Public: true},
+		{Method: "GET", Path: "/api/v1/uptime/probes/checks", Handler: v1Handler.ListProbeChecks, Public: true},
+		{Method: "POST", Path: "/api/v1/uptime/probes/results", Handler: v1Handler.ReportProbeResults, Public: true},
+
+		// Status pages, read-only views for customers without an Ark login
+		{Method: "GET", Path: "/status/{slug}", Handler: apiHandler.PublicStatusPage, Public: true},
+		{Method: "GET", Path: "/status/{slug}.json", Handler: apiHandler.PublicStatusPageJSON, Public: true},
 	}
 }
 
 // Shutdown gracefully shuts down the uptime feature
 func (f *Feature) Shutdown(ctx context.Context) error {
 	f.Logger().Info("Shutting down uptime feature")
+
+	if err := f.service.Stop(ctx); err != nil {
+		f.Logger().Error("Failed to stop uptime monitor", "error", err)
+	}
+
 	return f.BaseFeature.Shutdown(ctx)
 }
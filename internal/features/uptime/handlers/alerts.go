@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxAlertHistory bounds the alert history a single request may return
+const maxAlertHistory = 200
+
+// SetWebsiteAlerts replaces the channels and alert policy of a website with
+// the notifications form values
+func (h *APIHandler) SetWebsiteAlerts(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	channelIDs, err := parseChannelIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	policy, err := parseAlertPolicy(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.SetWebsiteAlerts(websiteID, channelIDs, policy)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, models.ErrUnknownEscalationChannel) {
+		http.Error(w, "Unknown escalation channel", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to set website alerts", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "channel_ids": channelIDs, "alerts": policy})
+}
+
+// GetAlertHistory returns a website's most recent alert delivery attempts
+func (h *APIHandler) GetAlertHistory(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, maxAlertHistory)
+	}
+
+	alerts, err := h.server.GetAlertHistory(websiteID, limit)
+	if err != nil {
+		h.logger.Error("Failed to get alert history", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"alerts": alerts})
+}
+
+// parseAlertPolicy reads the re-notify, escalation, quiet hours and latency
+// fields of a website form
+func parseAlertPolicy(r *http.Request) (models.AlertPolicy, error) {
+	policy := models.AlertPolicy{
+		QuietHoursStart:    strings.TrimSpace(r.FormValue("quiet_hours_start")),
+		QuietHoursEnd:      strings.TrimSpace(r.FormValue("quiet_hours_end")),
+		QuietHoursTimezone: strings.TrimSpace(r.FormValue("quiet_hours_timezone")),
+	}
+
+	fields := []struct {
+		name  string
+		label string
+		value *int
+	}{
+		{"renotify_interval", "re-notify interval", &policy.RenotifyInterval},
+		{"escalation_channel_id", "escalation channel", &policy.EscalationChannelID},
+		{"escalate_after", "escalation delay", &policy.EscalateAfter},
+		{"latency_threshold", "latency threshold", &policy.LatencyThreshold},
+		{"latency_deviation", "latency deviation", &policy.LatencyDeviation},
+	}
+	for _, field := range fields {
+		raw := strings.TrimSpace(r.FormValue(field.name))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return policy, fmt.Errorf("invalid %s %q", field.label, raw)
+		}
+		*field.value = n
+	}
+
+	// Without an escalation contact the delay means nothing
+	if policy.EscalationChannelID == 0 {
+		policy.EscalateAfter = 0
+	}
+
+	if err := policy.Validate(); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"the-ark/internal/features/uptime/models"
 	"the-ark/views/uptime"
 
@@ -25,9 +30,9 @@ func NewAPIHandler(logger *slog.Logger, server ServerInterface) *APIHandler {
 }
 
 func (h *APIHandler) ListWebsites(w http.ResponseWriter, r *http.Request) {
-	websites, err := h.server.GetActiveWebsites()
+	websites, err := h.server.GetWebsites()
 	if err != nil {
-		h.logger.Error("Failed to get active websites", "error", err)
+		h.logger.Error("Failed to get websites", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -72,13 +77,27 @@ func (h *APIHandler) CreateWebsite(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("Form parsed successfully", "method", r.Method, "content_type", r.Header.Get("Content-Type"))
 
 	name := r.FormValue("name")
-	url := r.FormValue("url")
+	checkType := r.FormValue("check_type")
+	url := checkTarget(r, checkType)
+
+	// Heartbeat websites are identified by their secret ping URL
+	var heartbeatToken string
+	if checkType == models.CheckTypeHeartbeat {
+		token, err := models.NewHeartbeatToken()
+		if err != nil {
+			h.logger.Error("Failed to create heartbeat token", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		heartbeatToken = token
+		url = models.HeartbeatPath(token)
+	}
 	checkIntervalStr := r.FormValue("check_interval")
 
-	h.logger.Info("Form values", "name", name, "url", url, "check_interval", checkIntervalStr)
+	h.logger.Info("Form values", "name", name, "check_type", checkType, "url", url, "check_interval", checkIntervalStr)
 
 	if name == "" || url == "" {
-		http.Error(w, "Name and URL are required", http.StatusBadRequest)
+		http.Error(w, "Name and target are required", http.StatusBadRequest)
 		return
 	}
 
@@ -89,30 +108,87 @@ func (h *APIHandler) CreateWebsite(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Check if we're at the limit (8 sites)
-	websites, err := h.server.GetActiveWebsites()
+	// Check if we're at the site limit
+	limited, err := checkWebsiteLimit(h.server)
 	if err != nil {
-		h.logger.Error("Failed to get active websites", "error", err)
+		h.logger.Error("Failed to get websites", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	if len(websites) >= 8 {
-		http.Error(w, "Maximum of 8 sites allowed", http.StatusBadRequest)
+	if limited != "" {
+		http.Error(w, limited, http.StatusBadRequest)
+		return
+	}
+
+	tags, err := models.ParseTags(r.FormValue("tags"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Create the website
 	website := models.Website{
-		Name:          name,
-		URL:           url,
-		CheckInterval: checkInterval,
-		IsActive:      true,
+		Name:           name,
+		URL:            url,
+		CheckInterval:  checkInterval,
+		CheckType:      checkType,
+		Request:        models.DefaultRequestOptions(),
+		HeartbeatToken: heartbeatToken,
+		IsActive:       true,
+		Tags:           tags,
+	}
+
+	switch website.Type() {
+	case models.CheckTypeHTTP:
+		if website.Request, err = parseRequestOptions(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if website.Assertions, err = parseAssertions(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if website.Content, err = parseContentOptions(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case models.CheckTypeHeartbeat:
+		if gracePeriod := r.FormValue("grace_period"); gracePeriod != "" {
+			minutes, err := strconv.Atoi(gracePeriod)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid grace period %q", gracePeriod), http.StatusBadRequest)
+				return
+			}
+			website.GracePeriod = minutes * 60
+		}
+	case models.CheckTypeDNS:
+		website.DNS = models.DNSOptions{
+			RecordType: strings.ToUpper(r.FormValue("dns_record_type")),
+			Expected:   strings.TrimSpace(r.FormValue("dns_expected")),
+		}
+	}
+
+	if website.Confirmation, err = parseConfirmation(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if website.ChannelIDs, err = parseChannelIDs(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if website.Alerts, err = parseAlertPolicy(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := website.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Add to database (you'll need to implement this method)
-	err = h.server.CreateWebsite(website)
-	if err != nil {
+	if _, err := h.server.CreateWebsite(website); err != nil {
 		h.logger.Error("Failed to create website", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
@@ -137,6 +213,10 @@ func (h *APIHandler) DeleteWebsite(w http.ResponseWriter, r *http.Request) {
 
 	// Delete the website
 	err = h.server.DeleteWebsite(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to delete website", "website_id", id, "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -149,43 +229,136 @@ func (h *APIHandler) DeleteWebsite(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"success": true, "message": "Website deleted successfully"}`))
 }
 
-// GetDashboard returns the dashboard HTML for HTMX
-func (h *APIHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
-	websites, err := h.server.GetActiveWebsites()
+// UpdateWebsite edits a website from the edit modal's form. Only the fields
+// the form sends are changed, and the website's history is kept.
+func (h *APIHandler) UpdateWebsite(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("Failed to parse website ID", "error", err)
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.server.GetWebsiteByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		h.logger.Error("Failed to get active websites", "error", err)
+		h.logger.Error("Failed to get website by ID", "website_id", id, "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	// Convert to DashboardWebsite for the web interface
-	dashboardWebsites := make([]models.DashboardWebsite, len(websites))
-	for i, website := range websites {
-		// Get the latest status for this website
-		status, err := h.server.GetLastWebsiteStatus(website.ID)
-		if err != nil {
-			h.logger.Error("Failed to get website status", "website_id", website.ID, "error", err)
-			// Continue with unknown status
-			dashboardWebsites[i] = models.DashboardWebsite{
-				Website:   website,
-				Status:    "unknown",
-				CheckedAt: nil,
-			}
-			continue
-		}
+	if err := r.ParseForm(); err != nil {
+		h.logger.Error("Failed to parse form", "error", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
 
-		dashboardWebsites[i] = models.DashboardWebsite{
-			Website:   website,
-			Status:    status.Status,
-			CheckedAt: &status.CheckedAt,
-		}
+	input, err := parseWebsiteEdit(r, models.NewWebsiteInput(*current))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	website, err := input.Apply(*current)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Render the new card format for HTMX
-	for _, website := range dashboardWebsites {
-		component := uptime.UptimeWebsiteCard(website)
-		component.Render(r.Context(), w)
+	if err := h.server.UpdateWebsite(website); err != nil {
+		h.logger.Error("Failed to update website", "website_id", id, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
 	}
+
+	// Reload the page so the card or detail view shows the changes
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Website updated successfully"}`))
+}
+
+// PauseWebsite stops monitoring a website without touching its history and
+// returns its updated dashboard card
+func (h *APIHandler) PauseWebsite(w http.ResponseWriter, r *http.Request) {
+	h.setWebsiteActive(w, r, false)
+}
+
+// ResumeWebsite resumes monitoring a paused website and returns its updated
+// dashboard card
+func (h *APIHandler) ResumeWebsite(w http.ResponseWriter, r *http.Request) {
+	h.setWebsiteActive(w, r, true)
+}
+
+func (h *APIHandler) setWebsiteActive(w http.ResponseWriter, r *http.Request, active bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("Failed to parse website ID", "error", err)
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.SetWebsiteActive(id, active)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to pause or resume website", "website_id", id, "active", active, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	website, err := h.server.GetWebsiteByID(id)
+	if err != nil {
+		h.logger.Error("Failed to get website by ID", "website_id", id, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.UptimeWebsiteCard(dashboardWebsite(h.server, h.logger, *website))
+	component.Render(r.Context(), w)
+}
+
+// GetWebsiteCard returns a website's dashboard card for HTMX, so live
+// updates can refresh it in place
+func (h *APIHandler) GetWebsiteCard(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("Failed to parse website ID", "error", err)
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	website, err := h.server.GetWebsiteByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get website by ID", "website_id", id, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.UptimeWebsiteCard(dashboardWebsite(h.server, h.logger, *website))
+	component.Render(r.Context(), w)
+}
+
+// GetDashboard returns the dashboard's website groups for HTMX, filtered
+// and grouped as the query asks
+func (h *APIHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	view, err := dashboardView(h.server, h.logger, r)
+	if err != nil {
+		h.logger.Error("Failed to get websites", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.WebsiteGroups(view)
+	component.Render(r.Context(), w)
 }
 
 func (h *APIHandler) CheckWebsite(w http.ResponseWriter, r *http.Request) {
@@ -206,7 +379,11 @@ func (h *APIHandler) CheckWebsite(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Perform the check
-	err = h.server.CheckWebsite(*website)
+	err = h.server.CheckWebsite(r.Context(), *website)
+	if errors.Is(err, models.ErrWebsitePaused) {
+		http.Error(w, "Website is paused", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to check website", "website_id", id, "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -221,14 +398,217 @@ func (h *APIHandler) CheckWebsite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create dashboard website with updated status
+	// Create dashboard website with updated status. Heartbeats that have
+	// never pinged have no status yet.
 	dashboardWebsite := models.DashboardWebsite{
-		Website:   *website,
-		Status:    status.Status,
-		CheckedAt: &status.CheckedAt,
+		Website: *website,
+		Status:  "unknown",
+	}
+	if status != nil {
+		dashboardWebsite.Status = status.Status
+		dashboardWebsite.CheckedAt = &status.CheckedAt
+		dashboardWebsite.Message = statusMessage(status)
 	}
 
 	// Render the updated card
 	component := uptime.UptimeWebsiteCard(dashboardWebsite)
 	component.Render(r.Context(), w)
 }
+
+// statusMessage returns the error behind a check that isn't fully up, e.g.
+// the failure a pending check is waiting to confirm
+func statusMessage(status *models.WebsiteStatus) string {
+	if status.Status == models.StatusUp {
+		return ""
+	}
+	return status.Error
+}
+
+// checkTarget builds the website target from the fields of the form for
+// the chosen check type
+func checkTarget(r *http.Request, checkType string) string {
+	switch checkType {
+	case models.CheckTypeTCP, models.CheckTypeTLS:
+		host := strings.TrimSpace(r.FormValue(checkType + "_host"))
+		port := strings.TrimSpace(r.FormValue(checkType + "_port"))
+		if host == "" || port == "" {
+			return host
+		}
+		return net.JoinHostPort(host, port)
+	case models.CheckTypeDNS:
+		return strings.TrimSpace(r.FormValue("dns_host"))
+	default:
+		return strings.TrimSpace(r.FormValue("url"))
+	}
+}
+
+// parseWebsiteEdit applies the fields the edit modal's form sends to a
+// website's input, leaving the rest as they are
+func parseWebsiteEdit(r *http.Request, input models.WebsiteInput) (models.WebsiteInput, error) {
+	form := r.PostForm
+	if form.Has("name") {
+		input.Name = form.Get("name")
+	}
+	if form.Has("url") {
+		input.URL = form.Get("url")
+	}
+	if form.Has("check_interval") {
+		raw := strings.TrimSpace(form.Get("check_interval"))
+		interval, err := strconv.Atoi(raw)
+		if err != nil {
+			return input, fmt.Errorf("invalid check interval %q", raw)
+		}
+		input.CheckInterval = interval
+	}
+	if form.Has("grace_period") {
+		raw := strings.TrimSpace(form.Get("grace_period"))
+		minutes, err := strconv.Atoi(raw)
+		if err != nil {
+			return input, fmt.Errorf("invalid grace period %q", raw)
+		}
+		input.GracePeriod = minutes * 60
+	}
+	if form.Has("tags") {
+		tags, err := models.ParseTags(form.Get("tags"))
+		if err != nil {
+			return input, err
+		}
+		input.Tags = tags
+	}
+	// An unchecked checkbox isn't sent, so the content fields are read
+	// whenever the form has the ignore lists, which always are
+	if form.Has("content_ignore_selectors") {
+		content, err := parseContentOptions(r)
+		if err != nil {
+			return input, err
+		}
+		input.Content = content
+	}
+	return input, nil
+}
+
+// parseRequestOptions reads the request fields of the add-site form
+func parseRequestOptions(r *http.Request) (models.RequestOptions, error) {
+	options := models.DefaultRequestOptions()
+
+	if method := strings.TrimSpace(r.FormValue("request_method")); method != "" {
+		options.Method = strings.ToUpper(method)
+	}
+	options.Headers = parseHeaderLines(r.FormValue("request_headers"))
+	options.Body = r.FormValue("request_body")
+	options.AuthType = r.FormValue("auth_type")
+	options.AuthUsername = strings.TrimSpace(r.FormValue("auth_username"))
+	options.AuthSecret = r.FormValue("auth_secret")
+	options.UserAgent = strings.TrimSpace(r.FormValue("user_agent"))
+	options.FollowRedirects = r.FormValue("no_follow_redirects") == ""
+
+	if err := options.Validate(); err != nil {
+		return options, err
+	}
+
+	return options, nil
+}
+
+// parseAssertions reads the assertion fields of the add-site form
+func parseAssertions(r *http.Request) (models.Assertions, error) {
+	assertions := models.Assertions{
+		StatusCodes:     strings.TrimSpace(r.FormValue("status_codes")),
+		BodyContains:    r.FormValue("body_contains"),
+		BodyNotContains: r.FormValue("body_not_contains"),
+		BodyRegex:       r.FormValue("body_regex") != "",
+		Headers:         parseHeaderLines(r.FormValue("required_headers")),
+	}
+
+	if maxResponseTime := r.FormValue("max_response_time"); maxResponseTime != "" {
+		ms, err := strconv.Atoi(maxResponseTime)
+		if err != nil {
+			return assertions, fmt.Errorf("invalid max response time %q", maxResponseTime)
+		}
+		assertions.MaxResponseTime = ms
+	}
+
+	if err := assertions.Validate(); err != nil {
+		return assertions, err
+	}
+
+	return assertions, nil
+}
+
+// parseContentOptions reads the content monitoring fields of the add-site
+// and edit forms, which list ignored selectors and patterns one per line
+func parseContentOptions(r *http.Request) (models.ContentOptions, error) {
+	options := models.ContentOptions{
+		Enabled:         r.FormValue("content_monitoring") != "",
+		IgnoreSelectors: strings.Split(r.FormValue("content_ignore_selectors"), "\n"),
+		IgnorePatterns:  strings.Split(r.FormValue("content_ignore_patterns"), "\n"),
+	}.Normalize()
+
+	if err := options.Validate(); err != nil {
+		return options, err
+	}
+
+	return options, nil
+}
+
+// parseConfirmation reads the retry and threshold fields of the add-site
+// form. The retry delay is entered in seconds and stored in milliseconds.
+func parseConfirmation(r *http.Request) (models.Confirmation, error) {
+	confirmation := models.Confirmation{FailureThreshold: 1, RecoveryThreshold: 1}
+
+	fields := []struct {
+		name  string
+		label string
+		value *int
+	}{
+		{"retries", "retries", &confirmation.Retries},
+		{"failure_threshold", "failure threshold", &confirmation.FailureThreshold},
+		{"recovery_threshold", "recovery threshold", &confirmation.RecoveryThreshold},
+	}
+	for _, field := range fields {
+		raw := strings.TrimSpace(r.FormValue(field.name))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return confirmation, fmt.Errorf("invalid %s %q", field.label, raw)
+		}
+		*field.value = n
+	}
+
+	if retryDelay := strings.TrimSpace(r.FormValue("retry_delay")); retryDelay != "" {
+		seconds, err := strconv.ParseFloat(retryDelay, 64)
+		if err != nil || seconds < 0 {
+			return confirmation, fmt.Errorf("invalid retry delay %q", retryDelay)
+		}
+		confirmation.RetryDelay = int(seconds * 1000)
+	}
+
+	if err := confirmation.Validate(); err != nil {
+		return confirmation, err
+	}
+
+	return confirmation, nil
+}
+
+// parseHeaderLines parses "Name: value" lines into a header map
+func parseHeaderLines(text string) map[string]string {
+	headers := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/views/uptime"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListChannels returns every notification channel
+func (h *APIHandler) ListChannels(w http.ResponseWriter, r *http.Request) {
+	channels, err := h.server.GetNotificationChannels()
+	if err != nil {
+		h.logger.Error("Failed to get notification channels", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"channels": channels})
+}
+
+// CreateChannel stores a notification channel from the channel form
+func (h *APIHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	channel := parseChannelForm(r)
+	if err := channel.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.server.CreateNotificationChannel(channel); err != nil {
+		h.logger.Error("Failed to create notification channel", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.renderChannelList(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Channel added successfully"}`))
+}
+
+// UpdateChannel replaces a notification channel's settings with the channel
+// form. A URL or token sent back redacted or left blank keeps the stored one.
+func (h *APIHandler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
+	channelID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.server.GetNotificationChannel(channelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Channel not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get notification channel", "channel_id", channelID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	channel := parseChannelForm(r)
+	channel.ID = channelID
+	channel.KeepSecrets(*current)
+	if err := channel.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.UpdateNotificationChannel(channel)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Channel not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update notification channel", "channel_id", channelID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.renderChannelList(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Channel updated successfully"}`))
+}
+
+// DeleteChannel removes a notification channel from every website
+func (h *APIHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	channelID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.DeleteNotificationChannel(channelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Channel not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete notification channel", "channel_id", channelID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Channel deleted successfully"}`))
+}
+
+// TestChannel sends a test notification through a channel
+func (h *APIHandler) TestChannel(w http.ResponseWriter, r *http.Request) {
+	channelID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.TestNotificationChannel(r.Context(), channelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Channel not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Warn("Test notification failed", "channel_id", channelID, "error", err)
+		http.Error(w, "Test notification failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Test notification sent"}`))
+}
+
+// SetWebsiteChannels replaces the channels a website alerts with the
+// channels form values
+func (h *APIHandler) SetWebsiteChannels(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	channelIDs, err := parseChannelIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.server.SetWebsiteChannels(websiteID, channelIDs); err != nil {
+		h.logger.Error("Failed to set website channels", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "channel_ids": channelIDs})
+}
+
+// renderChannelList renders the channel list of the channels page
+func (h *APIHandler) renderChannelList(w http.ResponseWriter, r *http.Request) {
+	channels, err := h.server.GetNotificationChannels()
+	if err != nil {
+		h.logger.Error("Failed to get notification channels", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.ChannelList(channels)
+	component.Render(r.Context(), w)
+}
+
+// parseChannelForm reads a notification channel from the channel form,
+// dropping the settings its type doesn't use
+func parseChannelForm(r *http.Request) models.NotificationChannel {
+	channel := models.NotificationChannel{
+		Name:      strings.TrimSpace(r.FormValue("name")),
+		Type:      r.FormValue("channel_type"),
+		URL:       strings.TrimSpace(r.FormValue("channel_url")),
+		Token:     strings.TrimSpace(r.FormValue("channel_token")),
+		Recipient: strings.TrimSpace(r.FormValue("channel_recipient")),
+	}
+	if channel.Type == models.ChannelEmail {
+		channel.URL, channel.Token = "", ""
+	} else {
+		channel.Recipient = ""
+	}
+	return channel
+}
+
+// parseChannelIDs reads the selected channels of a website form
+func parseChannelIDs(r *http.Request) ([]int, error) {
+	var channelIDs []int
+	for _, value := range r.Form["channels"] {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel ID %q", value)
+		}
+		channelIDs = append(channelIDs, id)
+	}
+	return channelIDs, nil
+}
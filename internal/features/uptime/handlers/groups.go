@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"the-ark/internal/features/uptime/models"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RunGroupAction pauses, resumes, checks or deletes every website with a
+// tag for the dashboard's group buttons, then has HTMX reload the page
+func (h *APIHandler) RunGroupAction(w http.ResponseWriter, r *http.Request) {
+	tag, action := chi.URLParam(r, "tag"), chi.URLParam(r, "action")
+	if err := models.ValidateGroupAction(action); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	affected, err := h.server.RunGroupAction(tag, action)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to run group action", "tag", tag, "action", action, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "affected": affected})
+}
+
+// checkWebsiteLimit returns why no more websites may be added, or an empty
+// string while below the configured limit
+func checkWebsiteLimit(server ServerInterface) (string, error) {
+	limit := server.MaxWebsites()
+	if limit == 0 {
+		return "", nil
+	}
+
+	websites, err := server.GetWebsites()
+	if err != nil {
+		return "", err
+	}
+	if len(websites) >= limit {
+		return fmt.Sprintf("Maximum of %d sites allowed", limit), nil
+	}
+	return "", nil
+}
+
+// dashboardView gathers the websites the dashboard shows with their latest
+// status, filtered by the tag query parameter and grouped by tag when the
+// group parameter is "tag"
+func dashboardView(server ServerInterface, logger *slog.Logger, r *http.Request) (models.DashboardView, error) {
+	websites, err := server.GetWebsites()
+	if err != nil {
+		return models.DashboardView{}, err
+	}
+	tags, err := server.GetTags()
+	if err != nil {
+		return models.DashboardView{}, err
+	}
+
+	query := r.URL.Query()
+	view := models.DashboardView{
+		Total:   len(websites),
+		Limit:   server.MaxWebsites(),
+		Tags:    tags,
+		Tag:     query.Get("tag"),
+		Grouped: query.Get("group") == "tag",
+	}
+
+	for _, website := range websites {
+		if view.Tag != "" && !website.HasTag(view.Tag) {
+			continue
+		}
+		view.Websites = append(view.Websites, dashboardWebsite(server, logger, website))
+	}
+	return view, nil
+}
+
+// dashboardWebsite pairs a website with its latest status, which is unknown
+// for websites that haven't been checked or whose status can't be read
+func dashboardWebsite(server ServerInterface, logger *slog.Logger, website models.Website) models.DashboardWebsite {
+	dashboardWebsite := models.DashboardWebsite{
+		Website: website,
+		Status:  "unknown",
+	}
+
+	status, err := server.GetLastWebsiteStatus(website.ID)
+	if err != nil {
+		logger.Error("Failed to get website status", "website_id", website.ID, "error", err)
+		return dashboardWebsite
+	}
+	if status != nil {
+		dashboardWebsite.Status = status.Status
+		dashboardWebsite.CheckedAt = &status.CheckedAt
+		dashboardWebsite.Message = statusMessage(status)
+	}
+	return dashboardWebsite
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxPingMessageBytes caps how much of a failure ping's body is stored
+const maxPingMessageBytes = 1024
+
+// Ping records a successful heartbeat. Jobs call it without a session, so
+// the secret token in the path is the only credential.
+func (h *APIHandler) Ping(w http.ResponseWriter, r *http.Request) {
+	h.recordPing(w, r, true)
+}
+
+// PingFailure records a heartbeat from a job that ran but failed. The
+// request body or the msg query parameter is stored as the failure reason.
+func (h *APIHandler) PingFailure(w http.ResponseWriter, r *http.Request) {
+	h.recordPing(w, r, false)
+}
+
+func (h *APIHandler) recordPing(w http.ResponseWriter, r *http.Request, isUp bool) {
+	website, err := h.server.GetWebsiteByHeartbeatToken(chi.URLParam(r, "token"))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && website.Type() != models.CheckTypeHeartbeat) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get heartbeat website", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	var message string
+	if !isUp {
+		message = pingMessage(r)
+	}
+
+	if err := h.server.RecordHeartbeat(*website, isUp, message); err != nil {
+		h.logger.Error("Failed to record heartbeat", "website_id", website.ID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK\n"))
+}
+
+// pingMessage reads the failure reason sent with a failure ping
+func pingMessage(r *http.Request) string {
+	if message := r.URL.Query().Get("msg"); message != "" {
+		return truncate(message, maxPingMessageBytes)
+	}
+
+	if r.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPingMessageBytes))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"the-ark/internal/auth"
+	"the-ark/views/uptime"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListIncidents returns a website's most recent incidents
+func (h *APIHandler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 && n <= 500 {
+			limit = n
+		}
+	}
+
+	incidents, err := h.server.GetIncidents(websiteID, limit)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"incidents": incidents})
+}
+
+// GetIncident returns an incident with its timeline
+func (h *APIHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	incidentID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	h.respondWithIncident(w, r, incidentID)
+}
+
+// AcknowledgeIncident marks an incident as being worked on by the current user
+func (h *APIHandler) AcknowledgeIncident(w http.ResponseWriter, r *http.Request) {
+	h.updateIncident(w, r, func(incidentID int, author string) error {
+		return h.server.AcknowledgeIncident(incidentID, author)
+	})
+}
+
+// SetIncidentRootCause records the root_cause form value on an incident
+func (h *APIHandler) SetIncidentRootCause(w http.ResponseWriter, r *http.Request) {
+	rootCause := strings.TrimSpace(r.FormValue("root_cause"))
+	if rootCause == "" {
+		http.Error(w, "Root cause is required", http.StatusBadRequest)
+		return
+	}
+
+	h.updateIncident(w, r, func(incidentID int, author string) error {
+		return h.server.SetIncidentRootCause(incidentID, rootCause, author)
+	})
+}
+
+// AddIncidentComment adds the message form value to an incident's timeline
+func (h *APIHandler) AddIncidentComment(w http.ResponseWriter, r *http.Request) {
+	message := strings.TrimSpace(r.FormValue("message"))
+	if message == "" {
+		http.Error(w, "Comment is required", http.StatusBadRequest)
+		return
+	}
+
+	h.updateIncident(w, r, func(incidentID int, author string) error {
+		return h.server.AddIncidentComment(incidentID, message, author)
+	})
+}
+
+// updateIncident applies an update to the incident in the URL on behalf of
+// the current user and responds with the updated incident
+func (h *APIHandler) updateIncident(w http.ResponseWriter, r *http.Request, update func(incidentID int, author string) error) {
+	incidentID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	err = update(incidentID, incidentAuthor(r))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Incident not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update incident", "incident_id", incidentID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondWithIncident(w, r, incidentID)
+}
+
+// respondWithIncident renders the incident for HTMX requests from the
+// website detail page and returns JSON otherwise
+func (h *APIHandler) respondWithIncident(w http.ResponseWriter, r *http.Request, incidentID int) {
+	incident, err := h.server.GetIncident(incidentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Incident not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get incident", "incident_id", incidentID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		component := uptime.IncidentEntry(*incident)
+		component.Render(r.Context(), w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"incident": incident})
+}
+
+// incidentAuthor names the current user in incident timelines
+func incidentAuthor(r *http.Request) string {
+	user := auth.GetUserFromContext(r)
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Email
+}
@@ -1,13 +1,60 @@
 package handlers
 
-import "the-ark/internal/features/uptime/models"
+import (
+	"context"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
 
 type ServerInterface interface {
-	GetActiveWebsites() ([]models.Website, error)
+	GetWebsites() ([]models.Website, error)
+	MaxWebsites() int
+	GetTags() ([]models.Tag, error)
+	RunGroupAction(tag, action string) (int, error)
 	GetWebsiteByID(websiteID int) (*models.Website, error)
 	GetLastWebsiteStatus(websiteID int) (*models.WebsiteStatus, error)
-	CheckWebsite(website models.Website) error
+	CheckWebsite(ctx context.Context, website models.Website) error
 	GetWebsiteDetailData(websiteID int) (*models.WebsiteDetailData, error)
-	CreateWebsite(website models.Website) error
+	CreateWebsite(website models.Website) (int, error)
+	UpdateWebsite(website models.Website) error
+	SetWebsiteActive(websiteID int, active bool) error
 	DeleteWebsite(websiteID int) error
+	GetWebsiteByHeartbeatToken(token string) (*models.Website, error)
+	RecordHeartbeat(website models.Website, isUp bool, message string) error
+	RegisterProbe(token, name, currentKey string) (*models.ProbeRegistration, error)
+	AuthenticateProbe(key string) (*models.Probe, error)
+	GetProbeChecks() ([]models.ProbeCheck, error)
+	StoreProbeResults(probeID int, results []models.ProbeResult) (int, error)
+	GetProbes() ([]models.Probe, error)
+	DeleteProbe(probeID int) error
+	GetIncidents(websiteID int, limit int) ([]models.Incident, error)
+	GetIncidentPage(websiteID int, limit, offset int) ([]models.Incident, int, error)
+	GetIncident(incidentID int) (*models.Incident, error)
+	AcknowledgeIncident(incidentID int, author string) error
+	SetIncidentRootCause(incidentID int, rootCause, author string) error
+	AddIncidentComment(incidentID int, message, author string) error
+	GetNotificationChannels() ([]models.NotificationChannel, error)
+	GetNotificationChannel(channelID int) (*models.NotificationChannel, error)
+	CreateNotificationChannel(channel models.NotificationChannel) error
+	UpdateNotificationChannel(channel models.NotificationChannel) error
+	DeleteNotificationChannel(channelID int) error
+	TestNotificationChannel(ctx context.Context, channelID int) error
+	SetWebsiteChannels(websiteID int, channelIDs []int) error
+	SetWebsiteAlerts(websiteID int, channelIDs []int, policy models.AlertPolicy) error
+	GetAlertHistory(websiteID int, limit int) ([]models.AlertRecord, error)
+	GetWebsiteMetrics(websiteID int, period models.MetricsPeriod, resolution string) (*models.WebsiteMetrics, error)
+	GetUptimeHistory(websiteID int, limit int) ([]models.WebsiteStatus, error)
+	GetCheckPage(websiteID int, limit, offset int) ([]models.WebsiteStatus, int, error)
+	SLATarget() float64
+	GetSLAReport(month time.Time, target float64) (*models.SLAReport, error)
+	GetMaintenanceWindows() ([]models.MaintenanceWindow, error)
+	GetWebsiteMaintenanceWindows(websiteID int) ([]models.MaintenanceWindow, error)
+	CreateMaintenanceWindow(window models.MaintenanceWindow) error
+	DeleteMaintenanceWindow(windowID int) error
+	GetStatusPages() ([]models.StatusPage, error)
+	CreateStatusPage(page models.StatusPage) error
+	DeleteStatusPage(pageID int) error
+	AddStatusComponent(component models.StatusComponent) error
+	DeleteStatusComponent(componentID int) error
+	GetPublicStatusPage(slug string) (*models.PublicStatusPage, error)
 }
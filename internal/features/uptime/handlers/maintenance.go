@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/views/uptime"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maintenanceTimeLayout is the format of datetime-local form inputs
+const maintenanceTimeLayout = "2006-01-02T15:04"
+
+// ListMaintenance returns every maintenance window
+func (h *APIHandler) ListMaintenance(w http.ResponseWriter, r *http.Request) {
+	windows, err := h.server.GetMaintenanceWindows()
+	if err != nil {
+		h.logger.Error("Failed to get maintenance windows", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"windows": windows})
+}
+
+// ListWebsiteMaintenance returns the maintenance windows covering a website,
+// including global windows
+func (h *APIHandler) ListWebsiteMaintenance(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	windows, err := h.server.GetWebsiteMaintenanceWindows(websiteID)
+	if err != nil {
+		h.logger.Error("Failed to get maintenance windows", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"windows": windows})
+}
+
+// CreateMaintenance stores a maintenance window from the maintenance form
+func (h *APIHandler) CreateMaintenance(w http.ResponseWriter, r *http.Request) {
+	window, err := parseMaintenanceWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !window.IsGlobal() {
+		if _, err := h.server.GetWebsiteByID(window.WebsiteID); errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Website not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			h.logger.Error("Failed to get website", "website_id", window.WebsiteID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := h.server.CreateMaintenanceWindow(window); err != nil {
+		h.logger.Error("Failed to create maintenance window", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.renderMaintenanceList(w, r, window.WebsiteID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Maintenance window added successfully"}`))
+}
+
+// DeleteMaintenance removes a maintenance window
+func (h *APIHandler) DeleteMaintenance(w http.ResponseWriter, r *http.Request) {
+	windowID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.DeleteMaintenanceWindow(windowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete maintenance window", "window_id", windowID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Maintenance window deleted successfully"}`))
+}
+
+// renderMaintenanceList renders the maintenance list of a website's detail
+// page
+func (h *APIHandler) renderMaintenanceList(w http.ResponseWriter, r *http.Request, websiteID int) {
+	windows, err := h.server.GetWebsiteMaintenanceWindows(websiteID)
+	if err != nil {
+		h.logger.Error("Failed to get maintenance windows", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.MaintenanceList(windows, time.Now())
+	component.Render(r.Context(), w)
+}
+
+// parseMaintenanceWindow reads the maintenance form. A schedule makes the
+// window recurring, otherwise it runs once between the start and end times,
+// which are read in the window's timezone.
+func parseMaintenanceWindow(r *http.Request) (models.MaintenanceWindow, error) {
+	window := models.MaintenanceWindow{
+		Name:     strings.TrimSpace(r.FormValue("name")),
+		Schedule: strings.Join(strings.Fields(r.FormValue("schedule")), " "),
+		Timezone: strings.TrimSpace(r.FormValue("timezone")),
+	}
+
+	if raw := strings.TrimSpace(r.FormValue("website_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id < 0 {
+			return window, fmt.Errorf("invalid website ID %q", raw)
+		}
+		window.WebsiteID = id
+	}
+
+	loc := time.Local
+	if window.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(window.Timezone); err != nil {
+			return window, fmt.Errorf("invalid maintenance timezone %q", window.Timezone)
+		}
+	}
+
+	if window.IsRecurring() {
+		raw := strings.TrimSpace(r.FormValue("duration"))
+		duration, err := strconv.Atoi(raw)
+		if err != nil {
+			return window, fmt.Errorf("invalid maintenance duration %q", raw)
+		}
+		window.Duration = duration
+	} else {
+		for _, field := range []struct {
+			name  string
+			value **time.Time
+		}{
+			{"starts_at", &window.StartsAt},
+			{"ends_at", &window.EndsAt},
+		} {
+			raw := strings.TrimSpace(r.FormValue(field.name))
+			if raw == "" {
+				continue
+			}
+			t, err := parseMaintenanceTime(raw, loc)
+			if err != nil {
+				return window, err
+			}
+			*field.value = &t
+		}
+	}
+
+	if err := window.Validate(); err != nil {
+		return window, err
+	}
+	return window, nil
+}
+
+// parseMaintenanceTime accepts RFC 3339 times from API clients and
+// datetime-local values from the form
+func parseMaintenanceTime(raw string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(maintenanceTimeLayout, raw, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid maintenance time %q", raw)
+	}
+	return t, nil
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"the-ark/internal/features/uptime/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxCheckHistory bounds the checks a single request may return
+const maxCheckHistory = 500
+
+// GetWebsiteMetrics returns a website's latency percentiles, status code
+// breakdown and uptime heatmap for the website detail charts. The period
+// defaults to 24h and the resolution to the period's own.
+func (h *APIHandler) GetWebsiteMetrics(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	periodName := r.URL.Query().Get("period")
+	if periodName == "" {
+		periodName = models.MetricsPeriods[0].Name
+	}
+	period, err := models.GetMetricsPeriod(periodName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resolution := r.URL.Query().Get("resolution")
+	if resolution == "" {
+		resolution = period.Resolution
+	}
+	if err := models.ValidateResolution(resolution); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metrics, err := h.server.GetWebsiteMetrics(websiteID, period, resolution)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get website metrics", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(metrics)
+}
+
+// GetCheckHistory returns a website's most recent checks, with the timing
+// breakdown of HTTP checks
+func (h *APIHandler) GetCheckHistory(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, maxCheckHistory)
+	}
+
+	checks, err := h.server.GetUptimeHistory(websiteID, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get check history", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if checks == nil {
+		checks = []models.WebsiteStatus{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"checks": checks})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"the-ark/internal/core"
+	"the-ark/internal/features/uptime/models"
+)
+
+// RegisterProbe registers a probe agent under the location name in the
+// body. Agents call it without a session, authenticating with the
+// registration token as a bearer token, and get back the key for their
+// later requests. Registering an existing name again takes the probe's
+// current key in the body.
+func (h *V1Handler) RegisterProbe(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string `json:"name"`
+		Key  string `json:"key"`
+	}
+	if err := decodeJSON(r, &input); err != nil {
+		h.writeError(w, "Invalid request body", err)
+		return
+	}
+
+	name, err := models.NormalizeProbeName(input.Name)
+	if err != nil {
+		h.writeError(w, "Invalid probe", core.NewValidationError(err.Error(), err))
+		return
+	}
+
+	registration, err := h.server.RegisterProbe(bearerToken(r), name, input.Key)
+	if errors.Is(err, models.ErrProbeNameTaken) {
+		err = core.NewForbiddenError("Probe name is already registered, delete the probe to register it again", err)
+	}
+	if err != nil {
+		h.writeError(w, "Failed to register probe", probeUnauthorized(err))
+		return
+	}
+
+	h.logger.Info("Probe registered", "probe", registration.Name)
+	writeData(w, http.StatusCreated, registration)
+}
+
+// ListProbeChecks returns the checks the probe agent authenticated by its
+// key runs, including the secrets the checks need
+func (h *V1Handler) ListProbeChecks(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.probe(w, r); !ok {
+		return
+	}
+
+	checks, err := h.server.GetProbeChecks()
+	if err != nil {
+		h.writeError(w, "Failed to get probe checks", err)
+		return
+	}
+
+	writeData(w, http.StatusOK, checks)
+}
+
+// ReportProbeResults stores the results a probe agent sends as
+// {"results": [...]} and returns how many were kept. Results for websites
+// that no longer exist are dropped.
+func (h *V1Handler) ReportProbeResults(w http.ResponseWriter, r *http.Request) {
+	probe, ok := h.probe(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Results []models.ProbeResult `json:"results"`
+	}
+	if err := decodeJSON(r, &input); err != nil {
+		h.writeError(w, "Invalid request body", err)
+		return
+	}
+
+	stored, err := h.server.StoreProbeResults(probe.ID, input.Results)
+	if err != nil {
+		h.writeError(w, "Failed to store probe results", err)
+		return
+	}
+
+	writeData(w, http.StatusOK, map[string]interface{}{"stored": stored})
+}
+
+// ListProbes returns every registered probe agent
+func (h *V1Handler) ListProbes(w http.ResponseWriter, r *http.Request) {
+	probes, err := h.server.GetProbes()
+	if err != nil {
+		h.writeError(w, "Failed to get probes", err)
+		return
+	}
+	if probes == nil {
+		probes = []models.Probe{}
+	}
+
+	writeData(w, http.StatusOK, probes)
+}
+
+// DeleteProbe removes a probe agent and its results. The agent's key stops
+// working until it registers again.
+func (h *V1Handler) DeleteProbe(w http.ResponseWriter, r *http.Request) {
+	probeID, ok := h.id(w, r, "probe")
+	if !ok {
+		return
+	}
+
+	if err := h.server.DeleteProbe(probeID); err != nil {
+		h.writeError(w, "Failed to delete probe", notFound(err, "Probe not found"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// probe authenticates a probe agent by the key in its bearer token, writing
+// an error response if it can't
+func (h *V1Handler) probe(w http.ResponseWriter, r *http.Request) (*models.Probe, bool) {
+	key := bearerToken(r)
+	if key == "" {
+		h.writeError(w, "Missing probe key", core.NewUnauthorizedError("Missing probe key", nil))
+		return nil, false
+	}
+
+	probe, err := h.server.AuthenticateProbe(key)
+	if err != nil {
+		h.writeError(w, "Failed to authenticate probe", probeUnauthorized(err))
+		return nil, false
+	}
+	return probe, true
+}
+
+// bearerToken returns the token of a request's bearer authorization header
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// probeUnauthorized turns models.ErrProbeUnauthorized into an unauthorized
+// error
+func probeUnauthorized(err error) error {
+	if errors.Is(err, models.ErrProbeUnauthorized) {
+		return core.NewUnauthorizedError("Invalid probe credentials", err)
+	}
+	return err
+}
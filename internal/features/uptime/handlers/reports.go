@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/views/uptime"
+	"time"
+)
+
+// reportMonths is how many months the reports page offers
+const reportMonths = 12
+
+// GetSLAReport returns the SLA report of a month as HTML, CSV or JSON. The
+// month defaults to the previous one, the format to JSON and the target to
+// the configured one. CSV reports are served as a download.
+func (h *APIHandler) GetSLAReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	month := models.ReportMonth(time.Now()).AddDate(0, -1, 0)
+	if raw := query.Get("month"); raw != "" {
+		parsed, err := models.ParseReportMonth(raw)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		month = parsed
+	}
+
+	var target float64
+	if raw := query.Get("target"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			http.Error(w, "Invalid SLA target", http.StatusBadRequest)
+			return
+		}
+		if err := models.ValidateSLATarget(parsed); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		target = parsed
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "html" {
+		http.Error(w, fmt.Sprintf("unknown format %q, expected html, csv or json", format), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.server.GetSLAReport(month, target)
+	if err != nil {
+		h.logger.Error("Failed to get SLA report", "month", month.Format(models.ReportMonthLayout), "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	switch format {
+	case "html":
+		component := uptime.SLAReportDocument(*report)
+		component.Render(r.Context(), w)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"sla-report-%s.csv\"", report.Name()))
+		w.WriteHeader(http.StatusOK)
+		csv.NewWriter(w).WriteAll(report.CSV())
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(report)
+	}
+}
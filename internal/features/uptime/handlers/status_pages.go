@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/views/uptime"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// statusPageCacheAge is how long, in seconds, clients and proxies may cache
+// public status pages
+const statusPageCacheAge = 60
+
+// ListStatusPages returns every status page with its components
+func (h *APIHandler) ListStatusPages(w http.ResponseWriter, r *http.Request) {
+	pages, err := h.server.GetStatusPages()
+	if err != nil {
+		h.logger.Error("Failed to get status pages", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"pages": pages})
+}
+
+// CreateStatusPage stores a status page from the status page form. The slug
+// defaults to one derived from the title.
+func (h *APIHandler) CreateStatusPage(w http.ResponseWriter, r *http.Request) {
+	page := models.StatusPage{
+		Title:       strings.TrimSpace(r.FormValue("title")),
+		Slug:        strings.ToLower(strings.TrimSpace(r.FormValue("slug"))),
+		Description: strings.TrimSpace(r.FormValue("description")),
+	}
+	if page.Slug == "" {
+		page.Slug = models.Slugify(page.Title)
+	}
+	if err := page.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.server.CreateStatusPage(page); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			http.Error(w, fmt.Sprintf("The slug %q is already in use", page.Slug), http.StatusConflict)
+			return
+		}
+		h.logger.Error("Failed to create status page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.renderStatusPageList(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "slug": page.Slug})
+}
+
+// DeleteStatusPage removes a status page
+func (h *APIHandler) DeleteStatusPage(w http.ResponseWriter, r *http.Request) {
+	pageID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.DeleteStatusPage(pageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Status page not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete status page", "page_id", pageID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Status page deleted successfully"}`))
+}
+
+// AddStatusComponent adds a component grouping the selected websites to a
+// status page
+func (h *APIHandler) AddStatusComponent(w http.ResponseWriter, r *http.Request) {
+	pageID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	component := models.StatusComponent{
+		PageID: pageID,
+		Name:   strings.TrimSpace(r.FormValue("name")),
+	}
+	if component.Name == "" {
+		http.Error(w, "Component name is required", http.StatusBadRequest)
+		return
+	}
+	for _, value := range r.Form["websites"] {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid website ID %q", value), http.StatusBadRequest)
+			return
+		}
+		component.WebsiteIDs = append(component.WebsiteIDs, id)
+	}
+	if len(component.WebsiteIDs) == 0 {
+		http.Error(w, "Select at least one website", http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.AddStatusComponent(component)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Status page not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to add status component", "page_id", pageID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.renderStatusPageList(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Component added successfully"}`))
+}
+
+// DeleteStatusComponent removes a component from its status page
+func (h *APIHandler) DeleteStatusComponent(w http.ResponseWriter, r *http.Request) {
+	componentID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid component ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.DeleteStatusComponent(componentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Component not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete status component", "component_id", componentID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"success": true, "message": "Component deleted successfully"}`))
+}
+
+// PublicStatusPage renders a status page for customers. It is served without
+// authentication.
+func (h *APIHandler) PublicStatusPage(w http.ResponseWriter, r *http.Request) {
+	page, ok := h.publicStatusPage(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", statusPageCacheAge))
+	component := uptime.PublicStatusPage(*page)
+	component.Render(r.Context(), w)
+}
+
+// PublicStatusPageJSON serves a status page for programmatic consumers. It
+// is served without authentication.
+func (h *APIHandler) PublicStatusPageJSON(w http.ResponseWriter, r *http.Request) {
+	page, ok := h.publicStatusPage(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", statusPageCacheAge))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(page)
+}
+
+// publicStatusPage loads the status page named in the URL, writing an error
+// response if it can't
+func (h *APIHandler) publicStatusPage(w http.ResponseWriter, r *http.Request) (*models.PublicStatusPage, bool) {
+	slug := chi.URLParam(r, "slug")
+
+	page, err := h.server.GetPublicStatusPage(slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Status page not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("Failed to get status page", "slug", slug, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return page, true
+}
+
+// renderStatusPageList renders the page list of the status pages page
+func (h *APIHandler) renderStatusPageList(w http.ResponseWriter, r *http.Request) {
+	pages, err := h.server.GetStatusPages()
+	if err != nil {
+		h.logger.Error("Failed to get status pages", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	websites, err := h.server.GetWebsites()
+	if err != nil {
+		h.logger.Error("Failed to get websites", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.StatusPageList(pages, websites)
+	component.Render(r.Context(), w)
+}
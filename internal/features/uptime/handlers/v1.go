@@ -0,0 +1,528 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"the-ark/internal/core"
+	"the-ark/internal/features/uptime/models"
+
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Page sizes of the v1 list endpoints
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// maxRequestBody bounds the JSON bodies the v1 API accepts
+const maxRequestBody = 1 << 20
+
+// V1Handler serves the versioned JSON API under /api/v1/uptime. Successful
+// responses wrap their payload as {"success": true, "data": ...} and errors
+// use the core error envelope.
+type V1Handler struct {
+	logger *slog.Logger
+	server ServerInterface
+}
+
+func NewV1Handler(logger *slog.Logger, server ServerInterface) *V1Handler {
+	return &V1Handler{
+		logger: logger,
+		server: server,
+	}
+}
+
+// ListWebsites returns every website, or those with the tag query
+// parameter's tag
+func (h *V1Handler) ListWebsites(w http.ResponseWriter, r *http.Request) {
+	websites, err := h.server.GetWebsites()
+	if err != nil {
+		h.writeError(w, "Failed to get websites", err)
+		return
+	}
+
+	filtered := []models.Website{}
+	tag := r.URL.Query().Get("tag")
+	for _, website := range websites {
+		if tag == "" || website.HasTag(tag) {
+			filtered = append(filtered, website)
+		}
+	}
+
+	writeData(w, http.StatusOK, filtered)
+}
+
+// GetWebsite returns a website
+func (h *V1Handler) GetWebsite(w http.ResponseWriter, r *http.Request) {
+	website, ok := h.website(w, r)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, newWebsiteDetail(r, *website))
+}
+
+// CreateWebsite creates a website from a WebsiteInput body
+func (h *V1Handler) CreateWebsite(w http.ResponseWriter, r *http.Request) {
+	var input models.WebsiteInput
+	if err := decodeJSON(r, &input); err != nil {
+		h.writeError(w, "Invalid request body", err)
+		return
+	}
+
+	website, err := input.Apply(models.Website{IsActive: true})
+	if err != nil {
+		h.writeError(w, "Invalid website", core.NewValidationError(err.Error(), err))
+		return
+	}
+
+	limited, err := checkWebsiteLimit(h.server)
+	if err != nil {
+		h.writeError(w, "Failed to get websites", err)
+		return
+	}
+	if limited != "" {
+		h.writeError(w, "Too many websites", core.NewValidationError(limited, nil))
+		return
+	}
+
+	websiteID, err := h.server.CreateWebsite(website)
+	if err != nil {
+		h.writeError(w, "Failed to create website", err)
+		return
+	}
+
+	created, err := h.server.GetWebsiteByID(websiteID)
+	if err != nil {
+		h.writeError(w, "Failed to get created website", err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/uptime/websites/%d", websiteID))
+	writeData(w, http.StatusCreated, newWebsiteDetail(r, *created))
+}
+
+// ReplaceWebsite replaces a website's configuration with a WebsiteInput
+// body. Fields left out take their defaults.
+func (h *V1Handler) ReplaceWebsite(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.website(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.WebsiteInput
+	if err := decodeJSON(r, &input); err != nil {
+		h.writeError(w, "Invalid request body", err)
+		return
+	}
+
+	h.updateWebsite(w, r, *current, input)
+}
+
+// PatchWebsite updates part of a website's configuration with a JSON merge
+// patch (RFC 7396) of its WebsiteInput. Fields left out are unchanged and
+// null fields are reset to their defaults.
+func (h *V1Handler) PatchWebsite(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.website(w, r)
+	if !ok {
+		return
+	}
+
+	var patch map[string]any
+	if err := decodeJSON(r, &patch); err != nil {
+		h.writeError(w, "Invalid request body", err)
+		return
+	}
+
+	input, err := patchInput(models.NewWebsiteInput(*current), patch)
+	if err != nil {
+		h.writeError(w, "Invalid patch", err)
+		return
+	}
+
+	h.updateWebsite(w, r, *current, input)
+}
+
+// DeleteWebsite deletes a website with its history
+func (h *V1Handler) DeleteWebsite(w http.ResponseWriter, r *http.Request) {
+	websiteID, ok := h.id(w, r, "website")
+	if !ok {
+		return
+	}
+
+	if err := h.server.DeleteWebsite(websiteID); err != nil {
+		h.writeError(w, "Failed to delete website", notFound(err, "Website not found"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PauseWebsite stops monitoring a website, keeping its history
+func (h *V1Handler) PauseWebsite(w http.ResponseWriter, r *http.Request) {
+	h.setWebsiteActive(w, r, false)
+}
+
+// ResumeWebsite resumes monitoring a paused website
+func (h *V1Handler) ResumeWebsite(w http.ResponseWriter, r *http.Request) {
+	h.setWebsiteActive(w, r, true)
+}
+
+func (h *V1Handler) setWebsiteActive(w http.ResponseWriter, r *http.Request, active bool) {
+	websiteID, ok := h.id(w, r, "website")
+	if !ok {
+		return
+	}
+
+	if err := h.server.SetWebsiteActive(websiteID, active); err != nil {
+		h.writeError(w, "Failed to pause or resume website", notFound(err, "Website not found"))
+		return
+	}
+
+	website, err := h.server.GetWebsiteByID(websiteID)
+	if err != nil {
+		h.writeError(w, "Failed to get website", err)
+		return
+	}
+	writeData(w, http.StatusOK, website)
+}
+
+// CheckWebsite checks a website straight away and returns the result
+func (h *V1Handler) CheckWebsite(w http.ResponseWriter, r *http.Request) {
+	website, ok := h.website(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.server.CheckWebsite(r.Context(), *website)
+	if errors.Is(err, models.ErrWebsitePaused) {
+		err = core.NewValidationError("Website is paused", err)
+	}
+	if err != nil {
+		h.writeError(w, "Failed to check website", err)
+		return
+	}
+	status, err := h.server.GetLastWebsiteStatus(website.ID)
+	if err != nil {
+		h.writeError(w, "Failed to get website status", err)
+		return
+	}
+
+	writeData(w, http.StatusOK, status)
+}
+
+// ListChecks returns a page of a website's checks, newest first
+func (h *V1Handler) ListChecks(w http.ResponseWriter, r *http.Request) {
+	websiteID, ok := h.id(w, r, "website")
+	if !ok {
+		return
+	}
+	page, ok := h.page(w, r)
+	if !ok {
+		return
+	}
+
+	checks, total, err := h.server.GetCheckPage(websiteID, page.Limit, page.Offset)
+	if err != nil {
+		h.writeError(w, "Failed to get checks", notFound(err, "Website not found"))
+		return
+	}
+	if checks == nil {
+		checks = []models.WebsiteStatus{}
+	}
+
+	page.Total = total
+	writePage(w, checks, page)
+}
+
+// ListIncidents returns a page of a website's incidents, newest first
+func (h *V1Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	websiteID, ok := h.id(w, r, "website")
+	if !ok {
+		return
+	}
+	page, ok := h.page(w, r)
+	if !ok {
+		return
+	}
+
+	incidents, total, err := h.server.GetIncidentPage(websiteID, page.Limit, page.Offset)
+	if err != nil {
+		h.writeError(w, "Failed to get incidents", notFound(err, "Website not found"))
+		return
+	}
+	if incidents == nil {
+		incidents = []models.Incident{}
+	}
+
+	page.Total = total
+	writePage(w, incidents, page)
+}
+
+// GetIncident returns an incident with its timeline
+func (h *V1Handler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	incidentID, ok := h.id(w, r, "incident")
+	if !ok {
+		return
+	}
+
+	incident, err := h.server.GetIncident(incidentID)
+	if err != nil {
+		h.writeError(w, "Failed to get incident", notFound(err, "Incident not found"))
+		return
+	}
+
+	writeData(w, http.StatusOK, incident)
+}
+
+// ListTags returns every tag with how many websites have it
+func (h *V1Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.server.GetTags()
+	if err != nil {
+		h.writeError(w, "Failed to get tags", err)
+		return
+	}
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+
+	writeData(w, http.StatusOK, tags)
+}
+
+// RunGroupAction pauses, resumes, checks or deletes every website with a
+// tag and returns how many websites it affected
+func (h *V1Handler) RunGroupAction(w http.ResponseWriter, r *http.Request) {
+	tag, action := chi.URLParam(r, "tag"), chi.URLParam(r, "action")
+	if err := models.ValidateGroupAction(action); err != nil {
+		h.writeError(w, "Invalid group action", core.NewValidationError(err.Error(), err))
+		return
+	}
+
+	affected, err := h.server.RunGroupAction(tag, action)
+	if err != nil {
+		h.writeError(w, "Failed to run group action", notFound(err, "Tag not found"))
+		return
+	}
+
+	writeData(w, http.StatusOK, map[string]interface{}{
+		"tag":      tag,
+		"action":   action,
+		"affected": affected,
+	})
+}
+
+// updateWebsite applies an input to a website and stores the result
+func (h *V1Handler) updateWebsite(w http.ResponseWriter, r *http.Request, current models.Website, input models.WebsiteInput) {
+	website, err := input.Apply(current)
+	if err != nil {
+		h.writeError(w, "Invalid website", core.NewValidationError(err.Error(), err))
+		return
+	}
+
+	if err := h.server.UpdateWebsite(website); err != nil {
+		h.writeError(w, "Failed to update website", notFound(err, "Website not found"))
+		return
+	}
+
+	updated, err := h.server.GetWebsiteByID(website.ID)
+	if err != nil {
+		h.writeError(w, "Failed to get updated website", err)
+		return
+	}
+	writeData(w, http.StatusOK, newWebsiteDetail(r, *updated))
+}
+
+// websiteDetail is a single website as the v1 API returns it. Heartbeat
+// websites include their ping URL, which lists of websites leave out.
+type websiteDetail struct {
+	website models.Website
+	pingURL string
+}
+
+func newWebsiteDetail(r *http.Request, website models.Website) websiteDetail {
+	detail := websiteDetail{website: website}
+	if website.Type() == models.CheckTypeHeartbeat {
+		detail.pingURL = absoluteURL(r, website.URL)
+	}
+	return detail
+}
+
+// MarshalJSON adds the ping URL to the website's fields
+func (d websiteDetail) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(d.website)
+	if err != nil || d.pingURL == "" {
+		return encoded, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	if fields["ping_url"], err = json.Marshal(d.pingURL); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// website loads the website named by the URL's id, writing an error
+// response if it can't
+func (h *V1Handler) website(w http.ResponseWriter, r *http.Request) (*models.Website, bool) {
+	websiteID, ok := h.id(w, r, "website")
+	if !ok {
+		return nil, false
+	}
+
+	website, err := h.server.GetWebsiteByID(websiteID)
+	if err != nil {
+		h.writeError(w, "Failed to get website", notFound(err, "Website not found"))
+		return nil, false
+	}
+	return website, true
+}
+
+// id parses the URL's id, writing an error response if it isn't a number
+func (h *V1Handler) id(w http.ResponseWriter, r *http.Request, resource string) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, "Invalid ID", core.NewValidationError(fmt.Sprintf("Invalid %s ID", resource), err))
+		return 0, false
+	}
+	return id, true
+}
+
+// page parses the limit and offset query parameters, writing an error
+// response if they are invalid
+func (h *V1Handler) page(w http.ResponseWriter, r *http.Request) (models.Page, bool) {
+	page := models.Page{Limit: defaultPageSize}
+
+	query := r.URL.Query()
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			h.writeError(w, "Invalid limit", core.NewValidationError(fmt.Sprintf("Invalid limit %q", raw), err))
+			return page, false
+		}
+		page.Limit = min(limit, maxPageSize)
+	}
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			h.writeError(w, "Invalid offset", core.NewValidationError(fmt.Sprintf("Invalid offset %q", raw), err))
+			return page, false
+		}
+		page.Offset = offset
+	}
+	return page, true
+}
+
+// writeError writes the error envelope for err. Application errors keep
+// their code, sql.ErrNoRows becomes a 404 and anything else is logged and
+// hidden behind a 500.
+func (h *V1Handler) writeError(w http.ResponseWriter, message string, err error) {
+	var appErr *core.AppError
+	switch {
+	case errors.As(err, &appErr):
+		core.WriteErrorResponse(w, core.GetHTTPStatusCode(appErr), appErr)
+	case errors.Is(err, sql.ErrNoRows):
+		core.WriteErrorResponse(w, http.StatusNotFound, core.NewNotFoundError("Not found", nil))
+	default:
+		h.logger.Error(message, "error", err)
+		core.WriteErrorResponse(w, http.StatusInternalServerError, core.NewInternalError("Internal server error", nil))
+	}
+}
+
+// notFound turns sql.ErrNoRows into a not found error with the given message
+func notFound(err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.NewNotFoundError(message, err)
+	}
+	return err
+}
+
+// writeData writes a success envelope around data
+func writeData(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// writePage writes a success envelope around a page of a list
+func writePage(w http.ResponseWriter, data any, page models.Page) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":    true,
+		"data":       data,
+		"pagination": page,
+	})
+}
+
+// decodeJSON decodes a JSON request body, rejecting unknown fields. Errors
+// are validation errors.
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return core.NewValidationError(fmt.Sprintf("Invalid JSON body: %v", err), err)
+	}
+	if decoder.More() {
+		return core.NewValidationError("Invalid JSON body: unexpected data after the object", nil)
+	}
+	return nil
+}
+
+// patchInput applies a JSON merge patch to a website input
+func patchInput(input models.WebsiteInput, patch map[string]any) (models.WebsiteInput, error) {
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return input, err
+	}
+	var document map[string]any
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return input, err
+	}
+
+	merged, err := json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		return input, err
+	}
+
+	var patched models.WebsiteInput
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return input, core.NewValidationError(fmt.Sprintf("Invalid patch: %v", err), err)
+	}
+	return patched, nil
+}
+
+// mergePatch merges patch into target as RFC 7396 describes: objects are
+// merged recursively, null removes a field and anything else replaces it
+func mergePatch(target, patch map[string]any) map[string]any {
+	if target == nil {
+		target = make(map[string]any)
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if object, ok := value.(map[string]any); ok {
+			existing, _ := target[key].(map[string]any)
+			target[key] = mergePatch(existing, object)
+			continue
+		}
+		target[key] = value
+	}
+	return target
+}
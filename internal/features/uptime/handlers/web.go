@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 	"the-ark/internal/auth"
 	"the-ark/internal/features/uptime/models"
 	"the-ark/views/uptime"
+	"time"
 
 	"log/slog"
 
@@ -35,48 +38,15 @@ func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websites, err := h.server.GetActiveWebsites()
+	view, err := dashboardView(h.server, h.logger, r)
 	if err != nil {
-		h.logger.Error("Failed to get active websites", "error", err)
+		h.logger.Error("Failed to get websites", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	// Convert to DashboardWebsite for the web interface
-	dashboardWebsites := make([]models.DashboardWebsite, len(websites))
-	for i, website := range websites {
-		// Get the latest status for this website
-		status, err := h.server.GetLastWebsiteStatus(website.ID)
-		if err != nil {
-			h.logger.Error("Failed to get website status", "website_id", website.ID, "error", err)
-			// Continue with unknown status
-			dashboardWebsites[i] = models.DashboardWebsite{
-				Website:   website,
-				Status:    "unknown",
-				CheckedAt: nil,
-			}
-			continue
-		}
-
-		// Add defensive check for nil status
-		if status == nil {
-			dashboardWebsites[i] = models.DashboardWebsite{
-				Website:   website,
-				Status:    "unknown",
-				CheckedAt: nil,
-			}
-			continue
-		}
-
-		dashboardWebsites[i] = models.DashboardWebsite{
-			Website:   website,
-			Status:    status.Status,
-			CheckedAt: &status.CheckedAt,
-		}
-	}
-
 	// Render the dashboard page with user
-	component := uptime.Dashboard(user, dashboardWebsites)
+	component := uptime.Dashboard(user, view)
 	component.Render(r.Context(), w)
 }
 
@@ -100,6 +70,10 @@ func (h *WebHandler) WebsiteDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if detailData.Website.Type() == models.CheckTypeHeartbeat {
+		detailData.PingURL = absoluteURL(r, detailData.Website.URL)
+	}
+
 	// Render the website detail page
 	component := uptime.WebsiteDetail(user, *detailData)
 	component.Render(r.Context(), w)
@@ -107,6 +81,93 @@ func (h *WebHandler) WebsiteDetail(w http.ResponseWriter, r *http.Request) {
 
 func (h *WebHandler) AddSiteModal(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("AddSiteModal handler called")
-	component := uptime.AddSiteModal()
+
+	channels, err := h.server.GetNotificationChannels()
+	if err != nil {
+		h.logger.Error("Failed to get notification channels", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.AddSiteModal(channels)
+	component.Render(r.Context(), w)
+}
+
+// EditSiteModal renders the form that edits a website
+func (h *WebHandler) EditSiteModal(w http.ResponseWriter, r *http.Request) {
+	websiteID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("Failed to extract website ID", "error", err)
+		http.Error(w, "Invalid website ID", http.StatusBadRequest)
+		return
+	}
+
+	website, err := h.server.GetWebsiteByID(websiteID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Website not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get website by ID", "website_id", websiteID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.EditSiteModal(*website)
 	component.Render(r.Context(), w)
 }
+
+// Channels renders the notification channel management page
+func (h *WebHandler) Channels(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromContext(r)
+
+	channels, err := h.server.GetNotificationChannels()
+	if err != nil {
+		h.logger.Error("Failed to get notification channels", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.Channels(user, channels)
+	component.Render(r.Context(), w)
+}
+
+// StatusPages renders the status page management page
+func (h *WebHandler) StatusPages(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromContext(r)
+
+	pages, err := h.server.GetStatusPages()
+	if err != nil {
+		h.logger.Error("Failed to get status pages", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	websites, err := h.server.GetWebsites()
+	if err != nil {
+		h.logger.Error("Failed to get websites", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	component := uptime.StatusPages(user, pages, websites)
+	component.Render(r.Context(), w)
+}
+
+// Reports renders the SLA report page, linking each recent month's report
+func (h *WebHandler) Reports(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromContext(r)
+
+	months := models.RecentReportMonths(time.Now(), reportMonths)
+	component := uptime.Reports(user, months, h.server.SLATarget())
+	component.Render(r.Context(), w)
+}
+
+// absoluteURL resolves a path against the scheme and host the request was
+// made to, for URLs that are shown to be called from elsewhere
+func absoluteURL(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + path
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration101CreateUptimeTables creates the baseline uptime tables. They
+// match the tables the server creates on startup, so this is a no-op for
+// existing databases and lets later migrations run against a fresh one.
+var Migration101CreateUptimeTables = core.Migration{
+	Version:     101,
+	Name:        "create_uptime_tables",
+	Description: "Create baseline uptime monitoring tables",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_websites (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL UNIQUE,
+			check_interval INTEGER DEFAULT 300,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_checks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			website_id INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			response_time INTEGER,
+			status_code INTEGER,
+			error_message TEXT,
+			checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS alert_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			website_id INTEGER,
+			alert_type TEXT NOT NULL,
+			sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_checks_website_checked ON uptime_checks(website_id, checked_at);
+		CREATE INDEX IF NOT EXISTS idx_alert_history_website_type ON alert_history(website_id, alert_type, sent_at);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_alert_history_website_type;
+		DROP INDEX IF EXISTS idx_uptime_checks_website_checked;
+	`,
+}
@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration102AddAssertions stores each website's HTTP assertion set as JSON
+var Migration102AddAssertions = core.Migration{
+	Version:     102,
+	Name:        "add_uptime_assertions",
+	Description: "Add configurable HTTP assertions to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN assertions TEXT;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_websites DROP COLUMN assertions;
+	`,
+}
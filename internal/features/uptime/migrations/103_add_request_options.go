@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration103AddRequestOptions stores a full request definition per website
+var Migration103AddRequestOptions = core.Migration{
+	Version:     103,
+	Name:        "add_uptime_request_options",
+	Description: "Add request method, headers, body, auth and redirect policy to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN request_method TEXT NOT NULL DEFAULT 'GET';
+		ALTER TABLE uptime_websites ADD COLUMN request_headers TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN request_body TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN auth_type TEXT NOT NULL DEFAULT '';
+		ALTER TABLE uptime_websites ADD COLUMN auth_username TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN auth_secret TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN user_agent TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN follow_redirects BOOLEAN NOT NULL DEFAULT 1;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_websites DROP COLUMN follow_redirects;
+		ALTER TABLE uptime_websites DROP COLUMN user_agent;
+		ALTER TABLE uptime_websites DROP COLUMN auth_secret;
+		ALTER TABLE uptime_websites DROP COLUMN auth_username;
+		ALTER TABLE uptime_websites DROP COLUMN auth_type;
+		ALTER TABLE uptime_websites DROP COLUMN request_body;
+		ALTER TABLE uptime_websites DROP COLUMN request_headers;
+		ALTER TABLE uptime_websites DROP COLUMN request_method;
+	`,
+}
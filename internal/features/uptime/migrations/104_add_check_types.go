@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration104AddCheckTypes lets websites be checked over TCP, TLS or DNS
+// as well as HTTP
+var Migration104AddCheckTypes = core.Migration{
+	Version:     104,
+	Name:        "add_uptime_check_types",
+	Description: "Add check type and DNS record options to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN check_type TEXT NOT NULL DEFAULT 'http';
+		ALTER TABLE uptime_websites ADD COLUMN dns_record_type TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN dns_expected TEXT;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_websites DROP COLUMN dns_expected;
+		ALTER TABLE uptime_websites DROP COLUMN dns_record_type;
+		ALTER TABLE uptime_websites DROP COLUMN check_type;
+	`,
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration105AddHeartbeats adds push monitors that ping the Ark instead of
+// being probed
+var Migration105AddHeartbeats = core.Migration{
+	Version:     105,
+	Name:        "add_uptime_heartbeats",
+	Description: "Add heartbeat ping token, grace period and last ping time to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN heartbeat_token TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN grace_period INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE uptime_websites ADD COLUMN last_ping_at DATETIME;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_uptime_websites_heartbeat_token ON uptime_websites(heartbeat_token);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_websites_heartbeat_token;
+		ALTER TABLE uptime_websites DROP COLUMN last_ping_at;
+		ALTER TABLE uptime_websites DROP COLUMN grace_period;
+		ALTER TABLE uptime_websites DROP COLUMN heartbeat_token;
+	`,
+}
@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration106CreateIncidents stores incidents and their timelines instead
+// of deriving them from uptime_checks. Existing down periods are backfilled
+// as incidents so no history is lost.
+var Migration106CreateIncidents = core.Migration{
+	Version:     106,
+	Name:        "create_uptime_incidents",
+	Description: "Create uptime incident and incident timeline tables",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_incidents (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			website_id INTEGER NOT NULL,
+			status TEXT NOT NULL DEFAULT 'open',
+			cause TEXT,
+			root_cause TEXT,
+			started_at DATETIME NOT NULL,
+			acknowledged_at DATETIME,
+			acknowledged_by TEXT,
+			resolved_at DATETIME,
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_incident_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			incident_id INTEGER NOT NULL,
+			event_type TEXT NOT NULL,
+			message TEXT,
+			author TEXT,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (incident_id) REFERENCES uptime_incidents (id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_incidents_website_started ON uptime_incidents(website_id, started_at);
+		CREATE INDEX IF NOT EXISTS idx_uptime_incident_events_incident ON uptime_incident_events(incident_id, created_at);
+
+		-- A down period starts with a down check that follows an up check (or
+		-- no check at all) and ends with the next up check
+		INSERT INTO uptime_incidents (website_id, status, cause, started_at, resolved_at)
+		WITH ordered AS (
+			SELECT website_id, status, error_message, checked_at,
+				LAG(status) OVER (PARTITION BY website_id ORDER BY checked_at, id) AS prev_status
+			FROM uptime_checks
+		),
+		starts AS (
+			SELECT website_id, error_message, checked_at AS started_at
+			FROM ordered
+			WHERE status = 'down' AND (prev_status IS NULL OR prev_status != 'down')
+		),
+		periods AS (
+			SELECT s.website_id, s.error_message, s.started_at,
+				(SELECT MIN(c.checked_at) FROM uptime_checks c
+					WHERE c.website_id = s.website_id AND c.status != 'down' AND c.checked_at > s.started_at) AS resolved_at
+			FROM starts s
+		)
+		SELECT website_id,
+			CASE WHEN resolved_at IS NULL THEN 'open' ELSE 'resolved' END,
+			error_message, started_at, resolved_at
+		FROM periods
+		ORDER BY started_at;
+
+		INSERT INTO uptime_incident_events (incident_id, event_type, message, created_at)
+		SELECT id, 'opened', cause, started_at FROM uptime_incidents;
+
+		INSERT INTO uptime_incident_events (incident_id, event_type, created_at)
+		SELECT id, 'resolved', resolved_at FROM uptime_incidents WHERE resolved_at IS NOT NULL;
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_incident_events_incident;
+		DROP INDEX IF EXISTS idx_uptime_incidents_website_started;
+		DROP TABLE IF EXISTS uptime_incident_events;
+		DROP TABLE IF EXISTS uptime_incidents;
+	`,
+}
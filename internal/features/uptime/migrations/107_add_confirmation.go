@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration107AddConfirmation adds retry and consecutive-check settings so
+// transient failures don't take a website down
+var Migration107AddConfirmation = core.Migration{
+	Version:     107,
+	Name:        "add_uptime_confirmation",
+	Description: "Add retries, retry delay and failure/recovery thresholds to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN retries INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE uptime_websites ADD COLUMN retry_delay INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE uptime_websites ADD COLUMN failure_threshold INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE uptime_websites ADD COLUMN recovery_threshold INTEGER NOT NULL DEFAULT 1;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_websites DROP COLUMN recovery_threshold;
+		ALTER TABLE uptime_websites DROP COLUMN failure_threshold;
+		ALTER TABLE uptime_websites DROP COLUMN retry_delay;
+		ALTER TABLE uptime_websites DROP COLUMN retries;
+	`,
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration108CreateNotificationChannels stores the channels alerts are
+// delivered through and which channels each website alerts
+var Migration108CreateNotificationChannels = core.Migration{
+	Version:     108,
+	Name:        "create_uptime_notification_channels",
+	Description: "Create notification channel and website channel tables",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_notification_channels (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			channel_type TEXT NOT NULL,
+			url TEXT,
+			token TEXT,
+			recipient TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_website_channels (
+			website_id INTEGER NOT NULL,
+			channel_id INTEGER NOT NULL,
+			PRIMARY KEY (website_id, channel_id),
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE,
+			FOREIGN KEY (channel_id) REFERENCES uptime_notification_channels (id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_website_channels_channel ON uptime_website_channels(channel_id);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_website_channels_channel;
+		DROP TABLE IF EXISTS uptime_website_channels;
+		DROP TABLE IF EXISTS uptime_notification_channels;
+	`,
+}
@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration109AddAlertPolicies adds per-website re-notify, escalation and
+// quiet hours settings, and extends alert_history to record every delivery
+// attempt with its channel and outcome. Existing history counts as sent.
+var Migration109AddAlertPolicies = core.Migration{
+	Version:     109,
+	Name:        "add_uptime_alert_policies",
+	Description: "Add alert policies to uptime websites and delivery outcomes to alert history",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN renotify_interval INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE uptime_websites ADD COLUMN escalation_channel_id INTEGER;
+		ALTER TABLE uptime_websites ADD COLUMN escalate_after INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE uptime_websites ADD COLUMN quiet_hours_start TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN quiet_hours_end TEXT;
+		ALTER TABLE uptime_websites ADD COLUMN quiet_hours_timezone TEXT;
+
+		ALTER TABLE alert_history ADD COLUMN incident_id INTEGER;
+		ALTER TABLE alert_history ADD COLUMN channel_id INTEGER;
+		ALTER TABLE alert_history ADD COLUMN channel_name TEXT;
+		ALTER TABLE alert_history ADD COLUMN outcome TEXT NOT NULL DEFAULT 'sent';
+		ALTER TABLE alert_history ADD COLUMN reason TEXT;
+		ALTER TABLE alert_history ADD COLUMN error_message TEXT;
+
+		CREATE INDEX IF NOT EXISTS idx_alert_history_incident ON alert_history(incident_id, alert_type, outcome);
+		CREATE INDEX IF NOT EXISTS idx_alert_history_outcome ON alert_history(outcome);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_alert_history_outcome;
+		DROP INDEX IF EXISTS idx_alert_history_incident;
+
+		ALTER TABLE alert_history DROP COLUMN error_message;
+		ALTER TABLE alert_history DROP COLUMN reason;
+		ALTER TABLE alert_history DROP COLUMN outcome;
+		ALTER TABLE alert_history DROP COLUMN channel_name;
+		ALTER TABLE alert_history DROP COLUMN channel_id;
+		ALTER TABLE alert_history DROP COLUMN incident_id;
+
+		ALTER TABLE uptime_websites DROP COLUMN quiet_hours_timezone;
+		ALTER TABLE uptime_websites DROP COLUMN quiet_hours_end;
+		ALTER TABLE uptime_websites DROP COLUMN quiet_hours_start;
+		ALTER TABLE uptime_websites DROP COLUMN escalate_after;
+		ALTER TABLE uptime_websites DROP COLUMN escalation_channel_id;
+		ALTER TABLE uptime_websites DROP COLUMN renotify_interval;
+	`,
+}
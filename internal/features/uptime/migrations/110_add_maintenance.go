@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration110CreateMaintenanceWindows stores scheduled maintenance windows,
+// for one website or every website when website_id is NULL, and tags checks
+// taken during maintenance so they can be left out of uptime statistics
+var Migration110CreateMaintenanceWindows = core.Migration{
+	Version:     110,
+	Name:        "create_uptime_maintenance_windows",
+	Description: "Create maintenance windows and tag maintenance checks",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_maintenance_windows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			website_id INTEGER,
+			name TEXT NOT NULL,
+			starts_at DATETIME,
+			ends_at DATETIME,
+			schedule TEXT,
+			duration INTEGER NOT NULL DEFAULT 0,
+			timezone TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_maintenance_windows_website ON uptime_maintenance_windows(website_id);
+
+		ALTER TABLE uptime_checks ADD COLUMN maintenance BOOLEAN NOT NULL DEFAULT 0;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_checks DROP COLUMN maintenance;
+
+		DROP INDEX IF EXISTS idx_uptime_maintenance_windows_website;
+		DROP TABLE IF EXISTS uptime_maintenance_windows;
+	`,
+}
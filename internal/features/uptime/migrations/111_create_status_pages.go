@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration111CreateStatusPages stores public status pages, the named
+// components they group websites into and which websites each shows
+var Migration111CreateStatusPages = core.Migration{
+	Version:     111,
+	Name:        "create_uptime_status_pages",
+	Description: "Create status page, component and component website tables",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_status_pages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			slug TEXT NOT NULL UNIQUE,
+			title TEXT NOT NULL,
+			description TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_status_components (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			page_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			position INTEGER NOT NULL DEFAULT 0,
+			FOREIGN KEY (page_id) REFERENCES uptime_status_pages (id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_status_component_websites (
+			component_id INTEGER NOT NULL,
+			website_id INTEGER NOT NULL,
+			PRIMARY KEY (component_id, website_id),
+			FOREIGN KEY (component_id) REFERENCES uptime_status_components (id) ON DELETE CASCADE,
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_status_components_page ON uptime_status_components(page_id);
+		CREATE INDEX IF NOT EXISTS idx_uptime_status_component_websites_website ON uptime_status_component_websites(website_id);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_status_component_websites_website;
+		DROP INDEX IF EXISTS idx_uptime_status_components_page;
+		DROP TABLE IF EXISTS uptime_status_component_websites;
+		DROP TABLE IF EXISTS uptime_status_components;
+		DROP TABLE IF EXISTS uptime_status_pages;
+	`,
+}
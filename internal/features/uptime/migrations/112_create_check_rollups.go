@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration112CreateCheckRollups stores hourly and daily aggregates of raw
+// checks, so long periods can be reported after raw checks are pruned
+var Migration112CreateCheckRollups = core.Migration{
+	Version:     112,
+	Name:        "create_uptime_check_rollups",
+	Description: "Create check rollup and rollup progress tables",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_check_rollups (
+			website_id INTEGER NOT NULL,
+			resolution TEXT NOT NULL,
+			bucket_start DATETIME NOT NULL,
+			up_checks INTEGER NOT NULL DEFAULT 0,
+			degraded_checks INTEGER NOT NULL DEFAULT 0,
+			down_checks INTEGER NOT NULL DEFAULT 0,
+			latency_samples INTEGER NOT NULL DEFAULT 0,
+			min_response_time INTEGER,
+			avg_response_time REAL,
+			p95_response_time INTEGER,
+			max_response_time INTEGER,
+			status_codes TEXT NOT NULL DEFAULT '{}',
+			PRIMARY KEY (website_id, resolution, bucket_start),
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_rollup_progress (
+			resolution TEXT PRIMARY KEY,
+			rolled_up_to DATETIME NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_checks_checked_at ON uptime_checks(checked_at);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_checks_checked_at;
+		DROP TABLE IF EXISTS uptime_rollup_progress;
+		DROP TABLE IF EXISTS uptime_check_rollups;
+	`,
+}
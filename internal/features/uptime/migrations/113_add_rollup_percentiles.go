@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration113AddRollupPercentiles adds median and p99 latency to check
+// rollups for the website detail charts. Existing rollups leave them empty.
+var Migration113AddRollupPercentiles = core.Migration{
+	Version:     113,
+	Name:        "add_uptime_rollup_percentiles",
+	Description: "Add p50 and p99 response times to check rollups",
+	UpSQL: `
+		ALTER TABLE uptime_check_rollups ADD COLUMN p50_response_time INTEGER;
+		ALTER TABLE uptime_check_rollups ADD COLUMN p99_response_time INTEGER;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_check_rollups DROP COLUMN p99_response_time;
+		ALTER TABLE uptime_check_rollups DROP COLUMN p50_response_time;
+	`,
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration114AddCheckTiming stores the DNS, connect, TLS, time to first byte
+// and transfer phases of HTTP checks. Other check types and existing checks
+// leave them empty.
+var Migration114AddCheckTiming = core.Migration{
+	Version:     114,
+	Name:        "add_uptime_check_timing",
+	Description: "Add request phase timings to uptime checks",
+	UpSQL: `
+		ALTER TABLE uptime_checks ADD COLUMN dns_time INTEGER;
+		ALTER TABLE uptime_checks ADD COLUMN connect_time INTEGER;
+		ALTER TABLE uptime_checks ADD COLUMN tls_time INTEGER;
+		ALTER TABLE uptime_checks ADD COLUMN ttfb_time INTEGER;
+		ALTER TABLE uptime_checks ADD COLUMN transfer_time INTEGER;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_checks DROP COLUMN transfer_time;
+		ALTER TABLE uptime_checks DROP COLUMN ttfb_time;
+		ALTER TABLE uptime_checks DROP COLUMN tls_time;
+		ALTER TABLE uptime_checks DROP COLUMN connect_time;
+		ALTER TABLE uptime_checks DROP COLUMN dns_time;
+	`,
+}
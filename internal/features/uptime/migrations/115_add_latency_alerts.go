@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration115AddLatencyAlerts adds latency limits to website alert
+// policies, and tracks since when a website has been breaching them
+var Migration115AddLatencyAlerts = core.Migration{
+	Version:     115,
+	Name:        "add_uptime_latency_alerts",
+	Description: "Add latency thresholds and degraded state to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN latency_threshold INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE uptime_websites ADD COLUMN latency_deviation INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE uptime_websites ADD COLUMN degraded_since DATETIME;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_websites DROP COLUMN degraded_since;
+		ALTER TABLE uptime_websites DROP COLUMN latency_deviation;
+		ALTER TABLE uptime_websites DROP COLUMN latency_threshold;
+	`,
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration116CreateReportDeliveries records which monthly SLA reports have
+// been emailed, so each month is only sent once
+var Migration116CreateReportDeliveries = core.Migration{
+	Version:     116,
+	Name:        "create_uptime_report_deliveries",
+	Description: "Create the SLA report delivery table",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_report_deliveries (
+			month TEXT PRIMARY KEY,
+			recipient TEXT NOT NULL,
+			sent_at DATETIME NOT NULL
+		);
+	`,
+	DownSQL: `
+		DROP TABLE IF EXISTS uptime_report_deliveries;
+	`,
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration117CreateWebsiteTags stores the tags websites are grouped by
+var Migration117CreateWebsiteTags = core.Migration{
+	Version:     117,
+	Name:        "create_uptime_website_tags",
+	Description: "Create tag and website tag tables for uptime websites",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_website_tags (
+			website_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (website_id, tag_id),
+			FOREIGN KEY (website_id) REFERENCES uptime_websites (id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES uptime_tags (id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_website_tags_tag ON uptime_website_tags(tag_id);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_website_tags_tag;
+		DROP TABLE IF EXISTS uptime_website_tags;
+		DROP TABLE IF EXISTS uptime_tags;
+	`,
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration118AddWebsiteUpdatedAt lets websites be paused without losing
+// their history, and tracks when each website's configuration or paused
+// state last changed. Existing websites start active and from their
+// creation time.
+var Migration118AddWebsiteUpdatedAt = core.Migration{
+	Version:     118,
+	Name:        "add_uptime_website_updated_at",
+	Description: "Add a paused state and an updated_at timestamp to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT 1;
+		ALTER TABLE uptime_websites ADD COLUMN updated_at DATETIME;
+		UPDATE uptime_websites SET updated_at = created_at;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_websites DROP COLUMN updated_at;
+		ALTER TABLE uptime_websites DROP COLUMN is_active;
+	`,
+}
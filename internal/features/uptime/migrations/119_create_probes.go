@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration119CreateProbes stores the probe agents checking websites from
+// other locations and the latest result each probe reported per website.
+// Only a hash of each probe's key is kept.
+var Migration119CreateProbes = core.Migration{
+	Version:     119,
+	Name:        "create_uptime_probes",
+	Description: "Create tables for remote probe agents and their results",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_probes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			key_hash TEXT NOT NULL UNIQUE,
+			registered_at DATETIME NOT NULL,
+			last_seen_at DATETIME
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_probe_results (
+			probe_id INTEGER NOT NULL,
+			website_id INTEGER NOT NULL,
+			is_up BOOLEAN NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			response_time INTEGER NOT NULL DEFAULT 0,
+			error_message TEXT NOT NULL DEFAULT '',
+			checked_at DATETIME NOT NULL,
+			PRIMARY KEY (probe_id, website_id),
+			FOREIGN KEY (probe_id) REFERENCES uptime_probes(id) ON DELETE CASCADE,
+			FOREIGN KEY (website_id) REFERENCES uptime_websites(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_probe_results_website ON uptime_probe_results(website_id, checked_at);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_probe_results_website;
+		DROP TABLE IF EXISTS uptime_probe_results;
+		DROP TABLE IF EXISTS uptime_probes;
+	`,
+}
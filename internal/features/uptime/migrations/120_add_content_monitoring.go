@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration120AddContentMonitoring stores each website's content monitoring
+// options as JSON and a snapshot of its normalized content each time it
+// changes, with the diff against the previous snapshot
+var Migration120AddContentMonitoring = core.Migration{
+	Version:     120,
+	Name:        "add_uptime_content_monitoring",
+	Description: "Add content change monitoring and snapshots to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN content_options TEXT;
+
+		CREATE TABLE IF NOT EXISTS uptime_content_snapshots (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			website_id INTEGER NOT NULL,
+			hash TEXT NOT NULL,
+			content TEXT NOT NULL,
+			diff TEXT NOT NULL DEFAULT '',
+			captured_at DATETIME NOT NULL,
+			FOREIGN KEY (website_id) REFERENCES uptime_websites(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_uptime_content_snapshots_website ON uptime_content_snapshots(website_id, captured_at);
+	`,
+	DownSQL: `
+		DROP INDEX IF EXISTS idx_uptime_content_snapshots_website;
+		DROP TABLE IF EXISTS uptime_content_snapshots;
+		ALTER TABLE uptime_websites DROP COLUMN content_options;
+	`,
+}
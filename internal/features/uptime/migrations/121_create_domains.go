@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration121CreateDomains stores the registration of each distinct
+// registrable domain the websites are on, as looked up through RDAP, and
+// the expiry warnings sent for it. Warnings are keyed by the expiry date
+// so a renewed domain is warned about again.
+var Migration121CreateDomains = core.Migration{
+	Version:     121,
+	Name:        "create_uptime_domains",
+	Description: "Create tables for domain registration expiry tracking",
+	UpSQL: `
+		CREATE TABLE IF NOT EXISTS uptime_domains (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			registrar TEXT NOT NULL DEFAULT '',
+			expires_at DATETIME,
+			checked_at DATETIME,
+			error_message TEXT NOT NULL DEFAULT ''
+		);
+
+		CREATE TABLE IF NOT EXISTS uptime_domain_alerts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			domain_id INTEGER NOT NULL,
+			expires_on TEXT NOT NULL,
+			threshold INTEGER NOT NULL,
+			sent_at DATETIME NOT NULL,
+			UNIQUE (domain_id, expires_on, threshold),
+			FOREIGN KEY (domain_id) REFERENCES uptime_domains(id) ON DELETE CASCADE
+		);
+	`,
+	DownSQL: `
+		DROP TABLE IF EXISTS uptime_domain_alerts;
+		DROP TABLE IF EXISTS uptime_domains;
+	`,
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"the-ark/internal/core"
+)
+
+// Migration122AddWebsiteResumedAt records when each website was last
+// resumed, so a heartbeat that was paused is given a full period from the
+// resume rather than from its last ping before the pause
+var Migration122AddWebsiteResumedAt = core.Migration{
+	Version:     122,
+	Name:        "add_uptime_website_resumed_at",
+	Description: "Add a resumed_at timestamp to uptime websites",
+	UpSQL: `
+		ALTER TABLE uptime_websites ADD COLUMN resumed_at DATETIME;
+	`,
+	DownSQL: `
+		ALTER TABLE uptime_websites DROP COLUMN resumed_at;
+	`,
+}
@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"the-ark/internal/core"
+)
+
+// Manager handles uptime feature migrations
+type Manager struct {
+	migrationService *core.MigrationService
+	logger           *core.Logger
+}
+
+// NewManager creates a new uptime migration manager
+func NewManager(db *core.Database, logger *core.Logger) *Manager {
+	migrationService := core.NewMigrationService(db, logger)
+	return &Manager{
+		migrationService: migrationService,
+		logger:           logger,
+	}
+}
+
+// Migrations returns all uptime migrations in order. Uptime migrations are
+// numbered from 101 so they don't collide with other features sharing the
+// migrations table.
+func (m *Manager) Migrations() []core.Migration {
+	return []core.Migration{
+		Migration101CreateUptimeTables,
+		Migration102AddAssertions,
+		Migration103AddRequestOptions,
+		Migration104AddCheckTypes,
+		Migration105AddHeartbeats,
+		Migration106CreateIncidents,
+		Migration107AddConfirmation,
+		Migration108CreateNotificationChannels,
+		Migration109AddAlertPolicies,
+		Migration110CreateMaintenanceWindows,
+		Migration111CreateStatusPages,
+		Migration112CreateCheckRollups,
+		Migration113AddRollupPercentiles,
+		Migration114AddCheckTiming,
+		Migration115AddLatencyAlerts,
+		Migration116CreateReportDeliveries,
+		Migration117CreateWebsiteTags,
+		Migration118AddWebsiteUpdatedAt,
+		Migration119CreateProbes,
+		Migration120AddContentMonitoring,
+		Migration121CreateDomains,
+		Migration122AddWebsiteResumedAt,
+	}
+}
+
+// Migrate applies all pending uptime migrations
+func (m *Manager) Migrate(ctx context.Context) error {
+	// Initialize migrations table if it doesn't exist
+	if err := m.migrationService.InitMigrations(ctx); err != nil {
+		return fmt.Errorf("failed to initialize migrations: %w", err)
+	}
+
+	migrations := m.Migrations()
+	m.logger.Info("Starting uptime migrations", "count", len(migrations))
+
+	for _, migration := range migrations {
+		if err := m.migrationService.ApplyMigration(ctx, migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	m.logger.Info("Uptime migrations completed successfully")
+	return nil
+}
+
+// Status returns the current migration status
+func (m *Manager) Status(ctx context.Context) (*core.MigrationStatus, error) {
+	return m.migrationService.GetMigrationStatus(ctx)
+}
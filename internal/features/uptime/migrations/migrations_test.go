@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"the-ark/internal/core"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestUptimeMigrations(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	manager := NewManager(core.NewDatabase(db, core.NewLogger()), core.NewLogger())
+
+	ctx := context.Background()
+	if err := manager.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	// Migrations must be idempotent
+	if err := manager.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to re-apply migrations: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM migrations").Scan(&count); err != nil {
+		t.Fatalf("Failed to query migrations table: %v", err)
+	}
+	if count != len(manager.Migrations()) {
+		t.Errorf("Expected %d migrations, got %d", len(manager.Migrations()), count)
+	}
+
+	// Every uptime migration must fit in the uptime version range
+	for _, migration := range manager.Migrations() {
+		if migration.Version <= 100 || migration.Version >= 200 {
+			t.Errorf("Migration %s has version %d outside the uptime range", migration.Name, migration.Version)
+		}
+	}
+
+	columns := map[string][]string{
+		"uptime_websites":                  {"assertions", "request_method", "request_headers", "auth_secret", "follow_redirects", "check_type", "dns_record_type", "dns_expected", "heartbeat_token", "grace_period", "last_ping_at", "retries", "retry_delay", "failure_threshold", "recovery_threshold", "renotify_interval", "escalation_channel_id", "escalate_after", "quiet_hours_start", "quiet_hours_end", "quiet_hours_timezone", "latency_threshold", "latency_deviation", "degraded_since", "is_active", "updated_at", "content_options", "resumed_at"},
+		"uptime_notification_channels":     {"name", "channel_type", "url", "token", "recipient"},
+		"uptime_website_channels":          {"website_id", "channel_id"},
+		"alert_history":                    {"incident_id", "channel_id", "channel_name", "outcome", "reason", "error_message"},
+		"uptime_maintenance_windows":       {"website_id", "name", "starts_at", "ends_at", "schedule", "duration", "timezone"},
+		"uptime_checks":                    {"maintenance", "dns_time", "connect_time", "tls_time", "ttfb_time", "transfer_time"},
+		"uptime_status_pages":              {"slug", "title", "description"},
+		"uptime_status_components":         {"page_id", "name", "position"},
+		"uptime_status_component_websites": {"component_id", "website_id"},
+		"uptime_check_rollups":             {"website_id", "resolution", "bucket_start", "up_checks", "degraded_checks", "down_checks", "latency_samples", "min_response_time", "avg_response_time", "p95_response_time", "max_response_time", "status_codes", "p50_response_time", "p99_response_time"},
+		"uptime_rollup_progress":           {"resolution", "rolled_up_to"},
+		"uptime_report_deliveries":         {"month", "recipient", "sent_at"},
+		"uptime_tags":                      {"name"},
+		"uptime_website_tags":              {"website_id", "tag_id"},
+		"uptime_probes":                    {"name", "key_hash", "registered_at", "last_seen_at"},
+		"uptime_probe_results":             {"probe_id", "website_id", "is_up", "status_code", "response_time", "error_message", "checked_at"},
+		"uptime_content_snapshots":         {"website_id", "hash", "content", "diff", "captured_at"},
+		"uptime_domains":                   {"name", "registrar", "expires_at", "checked_at", "error_message"},
+		"uptime_domain_alerts":             {"domain_id", "expires_on", "threshold", "sent_at"},
+	}
+	for table, names := range columns {
+		for _, column := range names {
+			var found int
+			err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&found)
+			if err != nil {
+				t.Fatalf("Failed to inspect %s: %v", table, err)
+			}
+			if found != 1 {
+				t.Errorf("Expected column %s.%s to exist", table, column)
+			}
+		}
+	}
+}
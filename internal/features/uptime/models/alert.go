@@ -0,0 +1,174 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownEscalationChannel is returned when an alert policy escalates to
+// a notification channel that doesn't exist
+var ErrUnknownEscalationChannel = errors.New("escalation channel does not exist")
+
+// Alert types sent through notification channels
+const (
+	AlertDown     = "down"
+	AlertRecovery = "recovery"
+	AlertTest     = "test"
+
+	// AlertReminder re-notifies while a website stays down
+	AlertReminder = "reminder"
+
+	// AlertEscalation goes to the escalation contact when an incident stays
+	// unacknowledged
+	AlertEscalation = "escalation"
+
+	// AlertDegraded is sent when a website that is up keeps responding
+	// slower than its latency limits, and AlertDegradedRecovery when it
+	// responds within them again
+	AlertDegraded         = "degraded"
+	AlertDegradedRecovery = "degraded_recovery"
+
+	// AlertContentChanged is sent when the content of a website with
+	// content monitoring changes
+	AlertContentChanged = "content_changed"
+)
+
+// Outcomes of an alert delivery attempt recorded in alert_history
+const (
+	AlertSent     = "sent"
+	AlertFailed   = "failed"
+	AlertDeferred = "deferred"
+
+	// AlertReleased marks a deferred alert that has since been delivered
+	AlertReleased = "released"
+
+	// AlertDiscarded marks a deferred alert dropped because its website was
+	// paused before it could be delivered
+	AlertDiscarded = "discarded"
+)
+
+// quietHoursLayout is the format of quiet hours boundaries
+const quietHoursLayout = "15:04"
+
+// IsCriticalAlert reports whether an alert is delivered during quiet hours
+func IsCriticalAlert(alertType string) bool {
+	return alertType == AlertDown || alertType == AlertEscalation
+}
+
+// AlertPolicy controls when and to whom a website's alerts are delivered,
+// on top of the channels the website alerts
+type AlertPolicy struct {
+	// RenotifyInterval is how many minutes to wait before reminding the
+	// website's channels that it is still down. Zero disables reminders.
+	RenotifyInterval int `json:"renotify_interval"`
+
+	// EscalationChannelID is alerted when an incident stays unacknowledged
+	// for EscalateAfter minutes
+	EscalationChannelID int `json:"escalation_channel_id,omitempty"`
+	EscalateAfter       int `json:"escalate_after,omitempty"`
+
+	// QuietHoursStart and QuietHoursEnd bound a daily window, as HH:MM in
+	// QuietHoursTimezone, in which non-critical alerts are deferred. The
+	// window may wrap past midnight.
+	QuietHoursStart    string `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd      string `json:"quiet_hours_end,omitempty"`
+	QuietHoursTimezone string `json:"quiet_hours_timezone,omitempty"`
+
+	// LatencyThreshold is the response time in milliseconds above which a
+	// check is degraded. Zero disables it.
+	LatencyThreshold int `json:"latency_threshold,omitempty"`
+
+	// LatencyDeviation is how many percent above the website's median
+	// response time over the last day a check may be before it is
+	// degraded. Zero disables it.
+	LatencyDeviation int `json:"latency_deviation,omitempty"`
+}
+
+// Validate checks the policy's intervals and quiet hours
+func (p AlertPolicy) Validate() error {
+	if p.RenotifyInterval < 0 {
+		return fmt.Errorf("re-notify interval must not be negative")
+	}
+	if p.EscalationChannelID < 0 || p.EscalateAfter < 0 {
+		return fmt.Errorf("escalation settings must not be negative")
+	}
+	if p.EscalationChannelID > 0 && p.EscalateAfter == 0 {
+		return fmt.Errorf("escalation needs a delay in minutes")
+	}
+
+	if p.LatencyThreshold < 0 || p.LatencyDeviation < 0 {
+		return fmt.Errorf("latency limits must not be negative")
+	}
+
+	if (p.QuietHoursStart == "") != (p.QuietHoursEnd == "") {
+		return fmt.Errorf("quiet hours need both a start and an end")
+	}
+	for _, value := range []string{p.QuietHoursStart, p.QuietHoursEnd} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(quietHoursLayout, value); err != nil {
+			return fmt.Errorf("invalid quiet hours time %q, expected HH:MM", value)
+		}
+	}
+	if _, err := p.location(); err != nil {
+		return fmt.Errorf("invalid quiet hours timezone %q", p.QuietHoursTimezone)
+	}
+	return nil
+}
+
+// InQuietHours reports whether t falls within the policy's quiet hours
+func (p AlertPolicy) InQuietHours(t time.Time) bool {
+	if p.QuietHoursStart == "" || p.QuietHoursEnd == "" {
+		return false
+	}
+	start, err := time.Parse(quietHoursLayout, p.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(quietHoursLayout, p.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+	loc, err := p.location()
+	if err != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return minute >= from && minute < to
+	}
+	// The window wraps past midnight
+	return minute >= from || minute < to
+}
+
+// location returns the quiet hours timezone, defaulting to the server's
+func (p AlertPolicy) location() (*time.Location, error) {
+	if p.QuietHoursTimezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(p.QuietHoursTimezone)
+}
+
+// AlertRecord is one delivery attempt of an alert through a channel
+type AlertRecord struct {
+	ID         int    `json:"id"`
+	WebsiteID  int    `json:"website_id"`
+	IncidentID int    `json:"incident_id,omitempty"`
+	Type       string `json:"alert_type"`
+
+	// ChannelID is zero for alerts emailed to the default recipient
+	ChannelID   int    `json:"channel_id,omitempty"`
+	ChannelName string `json:"channel_name"`
+
+	Outcome string    `json:"outcome"`
+	Reason  string    `json:"reason,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+}
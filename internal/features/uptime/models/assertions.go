@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultStatusCodes are accepted when a website has no status code assertion
+const DefaultStatusCodes = "200-399"
+
+// Assertions describes what a check response must satisfy for a website to
+// be considered up
+type Assertions struct {
+	// StatusCodes is a comma separated list of codes and ranges, e.g. "200-299,301"
+	StatusCodes string `json:"status_codes,omitempty"`
+
+	// BodyContains must appear in the response body
+	BodyContains string `json:"body_contains,omitempty"`
+
+	// BodyNotContains must not appear in the response body
+	BodyNotContains string `json:"body_not_contains,omitempty"`
+
+	// BodyRegex treats BodyContains and BodyNotContains as regular expressions
+	BodyRegex bool `json:"body_regex,omitempty"`
+
+	// Headers maps required response header names to their expected value.
+	// An empty value only requires the header to be present.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// MaxResponseTime is the slowest acceptable response in milliseconds
+	MaxResponseTime int `json:"max_response_time,omitempty"`
+}
+
+// statusRange is an inclusive range of accepted status codes
+type statusRange struct {
+	min int
+	max int
+}
+
+// Validate checks the status code list and body patterns can be parsed
+func (a Assertions) Validate() error {
+	if _, err := parseStatusCodes(a.StatusCodes); err != nil {
+		return err
+	}
+
+	if a.BodyRegex {
+		for _, pattern := range []string{a.BodyContains, a.BodyNotContains} {
+			if pattern == "" {
+				continue
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid body pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	if a.MaxResponseTime < 0 {
+		return fmt.Errorf("max response time must not be negative")
+	}
+
+	return nil
+}
+
+// AcceptedStatusCodes returns the configured status code list or the default
+func (a Assertions) AcceptedStatusCodes() string {
+	if strings.TrimSpace(a.StatusCodes) == "" {
+		return DefaultStatusCodes
+	}
+	return a.StatusCodes
+}
+
+// AcceptsStatus reports whether a status code satisfies the assertions
+func (a Assertions) AcceptsStatus(code int) bool {
+	ranges, err := parseStatusCodes(a.AcceptedStatusCodes())
+	if err != nil {
+		return false
+	}
+
+	for _, r := range ranges {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBodyAssertions reports whether the response body needs to be read
+func (a Assertions) HasBodyAssertions() bool {
+	return a.BodyContains != "" || a.BodyNotContains != ""
+}
+
+// parseStatusCodes parses a list such as "200-299,301,404"
+func parseStatusCodes(spec string) ([]statusRange, error) {
+	var ranges []statusRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		low, high, isRange := strings.Cut(part, "-")
+		min, err := parseStatusCode(low)
+		if err != nil {
+			return nil, err
+		}
+		max := min
+		if isRange {
+			if max, err = parseStatusCode(high); err != nil {
+				return nil, err
+			}
+		}
+		if min > max {
+			return nil, fmt.Errorf("invalid status code range %q", part)
+		}
+
+		ranges = append(ranges, statusRange{min: min, max: max})
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %q", value)
+	}
+	return code, nil
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Check types supported by the monitor
+const (
+	CheckTypeHTTP = "http"
+	CheckTypeTCP  = "tcp"
+	CheckTypeTLS  = "tls"
+	CheckTypeDNS  = "dns"
+)
+
+// DNS record types a DNS check can resolve
+const (
+	DNSRecordA     = "A"
+	DNSRecordAAAA  = "AAAA"
+	DNSRecordCNAME = "CNAME"
+	DNSRecordMX    = "MX"
+	DNSRecordNS    = "NS"
+	DNSRecordTXT   = "TXT"
+)
+
+// DefaultTLSPort is used for TLS checks whose target has no port
+const DefaultTLSPort = "443"
+
+// DNSOptions describes the record a DNS check resolves
+type DNSOptions struct {
+	// RecordType is one of the DNSRecord constants, defaulting to A
+	RecordType string `json:"record_type,omitempty"`
+
+	// Expected must be among the resolved values when set, e.g. an IP
+	// address for A records or a mail host for MX records
+	Expected string `json:"expected,omitempty"`
+}
+
+// Type returns the website's check type, defaulting to HTTP
+func (w Website) Type() string {
+	if w.CheckType == "" {
+		return CheckTypeHTTP
+	}
+	return w.CheckType
+}
+
+// Validate checks the website's target matches its check type. HTTP checks
+// need an http(s) URL, TCP checks a host:port, TLS checks a host with an
+// optional port and DNS checks a bare hostname.
+func (w Website) Validate() error {
+	if err := w.Confirmation.Validate(); err != nil {
+		return err
+	}
+	if err := w.Alerts.Validate(); err != nil {
+		return err
+	}
+	if w.Content.Enabled && w.Type() != CheckTypeHTTP {
+		return fmt.Errorf("content monitoring needs an HTTP check")
+	}
+
+	switch w.Type() {
+	case CheckTypeHTTP:
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid URL %q", w.URL)
+		}
+	case CheckTypeTCP:
+		if _, _, err := SplitHostPort(w.URL, ""); err != nil {
+			return err
+		}
+	case CheckTypeTLS:
+		if _, _, err := SplitHostPort(w.URL, DefaultTLSPort); err != nil {
+			return err
+		}
+	case CheckTypeHeartbeat:
+		if w.HeartbeatToken == "" {
+			return fmt.Errorf("heartbeat websites need a ping token")
+		}
+		if w.GracePeriod < 0 {
+			return fmt.Errorf("grace period must not be negative")
+		}
+	case CheckTypeDNS:
+		if w.URL == "" || strings.ContainsAny(w.URL, "/: ") {
+			return fmt.Errorf("invalid hostname %q", w.URL)
+		}
+		switch w.DNS.Type() {
+		case DNSRecordA, DNSRecordAAAA, DNSRecordCNAME, DNSRecordMX, DNSRecordNS, DNSRecordTXT:
+		default:
+			return fmt.Errorf("unsupported DNS record type %q", w.DNS.RecordType)
+		}
+	default:
+		return fmt.Errorf("unsupported check type %q", w.CheckType)
+	}
+	return nil
+}
+
+// Type returns the record type, defaulting to A
+func (o DNSOptions) Type() string {
+	if o.RecordType == "" {
+		return DNSRecordA
+	}
+	return strings.ToUpper(o.RecordType)
+}
+
+// CheckTypeLabel returns a human readable name for a check type
+func CheckTypeLabel(checkType string) string {
+	switch checkType {
+	case CheckTypeTCP:
+		return "TCP port"
+	case CheckTypeTLS:
+		return "TLS handshake"
+	case CheckTypeDNS:
+		return "DNS"
+	case CheckTypeHeartbeat:
+		return "Heartbeat"
+	default:
+		return "HTTP/S"
+	}
+}
+
+// SplitHostPort splits a host:port target, falling back to defaultPort
+// when the target has no port. An empty defaultPort makes the port required.
+func SplitHostPort(target, defaultPort string) (string, string, error) {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		if defaultPort == "" || strings.Contains(target, "/") {
+			return "", "", fmt.Errorf("invalid target %q, expected host:port", target)
+		}
+		host, port = target, defaultPort
+	}
+
+	if host == "" {
+		return "", "", fmt.Errorf("invalid target %q, missing host", target)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("invalid port %q", port)
+	}
+	return host, port, nil
+}
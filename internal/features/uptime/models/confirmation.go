@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Check statuses stored in uptime_checks
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	// StatusPending marks a check that disagrees with the website's
+	// confirmed state, before enough consecutive checks confirm the change
+	StatusPending = "pending"
+
+	// StatusDegraded marks a check that only passed after retrying
+	StatusDegraded = "degraded"
+)
+
+// Limits on the confirmation settings of a website
+const (
+	MaxRetries           = 10
+	MaxConfirmationCount = 20
+	maxRetryBackoff      = 30 * time.Second
+)
+
+// IsUpStatus reports whether a check status counts as the website being up
+func IsUpStatus(status string) bool {
+	return status == StatusUp || status == StatusDegraded
+}
+
+// Confirmation controls how sure the monitor must be before it changes a
+// website's state
+type Confirmation struct {
+	// Retries is how many times a failed check is retried before it counts
+	// as a failure
+	Retries int `json:"retries"`
+
+	// RetryDelay is the delay before the first retry in milliseconds. It
+	// doubles for each following retry.
+	RetryDelay int `json:"retry_delay"`
+
+	// FailureThreshold is how many consecutive failed checks take an up
+	// website down. 0 uses the default of 1.
+	FailureThreshold int `json:"failure_threshold"`
+
+	// RecoveryThreshold is how many consecutive passing checks bring a
+	// down website back up. 0 uses the default of 1.
+	RecoveryThreshold int `json:"recovery_threshold"`
+}
+
+// Validate checks the confirmation settings are within bounds
+func (c Confirmation) Validate() error {
+	if c.Retries < 0 || c.Retries > MaxRetries {
+		return fmt.Errorf("retries must be between 0 and %d", MaxRetries)
+	}
+	if c.RetryDelay < 0 {
+		return fmt.Errorf("retry delay must not be negative")
+	}
+	if c.FailureThreshold < 0 || c.FailureThreshold > MaxConfirmationCount {
+		return fmt.Errorf("failure threshold must be between 0 and %d (0 uses the default)", MaxConfirmationCount)
+	}
+	if c.RecoveryThreshold < 0 || c.RecoveryThreshold > MaxConfirmationCount {
+		return fmt.Errorf("recovery threshold must be between 0 and %d (0 uses the default)", MaxConfirmationCount)
+	}
+	return nil
+}
+
+// Threshold returns how many consecutive checks confirm a change away from
+// the current state
+func (c Confirmation) Threshold(currentlyUp bool) int {
+	threshold := c.RecoveryThreshold
+	if currentlyUp {
+		threshold = c.FailureThreshold
+	}
+	if threshold < 1 {
+		return 1
+	}
+	return threshold
+}
+
+// Backoff returns the delay before a retry, counting retries from 1
+func (c Confirmation) Backoff(retry int) time.Duration {
+	delay := time.Duration(c.RetryDelay) * time.Millisecond
+	for i := 1; i < retry && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
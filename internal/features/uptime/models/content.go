@@ -0,0 +1,341 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxAlertDiffLines caps the diff lines included in a content change alert.
+// The full diff is kept with the snapshot.
+const MaxAlertDiffLines = 40
+
+// MaxContentSnapshots is how many snapshots are kept for each website. Older
+// ones are deleted as new ones are stored.
+const MaxContentSnapshots = 50
+
+// maxDiffCells bounds the work of diffing two contents line by line, the
+// product of the numbers of lines compared. Memory only grows with the
+// number of lines. Larger changes are shown as every line removed and added.
+const maxDiffCells = 4_000_000
+
+// diffContext is how many unchanged lines are shown around changes
+const diffContext = 2
+
+// ContentOptions configures content change monitoring of an HTTP website,
+// e.g. to catch a defaced homepage. Ignored elements and text are left out
+// of the comparison so dynamic parts of a page don't raise alerts.
+type ContentOptions struct {
+	// Enabled keeps a snapshot of the page and alerts when it changes
+	Enabled bool `json:"enabled,omitempty"`
+
+	// IgnoreSelectors lists HTML elements left out, as simple selectors
+	// such as div, #clock, .ad or span.timestamp
+	IgnoreSelectors []string `json:"ignore_selectors,omitempty"`
+
+	// IgnorePatterns lists regular expressions for text left out, e.g.
+	// dates or visitor counters
+	IgnorePatterns []string `json:"ignore_patterns,omitempty"`
+}
+
+// Normalize trims the ignored selectors and patterns, dropping empty ones
+func (o ContentOptions) Normalize() ContentOptions {
+	trim := func(values []string) []string {
+		var trimmed []string
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				trimmed = append(trimmed, value)
+			}
+		}
+		return trimmed
+	}
+	o.IgnoreSelectors = trim(o.IgnoreSelectors)
+	o.IgnorePatterns = trim(o.IgnorePatterns)
+	return o
+}
+
+// Validate checks the ignored selectors and patterns can be parsed
+func (o ContentOptions) Validate() error {
+	if _, err := o.Selectors(); err != nil {
+		return err
+	}
+	if _, err := o.Patterns(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Selectors parses the ignored selectors
+func (o ContentOptions) Selectors() ([]Selector, error) {
+	selectors := make([]Selector, 0, len(o.IgnoreSelectors))
+	for _, s := range o.IgnoreSelectors {
+		selector, err := ParseSelector(s)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// Patterns compiles the ignored patterns
+func (o ContentOptions) Patterns() ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(o.IgnorePatterns))
+	for _, p := range o.IgnorePatterns {
+		pattern, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// Selector is a simple CSS selector matching elements by tag, ID and
+// classes, e.g. div#main.banner
+type Selector struct {
+	Tag     string
+	ID      string
+	Classes []string
+}
+
+// selectorPattern matches a simple selector: an optional tag followed by
+// any number of #id and .class parts
+var selectorPattern = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*)?((?:[#.][a-zA-Z0-9_-]+)*)$`)
+
+// selectorPartPattern matches each #id and .class part of a selector
+var selectorPartPattern = regexp.MustCompile(`[#.][^#.]+`)
+
+// ParseSelector parses a simple selector. Combinators and attribute
+// selectors aren't supported.
+func ParseSelector(s string) (Selector, error) {
+	s = strings.TrimSpace(s)
+	match := selectorPattern.FindStringSubmatch(s)
+	if s == "" || match == nil {
+		return Selector{}, fmt.Errorf("invalid selector %q, expected a tag, #id or .class", s)
+	}
+
+	selector := Selector{Tag: strings.ToLower(match[1])}
+	for _, part := range selectorPartPattern.FindAllString(match[2], -1) {
+		if part[0] == '#' {
+			if selector.ID != "" {
+				return Selector{}, fmt.Errorf("invalid selector %q, more than one #id", s)
+			}
+			selector.ID = part[1:]
+		} else {
+			selector.Classes = append(selector.Classes, part[1:])
+		}
+	}
+	return selector, nil
+}
+
+// Matches reports whether an element with a tag, ID and class attribute
+// matches the selector
+func (s Selector) Matches(tag, id, class string) bool {
+	if s.Tag != "" && s.Tag != strings.ToLower(tag) {
+		return false
+	}
+	if s.ID != "" && s.ID != id {
+		return false
+	}
+	classes := strings.Fields(class)
+	for _, required := range s.Classes {
+		if !slices.Contains(classes, required) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentSnapshot is a website's normalized content when it was first seen
+// or changed, with the diff against the snapshot before it
+type ContentSnapshot struct {
+	ID         int       `json:"id"`
+	WebsiteID  int       `json:"website_id"`
+	Hash       string    `json:"hash"`
+	Content    string    `json:"content"`
+	Diff       string    `json:"diff,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// ContentHash returns the hash that identifies normalized content
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// ContentDiff describes the changed lines between two contents
+type ContentDiff struct {
+	Added   int
+	Removed int
+
+	// Lines are the changed lines prefixed with "+ " or "- ", with a few
+	// unchanged lines prefixed with "  " around them. Gaps between changes
+	// are marked with "...".
+	Lines []string
+}
+
+// Summary describes how many lines changed
+func (d ContentDiff) Summary() string {
+	return fmt.Sprintf("%d lines added, %d removed", d.Added, d.Removed)
+}
+
+// Text returns the diff's lines, at most limit of them when limit is positive
+func (d ContentDiff) Text(limit int) string {
+	lines := d.Lines
+	if limit > 0 && len(lines) > limit {
+		lines = append(lines[:limit:limit], fmt.Sprintf("... %d more lines", len(d.Lines)-limit))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffContent compares two normalized contents line by line
+func DiffContent(previous, current string) ContentDiff {
+	a, b := splitLines(previous), splitLines(current)
+
+	// Only the lines between the common prefix and suffix need comparing
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, line := range midA {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range midB {
+			ops = append(ops, diffOp{'+', line})
+		}
+	} else {
+		ops = diffLines(midA, midB, ops)
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	// Keep the changes and the unchanged lines close to them
+	var diff ContentDiff
+	lastShown := -1
+	for k, o := range ops {
+		switch o.kind {
+		case '+':
+			diff.Added++
+		case '-':
+			diff.Removed++
+		}
+
+		near := false
+		for c := max(0, k-diffContext); c <= min(len(ops)-1, k+diffContext); c++ {
+			if ops[c].kind != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+
+		if lastShown >= 0 && k > lastShown+1 {
+			diff.Lines = append(diff.Lines, "...")
+		}
+		diff.Lines = append(diff.Lines, string(o.kind)+" "+o.line)
+		lastShown = k
+	}
+	return diff
+}
+
+// diffOp is a line of the merged contents: ' ' for unchanged lines, '-' for
+// removed and '+' for added ones
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines appends the ops turning a into b along their longest common
+// subsequence. It uses Hirschberg's divide and conquer so only a couple of
+// rows of the LCS table are kept at a time, rather than the whole table.
+func diffLines(a, b []string, ops []diffOp) []diffOp {
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		return ops
+	case len(a) == 1:
+		k := slices.Index(b, a[0])
+		if k < 0 {
+			ops = append(ops, diffOp{'-', a[0]})
+			return diffLines(nil, b, ops)
+		}
+		ops = diffLines(nil, b[:k], ops)
+		ops = append(ops, diffOp{' ', a[0]})
+		return diffLines(nil, b[k+1:], ops)
+	}
+
+	// Split a in half and b where the LCS lengths of the halves, one
+	// computed forwards and one backwards, add up to the most
+	mid := len(a) / 2
+	forward := lcsLengths(a[:mid], b)
+	backward := lcsLengths(reversed(a[mid:]), reversed(b))
+	split, best := 0, -1
+	for k := 0; k <= len(b); k++ {
+		if length := forward[k] + backward[len(b)-k]; length > best {
+			split, best = k, length
+		}
+	}
+
+	ops = diffLines(a[:mid], b[:split], ops)
+	return diffLines(a[mid:], b[split:], ops)
+}
+
+// lcsLengths returns the last row of the LCS table of a and b: the length
+// of the longest common subsequence of a and each prefix of b
+func lcsLengths(a, b []string) []int {
+	previous, current := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				current[j+1] = previous[j] + 1
+			} else {
+				current[j+1] = max(previous[j+1], current[j])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous
+}
+
+// reversed returns a reversed copy of lines
+func reversed(lines []string) []string {
+	r := slices.Clone(lines)
+	slices.Reverse(r)
+	return r
+}
+
+// splitLines splits content into lines, returning none for empty content
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
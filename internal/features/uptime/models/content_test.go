@@ -0,0 +1,147 @@
+package models
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffContent(t *testing.T) {
+	previous := "Welcome\nAbout us\nOur products\nContact\nFooter"
+	current := "Welcome\nAbout us\nHacked by someone\nContact\nFooter"
+
+	diff := DiffContent(previous, current)
+	if diff.Added != 1 || diff.Removed != 1 {
+		t.Fatalf("Expected 1 line added and 1 removed, got %+v", diff)
+	}
+	want := []string{"  Welcome", "  About us", "- Our products", "+ Hacked by someone", "  Contact", "  Footer"}
+	if !slices.Equal(diff.Lines, want) {
+		t.Errorf("Expected %q, got %q", want, diff.Lines)
+	}
+	if diff.Summary() != "1 lines added, 1 removed" {
+		t.Errorf("Unexpected summary %q", diff.Summary())
+	}
+
+	// Unchanged lines far from any change are left out
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", i+1)
+	}
+	changed := slices.Clone(lines)
+	changed[0], changed[19] = "first", "last"
+	diff = DiffContent(strings.Join(lines, "\n"), strings.Join(changed, "\n"))
+	if !slices.Contains(diff.Lines, "...") || len(diff.Lines) != 9 {
+		t.Errorf("Expected two hunks separated by a gap, got %q", diff.Lines)
+	}
+
+	if text := diff.Text(2); !strings.HasSuffix(text, "... 7 more lines") {
+		t.Errorf("Expected the text to be capped, got %q", text)
+	}
+
+	if diff := DiffContent("", "Hello"); diff.Added != 1 || diff.Removed != 0 {
+		t.Errorf("Expected a single added line, got %+v", diff)
+	}
+}
+
+func TestDiffLinesIsMinimal(t *testing.T) {
+	// lcsLength is the textbook quadratic LCS the diff must match
+	lcsLength := func(a, b []string) int {
+		table := make([][]int, len(a)+1)
+		for i := range table {
+			table[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					table[i][j] = table[i+1][j+1] + 1
+				} else {
+					table[i][j] = max(table[i+1][j], table[i][j+1])
+				}
+			}
+		}
+		return table[0][0]
+	}
+
+	random := rand.New(rand.NewPCG(1, 2))
+	lines := func() []string {
+		out := make([]string, random.IntN(40))
+		for i := range out {
+			out[i] = string(rune('a' + random.IntN(4)))
+		}
+		return out
+	}
+
+	for range 200 {
+		a, b := lines(), lines()
+		ops := diffLines(a, b, nil)
+
+		var fromA, fromB []string
+		changes := 0
+		for _, op := range ops {
+			if op.kind != '+' {
+				fromA = append(fromA, op.line)
+			}
+			if op.kind != '-' {
+				fromB = append(fromB, op.line)
+			}
+			if op.kind != ' ' {
+				changes++
+			}
+		}
+		if !slices.Equal(fromA, a) || !slices.Equal(fromB, b) {
+			t.Fatalf("Diff of %q and %q doesn't reproduce them: %v", a, b, ops)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+			t.Fatalf("Diff of %q and %q has %d changes, want %d", a, b, changes, want)
+		}
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	selector, err := ParseSelector("DIV#clock.live.small")
+	if err != nil {
+		t.Fatalf("Failed to parse selector: %v", err)
+	}
+	if selector.Tag != "div" || selector.ID != "clock" || !slices.Equal(selector.Classes, []string{"live", "small"}) {
+		t.Fatalf("Unexpected selector %+v", selector)
+	}
+	if !selector.Matches("div", "clock", "small live extra") {
+		t.Error("Expected the selector to match an element with extra classes")
+	}
+	if selector.Matches("span", "clock", "live small") || selector.Matches("div", "clock", "live") {
+		t.Error("Expected the selector not to match another tag or missing class")
+	}
+
+	for _, value := range []string{"", "div > p", "#a#b", "[data-x]", ".ad:hover"} {
+		if _, err := ParseSelector(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestContentOptionsValidate(t *testing.T) {
+	options := ContentOptions{
+		Enabled:         true,
+		IgnoreSelectors: []string{" #clock ", ""},
+		IgnorePatterns:  []string{`\d{4}-\d{2}-\d{2}`, "  "},
+	}.Normalize()
+	if !slices.Equal(options.IgnoreSelectors, []string{"#clock"}) || len(options.IgnorePatterns) != 1 {
+		t.Fatalf("Expected empty entries dropped, got %+v", options)
+	}
+	if err := options.Validate(); err != nil {
+		t.Errorf("Expected valid options, got %v", err)
+	}
+
+	if err := (ContentOptions{IgnorePatterns: []string{"(unclosed"}}).Validate(); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+	if err := (ContentOptions{IgnoreSelectors: []string{"div p"}}).Validate(); err == nil {
+		t.Error("Expected an unsupported selector to be rejected")
+	}
+
+	website := Website{Name: "Example", URL: "example.com:22", CheckType: CheckTypeTCP, Content: ContentOptions{Enabled: true}}
+	if err := website.Validate(); err == nil {
+		t.Error("Expected content monitoring of a TCP check to be rejected")
+	}
+}
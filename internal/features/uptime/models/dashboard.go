@@ -0,0 +1,78 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// WebsiteGroup is a section of the dashboard listing the websites that share
+// a tag. Websites without tags are grouped under an empty tag.
+type WebsiteGroup struct {
+	Tag      string
+	Websites []DashboardWebsite
+}
+
+// DashboardView is what the uptime dashboard shows
+type DashboardView struct {
+	// Websites are the websites shown, after filtering by Tag
+	Websites []DashboardWebsite
+
+	// Total is how many websites are monitored, including any filtered out
+	Total int
+
+	// Limit is the most websites that may be monitored, 0 for no limit
+	Limit int
+
+	// Tags lists every tag to filter by
+	Tags []Tag
+
+	// Tag is the tag the dashboard is filtered by, if any
+	Tag string
+
+	// Grouped shows a section per tag rather than a single grid
+	Grouped bool
+}
+
+// AtLimit reports whether no more websites may be added
+func (v DashboardView) AtLimit() bool {
+	return v.Limit > 0 && v.Total >= v.Limit
+}
+
+// Groups returns the sections the dashboard shows: one per tag when grouped,
+// otherwise a single section under the filter tag
+func (v DashboardView) Groups() []WebsiteGroup {
+	if v.Grouped {
+		return GroupByTag(v.Websites)
+	}
+	return []WebsiteGroup{{Tag: v.Tag, Websites: v.Websites}}
+}
+
+// GroupByTag groups websites by tag in tag order. A website with several
+// tags is listed under each, and websites without tags come last.
+func GroupByTag(websites []DashboardWebsite) []WebsiteGroup {
+	var groups []WebsiteGroup
+	var untagged []DashboardWebsite
+	index := make(map[string]int)
+
+	for _, website := range websites {
+		if len(website.Website.Tags) == 0 {
+			untagged = append(untagged, website)
+			continue
+		}
+		for _, tag := range website.Website.Tags {
+			i, ok := index[tag]
+			if !ok {
+				i = len(groups)
+				index[tag] = i
+				groups = append(groups, WebsiteGroup{Tag: tag})
+			}
+			groups[i].Websites = append(groups[i].Websites, website)
+		}
+	}
+
+	slices.SortFunc(groups, func(a, b WebsiteGroup) int { return strings.Compare(a.Tag, b.Tag) })
+	if len(untagged) > 0 {
+		groups = append(groups, WebsiteGroup{Websites: untagged})
+	}
+	return groups
+}
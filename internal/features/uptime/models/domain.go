@@ -0,0 +1,155 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// DefaultDomainExpiryThresholds are the days before a domain's registration
+// expires that warnings are sent at
+var DefaultDomainExpiryThresholds = []int{30, 14, 7, 1}
+
+// Domain is the registration of a registrable domain websites are on, as
+// last looked up through RDAP
+type Domain struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Registrar string     `json:"registrar,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+
+	// Error is why the last lookup failed, cleared by the next successful
+	// one. The registration from before the failure is kept.
+	Error string `json:"error,omitempty"`
+}
+
+// DaysLeft returns the whole days until the registration expires, negative
+// once it has
+func (d Domain) DaysLeft(now time.Time) int {
+	if d.ExpiresAt == nil {
+		return 0
+	}
+	left := d.ExpiresAt.Sub(now)
+	days := int(left / (24 * time.Hour))
+	if left < 0 {
+		days--
+	}
+	return days
+}
+
+// IsExpired reports whether the registration has expired at now
+func (d Domain) IsExpired(now time.Time) bool {
+	return d.ExpiresAt != nil && now.After(*d.ExpiresAt)
+}
+
+// DueThreshold returns the most urgent threshold the domain's registration
+// has crossed at now, or zero once it has expired. It reports false while
+// no threshold has been crossed or the expiry isn't known.
+func (d Domain) DueThreshold(thresholds []int, now time.Time) (int, bool) {
+	if d.ExpiresAt == nil {
+		return 0, false
+	}
+	if d.IsExpired(now) {
+		return 0, true
+	}
+
+	daysLeft := d.DaysLeft(now)
+	due, ok := 0, false
+	for _, threshold := range thresholds {
+		if daysLeft <= threshold && (!ok || threshold < due) {
+			due, ok = threshold, true
+		}
+	}
+	return due, ok
+}
+
+// ParseDomainThresholds reads a comma separated list of days, returned in
+// descending order
+func ParseDomainThresholds(raw string) ([]int, error) {
+	var thresholds []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		days, err := strconv.Atoi(part)
+		if err != nil || days <= 0 || days > 365 {
+			return nil, fmt.Errorf("invalid domain expiry threshold %q, expected days between 1 and 365", part)
+		}
+		if !seen[days] {
+			seen[days] = true
+			thresholds = append(thresholds, days)
+		}
+	}
+	if len(thresholds) == 0 {
+		return nil, fmt.Errorf("at least one domain expiry threshold is required")
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(thresholds)))
+	return thresholds, nil
+}
+
+// RegistrableDomain returns the domain a host name is registered under,
+// e.g. example.co.uk for www.example.co.uk. Only suffixes managed by ICANN
+// count, so hosts on shared hosting suffixes such as github.io resolve to
+// the hosting provider's domain. IP addresses and names outside any public
+// suffix aren't registered and return an error.
+func RegistrableDomain(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" || net.ParseIP(host) != nil {
+		return "", fmt.Errorf("%q is not a domain name", host)
+	}
+
+	suffix, icann := publicsuffix.PublicSuffix(host)
+	for !icann {
+		_, parent, ok := strings.Cut(suffix, ".")
+		if !ok {
+			return "", fmt.Errorf("%q is not under a public suffix", host)
+		}
+		suffix, icann = publicsuffix.PublicSuffix(parent)
+	}
+	if host == suffix {
+		return "", fmt.Errorf("%q is a public suffix", host)
+	}
+
+	rest := strings.TrimSuffix(host, "."+suffix)
+	return rest[strings.LastIndex(rest, ".")+1:] + "." + suffix, nil
+}
+
+// Hostname returns the host name a website's checks target, empty for
+// heartbeat websites
+func (w Website) Hostname() string {
+	switch w.Type() {
+	case CheckTypeHTTP:
+		u, err := url.Parse(w.URL)
+		if err != nil {
+			return ""
+		}
+		return u.Hostname()
+	case CheckTypeTCP, CheckTypeTLS:
+		host, _, err := SplitHostPort(w.URL, DefaultTLSPort)
+		if err != nil {
+			return ""
+		}
+		return host
+	case CheckTypeDNS:
+		return w.URL
+	default:
+		return ""
+	}
+}
+
+// Domain returns the registrable domain of the website's host, reporting
+// false for websites that aren't on a registered domain
+func (w Website) Domain() (string, bool) {
+	domain, err := RegistrableDomain(w.Hostname())
+	return domain, err == nil
+}
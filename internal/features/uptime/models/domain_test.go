@@ -0,0 +1,86 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRegistrableDomain(t *testing.T) {
+	cases := map[string]string{
+		"example.com":         "example.com",
+		"WWW.Example.com.":    "example.com",
+		"shop.example.co.uk":  "example.co.uk",
+		"blog.user.github.io": "github.io",
+	}
+	for host, want := range cases {
+		if got, err := RegistrableDomain(host); err != nil || got != want {
+			t.Errorf("Expected %q for %q, got %q, %v", want, host, got, err)
+		}
+	}
+
+	for _, host := range []string{"", "192.0.2.1", "::1", "localhost", "db.internal", "co.uk"} {
+		if got, err := RegistrableDomain(host); err == nil {
+			t.Errorf("Expected %q to have no registrable domain, got %q", host, got)
+		}
+	}
+
+	websites := map[string]Website{
+		"example.com":   {URL: "https://status.example.com:8443/health"},
+		"example.org":   {URL: "mail.example.org:25", CheckType: CheckTypeTCP},
+		"example.net":   {URL: "example.net", CheckType: CheckTypeTLS},
+		"example.co.uk": {URL: "www.example.co.uk", CheckType: CheckTypeDNS},
+	}
+	for want, website := range websites {
+		if got, ok := website.Domain(); !ok || got != want {
+			t.Errorf("Expected %q for %s, got %q", want, website.URL, got)
+		}
+	}
+	if got, ok := (Website{CheckType: CheckTypeHeartbeat, HeartbeatToken: "abc"}).Domain(); ok {
+		t.Errorf("Expected heartbeats to have no domain, got %q", got)
+	}
+}
+
+func TestDomainDueThreshold(t *testing.T) {
+	thresholds, err := ParseDomainThresholds(" 7, 30,,14,7")
+	if err != nil {
+		t.Fatalf("Failed to parse thresholds: %v", err)
+	}
+	if want := []int{30, 14, 7}; !slices.Equal(thresholds, want) {
+		t.Fatalf("Expected %v, got %v", want, thresholds)
+	}
+	for _, raw := range []string{"", "0", "abc", "400"} {
+		if _, err := ParseDomainThresholds(raw); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+	}
+
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		expiresAt := now.Add(time.Duration(days)*24*time.Hour + time.Hour)
+		return &expiresAt
+	}
+
+	cases := []struct {
+		domain    Domain
+		threshold int
+		due       bool
+	}{
+		{Domain{}, 0, false},
+		{Domain{ExpiresAt: at(90)}, 0, false},
+		{Domain{ExpiresAt: at(30)}, 30, true},
+		{Domain{ExpiresAt: at(10)}, 14, true},
+		{Domain{ExpiresAt: at(2)}, 7, true},
+		{Domain{ExpiresAt: at(-3)}, 0, true},
+	}
+	for _, c := range cases {
+		threshold, due := c.domain.DueThreshold(thresholds, now)
+		if threshold != c.threshold || due != c.due {
+			t.Errorf("Expected %d, %v for %+v, got %d, %v", c.threshold, c.due, c.domain.ExpiresAt, threshold, due)
+		}
+	}
+
+	if days := (Domain{ExpiresAt: at(-3)}).DaysLeft(now); days != -3 {
+		t.Errorf("Expected -3 days left, got %d", days)
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Topics of the live events the monitor publishes
+const (
+	// EventCheck is published for every stored check result
+	EventCheck = "uptime.check"
+
+	// EventStatus is published when a website's confirmed status changes
+	EventStatus = "uptime.status"
+
+	// EventIncident is published when an incident opens or resolves
+	EventIncident = "uptime.incident"
+)
+
+// CheckEvent describes a stored check result
+type CheckEvent struct {
+	WebsiteID    int       `json:"website_id"`
+	Status       string    `json:"status"`
+	StatusCode   int       `json:"status_code"`
+	ResponseTime int64     `json:"response_time"`
+	Error        string    `json:"error,omitempty"`
+	Maintenance  bool      `json:"maintenance"`
+	CheckedAt    time.Time `json:"checked_at"`
+}
+
+// StatusEvent describes a change of a website's confirmed status
+type StatusEvent struct {
+	WebsiteID int    `json:"website_id"`
+	Previous  string `json:"previous"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// IncidentChange describes an incident opening or resolving. Action is
+// IncidentEventOpened or IncidentEventResolved.
+type IncidentChange struct {
+	WebsiteID int    `json:"website_id"`
+	Action    string `json:"action"`
+	Cause     string `json:"cause,omitempty"`
+}
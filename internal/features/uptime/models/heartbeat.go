@@ -0,0 +1,28 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+)
+
+// CheckTypeHeartbeat websites aren't probed, they are expected to ping the
+// monitor at least once per check interval
+const CheckTypeHeartbeat = "heartbeat"
+
+// HeartbeatPathPrefix is where heartbeat ping URLs are served
+const HeartbeatPathPrefix = "/uptime/ping/"
+
+// NewHeartbeatToken returns a random secret for a heartbeat ping URL
+func NewHeartbeatToken() (string, error) {
+	randomBytes := make([]byte, 20)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("failed to generate heartbeat token: %w", err)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes), nil
+}
+
+// HeartbeatPath returns the ping path for a heartbeat token
+func HeartbeatPath(token string) string {
+	return HeartbeatPathPrefix + token
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Incident lifecycle states
+const (
+	IncidentOpen         = "open"
+	IncidentAcknowledged = "acknowledged"
+	IncidentResolved     = "resolved"
+)
+
+// Incident timeline event types
+const (
+	IncidentEventOpened       = "opened"
+	IncidentEventAcknowledged = "acknowledged"
+	IncidentEventRootCause    = "root_cause"
+	IncidentEventComment      = "comment"
+	IncidentEventResolved     = "resolved"
+)
+
+// Incident represents a downtime period, opened by the monitor when a
+// website goes down and resolved when it recovers
+type Incident struct {
+	ID             int             `json:"id"`
+	WebsiteID      int             `json:"website_id"`
+	Status         string          `json:"status"`
+	Cause          string          `json:"cause,omitempty"`
+	RootCause      string          `json:"root_cause,omitempty"`
+	StartedAt      time.Time       `json:"started_at"`
+	AcknowledgedAt *time.Time      `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string          `json:"acknowledged_by,omitempty"`
+	ResolvedAt     *time.Time      `json:"resolved_at,omitempty"`
+	Duration       time.Duration   `json:"duration"`
+	Timeline       []IncidentEvent `json:"timeline,omitempty"`
+}
+
+// IncidentEvent is a single entry in an incident's timeline
+type IncidentEvent struct {
+	ID         int       `json:"id"`
+	IncidentID int       `json:"incident_id"`
+	Type       string    `json:"type"`
+	Message    string    `json:"message,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IsResolved reports whether the website has recovered
+func (i Incident) IsResolved() bool {
+	return i.ResolvedAt != nil
+}
+
+// DowntimeExcluding returns the incident's duration minus the time covered
+// by maintenance windows
+func (i Incident) DowntimeExcluding(windows []MaintenanceWindow) time.Duration {
+	end := i.StartedAt.Add(i.Duration)
+	return i.Duration - MaintenanceDuration(windows, i.StartedAt, end)
+}
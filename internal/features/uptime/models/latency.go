@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// LatencyBaselineWindow is how far back a website's latency baseline looks
+const LatencyBaselineWindow = 24 * time.Hour
+
+// MinBaselineSamples is how many checks a latency baseline needs before
+// deviations from it count
+const MinBaselineSamples = 10
+
+// HasLatencyLimits reports whether the policy marks slow checks degraded
+func (p AlertPolicy) HasLatencyLimits() bool {
+	return p.LatencyThreshold > 0 || p.LatencyDeviation > 0
+}
+
+// LatencyBreach returns why a response time breaches the policy's latency
+// limits, or an empty string if it doesn't. A zero baseline means there is
+// no baseline yet and only the absolute threshold applies.
+func (p AlertPolicy) LatencyBreach(responseTime, baseline int64) string {
+	if p.LatencyThreshold > 0 && responseTime > int64(p.LatencyThreshold) {
+		return fmt.Sprintf("response time %d ms exceeds the %d ms threshold", responseTime, p.LatencyThreshold)
+	}
+	if p.LatencyDeviation > 0 && baseline > 0 {
+		limit := baseline + baseline*int64(p.LatencyDeviation)/100
+		if responseTime > limit {
+			return fmt.Sprintf("response time %d ms is more than %d%% above the %d ms baseline", responseTime, p.LatencyDeviation, baseline)
+		}
+	}
+	return ""
+}
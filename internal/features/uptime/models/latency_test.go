@@ -0,0 +1,35 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLatencyBreach(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       AlertPolicy
+		responseTime int64
+		baseline     int64
+		want         string
+	}{
+		{name: "no limits", policy: AlertPolicy{}, responseTime: 9000},
+		{name: "within threshold", policy: AlertPolicy{LatencyThreshold: 1000}, responseTime: 1000},
+		{name: "over threshold", policy: AlertPolicy{LatencyThreshold: 1000}, responseTime: 1001, want: "1000 ms threshold"},
+		{name: "no baseline yet", policy: AlertPolicy{LatencyDeviation: 50}, responseTime: 9000},
+		{name: "within deviation", policy: AlertPolicy{LatencyDeviation: 50}, responseTime: 300, baseline: 200},
+		{name: "over deviation", policy: AlertPolicy{LatencyDeviation: 50}, responseTime: 301, baseline: 200, want: "200 ms baseline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.LatencyBreach(tt.responseTime, tt.baseline)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("LatencyBreach() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := (AlertPolicy{LatencyThreshold: -1}).Validate(); err == nil {
+		t.Errorf("Expected a negative threshold to be rejected")
+	}
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxMaintenanceDuration bounds how long a recurring window may last
+const maxMaintenanceDuration = 7 * 24 * 60
+
+// MaintenanceWindow is a period of planned downtime. During a window alerts
+// are suppressed, checks are tagged as maintenance and the time is left out
+// of uptime statistics. A window is either one-off, between StartsAt and
+// EndsAt, or recurring, starting whenever Schedule fires and lasting
+// Duration minutes.
+type MaintenanceWindow struct {
+	ID int `json:"id"`
+
+	// WebsiteID is zero for windows covering every website
+	WebsiteID int    `json:"website_id,omitempty"`
+	Name      string `json:"name"`
+
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+
+	// Schedule is a five field cron expression evaluated in Timezone
+	Schedule string `json:"schedule,omitempty"`
+	Duration int    `json:"duration,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Period is a span of time
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// IsGlobal reports whether the window covers every website
+func (w MaintenanceWindow) IsGlobal() bool {
+	return w.WebsiteID == 0
+}
+
+// IsRecurring reports whether the window repeats on a schedule
+func (w MaintenanceWindow) IsRecurring() bool {
+	return w.Schedule != ""
+}
+
+// Validate checks the window is either a valid one-off or recurring window
+func (w MaintenanceWindow) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("maintenance window name is required")
+	}
+	if _, err := w.location(); err != nil {
+		return fmt.Errorf("invalid maintenance timezone %q", w.Timezone)
+	}
+
+	if w.IsRecurring() {
+		if w.StartsAt != nil || w.EndsAt != nil {
+			return fmt.Errorf("recurring maintenance windows take a schedule, not start and end times")
+		}
+		if _, err := ParseCron(w.Schedule); err != nil {
+			return err
+		}
+		if w.Duration <= 0 || w.Duration > maxMaintenanceDuration {
+			return fmt.Errorf("maintenance duration must be between 1 and %d minutes", maxMaintenanceDuration)
+		}
+		return nil
+	}
+
+	if w.StartsAt == nil || w.EndsAt == nil {
+		return fmt.Errorf("maintenance windows need a start and end, or a schedule")
+	}
+	if !w.EndsAt.After(*w.StartsAt) {
+		return fmt.Errorf("maintenance must end after it starts")
+	}
+	return nil
+}
+
+// Periods returns the spans of the window falling between from and to
+func (w MaintenanceWindow) Periods(from, to time.Time) []Period {
+	if !w.IsRecurring() {
+		if w.StartsAt == nil || w.EndsAt == nil {
+			return nil
+		}
+		if period, ok := clipPeriod(Period{*w.StartsAt, *w.EndsAt}, from, to); ok {
+			return []Period{period}
+		}
+		return nil
+	}
+
+	schedule, err := ParseCron(w.Schedule)
+	if err != nil {
+		return nil
+	}
+	loc, err := w.location()
+	if err != nil {
+		return nil
+	}
+	duration := time.Duration(w.Duration) * time.Minute
+
+	// Occurrences starting up to a duration before from still overlap it
+	var periods []Period
+	t := from.Add(-duration).In(loc).Truncate(time.Minute)
+	for t.Before(to) {
+		if !schedule.matchesHour(t) {
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+		if schedule.Matches(t) {
+			if period, ok := clipPeriod(Period{t, t.Add(duration)}, from, to); ok {
+				periods = append(periods, period)
+			}
+		}
+		t = t.Add(time.Minute)
+	}
+	return mergePeriods(periods)
+}
+
+// Active reports whether the window covers t
+func (w MaintenanceWindow) Active(t time.Time) bool {
+	return len(w.Periods(t, t.Add(time.Second))) > 0
+}
+
+// NextStart returns when the window next starts after t, looking up to a
+// year ahead, or nil if it never does
+func (w MaintenanceWindow) NextStart(t time.Time) *time.Time {
+	if !w.IsRecurring() {
+		if w.StartsAt != nil && w.StartsAt.After(t) {
+			return w.StartsAt
+		}
+		return nil
+	}
+
+	schedule, err := ParseCron(w.Schedule)
+	if err != nil {
+		return nil
+	}
+	loc, err := w.location()
+	if err != nil {
+		return nil
+	}
+
+	limit := t.AddDate(1, 0, 0)
+	next := t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	for next.Before(limit) {
+		if !schedule.matchesHour(next) {
+			next = next.Add(time.Duration(60-next.Minute()) * time.Minute)
+			continue
+		}
+		if schedule.Matches(next) {
+			return &next
+		}
+		next = next.Add(time.Minute)
+	}
+	return nil
+}
+
+// location returns the window's timezone, defaulting to the server's
+func (w MaintenanceWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
+
+// ActiveMaintenance returns the first of windows covering t, or nil
+func ActiveMaintenance(windows []MaintenanceWindow, t time.Time) *MaintenanceWindow {
+	for i := range windows {
+		if windows[i].Active(t) {
+			return &windows[i]
+		}
+	}
+	return nil
+}
+
+// MaintenanceDuration returns how much of the span between from and to is
+// covered by windows, counting overlapping windows once
+func MaintenanceDuration(windows []MaintenanceWindow, from, to time.Time) time.Duration {
+	var periods []Period
+	for _, window := range windows {
+		periods = append(periods, window.Periods(from, to)...)
+	}
+
+	var total time.Duration
+	for _, period := range mergePeriods(periods) {
+		total += period.End.Sub(period.Start)
+	}
+	return total
+}
+
+// clipPeriod limits a period to the span between from and to, reporting
+// whether anything is left
+func clipPeriod(period Period, from, to time.Time) (Period, bool) {
+	if period.Start.Before(from) {
+		period.Start = from
+	}
+	if period.End.After(to) {
+		period.End = to
+	}
+	return period, period.Start.Before(period.End)
+}
+
+// mergePeriods sorts periods and joins those that overlap or touch
+func mergePeriods(periods []Period) []Period {
+	if len(periods) < 2 {
+		return periods
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+
+	merged := []Period{periods[0]}
+	for _, period := range periods[1:] {
+		last := &merged[len(merged)-1]
+		if period.Start.After(last.End) {
+			merged = append(merged, period)
+			continue
+		}
+		if period.End.After(last.End) {
+			last.End = period.End
+		}
+	}
+	return merged
+}
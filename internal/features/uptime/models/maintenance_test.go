@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		at      time.Time
+		matches bool
+	}{
+		{"0 2 * * 0", time.Date(2024, 5, 5, 2, 0, 0, 0, time.UTC), true},
+		{"0 2 * * 7", time.Date(2024, 5, 5, 2, 0, 0, 0, time.UTC), true},
+		{"0 2 * * 0", time.Date(2024, 5, 6, 2, 0, 0, 0, time.UTC), false},
+		{"*/15 9-17 * * 1-5", time.Date(2024, 5, 6, 9, 45, 0, 0, time.UTC), true},
+		{"*/15 9-17 * * 1-5", time.Date(2024, 5, 6, 9, 50, 0, 0, time.UTC), false},
+		{"30 4 1,15 * *", time.Date(2024, 5, 15, 4, 30, 0, 0, time.UTC), true},
+		// Restricted day of month and day of week match either
+		{"0 0 1 * 1", time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) failed: %v", tt.expr, err)
+		}
+		if got := schedule.Matches(tt.at); got != tt.matches {
+			t.Errorf("%q at %v: expected %v, got %v", tt.expr, tt.at, tt.matches, got)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("Expected ParseCron(%q) to fail", expr)
+		}
+	}
+}
+
+func TestMaintenanceWindowPeriods(t *testing.T) {
+	// Every day at 23:30 for an hour, wrapping past midnight
+	recurring := MaintenanceWindow{Name: "Nightly", Schedule: "30 23 * * *", Duration: 60, Timezone: "UTC"}
+	if err := recurring.Validate(); err != nil {
+		t.Fatalf("Expected a valid window: %v", err)
+	}
+
+	if !recurring.Active(time.Date(2024, 5, 2, 0, 15, 0, 0, time.UTC)) {
+		t.Errorf("Expected the window to be active after midnight")
+	}
+	if recurring.Active(time.Date(2024, 5, 2, 0, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the window to have ended")
+	}
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+	if got := MaintenanceDuration([]MaintenanceWindow{recurring}, from, to); got != 3*time.Hour {
+		t.Errorf("Expected 3h of maintenance over three days, got %v", got)
+	}
+
+	start := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	once := MaintenanceWindow{Name: "Deploy", StartsAt: &start, EndsAt: &end}
+
+	// Overlapping windows count once
+	if got := MaintenanceDuration([]MaintenanceWindow{recurring, once}, from, to); got != 3*time.Hour+30*time.Minute {
+		t.Errorf("Expected overlapping windows to be merged, got %v", got)
+	}
+
+	incident := Incident{StartedAt: start, Duration: 2 * time.Hour}
+	if got := incident.DowntimeExcluding([]MaintenanceWindow{recurring, once}); got != 30*time.Minute {
+		t.Errorf("Expected 30m of downtime outside maintenance, got %v", got)
+	}
+
+	if next := recurring.NextStart(from); next == nil || !next.Equal(time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next start at 23:30, got %v", next)
+	}
+}
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	start := time.Now()
+	end := start.Add(-time.Hour)
+	invalid := []MaintenanceWindow{
+		{Name: "", Schedule: "0 2 * * *", Duration: 60},
+		{Name: "No times"},
+		{Name: "Backwards", StartsAt: &start, EndsAt: &end},
+		{Name: "No duration", Schedule: "0 2 * * *"},
+		{Name: "Bad schedule", Schedule: "0 2 * *", Duration: 60},
+		{Name: "Bad timezone", Schedule: "0 2 * * *", Duration: 60, Timezone: "Mars/Olympus"},
+	}
+	for _, window := range invalid {
+		if err := window.Validate(); err == nil {
+			t.Errorf("Expected %q to be invalid", window.Name)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// MetricsPeriod is a period the website detail charts can show
+type MetricsPeriod struct {
+	Name     string
+	Duration time.Duration
+
+	// Resolution is the bucket size used when none is requested
+	Resolution string
+}
+
+// MetricsPeriods are the periods the website detail charts can show
+var MetricsPeriods = []MetricsPeriod{
+	{Name: "24h", Duration: 24 * time.Hour, Resolution: ResolutionHour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour, Resolution: ResolutionHour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour, Resolution: ResolutionDay},
+}
+
+// GetMetricsPeriod looks up a metrics period by name
+func GetMetricsPeriod(name string) (MetricsPeriod, error) {
+	for _, period := range MetricsPeriods {
+		if period.Name == name {
+			return period, nil
+		}
+	}
+	return MetricsPeriod{}, fmt.Errorf("unknown period %q, expected 24h, 7d or 30d", name)
+}
+
+// ValidateResolution checks a resolution is one rollups are kept at
+func ValidateResolution(resolution string) error {
+	if resolution != ResolutionHour && resolution != ResolutionDay {
+		return fmt.Errorf("unknown resolution %q, expected hour or day", resolution)
+	}
+	return nil
+}
+
+// LatencySummary is the latency and uptime of a whole metrics period.
+// Percentiles are exact while raw checks cover the period, and merged from
+// the rollups once some have been pruned.
+type LatencySummary struct {
+	Checks          int     `json:"checks"`
+	Uptime          float64 `json:"uptime"`
+	LatencySamples  int     `json:"latency_samples"`
+	AvgResponseTime float64 `json:"avg_response_time"`
+	P50ResponseTime int64   `json:"p50_response_time"`
+	P95ResponseTime int64   `json:"p95_response_time"`
+	P99ResponseTime int64   `json:"p99_response_time"`
+
+	// Approximate is set when the percentiles were merged from rollups
+	Approximate bool `json:"approximate"`
+}
+
+// StatusCodeCount is how many checks returned a status code. Code 0 counts
+// checks that got no response.
+type StatusCodeCount struct {
+	Code  int `json:"code"`
+	Count int `json:"count"`
+}
+
+// HeatmapDay is a UTC day of hourly uptime. Hours without checks are nil.
+type HeatmapDay struct {
+	Date  time.Time  `json:"date"`
+	Hours []*float64 `json:"hours"`
+}
+
+// WebsiteMetrics is what the website detail charts show for a period
+type WebsiteMetrics struct {
+	WebsiteID   int               `json:"website_id"`
+	Period      string            `json:"period"`
+	Resolution  string            `json:"resolution"`
+	Since       time.Time         `json:"since"`
+	Summary     LatencySummary    `json:"summary"`
+	Points      []CheckRollup     `json:"points"`
+	StatusCodes []StatusCodeCount `json:"status_codes"`
+	Heatmap     []HeatmapDay      `json:"heatmap"`
+
+	// Timing is the average timing of the period's HTTP checks
+	Timing *CheckTiming `json:"timing,omitempty"`
+}
+
+// NewHeatmap lays hourly rollups out as a grid of UTC days and hours, from
+// since's day to now's
+func NewHeatmap(hourly []CheckRollup, since, now time.Time) []HeatmapDay {
+	first := TruncateBucket(since, ResolutionDay)
+	last := TruncateBucket(now, ResolutionDay)
+
+	var days []HeatmapDay
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		days = append(days, HeatmapDay{Date: date, Hours: make([]*float64, 24)})
+	}
+
+	for _, rollup := range hourly {
+		if rollup.TotalChecks() == 0 {
+			continue
+		}
+		start := rollup.BucketStart.UTC()
+		index := int(TruncateBucket(start, ResolutionDay).Sub(first) / (24 * time.Hour))
+		if index < 0 || index >= len(days) {
+			continue
+		}
+		uptime := rollup.Percentage()
+		days[index].Hours[start.Hour()] = &uptime
+	}
+	return days
+}
+
+// SortedStatusCodes lists status code counts in code order
+func SortedStatusCodes(counts map[int]int) []StatusCodeCount {
+	codes := make([]StatusCodeCount, 0, len(counts))
+	for code, count := range counts {
+		codes = append(codes, StatusCodeCount{Code: code, Count: count})
+	}
+	slices.SortFunc(codes, func(a, b StatusCodeCount) int { return a.Code - b.Code })
+	return codes
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetMetricsPeriod(t *testing.T) {
+	period, err := GetMetricsPeriod("7d")
+	if err != nil || period.Duration != 7*24*time.Hour || period.Resolution != ResolutionHour {
+		t.Errorf("Unexpected period %+v, %v", period, err)
+	}
+	if _, err := GetMetricsPeriod("1y"); err == nil {
+		t.Error("Expected an error for an unknown period")
+	}
+	if err := ValidateResolution("minute"); err == nil {
+		t.Error("Expected an error for an unknown resolution")
+	}
+}
+
+func TestNewHeatmap(t *testing.T) {
+	now := time.Date(2025, 3, 3, 12, 30, 0, 0, time.UTC)
+	since := now.Add(-48 * time.Hour)
+	hourly := []CheckRollup{
+		{BucketStart: time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC), UpChecks: 1, DownChecks: 1},
+		{BucketStart: time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC), UpChecks: 4},
+		{BucketStart: time.Date(2025, 3, 2, 5, 0, 0, 0, time.UTC)},
+	}
+
+	days := NewHeatmap(hourly, since, now)
+	if len(days) != 3 || !days[0].Date.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected three days from March 1st, got %+v", days)
+	}
+	if uptime := days[0].Hours[13]; uptime == nil || *uptime != 50 {
+		t.Errorf("Expected 50%% at 13:00 on the first day, got %v", uptime)
+	}
+	if uptime := days[2].Hours[12]; uptime == nil || *uptime != 100 {
+		t.Errorf("Expected 100%% at 12:00 today, got %v", uptime)
+	}
+	if days[1].Hours[5] != nil {
+		t.Error("Expected hours without checks to be empty")
+	}
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+)
+
+// Notification channel types
+const (
+	ChannelWebhook = "webhook"
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+	ChannelNtfy    = "ntfy"
+	ChannelGotify  = "gotify"
+	ChannelEmail   = "email"
+)
+
+// ChannelTypes lists the channel types in the order the UI offers them
+var ChannelTypes = []string{ChannelEmail, ChannelWebhook, ChannelSlack, ChannelDiscord, ChannelNtfy, ChannelGotify}
+
+// NotificationChannel is a destination alerts can be delivered to
+type NotificationChannel struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// URL is the webhook URL for webhook, Slack and Discord channels, the
+	// topic URL for ntfy and the server URL for Gotify
+	URL string `json:"url,omitempty"`
+
+	// Token authenticates push channels: a bearer token for webhooks and
+	// ntfy, the application token for Gotify
+	Token string `json:"-"`
+
+	// Recipient is the address email channels deliver to
+	Recipient string `json:"recipient,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the channel has the settings its type needs
+func (c NotificationChannel) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("channel name is required")
+	}
+
+	switch c.Type {
+	case ChannelEmail:
+		if _, err := mail.ParseAddress(c.Recipient); err != nil {
+			return fmt.Errorf("invalid recipient %q", c.Recipient)
+		}
+	case ChannelWebhook, ChannelSlack, ChannelDiscord, ChannelNtfy:
+		return validateChannelURL(c.URL)
+	case ChannelGotify:
+		if c.Token == "" {
+			return fmt.Errorf("gotify channels need an application token")
+		}
+		return validateChannelURL(c.URL)
+	default:
+		return fmt.Errorf("unsupported channel type %q", c.Type)
+	}
+	return nil
+}
+
+// MarshalJSON redacts the channel's URL when it embeds a secret, keeping
+// only the host it delivers to
+func (c NotificationChannel) MarshalJSON() ([]byte, error) {
+	type plain NotificationChannel
+	redacted := plain(c)
+	if c.secretURL() && c.URL != "" {
+		redacted.URL = redactedValue
+	}
+
+	return json.Marshal(struct {
+		plain
+		Target   string `json:"target"`
+		TokenSet bool   `json:"token_set"`
+	}{redacted, c.Target(), c.Token != ""})
+}
+
+// KeepSecrets restores the stored URL and token of a channel being edited
+// when the edit sends them back redacted or leaves them blank. Nothing is
+// kept when the edit changes the channel's type.
+func (c *NotificationChannel) KeepSecrets(current NotificationChannel) {
+	if c.Type != current.Type {
+		return
+	}
+	if c.URL == "" || c.URL == redactedValue {
+		c.URL = current.URL
+	}
+	if c.Token == "" || c.Token == redactedValue {
+		c.Token = current.Token
+	}
+}
+
+// secretURL reports whether the channel's URL is a credential in itself, as
+// webhook URLs and ntfy topics are
+func (c NotificationChannel) secretURL() bool {
+	switch c.Type {
+	case ChannelWebhook, ChannelSlack, ChannelDiscord, ChannelNtfy:
+		return true
+	}
+	return false
+}
+
+// Target returns where the channel delivers, for display
+func (c NotificationChannel) Target() string {
+	if c.Type == ChannelEmail {
+		return c.Recipient
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
+	}
+	// Webhook paths often embed secrets, so only show the host
+	return u.Host
+}
+
+// ChannelTypeLabel returns a human readable name for a channel type
+func ChannelTypeLabel(channelType string) string {
+	switch channelType {
+	case ChannelWebhook:
+		return "Webhook"
+	case ChannelSlack:
+		return "Slack"
+	case ChannelDiscord:
+		return "Discord"
+	case ChannelNtfy:
+		return "ntfy"
+	case ChannelGotify:
+		return "Gotify"
+	default:
+		return "Email"
+	}
+}
+
+func validateChannelURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid channel URL %q", raw)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package models
+
+// Page describes which part of a longer list a JSON API response holds
+type Page struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+
+	// Total is the length of the whole list
+	Total int `json:"total"`
+}
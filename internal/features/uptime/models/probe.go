@@ -0,0 +1,139 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrProbeUnauthorized is returned for probe requests with a wrong
+// registration token or key, and for registrations while none is configured
+var ErrProbeUnauthorized = errors.New("probe is not authorized")
+
+// ErrProbeNameTaken is returned when a probe registers under the name of
+// another probe without presenting that probe's key
+var ErrProbeNameTaken = errors.New("probe name is already registered")
+
+// maxProbeNameLength is the longest a probe's location name may be
+const maxProbeNameLength = 64
+
+// Probe is an agent checking websites from another location and reporting
+// the results to this Ark
+type Probe struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	RegisteredAt time.Time  `json:"registered_at"`
+	LastSeenAt   *time.Time `json:"last_seen_at,omitempty"`
+}
+
+// ProbeRegistration is returned to a probe that registers. The key
+// authenticates its later requests and is only ever shown this once.
+type ProbeRegistration struct {
+	Probe
+	Key string `json:"key"`
+}
+
+// ProbeCheck is a website as assigned to probes. Unlike a Website's JSON it
+// carries the request secrets, since probes must send them too.
+type ProbeCheck struct {
+	ID            int          `json:"id"`
+	URL           string       `json:"url"`
+	Name          string       `json:"name"`
+	CheckType     string       `json:"check_type"`
+	CheckInterval int          `json:"check_interval"`
+	Request       ProbeRequest `json:"request"`
+	DNS           DNSOptions   `json:"dns"`
+	Assertions    Assertions   `json:"assertions"`
+	Retries       int          `json:"retries"`
+	RetryDelay    int          `json:"retry_delay"`
+}
+
+// ProbeRequest is RequestOptions without the redaction of its JSON
+type ProbeRequest struct {
+	Method          string            `json:"method,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	AuthType        string            `json:"auth_type,omitempty"`
+	AuthUsername    string            `json:"auth_username,omitempty"`
+	AuthSecret      string            `json:"auth_secret,omitempty"`
+	UserAgent       string            `json:"user_agent,omitempty"`
+	FollowRedirects bool              `json:"follow_redirects"`
+}
+
+// NewProbeCheck describes a website for probes
+func NewProbeCheck(website Website) ProbeCheck {
+	return ProbeCheck{
+		ID:            website.ID,
+		URL:           website.URL,
+		Name:          website.Name,
+		CheckType:     website.CheckType,
+		CheckInterval: website.CheckInterval,
+		Request:       ProbeRequest(website.Request),
+		DNS:           website.DNS,
+		Assertions:    website.Assertions,
+		Retries:       website.Confirmation.Retries,
+		RetryDelay:    website.Confirmation.RetryDelay,
+	}
+}
+
+// Website turns the check back into the website a probe's checkers expect
+func (c ProbeCheck) Website() Website {
+	return Website{
+		ID:            c.ID,
+		URL:           c.URL,
+		Name:          c.Name,
+		CheckType:     c.CheckType,
+		CheckInterval: c.CheckInterval,
+		Request:       RequestOptions(c.Request),
+		DNS:           c.DNS,
+		Assertions:    c.Assertions,
+		Confirmation:  Confirmation{Retries: c.Retries, RetryDelay: c.RetryDelay},
+		IsActive:      true,
+	}
+}
+
+// ProbeResult is the latest result a probe reported for a website
+type ProbeResult struct {
+	WebsiteID    int       `json:"website_id"`
+	IsUp         bool      `json:"is_up"`
+	StatusCode   int       `json:"status_code"`
+	ResponseTime int64     `json:"response_time"`
+	Error        string    `json:"error,omitempty"`
+	CheckedAt    time.Time `json:"checked_at"`
+
+	// Location is the name of the probe that reported the result. Probes
+	// don't send it, it is filled in from their key.
+	Location string `json:"location,omitempty"`
+}
+
+// NormalizeProbeName trims a probe's location name and checks it is usable
+func NormalizeProbeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("probe name must not be empty")
+	}
+	if len(name) > maxProbeNameLength {
+		return "", fmt.Errorf("probe name is longer than %d characters", maxProbeNameLength)
+	}
+	return name, nil
+}
+
+// NewProbeKey returns a random key for a probe to authenticate with
+func NewProbeKey() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("failed to generate probe key: %w", err)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes), nil
+}
+
+// HashProbeKey returns the hash a probe key is stored and looked up by
+func HashProbeKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,265 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ReportMonthLayout is the format of report months, e.g. 2026-09
+const ReportMonthLayout = "2006-01"
+
+// DefaultSLATarget is the uptime percentage websites are reported against
+// when no target is configured
+const DefaultSLATarget = 99.9
+
+// ParseReportMonth parses a report month into the start of the UTC month
+func ParseReportMonth(value string) (time.Time, error) {
+	month, err := time.Parse(ReportMonthLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q, expected YYYY-MM", value)
+	}
+	return month, nil
+}
+
+// ReportMonth returns the start of the UTC month containing t
+func ReportMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// RecentReportMonths returns the starts of the count months up to and
+// including now's, newest first
+func RecentReportMonths(now time.Time, count int) []time.Time {
+	current := ReportMonth(now)
+	months := make([]time.Time, count)
+	for i := range months {
+		months[i] = current.AddDate(0, -i, 0)
+	}
+	return months
+}
+
+// ValidateSLATarget checks an SLA target is a percentage below 100
+func ValidateSLATarget(target float64) error {
+	if target <= 0 || target >= 100 {
+		return fmt.Errorf("SLA target must be above 0 and below 100")
+	}
+	return nil
+}
+
+// SLAReport is the monthly SLA summary of every website
+type SLAReport struct {
+	Month       time.Time    `json:"month"`
+	Target      float64      `json:"target"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Websites    []WebsiteSLA `json:"websites"`
+
+	// Partial marks a report of a month that hasn't ended yet
+	Partial bool `json:"partial"`
+}
+
+// Name returns the report's month as YYYY-MM
+func (r SLAReport) Name() string {
+	return r.Month.Format(ReportMonthLayout)
+}
+
+// Title returns the report's month for display, e.g. September 2026
+func (r SLAReport) Title() string {
+	return r.Month.Format("January 2006")
+}
+
+// MetTarget returns how many websites met the SLA target
+func (r SLAReport) MetTarget() int {
+	met := 0
+	for _, website := range r.Websites {
+		if website.MetTarget {
+			met++
+		}
+	}
+	return met
+}
+
+// reportCSVHeader names the columns of a report's CSV rows
+var reportCSVHeader = []string{
+	"month", "website_id", "name", "url", "checks", "down_checks", "uptime", "target", "met_target",
+	"error_budget_seconds", "budget_remaining_seconds", "budget_remaining", "downtime_seconds", "incidents",
+	"latency_samples", "avg_response_time", "p50_response_time", "p95_response_time", "p99_response_time",
+}
+
+// CSV returns the report as a header row followed by a row per website.
+// Incidents are counted rather than listed.
+func (r SLAReport) CSV() [][]string {
+	rows := [][]string{reportCSVHeader}
+	for _, w := range r.Websites {
+		rows = append(rows, []string{
+			r.Name(),
+			strconv.Itoa(w.WebsiteID),
+			w.Name,
+			w.URL,
+			strconv.Itoa(w.Checks),
+			strconv.Itoa(w.DownChecks),
+			strconv.FormatFloat(w.Uptime, 'f', 4, 64),
+			strconv.FormatFloat(r.Target, 'f', -1, 64),
+			strconv.FormatBool(w.MetTarget),
+			strconv.FormatInt(w.ErrorBudgetSeconds, 10),
+			strconv.FormatInt(w.BudgetRemainingSeconds, 10),
+			strconv.FormatFloat(w.BudgetRemaining, 'f', 2, 64),
+			strconv.FormatInt(w.DowntimeSeconds, 10),
+			strconv.Itoa(len(w.Incidents)),
+			strconv.Itoa(w.LatencySamples),
+			strconv.FormatFloat(w.AvgResponseTime, 'f', 1, 64),
+			strconv.FormatInt(w.P50ResponseTime, 10),
+			strconv.FormatInt(w.P95ResponseTime, 10),
+			strconv.FormatInt(w.P99ResponseTime, 10),
+		})
+	}
+	return rows
+}
+
+// WebsiteSLA is a website's uptime, error budget, incidents and latency over
+// a report month. The error budget is the downtime the SLA target allows
+// over the whole month, spent at the rate checks failed.
+type WebsiteSLA struct {
+	WebsiteID int    `json:"website_id"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+
+	Checks     int     `json:"checks"`
+	DownChecks int     `json:"down_checks"`
+	Uptime     float64 `json:"uptime"`
+	MetTarget  bool    `json:"met_target"`
+
+	ErrorBudgetSeconds     int64 `json:"error_budget_seconds"`
+	BudgetRemainingSeconds int64 `json:"budget_remaining_seconds"`
+
+	// BudgetRemaining is the percentage of the error budget left, negative
+	// once it is overspent
+	BudgetRemaining float64 `json:"budget_remaining"`
+
+	// DowntimeSeconds is the time the month's incidents lasted, excluding
+	// maintenance
+	DowntimeSeconds int64            `json:"downtime_seconds"`
+	Incidents       []ReportIncident `json:"incidents"`
+
+	// Latency percentiles are exact for days that haven't been rolled up
+	// and sample-weighted means of the daily percentiles for the rest
+	LatencySamples  int     `json:"latency_samples"`
+	AvgResponseTime float64 `json:"avg_response_time"`
+	P50ResponseTime int64   `json:"p50_response_time"`
+	P95ResponseTime int64   `json:"p95_response_time"`
+	P99ResponseTime int64   `json:"p99_response_time"`
+}
+
+// ReportIncident is an incident within a report month. Its downtime only
+// counts the part within the month, excluding maintenance.
+type ReportIncident struct {
+	ID              int        `json:"id"`
+	Cause           string     `json:"cause,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	DowntimeSeconds int64      `json:"downtime_seconds"`
+}
+
+// Downtime returns the incident's downtime for display
+func (i ReportIncident) Downtime() string {
+	return FormatSeconds(i.DowntimeSeconds)
+}
+
+// Downtime returns the website's downtime for display
+func (w WebsiteSLA) Downtime() string {
+	return FormatSeconds(w.DowntimeSeconds)
+}
+
+// ErrorBudget returns the website's error budget for display
+func (w WebsiteSLA) ErrorBudget() string {
+	return FormatSeconds(w.ErrorBudgetSeconds)
+}
+
+// BudgetLeft returns what's left of the website's error budget for display
+func (w WebsiteSLA) BudgetLeft() string {
+	if w.BudgetRemainingSeconds < 0 {
+		return "-" + FormatSeconds(-w.BudgetRemainingSeconds)
+	}
+	return FormatSeconds(w.BudgetRemainingSeconds)
+}
+
+// NewWebsiteSLA works out a website's SLA over the month starting at month
+// from its check summary and incidents, as of now
+func NewWebsiteSLA(website Website, summary CheckRollup, incidents []Incident, windows []MaintenanceWindow, month time.Time, target float64, now time.Time) WebsiteSLA {
+	end := month.AddDate(0, 1, 0)
+	if now.After(end) {
+		now = end
+	}
+	elapsed := now.Sub(month)
+
+	sla := WebsiteSLA{
+		WebsiteID:       website.ID,
+		Name:            website.Name,
+		URL:             website.URL,
+		Checks:          summary.TotalChecks(),
+		DownChecks:      summary.DownChecks,
+		Uptime:          summary.Percentage(),
+		LatencySamples:  summary.LatencySamples,
+		AvgResponseTime: summary.AvgResponseTime,
+		P50ResponseTime: summary.P50ResponseTime,
+		P95ResponseTime: summary.P95ResponseTime,
+		P99ResponseTime: summary.P99ResponseTime,
+		Incidents:       []ReportIncident{},
+	}
+	sla.MetTarget = sla.Uptime >= target
+
+	budget := end.Sub(month).Seconds() * (100 - target) / 100
+	spent := elapsed.Seconds() * (100 - sla.Uptime) / 100
+	sla.ErrorBudgetSeconds = int64(math.Round(budget))
+	sla.BudgetRemainingSeconds = int64(math.Round(budget - spent))
+	if budget > 0 {
+		sla.BudgetRemaining = (budget - spent) / budget * 100
+	}
+
+	for _, incident := range incidents {
+		from, to := incident.StartedAt, now
+		if incident.ResolvedAt != nil && incident.ResolvedAt.Before(to) {
+			to = *incident.ResolvedAt
+		}
+		if from.Before(month) {
+			from = month
+		}
+		if !from.Before(to) {
+			continue
+		}
+
+		downtime := to.Sub(from) - MaintenanceDuration(windows, from, to)
+		sla.DowntimeSeconds += int64(downtime.Seconds())
+		sla.Incidents = append(sla.Incidents, ReportIncident{
+			ID:              incident.ID,
+			Cause:           incident.Cause,
+			StartedAt:       incident.StartedAt,
+			ResolvedAt:      incident.ResolvedAt,
+			DowntimeSeconds: int64(downtime.Seconds()),
+		})
+	}
+	return sla
+}
+
+// FormatSeconds formats a number of seconds as days, hours, minutes and
+// seconds, leaving out leading zero units
+func FormatSeconds(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	days := int64(d / (24 * time.Hour))
+	d -= time.Duration(days) * 24 * time.Hour
+	hours := int64(d / time.Hour)
+	d -= time.Duration(hours) * time.Hour
+	minutes := int64(d / time.Minute)
+	secs := int64((d - time.Duration(minutes)*time.Minute) / time.Second)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm %ds", minutes, secs)
+	}
+	return fmt.Sprintf("%ds", secs)
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewWebsiteSLA(t *testing.T) {
+	month := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	end := month.AddDate(0, 1, 0)
+	website := Website{ID: 1, Name: "Example", URL: "https://example.com"}
+
+	// 99% uptime over a whole 30 day month against a 99.9% target
+	summary := CheckRollup{UpChecks: 990, DownChecks: 10, LatencySamples: 990, P95ResponseTime: 250}
+
+	// The first incident started the month before, the second is still open
+	started := month.Add(-time.Hour)
+	resolved := month.Add(30 * time.Minute)
+	openedAt := month.AddDate(0, 0, 29)
+	incidents := []Incident{
+		{ID: 1, StartedAt: started, ResolvedAt: &resolved, Cause: "timeout"},
+		{ID: 2, StartedAt: openedAt},
+	}
+	// Half of the open incident was spent in maintenance
+	windowEnd := openedAt.Add(12 * time.Hour)
+	windows := []MaintenanceWindow{{Name: "Upgrade", StartsAt: &openedAt, EndsAt: &windowEnd}}
+
+	sla := NewWebsiteSLA(website, summary, incidents, windows, month, 99.9, end.Add(time.Hour))
+
+	if sla.MetTarget || sla.Uptime != 99 {
+		t.Errorf("Expected 99%% uptime to miss the target, got %+v", sla)
+	}
+	// 0.1% of 30 days is 43m12s, and 1% of it has been spent
+	if sla.ErrorBudgetSeconds != 2592 {
+		t.Errorf("Expected a 2592s error budget, got %d", sla.ErrorBudgetSeconds)
+	}
+	if sla.BudgetRemainingSeconds != 2592-25920 || math.Round(sla.BudgetRemaining) != -900 {
+		t.Errorf("Expected the budget to be overspent ninefold, got %ds (%.1f%%)", sla.BudgetRemainingSeconds, sla.BudgetRemaining)
+	}
+	if sla.P95ResponseTime != 250 {
+		t.Errorf("Expected latency from the summary, got %d", sla.P95ResponseTime)
+	}
+
+	if len(sla.Incidents) != 2 {
+		t.Fatalf("Expected both incidents, got %+v", sla.Incidents)
+	}
+	if sla.Incidents[0].DowntimeSeconds != 30*60 {
+		t.Errorf("Expected the first incident clipped to the month, got %ds", sla.Incidents[0].DowntimeSeconds)
+	}
+	if sla.Incidents[1].DowntimeSeconds != 12*60*60 {
+		t.Errorf("Expected the open incident to end with the month less maintenance, got %ds", sla.Incidents[1].DowntimeSeconds)
+	}
+	if sla.DowntimeSeconds != 30*60+12*60*60 {
+		t.Errorf("Expected total downtime to add up, got %ds", sla.DowntimeSeconds)
+	}
+}
+
+func TestNewWebsiteSLAPartialMonth(t *testing.T) {
+	month := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	summary := CheckRollup{UpChecks: 99, DownChecks: 1}
+
+	// A third of the way through the month only a third of the budget the
+	// failure rate implies has been spent
+	sla := NewWebsiteSLA(Website{ID: 1}, summary, nil, nil, month, 99, month.AddDate(0, 0, 10))
+	if !sla.MetTarget {
+		t.Errorf("Expected 99%% uptime to meet a 99%% target")
+	}
+	if sla.ErrorBudgetSeconds != 25920 || sla.BudgetRemainingSeconds != 25920-8640 {
+		t.Errorf("Expected a third of the budget spent, got %d of %d left", sla.BudgetRemainingSeconds, sla.ErrorBudgetSeconds)
+	}
+	if sla.Incidents == nil {
+		t.Errorf("Expected an empty incident list rather than nil")
+	}
+}
+
+func TestParseReportMonth(t *testing.T) {
+	month, err := ParseReportMonth("2026-02")
+	if err != nil || !month.Equal(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected February 2026, got %v, %v", month, err)
+	}
+	for _, value := range []string{"", "2026", "2026-13", "02-2026"} {
+		if _, err := ParseReportMonth(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestRecentReportMonths(t *testing.T) {
+	months := RecentReportMonths(time.Date(2026, time.February, 14, 9, 0, 0, 0, time.UTC), 3)
+	want := []time.Time{
+		time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i := range want {
+		if !months[i].Equal(want[i]) {
+			t.Errorf("Expected month %d to be %v, got %v", i, want[i], months[i])
+		}
+	}
+}
+
+func TestSLAReportCSV(t *testing.T) {
+	report := SLAReport{
+		Month:  time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		Target: 99.9,
+		Websites: []WebsiteSLA{
+			{WebsiteID: 3, Name: "Example", URL: "https://example.com", Uptime: 99.95, MetTarget: true, Incidents: []ReportIncident{{ID: 1}}},
+		},
+	}
+
+	rows := report.CSV()
+	if len(rows) != 2 {
+		t.Fatalf("Expected a header and a row, got %v", rows)
+	}
+	if len(rows[1]) != len(rows[0]) {
+		t.Fatalf("Expected %d columns, got %d", len(rows[0]), len(rows[1]))
+	}
+	row := map[string]string{}
+	for i, column := range rows[0] {
+		row[column] = rows[1][i]
+	}
+	if row["month"] != "2026-09" || row["website_id"] != "3" || row["uptime"] != "99.9500" || row["target"] != "99.9" || row["met_target"] != "true" || row["incidents"] != "1" {
+		t.Errorf("Unexpected row %v", row)
+	}
+}
+
+func TestFormatSeconds(t *testing.T) {
+	tests := map[int64]string{
+		0:      "0s",
+		59:     "59s",
+		61:     "1m 1s",
+		3660:   "1h 1m",
+		2592:   "43m 12s",
+		90061:  "1d 1h 1m",
+		864000: "10d 0h 0m",
+	}
+	for seconds, want := range tests {
+		if got := FormatSeconds(seconds); got != want {
+			t.Errorf("FormatSeconds(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Authentication types supported for monitored requests
+const (
+	AuthNone   = ""
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+// DefaultUserAgent is sent when a website has no custom User-Agent
+const DefaultUserAgent = "The Ark Uptime Monitor/1.0"
+
+// redactedValue replaces secrets in JSON responses
+const redactedValue = "********"
+
+// RequestOptions describes how the monitor builds the HTTP request for a website
+type RequestOptions struct {
+	Method          string            `json:"method,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	AuthType        string            `json:"auth_type,omitempty"`
+	AuthUsername    string            `json:"auth_username,omitempty"`
+	AuthSecret      string            `json:"-"`
+	UserAgent       string            `json:"user_agent,omitempty"`
+	FollowRedirects bool              `json:"follow_redirects"`
+}
+
+// DefaultRequestOptions returns the options used for a plain GET check
+func DefaultRequestOptions() RequestOptions {
+	return RequestOptions{
+		Method:          http.MethodGet,
+		FollowRedirects: true,
+	}
+}
+
+// Validate checks the method and authentication settings
+func (o RequestOptions) Validate() error {
+	switch o.RequestMethod() {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		return fmt.Errorf("unsupported request method %q", o.Method)
+	}
+
+	switch o.AuthType {
+	case AuthNone:
+	case AuthBasic:
+		if o.AuthUsername == "" {
+			return fmt.Errorf("basic auth requires a username")
+		}
+	case AuthBearer:
+		if o.AuthSecret == "" {
+			return fmt.Errorf("bearer auth requires a token")
+		}
+	default:
+		return fmt.Errorf("unsupported auth type %q", o.AuthType)
+	}
+
+	return nil
+}
+
+// RequestMethod returns the configured method, defaulting to GET
+func (o RequestOptions) RequestMethod() string {
+	if o.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(o.Method)
+}
+
+// MarshalJSON never echoes the auth secret or sensitive header values back
+func (o RequestOptions) MarshalJSON() ([]byte, error) {
+	type plain RequestOptions
+	redacted := plain(o)
+
+	if len(o.Headers) > 0 {
+		redacted.Headers = make(map[string]string, len(o.Headers))
+		for name, value := range o.Headers {
+			if isSensitiveHeader(name) {
+				value = redactedValue
+			}
+			redacted.Headers[name] = value
+		}
+	}
+
+	// The redacted secret can be sent back to keep the stored one
+	var authSecret string
+	if o.AuthSecret != "" {
+		authSecret = redactedValue
+	}
+
+	return json.Marshal(struct {
+		plain
+		AuthSecret    string `json:"auth_secret,omitempty"`
+		AuthSecretSet bool   `json:"auth_secret_set"`
+	}{redacted, authSecret, o.AuthSecret != ""})
+}
+
+// isSensitiveHeader reports whether a header usually carries credentials
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	return strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "key")
+}
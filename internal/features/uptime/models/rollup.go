@@ -0,0 +1,155 @@
+package models
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Rollup resolutions
+const (
+	ResolutionHour = "hour"
+	ResolutionDay  = "day"
+)
+
+// HourlyRollupRetention is how long hourly rollups are kept. Longer periods
+// are reported from daily rollups, which are kept forever.
+const HourlyRollupRetention = 90 * 24 * time.Hour
+
+// CheckRollup aggregates a website's checks over an hour or a day, starting
+// at BucketStart in UTC. Pending checks and checks taken during maintenance
+// aren't counted, and latency is only sampled from up and degraded checks.
+type CheckRollup struct {
+	WebsiteID       int         `json:"website_id"`
+	Resolution      string      `json:"resolution"`
+	BucketStart     time.Time   `json:"bucket_start"`
+	UpChecks        int         `json:"up_checks"`
+	DegradedChecks  int         `json:"degraded_checks"`
+	DownChecks      int         `json:"down_checks"`
+	LatencySamples  int         `json:"latency_samples"`
+	MinResponseTime int64       `json:"min_response_time"`
+	AvgResponseTime float64     `json:"avg_response_time"`
+	P50ResponseTime int64       `json:"p50_response_time"`
+	P95ResponseTime int64       `json:"p95_response_time"`
+	P99ResponseTime int64       `json:"p99_response_time"`
+	MaxResponseTime int64       `json:"max_response_time"`
+	StatusCodes     map[int]int `json:"status_codes"`
+}
+
+// TotalChecks returns the number of checks counted towards uptime
+func (r CheckRollup) TotalChecks() int {
+	return r.UpChecks + r.DegradedChecks + r.DownChecks
+}
+
+// Percentage returns the share of checks that were up or degraded, or 100
+// without checks
+func (r CheckRollup) Percentage() float64 {
+	if r.TotalChecks() == 0 {
+		return 100
+	}
+	return float64(r.UpChecks+r.DegradedChecks) / float64(r.TotalChecks()) * 100
+}
+
+// TruncateBucket returns the start of the UTC hour or day containing t
+func TruncateBucket(t time.Time, resolution string) time.Time {
+	t = t.UTC()
+	if resolution == ResolutionDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// RollupChecks aggregates raw checks into a rollup
+func RollupChecks(websiteID int, resolution string, bucketStart time.Time, checks []WebsiteStatus) CheckRollup {
+	rollup := CheckRollup{
+		WebsiteID:   websiteID,
+		Resolution:  resolution,
+		BucketStart: bucketStart,
+		StatusCodes: make(map[int]int),
+	}
+
+	var latencies []int64
+	for _, check := range checks {
+		if check.Maintenance || check.Status == StatusPending {
+			continue
+		}
+		switch check.Status {
+		case StatusUp:
+			rollup.UpChecks++
+		case StatusDegraded:
+			rollup.DegradedChecks++
+		default:
+			rollup.DownChecks++
+		}
+		rollup.StatusCodes[check.StatusCode]++
+		if check.Status == StatusUp || check.Status == StatusDegraded {
+			latencies = append(latencies, check.ResponseTime)
+		}
+	}
+
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		var sum int64
+		for _, latency := range latencies {
+			sum += latency
+		}
+		rollup.LatencySamples = len(latencies)
+		rollup.MinResponseTime = latencies[0]
+		rollup.MaxResponseTime = latencies[len(latencies)-1]
+		rollup.AvgResponseTime = float64(sum) / float64(len(latencies))
+		rollup.P50ResponseTime = Percentile(latencies, 50)
+		rollup.P95ResponseTime = Percentile(latencies, 95)
+		rollup.P99ResponseTime = Percentile(latencies, 99)
+	}
+	return rollup
+}
+
+// Merge adds another rollup's checks to r. Percentiles can't be combined
+// exactly, so merged percentiles are the sample-weighted means of both.
+func (r *CheckRollup) Merge(other CheckRollup) {
+	r.UpChecks += other.UpChecks
+	r.DegradedChecks += other.DegradedChecks
+	r.DownChecks += other.DownChecks
+	if r.StatusCodes == nil {
+		r.StatusCodes = make(map[int]int)
+	}
+	for code, count := range other.StatusCodes {
+		r.StatusCodes[code] += count
+	}
+
+	if other.LatencySamples == 0 {
+		return
+	}
+	if r.LatencySamples == 0 {
+		r.LatencySamples = other.LatencySamples
+		r.MinResponseTime = other.MinResponseTime
+		r.MaxResponseTime = other.MaxResponseTime
+		r.AvgResponseTime = other.AvgResponseTime
+		r.P50ResponseTime = other.P50ResponseTime
+		r.P95ResponseTime = other.P95ResponseTime
+		r.P99ResponseTime = other.P99ResponseTime
+		return
+	}
+
+	total := float64(r.LatencySamples + other.LatencySamples)
+	weight := float64(r.LatencySamples) / total
+	mean := func(a, b int64) int64 {
+		return int64(math.Round(float64(a)*weight + float64(b)*(1-weight)))
+	}
+	r.AvgResponseTime = r.AvgResponseTime*weight + other.AvgResponseTime*(1-weight)
+	r.P50ResponseTime = mean(r.P50ResponseTime, other.P50ResponseTime)
+	r.P95ResponseTime = mean(r.P95ResponseTime, other.P95ResponseTime)
+	r.P99ResponseTime = mean(r.P99ResponseTime, other.P99ResponseTime)
+	r.MinResponseTime = min(r.MinResponseTime, other.MinResponseTime)
+	r.MaxResponseTime = max(r.MaxResponseTime, other.MaxResponseTime)
+	r.LatencySamples += other.LatencySamples
+}
+
+// Percentile returns the nearest-rank percentile p of sorted values
+func Percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
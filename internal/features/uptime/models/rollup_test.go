@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRollupChecks(t *testing.T) {
+	bucket := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	checks := []WebsiteStatus{
+		{Status: StatusUp, StatusCode: 200, ResponseTime: 120},
+		{Status: StatusDegraded, StatusCode: 200, ResponseTime: 900},
+		{Status: StatusDown, StatusCode: 503, ResponseTime: 40},
+		{Status: StatusPending, StatusCode: 503},
+		{Status: StatusDown, StatusCode: 0, Maintenance: true},
+	}
+
+	rollup := RollupChecks(1, ResolutionHour, bucket, checks)
+	if rollup.UpChecks != 1 || rollup.DegradedChecks != 1 || rollup.DownChecks != 1 {
+		t.Errorf("Expected pending and maintenance checks to be skipped, got %+v", rollup)
+	}
+	if rollup.LatencySamples != 2 || rollup.MinResponseTime != 120 || rollup.MaxResponseTime != 900 || rollup.AvgResponseTime != 510 {
+		t.Errorf("Expected latency from up and degraded checks, got %+v", rollup)
+	}
+	if len(rollup.StatusCodes) != 2 || rollup.StatusCodes[200] != 2 || rollup.StatusCodes[503] != 1 {
+		t.Errorf("Unexpected status codes %v", rollup.StatusCodes)
+	}
+	if got := rollup.Percentage(); got < 66.6 || got > 66.7 {
+		t.Errorf("Expected two thirds uptime, got %.2f", got)
+	}
+}
+
+func TestCheckRollupMerge(t *testing.T) {
+	a := CheckRollup{UpChecks: 3, LatencySamples: 3, MinResponseTime: 100, MaxResponseTime: 300, AvgResponseTime: 200, P95ResponseTime: 300, StatusCodes: map[int]int{200: 3}}
+	b := CheckRollup{UpChecks: 1, DownChecks: 1, LatencySamples: 1, MinResponseTime: 50, MaxResponseTime: 50, AvgResponseTime: 50, P95ResponseTime: 50, StatusCodes: map[int]int{200: 1, 500: 1}}
+
+	var merged CheckRollup
+	merged.Merge(a)
+	merged.Merge(b)
+	merged.Merge(CheckRollup{})
+
+	if merged.UpChecks != 4 || merged.DownChecks != 1 || merged.StatusCodes[200] != 4 || merged.StatusCodes[500] != 1 {
+		t.Errorf("Unexpected counts %+v", merged)
+	}
+	if merged.LatencySamples != 4 || merged.MinResponseTime != 50 || merged.MaxResponseTime != 300 {
+		t.Errorf("Unexpected latency range %+v", merged)
+	}
+	if merged.AvgResponseTime != 162.5 || merged.P95ResponseTime != 238 {
+		t.Errorf("Expected sample-weighted averages, got avg %.2f p95 %d", merged.AvgResponseTime, merged.P95ResponseTime)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	tests := map[float64]int64{50: 50, 95: 100, 99: 100, 10: 10, 0: 10}
+	for p, want := range tests {
+		if got := Percentile(values, p); got != want {
+			t.Errorf("Percentile(%v) = %d, want %d", p, got, want)
+		}
+	}
+	if got := Percentile(nil, 95); got != 0 {
+		t.Errorf("Expected 0 without values, got %d", got)
+	}
+}
+
+func TestTruncateBucket(t *testing.T) {
+	at := time.Date(2025, 3, 1, 23, 45, 10, 0, time.FixedZone("EST", -5*3600))
+	if got := TruncateBucket(at, ResolutionHour); !got.Equal(time.Date(2025, 3, 2, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected hour bucket %s", got)
+	}
+	if got := TruncateBucket(at, ResolutionDay); !got.Equal(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected day bucket %s", got)
+	}
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// StatusPageDays is how many days of daily uptime a status page shows
+const StatusPageDays = 90
+
+// Status page and component states, from best to worst
+const (
+	PageOperational   = "operational"
+	PageMaintenance   = "maintenance"
+	PageDegraded      = "degraded"
+	PagePartialOutage = "partial_outage"
+	PageMajorOutage   = "major_outage"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// StatusPage is a public, read-only view of selected websites grouped into
+// named components. It is served at /status/{slug} without authentication.
+type StatusPage struct {
+	ID          int               `json:"id"`
+	Slug        string            `json:"slug"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Components  []StatusComponent `json:"components"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// StatusComponent is a named group of websites on a status page
+type StatusComponent struct {
+	ID         int    `json:"id"`
+	PageID     int    `json:"page_id"`
+	Name       string `json:"name"`
+	Position   int    `json:"position"`
+	WebsiteIDs []int  `json:"website_ids"`
+}
+
+// Validate checks the page has a title and a usable slug
+func (p StatusPage) Validate() error {
+	if p.Title == "" {
+		return fmt.Errorf("status page title is required")
+	}
+	if len(p.Slug) > 64 || !slugPattern.MatchString(p.Slug) {
+		return fmt.Errorf("status page slug %q must be lowercase letters, numbers and dashes", p.Slug)
+	}
+	return nil
+}
+
+// Slugify derives a slug from a title, e.g. "Acme API" becomes "acme-api"
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// DailyUptime counts a website's checks on one day. Checks taken during
+// maintenance aren't counted.
+type DailyUptime struct {
+	Date     time.Time `json:"date"`
+	Checks   int       `json:"checks"`
+	UpChecks int       `json:"up_checks"`
+}
+
+// Percentage returns the share of the day's checks that were up, or 100 on
+// days without checks
+func (d DailyUptime) Percentage() float64 {
+	if d.Checks == 0 {
+		return 100
+	}
+	return float64(d.UpChecks) / float64(d.Checks) * 100
+}
+
+// Status summarises the day for a status bar: "none" without checks, "up"
+// when every check passed, "partial" when most did and "down" otherwise
+func (d DailyUptime) Status() string {
+	switch {
+	case d.Checks == 0:
+		return "none"
+	case d.UpChecks == d.Checks:
+		return "up"
+	case d.Percentage() >= 95:
+		return "partial"
+	default:
+		return "down"
+	}
+}
+
+// PublicStatusPage is what a status page shows customers. It deliberately
+// leaves out website URLs and internal incident notes.
+type PublicStatusPage struct {
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Status      string            `json:"status"`
+	Components  []PublicComponent `json:"components"`
+	Incidents   []PublicIncident  `json:"incidents"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+// PublicComponent is a component's current state and the websites in it
+type PublicComponent struct {
+	Name     string          `json:"name"`
+	Status   string          `json:"status"`
+	Websites []PublicWebsite `json:"websites"`
+}
+
+// PublicWebsite is a website's current state and daily uptime history
+type PublicWebsite struct {
+	Name   string        `json:"name"`
+	Status string        `json:"status"`
+	Uptime float64       `json:"uptime"`
+	Days   []DailyUptime `json:"days"`
+}
+
+// PublicIncident is an active or recent incident on a status page
+type PublicIncident struct {
+	Website    string     `json:"website"`
+	Component  string     `json:"component"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// WebsiteState maps a website's last check onto a status page state
+func WebsiteState(status *WebsiteStatus) string {
+	switch {
+	case status == nil:
+		return PageOperational
+	case status.Maintenance:
+		return PageMaintenance
+	case status.Status == StatusDown:
+		return PageMajorOutage
+	case status.Status == StatusDegraded:
+		return PageDegraded
+	default:
+		return PageOperational
+	}
+}
+
+// CombineStates summarises the states of a group of websites or components:
+// everything down is a major outage and anything down is a partial outage
+func CombineStates(states []string) string {
+	counts := make(map[string]int)
+	for _, state := range states {
+		counts[state]++
+	}
+
+	switch {
+	case len(states) > 0 && counts[PageMajorOutage] == len(states):
+		return PageMajorOutage
+	case counts[PageMajorOutage] > 0 || counts[PagePartialOutage] > 0:
+		return PagePartialOutage
+	case counts[PageDegraded] > 0:
+		return PageDegraded
+	case counts[PageMaintenance] > 0:
+		return PageMaintenance
+	default:
+		return PageOperational
+	}
+}
+
+// OverallUptime returns the uptime across a website's days
+func OverallUptime(days []DailyUptime) float64 {
+	checks, up := 0, 0
+	for _, day := range days {
+		checks += day.Checks
+		up += day.UpChecks
+	}
+	if checks == 0 {
+		return 100
+	}
+	return float64(up) / float64(checks) * 100
+}
@@ -0,0 +1,57 @@
+package models
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Acme API":          "acme-api",
+		"  Acme -- Status ": "acme-status",
+		"Ümlaut Corp!":      "mlaut-corp",
+		"2024 Status":       "2024-status",
+	}
+	for title, want := range tests {
+		if got := Slugify(title); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestStatusPageValidate(t *testing.T) {
+	tests := []struct {
+		page  StatusPage
+		valid bool
+	}{
+		{StatusPage{Title: "Acme", Slug: "acme"}, true},
+		{StatusPage{Title: "Acme", Slug: "acme-status-2"}, true},
+		{StatusPage{Slug: "acme"}, false},
+		{StatusPage{Title: "Acme", Slug: ""}, false},
+		{StatusPage{Title: "Acme", Slug: "Acme"}, false},
+		{StatusPage{Title: "Acme", Slug: "acme/status"}, false},
+		{StatusPage{Title: "Acme", Slug: "-acme"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.page.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, want valid %v", tt.page, err, tt.valid)
+		}
+	}
+}
+
+func TestCombineStates(t *testing.T) {
+	tests := []struct {
+		states []string
+		want   string
+	}{
+		{nil, PageOperational},
+		{[]string{PageOperational, PageOperational}, PageOperational},
+		{[]string{PageOperational, PageMaintenance}, PageMaintenance},
+		{[]string{PageMaintenance, PageDegraded}, PageDegraded},
+		{[]string{PageOperational, PageMajorOutage}, PagePartialOutage},
+		{[]string{PageMajorOutage, PageMajorOutage}, PageMajorOutage},
+		{[]string{PagePartialOutage, PageOperational}, PagePartialOutage},
+	}
+	for _, tt := range tests {
+		if got := CombineStates(tt.states); got != tt.want {
+			t.Errorf("CombineStates(%v) = %q, want %q", tt.states, got, tt.want)
+		}
+	}
+}
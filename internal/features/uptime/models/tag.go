@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxTagLength is the longest a tag may be
+const maxTagLength = 32
+
+// Tag is a label websites are grouped by, e.g. prod, staging or client-x
+type Tag struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+
+	// WebsiteCount is how many websites have the tag
+	WebsiteCount int `json:"website_count"`
+}
+
+// Actions that can be run on every website of a group
+const (
+	GroupActionPause  = "pause"
+	GroupActionResume = "resume"
+	GroupActionCheck  = "check"
+	GroupActionDelete = "delete"
+)
+
+// ValidateGroupAction checks an action is one that can be run on a group
+func ValidateGroupAction(action string) error {
+	switch action {
+	case GroupActionPause, GroupActionResume, GroupActionCheck, GroupActionDelete:
+		return nil
+	}
+	return fmt.Errorf("unknown group action %q, expected pause, resume, check or delete", action)
+}
+
+// NormalizeTag lowercases a tag and replaces spaces with dashes, so "Client X"
+// and "client-x" are the same tag. Tags may only contain letters, digits,
+// dashes, underscores and dots.
+func NormalizeTag(name string) (string, error) {
+	tag := strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	if tag == "" {
+		return "", fmt.Errorf("tag must not be empty")
+	}
+	if len(tag) > maxTagLength {
+		return "", fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+	}
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && !strings.ContainsRune("-_.", r) {
+			return "", fmt.Errorf("tag %q may only contain letters, digits, dashes, underscores and dots", tag)
+		}
+	}
+	return tag, nil
+}
+
+// NormalizeTags normalizes a list of tags, dropping blanks and duplicates,
+// and sorts them
+func NormalizeTags(names []string) ([]string, error) {
+	var tags []string
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		tag, err := NormalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
+
+// ParseTags parses a comma-separated list of tags, as entered in forms
+func ParseTags(value string) ([]string, error) {
+	return NormalizeTags(strings.Split(value, ","))
+}
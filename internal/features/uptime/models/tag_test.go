@@ -0,0 +1,59 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags(" Prod, client x ,, staging,prod")
+	if err != nil {
+		t.Fatalf("Failed to parse tags: %v", err)
+	}
+	if want := []string{"client-x", "prod", "staging"}; !slices.Equal(tags, want) {
+		t.Errorf("Expected %v, got %v", want, tags)
+	}
+
+	if tags, err := ParseTags(""); err != nil || len(tags) != 0 {
+		t.Errorf("Expected no tags, got %v, %v", tags, err)
+	}
+
+	for _, value := range []string{"prod!", "client/x", "a-tag-that-is-far-too-long-to-be-useful"} {
+		if _, err := ParseTags(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestGroupByTag(t *testing.T) {
+	websites := []DashboardWebsite{
+		{Website: Website{ID: 1, Tags: []string{"staging"}}},
+		{Website: Website{ID: 2}},
+		{Website: Website{ID: 3, Tags: []string{"prod", "staging"}}},
+	}
+
+	groups := GroupByTag(websites)
+	var got []string
+	for _, group := range groups {
+		var ids []int
+		for _, website := range group.Websites {
+			ids = append(ids, website.Website.ID)
+		}
+		got = append(got, group.Tag+":"+fmt.Sprint(ids))
+	}
+	if want := []string{"prod:[3]", "staging:[1 3]", ":[2]"}; !slices.Equal(got, want) {
+		t.Errorf("Expected groups %v, got %v", want, got)
+	}
+
+	view := DashboardView{Websites: websites, Tag: "staging", Total: 3, Limit: 3}
+	if groups := view.Groups(); len(groups) != 1 || groups[0].Tag != "staging" {
+		t.Errorf("Expected a single section for the filter tag, got %+v", groups)
+	}
+	if !view.AtLimit() {
+		t.Error("Expected the view to be at its limit")
+	}
+	if (DashboardView{Total: 100}).AtLimit() {
+		t.Error("Expected no limit without one configured")
+	}
+}
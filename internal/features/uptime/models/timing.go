@@ -0,0 +1,39 @@
+package models
+
+// CheckTiming breaks an HTTP check down into the phases of its request, in
+// milliseconds. Phases of every request in a redirect chain are added up,
+// and phases skipped by reusing a connection are 0.
+type CheckTiming struct {
+	DNS     int64 `json:"dns"`
+	Connect int64 `json:"connect"`
+	TLS     int64 `json:"tls"`
+
+	// TTFB is how long the server took to start responding once the
+	// request was sent
+	TTFB int64 `json:"ttfb"`
+
+	// Transfer is how long the response body took to download
+	Transfer int64 `json:"transfer"`
+}
+
+// Total returns the time spent across all phases
+func (t CheckTiming) Total() int64 {
+	return t.DNS + t.Connect + t.TLS + t.TTFB + t.Transfer
+}
+
+// TimingPhase is one phase of a check timing, for display
+type TimingPhase struct {
+	Name     string
+	Duration int64
+}
+
+// Phases lists the timing's phases in request order
+func (t CheckTiming) Phases() []TimingPhase {
+	return []TimingPhase{
+		{Name: "DNS", Duration: t.DNS},
+		{Name: "Connect", Duration: t.Connect},
+		{Name: "TLS", Duration: t.TLS},
+		{Name: "TTFB", Duration: t.TTFB},
+		{Name: "Transfer", Duration: t.Transfer},
+	}
+}
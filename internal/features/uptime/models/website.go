@@ -1,15 +1,75 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrWebsitePaused is returned for checks of a website that is paused
+var ErrWebsitePaused = errors.New("website is paused")
 
 type Website struct {
-	ID            int       `json:"id"`
-	URL           string    `json:"url"`
-	Name          string    `json:"name"`
-	CheckInterval int       `json:"check_interval"`
-	IsActive      bool      `json:"is_active"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            int            `json:"id"`
+	URL           string         `json:"url"`
+	Name          string         `json:"name"`
+	CheckInterval int            `json:"check_interval"`
+	CheckType     string         `json:"check_type"`
+	Request       RequestOptions `json:"request"`
+	DNS           DNSOptions     `json:"dns"`
+	Assertions    Assertions     `json:"assertions"`
+	Confirmation  Confirmation   `json:"confirmation"`
+	Alerts        AlertPolicy    `json:"alerts"`
+	Content       ContentOptions `json:"content"`
+	IsActive      bool           `json:"is_active"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	// HeartbeatToken is the secret in a heartbeat website's ping URL
+	HeartbeatToken string `json:"-"`
+
+	// GracePeriod is how many seconds a heartbeat may be late before the
+	// website is considered down
+	GracePeriod int        `json:"grace_period,omitempty"`
+	LastPingAt  *time.Time `json:"last_ping_at,omitempty"`
+
+	// ResumedAt is when monitoring the website was last resumed after a
+	// pause, or nil if it never was
+	ResumedAt *time.Time `json:"resumed_at,omitempty"`
+
+	// DegradedSince is when the website started breaching its latency
+	// limits, or nil while it responds within them
+	DegradedSince *time.Time `json:"degraded_since,omitempty"`
+
+	// ChannelIDs are the notification channels the website alerts. A
+	// website without channels alerts the default recipient by email.
+	ChannelIDs []int `json:"channel_ids,omitempty"`
+
+	// Tags group the website on the dashboard, normalized and sorted
+	Tags []string `json:"tags"`
+}
+
+// MarshalJSON leaves out the URL of heartbeat websites, which holds their
+// secret ping token. Responses about a single website give the ping URL
+// separately.
+func (w Website) MarshalJSON() ([]byte, error) {
+	type plain Website
+	redacted := plain(w)
+	if w.Type() == CheckTypeHeartbeat {
+		redacted.URL = ""
+	}
+	return json.Marshal(redacted)
+}
+
+// HasChannel reports whether the website alerts the given channel
+func (w Website) HasChannel(channelID int) bool {
+	return slices.Contains(w.ChannelIDs, channelID)
+}
+
+// HasTag reports whether the website is tagged with tag
+func (w Website) HasTag(tag string) bool {
+	return slices.Contains(w.Tags, tag)
 }
 
 type WebsiteStatus struct {
@@ -20,6 +80,12 @@ type WebsiteStatus struct {
 	StatusCode   int       `json:"status_code"`
 	Error        string    `json:"error,omitempty"`
 	CheckedAt    time.Time `json:"checked_at"`
+
+	// Maintenance marks checks taken during a maintenance window
+	Maintenance bool `json:"maintenance,omitempty"`
+
+	// Timing breaks down the response time of HTTP checks
+	Timing *CheckTiming `json:"timing,omitempty"`
 }
 
 // DashboardWebsite combines Website with its current status for the web interface
@@ -27,18 +93,10 @@ type DashboardWebsite struct {
 	Website   Website
 	Status    string
 	CheckedAt *time.Time
-}
 
-// Incident represents a downtime period
-type Incident struct {
-	ID         int           `json:"id"`
-	WebsiteID  int           `json:"website_id"`
-	Status     string        `json:"status"`
-	StartedAt  time.Time     `json:"started_at"`
-	ResolvedAt *time.Time    `json:"resolved_at,omitempty"`
-	Duration   time.Duration `json:"duration"`
-	RootCause  string        `json:"root_cause,omitempty"`
-	Comments   string        `json:"comments,omitempty"`
+	// Message explains a status that isn't up, e.g. the failure behind a
+	// pending check
+	Message string
 }
 
 // UptimeStats represents uptime statistics for a website
@@ -60,4 +118,31 @@ type WebsiteDetailData struct {
 	UptimeStats []UptimeStats  `json:"uptime_stats"`
 	Incidents   []Incident     `json:"incidents"`
 	AvgResponse float64        `json:"avg_response"`
+
+	// AvgTiming is the average timing of the last day's HTTP checks
+	AvgTiming *CheckTiming `json:"avg_timing,omitempty"`
+
+	// PingURL is the absolute ping URL of a heartbeat website
+	PingURL string `json:"ping_url,omitempty"`
+
+	// Channels lists every notification channel the website could alert
+	Channels []NotificationChannel `json:"channels"`
+
+	// AlertHistory lists the most recent alert delivery attempts
+	AlertHistory []AlertRecord `json:"alert_history"`
+
+	// Maintenance lists the maintenance windows covering the website,
+	// including global windows
+	Maintenance []MaintenanceWindow `json:"maintenance"`
+
+	// Locations lists the latest result of each probe agent checking the
+	// website from another location
+	Locations []ProbeResult `json:"locations"`
+
+	// Snapshots lists the most recent content snapshots, newest first
+	Snapshots []ContentSnapshot `json:"snapshots"`
+
+	// Domain is the registration of the website's domain, nil until it has
+	// been looked up or for websites without one
+	Domain *Domain `json:"domain,omitempty"`
 }
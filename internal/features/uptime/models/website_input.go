@@ -0,0 +1,186 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultCheckInterval is the check interval in seconds of websites created
+// without one
+const DefaultCheckInterval = 300
+
+// WebsiteInput is the writable configuration of a website, as accepted by
+// the JSON API
+type WebsiteInput struct {
+	Name          string `json:"name"`
+	CheckType     string `json:"check_type"`
+	CheckInterval int    `json:"check_interval"`
+
+	// URL is the target of the check: a URL for HTTP checks, host:port for
+	// TCP and TLS checks and a hostname for DNS checks. It is ignored for
+	// heartbeat websites, which are given a ping URL.
+	URL string `json:"url"`
+
+	Request      RequestInput   `json:"request"`
+	DNS          DNSOptions     `json:"dns"`
+	Assertions   Assertions     `json:"assertions"`
+	Confirmation Confirmation   `json:"confirmation"`
+	Alerts       AlertPolicy    `json:"alerts"`
+	Content      ContentOptions `json:"content"`
+	GracePeriod  int            `json:"grace_period"`
+	ChannelIDs   []int          `json:"channel_ids"`
+	Tags         []string       `json:"tags"`
+}
+
+// RequestInput is the writable form of RequestOptions. Unlike responses,
+// it carries the auth secret.
+type RequestInput struct {
+	Method       string            `json:"method"`
+	Headers      map[string]string `json:"headers"`
+	Body         string            `json:"body"`
+	AuthType     string            `json:"auth_type"`
+	AuthUsername string            `json:"auth_username"`
+	AuthSecret   string            `json:"auth_secret"`
+	UserAgent    string            `json:"user_agent"`
+
+	// FollowRedirects defaults to true when left out
+	FollowRedirects *bool `json:"follow_redirects"`
+}
+
+// NewWebsiteInput returns the input that would recreate a website's
+// configuration, as the base of a partial update. The auth secret is
+// redacted, which Apply reads as keeping it.
+func NewWebsiteInput(website Website) WebsiteInput {
+	followRedirects := website.Request.FollowRedirects
+	authSecret := website.Request.AuthSecret
+	if authSecret != "" {
+		authSecret = redactedValue
+	}
+	return WebsiteInput{
+		Name:          website.Name,
+		CheckType:     website.Type(),
+		CheckInterval: website.CheckInterval,
+		URL:           website.URL,
+		Request: RequestInput{
+			Method:          website.Request.Method,
+			Headers:         website.Request.Headers,
+			Body:            website.Request.Body,
+			AuthType:        website.Request.AuthType,
+			AuthUsername:    website.Request.AuthUsername,
+			AuthSecret:      authSecret,
+			UserAgent:       website.Request.UserAgent,
+			FollowRedirects: &followRedirects,
+		},
+		DNS:          website.DNS,
+		Assertions:   website.Assertions,
+		Confirmation: website.Confirmation,
+		Alerts:       website.Alerts,
+		Content:      website.Content,
+		GracePeriod:  website.GracePeriod,
+		ChannelIDs:   website.ChannelIDs,
+		Tags:         website.Tags,
+	}
+}
+
+// Apply returns current with its configuration replaced by the input, or
+// an error describing why the result isn't valid. Secrets that responses
+// redact are kept when left empty or sent back redacted, so a fetched
+// website can be edited and sent back as is. Heartbeat websites keep their
+// ping token, and websites switched to heartbeat are given one.
+func (in WebsiteInput) Apply(current Website) (Website, error) {
+	website := current
+	website.Name = strings.TrimSpace(in.Name)
+	website.CheckType = in.CheckType
+	website.CheckInterval = in.CheckInterval
+	website.URL = strings.TrimSpace(in.URL)
+	website.DNS = DNSOptions{RecordType: strings.ToUpper(in.DNS.RecordType), Expected: strings.TrimSpace(in.DNS.Expected)}
+	website.Assertions = in.Assertions
+	website.Confirmation = in.Confirmation
+	website.Alerts = in.Alerts
+	website.Content = in.Content.Normalize()
+	website.GracePeriod = in.GracePeriod
+	website.ChannelIDs = in.ChannelIDs
+
+	website.Request = DefaultRequestOptions()
+	if in.Request.Method != "" {
+		website.Request.Method = strings.ToUpper(in.Request.Method)
+	}
+	website.Request.Body = in.Request.Body
+	website.Request.AuthType = in.Request.AuthType
+	website.Request.AuthUsername = in.Request.AuthUsername
+	website.Request.AuthSecret = in.Request.AuthSecret
+	website.Request.UserAgent = in.Request.UserAgent
+	if in.Request.FollowRedirects != nil {
+		website.Request.FollowRedirects = *in.Request.FollowRedirects
+	}
+	// The redacted secret keeps the stored one, but only under the scheme it
+	// was set for. An empty secret clears it.
+	if website.Request.AuthSecret == redactedValue {
+		website.Request.AuthSecret = ""
+		if website.Request.AuthType == current.Request.AuthType {
+			website.Request.AuthSecret = current.Request.AuthSecret
+		}
+	}
+	if website.Request.AuthType == AuthNone {
+		website.Request.AuthSecret = ""
+	}
+	if len(in.Request.Headers) > 0 {
+		website.Request.Headers = make(map[string]string, len(in.Request.Headers))
+		for name, value := range in.Request.Headers {
+			if value == redactedValue {
+				value = current.Request.Headers[name]
+			}
+			website.Request.Headers[name] = value
+		}
+	} else {
+		website.Request.Headers = nil
+	}
+
+	if website.Name == "" {
+		return website, fmt.Errorf("name is required")
+	}
+	tags, err := NormalizeTags(in.Tags)
+	if err != nil {
+		return website, err
+	}
+	website.Tags = tags
+
+	if website.CheckInterval == 0 {
+		website.CheckInterval = DefaultCheckInterval
+	}
+	if website.CheckInterval < 0 {
+		return website, fmt.Errorf("check interval must not be negative")
+	}
+
+	if website.Type() == CheckTypeHeartbeat {
+		if website.HeartbeatToken == "" {
+			token, err := NewHeartbeatToken()
+			if err != nil {
+				return website, err
+			}
+			website.HeartbeatToken = token
+		}
+		website.URL = HeartbeatPath(website.HeartbeatToken)
+	} else {
+		website.HeartbeatToken = ""
+		if website.URL == "" {
+			return website, fmt.Errorf("url is required")
+		}
+	}
+
+	if website.Type() == CheckTypeHTTP {
+		if err := website.Request.Validate(); err != nil {
+			return website, err
+		}
+		if err := website.Assertions.Validate(); err != nil {
+			return website, err
+		}
+		if err := website.Content.Validate(); err != nil {
+			return website, err
+		}
+	}
+	if err := website.Validate(); err != nil {
+		return website, err
+	}
+	return website, nil
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWebsiteInputApply(t *testing.T) {
+	website, err := WebsiteInput{Name: " Example ", URL: "https://example.com"}.Apply(Website{})
+	if err != nil {
+		t.Fatalf("Failed to apply input: %v", err)
+	}
+	if website.Name != "Example" || website.Type() != CheckTypeHTTP || website.CheckInterval != DefaultCheckInterval {
+		t.Errorf("Expected an HTTP website with defaults, got %+v", website)
+	}
+	if website.Request.Method != "GET" || !website.Request.FollowRedirects {
+		t.Errorf("Expected default request options, got %+v", website.Request)
+	}
+
+	tests := []struct {
+		name  string
+		input WebsiteInput
+		want  string
+	}{
+		{"missing name", WebsiteInput{URL: "https://example.com"}, "name is required"},
+		{"missing url", WebsiteInput{Name: "Example"}, "url is required"},
+		{"bad url", WebsiteInput{Name: "Example", URL: "example.com"}, "invalid URL"},
+		{"bad method", WebsiteInput{Name: "Example", URL: "https://example.com", Request: RequestInput{Method: "FETCH"}}, "unsupported request method"},
+		{"negative interval", WebsiteInput{Name: "Example", URL: "https://example.com", CheckInterval: -1}, "check interval"},
+		{"bad tcp target", WebsiteInput{Name: "Example", CheckType: CheckTypeTCP, URL: "example.com"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.input.Apply(Website{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWebsiteInputKeepsSecrets(t *testing.T) {
+	current := Website{
+		ID:   4,
+		Name: "API",
+		URL:  "https://api.example.com",
+		Request: RequestOptions{
+			Method:          "GET",
+			Headers:         map[string]string{"X-Api-Key": "secret", "Accept": "application/json"},
+			AuthType:        AuthBearer,
+			AuthSecret:      "token",
+			FollowRedirects: true,
+		},
+	}
+
+	// A website sent back as fetched has its secrets redacted
+	input := NewWebsiteInput(current)
+	input.Request.AuthSecret = redactedValue
+	input.Request.Headers = map[string]string{"X-Api-Key": redactedValue, "Accept": "text/html"}
+	input.Name = "Renamed"
+
+	website, err := input.Apply(current)
+	if err != nil {
+		t.Fatalf("Failed to apply input: %v", err)
+	}
+	if website.ID != 4 || website.Name != "Renamed" {
+		t.Errorf("Expected the website renamed in place, got %+v", website)
+	}
+	if website.Request.AuthSecret != "token" || website.Request.Headers["X-Api-Key"] != "secret" || website.Request.Headers["Accept"] != "text/html" {
+		t.Errorf("Expected secrets kept and other headers replaced, got %+v", website.Request)
+	}
+}
+
+func TestWebsiteInputAuthSecret(t *testing.T) {
+	current := Website{
+		Name: "API",
+		URL:  "https://api.example.com",
+		Request: RequestOptions{
+			Method:       "GET",
+			AuthType:     AuthBasic,
+			AuthUsername: "monitor",
+			AuthSecret:   "password",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		authType string
+		secret   string
+		want     string
+	}{
+		{"kept", AuthBasic, redactedValue, "password"},
+		{"replaced", AuthBasic, "new password", "new password"},
+		{"cleared", AuthBasic, "", ""},
+		{"scheme changed", AuthBearer, redactedValue, ""},
+		{"auth removed", AuthNone, redactedValue, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := NewWebsiteInput(current)
+			input.Request.AuthType = tt.authType
+			input.Request.AuthSecret = tt.secret
+
+			website, err := input.Apply(current)
+			if tt.authType == AuthBearer {
+				// Bearer auth without its own token is rejected rather than
+				// sending the basic auth password as one
+				if err == nil || website.Request.AuthSecret != "" {
+					t.Fatalf("Expected the old secret dropped and an error, got %q, %v", website.Request.AuthSecret, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to apply input: %v", err)
+			}
+			if website.Request.AuthSecret != tt.want {
+				t.Errorf("Expected secret %q, got %q", tt.want, website.Request.AuthSecret)
+			}
+		})
+	}
+
+	encoded, err := json.Marshal(current.Request)
+	if err != nil {
+		t.Fatalf("Failed to encode request options: %v", err)
+	}
+	if strings.Contains(string(encoded), "password") || !strings.Contains(string(encoded), `"auth_secret":"`+redactedValue+`"`) {
+		t.Errorf("Expected only the redacted secret in JSON, got %s", encoded)
+	}
+}
+
+func TestWebsiteInputHeartbeatToken(t *testing.T) {
+	website, err := WebsiteInput{Name: "Backup", CheckType: CheckTypeHeartbeat}.Apply(Website{})
+	if err != nil {
+		t.Fatalf("Failed to apply input: %v", err)
+	}
+	if website.HeartbeatToken == "" || website.URL != HeartbeatPath(website.HeartbeatToken) {
+		t.Fatalf("Expected a new ping token, got %+v", website)
+	}
+
+	// Editing a heartbeat website keeps its ping URL
+	input := NewWebsiteInput(website)
+	input.GracePeriod = 60
+	edited, err := input.Apply(website)
+	if err != nil {
+		t.Fatalf("Failed to apply input: %v", err)
+	}
+	if edited.HeartbeatToken != website.HeartbeatToken || edited.GracePeriod != 60 {
+		t.Errorf("Expected the ping token kept, got %+v", edited)
+	}
+
+	// Switching to another check type drops it
+	input.CheckType = CheckTypeHTTP
+	input.URL = "https://example.com"
+	switched, err := input.Apply(edited)
+	if err != nil {
+		t.Fatalf("Failed to apply input: %v", err)
+	}
+	if switched.HeartbeatToken != "" || switched.URL != "https://example.com" {
+		t.Errorf("Expected the ping token dropped, got %+v", switched)
+	}
+}
@@ -0,0 +1,210 @@
+package uptime
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"the-ark/internal/core"
+	"the-ark/internal/features/uptime/database"
+	"the-ark/internal/features/uptime/models"
+	monitor "the-ark/internal/features/uptime/services"
+	"the-ark/internal/server/services/mailer"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	_ "modernc.org/sqlite"
+)
+
+// newProbeTestServer serves the uptime routes of a feature backed by a
+// migrated in-memory database, without session authentication
+func newProbeTestServer(t *testing.T, config Config) (*Feature, *httptest.Server) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	logger := slog.New(slog.DiscardHandler)
+	feature := NewFeature(logger, core.NewDatabase(db, core.NewLogger()), mailer.Mailer{}, core.NewEventBus(), config)
+	if err := feature.migrationMgr.Migrate(context.Background()); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	router := chi.NewRouter()
+	for _, route := range feature.Routes() {
+		router.Method(route.Method, route.Path, route.Handler)
+	}
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return feature, server
+}
+
+// newTestAgent creates a probe agent for the test server. An agent that is
+// down sees every HTTP check fail, like a region with a network outage.
+func newTestAgent(server *httptest.Server, token, name string, down bool) *monitor.Agent {
+	agent := monitor.NewAgent(slog.New(slog.DiscardHandler), monitor.AgentConfig{
+		Server:       server.URL,
+		Token:        token,
+		Name:         name,
+		CheckTimeout: 5 * time.Second,
+	})
+	if down {
+		agent.RegisterChecker(models.CheckTypeHTTP, monitor.CheckerFunc(func(ctx context.Context, website models.Website) monitor.Result {
+			return monitor.Result{Error: "connection timed out"}
+		}))
+	}
+	return agent
+}
+
+func TestProbeAgents(t *testing.T) {
+	// The monitored website only answers requests with its bearer token, so
+	// probes must be given the secret
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer target.Close()
+
+	feature, server := newProbeTestServer(t, Config{ProbeToken: "registration-token", ProbeQuorum: 2})
+	service := feature.service
+
+	websiteID, err := service.CreateWebsite(models.Website{
+		Name:     "API",
+		URL:      target.URL,
+		IsActive: true,
+		Request:  models.RequestOptions{AuthType: models.AuthBearer, AuthSecret: "s3cret"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	ctx := context.Background()
+	intruder := newTestAgent(server, "wrong-token", "intruder", false)
+	if err := intruder.RunOnce(ctx); !errors.Is(err, models.ErrProbeUnauthorized) {
+		t.Fatalf("Expected a wrong registration token to be rejected, got %v", err)
+	}
+
+	// One location sees the website up, two see it time out
+	agents := []*monitor.Agent{
+		newTestAgent(server, "registration-token", "eu-west", false),
+		newTestAgent(server, "registration-token", "us-east", true),
+		newTestAgent(server, "registration-token", "ap-south", true),
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(agents))
+	for i, agent := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = agent.RunOnce(ctx)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Failed to run probe agent: %v", err)
+		}
+	}
+
+	dbService := database.NewDatabaseService(service.db)
+	results, err := dbService.GetProbeResults(websiteID, time.Time{})
+	if err != nil || len(results) != 3 {
+		t.Fatalf("Expected a result from each probe, got %+v, %v", results, err)
+	}
+	for _, result := range results {
+		if result.IsUp != (result.Location == "eu-west") {
+			t.Errorf("Expected only eu-west to see the website up, got %+v", result)
+		}
+	}
+
+	// This server sees the website up too, but two of four locations are
+	// enough to take it down
+	website, err := service.GetWebsiteByID(websiteID)
+	if err != nil {
+		t.Fatalf("Failed to get website: %v", err)
+	}
+	if err := service.CheckWebsite(ctx, *website); err != nil {
+		t.Fatalf("Failed to check website: %v", err)
+	}
+	status, err := service.GetLastWebsiteStatus(websiteID)
+	if err != nil || status == nil {
+		t.Fatalf("Failed to get website status: %v", err)
+	}
+	if status.Status != models.StatusDown || !strings.Contains(status.Error, "down from 2 of 4 locations") {
+		t.Errorf("Expected the website down by quorum, got %+v", status)
+	}
+
+	// The registration token alone doesn't take over a registered name
+	impostor := newTestAgent(server, "registration-token", "eu-west", false)
+	if err := impostor.RunOnce(ctx); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Fatalf("Expected registering a taken name to be rejected, got %v", err)
+	}
+	if err := agents[0].RunOnce(ctx); err != nil {
+		t.Fatalf("Expected the original probe's key to keep working, got %v", err)
+	}
+
+	// A deleted probe registers again the next time it reports
+	probes, err := service.GetProbes()
+	if err != nil || len(probes) != 3 {
+		t.Fatalf("Expected 3 registered probes, got %+v, %v", probes, err)
+	}
+	// Probes are listed by name, so eu-west is second
+	if err := service.DeleteProbe(probes[1].ID); err != nil {
+		t.Fatalf("Failed to delete probe: %v", err)
+	}
+	if err := agents[0].RunOnce(ctx); err != nil {
+		t.Fatalf("Expected the probe to register again, got %v", err)
+	}
+	if probes, _ := service.GetProbes(); len(probes) != 3 || probes[1].Name != "eu-west" {
+		t.Errorf("Expected the deleted probe registered again, got %+v", probes)
+	}
+}
+
+func TestWebsiteDetailLeavesOutStaleProbes(t *testing.T) {
+	feature, _ := newProbeTestServer(t, Config{})
+	db := database.NewDatabaseService(feature.service.db)
+
+	websiteID, err := feature.service.CreateWebsite(models.Website{
+		Name:          "Example",
+		URL:           "https://example.com",
+		CheckInterval: 60,
+		IsActive:      true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	now := time.Now()
+	for name, checkedAt := range map[string]time.Time{
+		"eu-west": now.Add(-time.Minute),
+		"us-east": now.Add(-time.Hour),
+	} {
+		probe, err := db.RegisterProbe(name, name+"-key", "", now)
+		if err != nil {
+			t.Fatalf("Failed to register probe: %v", err)
+		}
+		result := models.ProbeResult{WebsiteID: websiteID, IsUp: true, CheckedAt: checkedAt}
+		if _, err := db.StoreProbeResults(probe.ID, []models.ProbeResult{result}, now); err != nil {
+			t.Fatalf("Failed to store probe result: %v", err)
+		}
+	}
+
+	data, err := feature.service.GetWebsiteDetailData(websiteID)
+	if err != nil {
+		t.Fatalf("Failed to get website detail: %v", err)
+	}
+	if len(data.Locations) != 1 || data.Locations[0].Location != "eu-west" {
+		t.Errorf("Expected only the probe still reporting, got %+v", data.Locations)
+	}
+}
@@ -2,7 +2,9 @@ package uptime
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
+	"errors"
 	"fmt"
 	"the-ark/internal/features/uptime/database"
 	"the-ark/internal/features/uptime/handlers"
@@ -15,35 +17,125 @@ import (
 )
 
 type Service struct {
-	logger     *slog.Logger
-	db         *sql.DB
-	monitor    *uptimeservices.Monitor
-	apiHandler *handlers.APIHandler
-	webHandler *handlers.WebHandler
+	logger      *slog.Logger
+	db          *sql.DB
+	monitor     *uptimeservices.Monitor
+	rollup      *uptimeservices.Rollup
+	reporter    *uptimeservices.Reporter
+	domains     *uptimeservices.DomainTracker
+	slaTarget   float64
+	maxWebsites int
+	probeToken  string
+	apiHandler  *handlers.APIHandler
+	v1Handler   *handlers.V1Handler
+	webHandler  *handlers.WebHandler
 }
 
 type Config struct {
 	AlertRecipient string
-}
 
-func NewService(logger *slog.Logger, db *sql.DB, mailer mailer.Mailer, config Config) *Service {
-	dbService := database.NewDatabaseService(db)
+	// DefaultCheckInterval is the interval in seconds used for websites
+	// without their own check interval
+	DefaultCheckInterval int
+
+	// MaxConcurrentChecks bounds the monitor's worker pool
+	MaxConcurrentChecks int
+
+	// CheckTimeout is the per-check timeout in seconds
+	CheckTimeout int
+
+	// RawRetentionDays is how long raw checks are kept once rolled up
+	RawRetentionDays int
+
+	// SLATarget is the uptime percentage SLA reports measure websites against
+	SLATarget float64
+
+	// ReportRecipient is who monthly SLA reports are emailed to, falling
+	// back to AlertRecipient
+	ReportRecipient string
+
+	// MaxWebsites is the most websites that may be monitored, 0 for no
+	// limit
+	MaxWebsites int
+
+	// ProbeToken is the secret probe agents register with. Registration is
+	// disabled while it is empty.
+	ProbeToken string
+
+	// ProbeQuorum is how many locations must see a website fail before it
+	// is down, counting this server and probe agents that report recently
+	ProbeQuorum int
 
-	monitorConfig := uptimeservices.MonitorConfig{
-		AlertRecipient: config.AlertRecipient,
+	// RDAPURL is the RDAP service domain registrations are looked up
+	// through, defaulting to rdap.org
+	RDAPURL string
+
+	// DomainExpiryThresholds is a comma separated list of days before a
+	// domain's registration expires to send warnings at, defaulting to
+	// models.DefaultDomainExpiryThresholds
+	DomainExpiryThresholds string
+}
+
+func NewService(logger *slog.Logger, db *sql.DB, mailer mailer.Mailer, events uptimeservices.Publisher, config Config) *Service {
+	monitorConfig := uptimeservices.DefaultMonitorConfig()
+	monitorConfig.AlertRecipient = config.AlertRecipient
+	if config.DefaultCheckInterval > 0 {
+		monitorConfig.DefaultInterval = time.Duration(config.DefaultCheckInterval) * time.Second
+	}
+	if config.MaxConcurrentChecks > 0 {
+		monitorConfig.MaxWorkers = config.MaxConcurrentChecks
+	}
+	if config.CheckTimeout > 0 {
+		monitorConfig.CheckTimeout = time.Duration(config.CheckTimeout) * time.Second
+	}
+	if config.ProbeQuorum > 0 {
+		monitorConfig.Quorum = config.ProbeQuorum
 	}
 	monitor := uptimeservices.New(logger, mailer, monitorConfig)
+	monitor.SetPublisher(events)
 
-	apiHandler := handlers.NewAPIHandler(logger, dbService)
-	webHandler := handlers.NewWebHandler(logger, dbService)
+	rollupConfig := uptimeservices.DefaultRollupConfig()
+	if config.RawRetentionDays > 0 {
+		rollupConfig.RawRetention = time.Duration(config.RawRetentionDays) * 24 * time.Hour
+	}
 
-	return &Service{
-		logger:     logger,
-		db:         db,
-		monitor:    monitor,
-		apiHandler: apiHandler,
-		webHandler: webHandler,
+	reportConfig := uptimeservices.DefaultReportConfig()
+	reportConfig.Recipient = config.ReportRecipient
+	if reportConfig.Recipient == "" {
+		reportConfig.Recipient = config.AlertRecipient
+	}
+	if models.ValidateSLATarget(config.SLATarget) == nil {
+		reportConfig.Target = config.SLATarget
 	}
+
+	// The thresholds are validated when the config is loaded, and the tracker
+	// falls back to its defaults for an unset RDAP URL or thresholds
+	domainConfig := uptimeservices.DomainConfig{
+		Recipient: config.AlertRecipient,
+		RDAPURL:   config.RDAPURL,
+	}
+	if config.DomainExpiryThresholds != "" {
+		domainConfig.Thresholds, _ = models.ParseDomainThresholds(config.DomainExpiryThresholds)
+	}
+
+	service := &Service{
+		logger:      logger,
+		db:          db,
+		monitor:     monitor,
+		rollup:      uptimeservices.NewRollup(logger, rollupConfig),
+		reporter:    uptimeservices.NewReporter(logger, mailer, reportConfig),
+		domains:     uptimeservices.NewDomainTracker(logger, mailer, domainConfig),
+		slaTarget:   reportConfig.Target,
+		maxWebsites: max(config.MaxWebsites, 0),
+		probeToken:  config.ProbeToken,
+	}
+
+	// Handlers go through the service so website changes reach the monitor
+	service.apiHandler = handlers.NewAPIHandler(logger, service)
+	service.webHandler = handlers.NewWebHandler(logger, service)
+	service.v1Handler = handlers.NewV1Handler(logger, service)
+
+	return service
 }
 
 // Start starts the uptime monitoring service
@@ -51,6 +143,24 @@ func (s *Service) Start(ctx context.Context) {
 	s.logger.Info("Starting uptime monitoring service")
 	dbService := database.NewDatabaseService(s.db)
 	s.monitor.Start(ctx, dbService)
+	s.rollup.Start(ctx, dbService)
+	s.reporter.Start(ctx, dbService)
+	s.domains.Start(ctx, dbService)
+}
+
+// Stop stops the uptime monitoring service, cancelling in-flight checks
+func (s *Service) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping uptime monitoring service")
+	if err := s.domains.Stop(ctx); err != nil {
+		return err
+	}
+	if err := s.reporter.Stop(ctx); err != nil {
+		return err
+	}
+	if err := s.rollup.Stop(ctx); err != nil {
+		return err
+	}
+	return s.monitor.Stop(ctx)
 }
 
 // GetAPIHandler returns the API handler for routing
@@ -58,15 +168,73 @@ func (s *Service) GetAPIHandler() *handlers.APIHandler {
 	return s.apiHandler
 }
 
+// GetV1Handler returns the versioned JSON API handler for routing
+func (s *Service) GetV1Handler() *handlers.V1Handler {
+	return s.v1Handler
+}
+
 // GetWebHandler returns the web handler for routing
 func (s *Service) GetWebHandler() *handlers.WebHandler {
 	return s.webHandler
 }
 
-// GetActiveWebsites retrieves all active websites
-func (s *Service) GetActiveWebsites() ([]models.Website, error) {
+// GetWebsites retrieves every website, including paused ones
+func (s *Service) GetWebsites() ([]models.Website, error) {
 	dbService := database.NewDatabaseService(s.db)
-	return dbService.GetActiveWebsites()
+	return dbService.GetWebsites()
+}
+
+// MaxWebsites returns the most websites that may be monitored, 0 for no
+// limit
+func (s *Service) MaxWebsites() int {
+	return s.maxWebsites
+}
+
+// GetTags retrieves every tag with how many websites have it
+func (s *Service) GetTags() ([]models.Tag, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetTags()
+}
+
+// RunGroupAction pauses, resumes, checks or deletes every website with a
+// tag, returning how many websites it affected or sql.ErrNoRows for tags no
+// website has. Checks skip paused websites and are queued on the monitor
+// rather than waited for. Deletes remove the whole group or nothing.
+func (s *Service) RunGroupAction(tag, action string) (int, error) {
+	dbService := database.NewDatabaseService(s.db)
+	websites, err := dbService.GetWebsitesByTag(tag)
+	if err != nil {
+		return 0, err
+	}
+	if len(websites) == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	switch action {
+	case models.GroupActionPause, models.GroupActionResume:
+		n, err := dbService.SetTagActive(tag, action == models.GroupActionResume)
+		if err != nil {
+			return 0, err
+		}
+		s.monitor.Reload()
+		return n, nil
+	case models.GroupActionCheck:
+		var active []models.Website
+		for _, website := range websites {
+			if website.IsActive {
+				active = append(active, website)
+			}
+		}
+		return s.monitor.Queue(active), nil
+	case models.GroupActionDelete:
+		deleted, err := dbService.DeleteTaggedWebsites(tag)
+		if err != nil {
+			return 0, err
+		}
+		s.monitor.Reload()
+		return deleted, nil
+	}
+	return 0, models.ValidateGroupAction(action)
 }
 
 // GetWebsiteByID retrieves a specific website by ID
@@ -81,13 +249,373 @@ func (s *Service) GetLastWebsiteStatus(websiteID int) (*models.WebsiteStatus, er
 	return dbService.GetLastWebsiteStatus(websiteID)
 }
 
-// CheckWebsite performs a manual check of a website
-func (s *Service) CheckWebsite(website models.Website) error {
+// CheckWebsite performs a manual check of a website, returning
+// models.ErrWebsitePaused for paused websites
+func (s *Service) CheckWebsite(ctx context.Context, website models.Website) error {
+	if !website.IsActive {
+		return models.ErrWebsitePaused
+	}
 	dbService := database.NewDatabaseService(s.db)
-	s.monitor.CheckWebsite(website, dbService)
+	s.monitor.CheckWebsite(ctx, website, dbService)
 	return nil // The monitor's CheckWebsite doesn't return anything, so we return nil
 }
 
+// GetWebsiteByHeartbeatToken retrieves the heartbeat website a ping URL belongs to
+func (s *Service) GetWebsiteByHeartbeatToken(token string) (*models.Website, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetWebsiteByHeartbeatToken(token)
+}
+
+// RecordHeartbeat records a ping from a heartbeat website. Pings of paused
+// websites are accepted but ignored.
+func (s *Service) RecordHeartbeat(website models.Website, isUp bool, message string) error {
+	if !website.IsActive {
+		return nil
+	}
+	dbService := database.NewDatabaseService(s.db)
+	s.monitor.RecordHeartbeat(website, isUp, message, dbService)
+	return nil
+}
+
+// RegisterProbe registers a probe agent under its location name and returns
+// its new key, or models.ErrProbeUnauthorized if the token is wrong or
+// registration is disabled. A probe registering again under its name must
+// present its current key, or models.ErrProbeNameTaken is returned.
+func (s *Service) RegisterProbe(token, name, currentKey string) (*models.ProbeRegistration, error) {
+	if s.probeToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.probeToken)) != 1 {
+		return nil, models.ErrProbeUnauthorized
+	}
+
+	key, err := models.NewProbeKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var currentKeyHash string
+	if currentKey != "" {
+		currentKeyHash = models.HashProbeKey(currentKey)
+	}
+
+	dbService := database.NewDatabaseService(s.db)
+	probe, err := dbService.RegisterProbe(name, models.HashProbeKey(key), currentKeyHash, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &models.ProbeRegistration{Probe: *probe, Key: key}, nil
+}
+
+// AuthenticateProbe retrieves the probe a key belongs to, returning
+// models.ErrProbeUnauthorized for unknown keys
+func (s *Service) AuthenticateProbe(key string) (*models.Probe, error) {
+	dbService := database.NewDatabaseService(s.db)
+	probe, err := dbService.GetProbeByKeyHash(models.HashProbeKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrProbeUnauthorized
+	}
+	return probe, err
+}
+
+// GetProbeChecks retrieves the checks probe agents run: every active
+// website except heartbeats, which only this server receives
+func (s *Service) GetProbeChecks() ([]models.ProbeCheck, error) {
+	dbService := database.NewDatabaseService(s.db)
+	websites, err := dbService.GetActiveWebsites()
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []models.ProbeCheck{}
+	for _, website := range websites {
+		if website.Type() != models.CheckTypeHeartbeat {
+			checks = append(checks, models.NewProbeCheck(website))
+		}
+	}
+	return checks, nil
+}
+
+// StoreProbeResults stores the results a probe agent reported, returning
+// how many were kept
+func (s *Service) StoreProbeResults(probeID int, results []models.ProbeResult) (int, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.StoreProbeResults(probeID, results, time.Now())
+}
+
+// GetProbes retrieves every registered probe agent
+func (s *Service) GetProbes() ([]models.Probe, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetProbes()
+}
+
+// DeleteProbe removes a probe agent and its results, returning
+// sql.ErrNoRows for unknown probes. The agent has to register again.
+func (s *Service) DeleteProbe(probeID int) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.DeleteProbe(probeID)
+}
+
+// CreateWebsite adds a new website and schedules it for monitoring,
+// returning its ID
+func (s *Service) CreateWebsite(website models.Website) (int, error) {
+	dbService := database.NewDatabaseService(s.db)
+	websiteID, err := dbService.CreateWebsite(website)
+	if err != nil {
+		return 0, err
+	}
+
+	s.monitor.Reload()
+	return websiteID, nil
+}
+
+// UpdateWebsite replaces a website's configuration and reschedules it,
+// returning sql.ErrNoRows for unknown websites
+func (s *Service) UpdateWebsite(website models.Website) error {
+	dbService := database.NewDatabaseService(s.db)
+	if err := dbService.UpdateWebsite(website); err != nil {
+		return err
+	}
+
+	s.monitor.Reload()
+	return nil
+}
+
+// SetWebsiteActive pauses or resumes monitoring a website, keeping its
+// history, and returns sql.ErrNoRows for unknown websites
+func (s *Service) SetWebsiteActive(websiteID int, active bool) error {
+	dbService := database.NewDatabaseService(s.db)
+	if err := dbService.SetWebsiteActive(websiteID, active); err != nil {
+		return err
+	}
+
+	s.monitor.Reload()
+	return nil
+}
+
+// DeleteWebsite removes a website and drops it from the monitoring
+// schedule, returning sql.ErrNoRows for unknown websites
+func (s *Service) DeleteWebsite(websiteID int) error {
+	dbService := database.NewDatabaseService(s.db)
+	if err := dbService.DeleteWebsite(websiteID); err != nil {
+		return err
+	}
+
+	s.monitor.Reload()
+	return nil
+}
+
+// GetCheckPage retrieves a page of a website's checks with the number of
+// checks it has, returning sql.ErrNoRows for unknown websites
+func (s *Service) GetCheckPage(websiteID int, limit, offset int) ([]models.WebsiteStatus, int, error) {
+	dbService := database.NewDatabaseService(s.db)
+	if _, err := dbService.GetWebsiteByID(websiteID); err != nil {
+		return nil, 0, err
+	}
+	return dbService.GetCheckPage(websiteID, limit, offset)
+}
+
+// GetIncidentPage retrieves a page of a website's incidents with the number
+// of incidents it has, returning sql.ErrNoRows for unknown websites
+func (s *Service) GetIncidentPage(websiteID int, limit, offset int) ([]models.Incident, int, error) {
+	dbService := database.NewDatabaseService(s.db)
+	if _, err := dbService.GetWebsiteByID(websiteID); err != nil {
+		return nil, 0, err
+	}
+	return dbService.GetIncidentPage(websiteID, limit, offset)
+}
+
+// GetIncidents retrieves a website's most recent incidents
+func (s *Service) GetIncidents(websiteID int, limit int) ([]models.Incident, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetIncidents(websiteID, limit)
+}
+
+// GetIncident retrieves an incident with its timeline
+func (s *Service) GetIncident(incidentID int) (*models.Incident, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetIncident(incidentID)
+}
+
+// AcknowledgeIncident marks an incident as being worked on
+func (s *Service) AcknowledgeIncident(incidentID int, author string) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.AcknowledgeIncident(incidentID, author)
+}
+
+// SetIncidentRootCause records what caused an incident
+func (s *Service) SetIncidentRootCause(incidentID int, rootCause, author string) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.SetIncidentRootCause(incidentID, rootCause, author)
+}
+
+// AddIncidentComment adds a comment to an incident's timeline
+func (s *Service) AddIncidentComment(incidentID int, message, author string) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.AddIncidentComment(incidentID, message, author)
+}
+
+// GetNotificationChannels retrieves every notification channel
+func (s *Service) GetNotificationChannels() ([]models.NotificationChannel, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetNotificationChannels()
+}
+
+// CreateNotificationChannel stores a new notification channel
+func (s *Service) CreateNotificationChannel(channel models.NotificationChannel) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.CreateNotificationChannel(channel)
+}
+
+// GetNotificationChannel retrieves a notification channel, returning
+// sql.ErrNoRows for unknown channels
+func (s *Service) GetNotificationChannel(channelID int) (*models.NotificationChannel, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetNotificationChannel(channelID)
+}
+
+// UpdateNotificationChannel replaces a notification channel's settings
+func (s *Service) UpdateNotificationChannel(channel models.NotificationChannel) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.UpdateNotificationChannel(channel)
+}
+
+// DeleteNotificationChannel removes a notification channel
+func (s *Service) DeleteNotificationChannel(channelID int) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.DeleteNotificationChannel(channelID)
+}
+
+// TestNotificationChannel sends a test notification through a channel
+func (s *Service) TestNotificationChannel(ctx context.Context, channelID int) error {
+	dbService := database.NewDatabaseService(s.db)
+	channel, err := dbService.GetNotificationChannel(channelID)
+	if err != nil {
+		return err
+	}
+
+	notification := uptimeservices.Notification{
+		Website:   models.Website{Name: "Test notification", URL: "https://example.com"},
+		Type:      models.AlertTest,
+		Timestamp: time.Now(),
+	}
+	return s.monitor.SendNotification(ctx, *channel, notification)
+}
+
+// SetWebsiteChannels replaces the notification channels a website alerts
+func (s *Service) SetWebsiteChannels(websiteID int, channelIDs []int) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.SetWebsiteChannels(websiteID, channelIDs)
+}
+
+// SetWebsiteAlerts replaces the notification channels and alert policy of a
+// website in one transaction, returning models.ErrUnknownEscalationChannel
+// when the escalation channel doesn't exist
+func (s *Service) SetWebsiteAlerts(websiteID int, channelIDs []int, policy models.AlertPolicy) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.SetWebsiteAlerts(websiteID, channelIDs, policy)
+}
+
+// GetWebsiteMetrics gathers what the website detail charts show for a
+// period, returning sql.ErrNoRows for unknown websites
+func (s *Service) GetWebsiteMetrics(websiteID int, period models.MetricsPeriod, resolution string) (*models.WebsiteMetrics, error) {
+	dbService := database.NewDatabaseService(s.db)
+	if _, err := dbService.GetWebsiteByID(websiteID); err != nil {
+		return nil, err
+	}
+	return dbService.GetWebsiteMetrics(websiteID, period, resolution, time.Now())
+}
+
+// GetUptimeHistory retrieves a website's most recent checks with their
+// timings
+func (s *Service) GetUptimeHistory(websiteID int, limit int) ([]models.WebsiteStatus, error) {
+	dbService := database.NewDatabaseService(s.db)
+	if _, err := dbService.GetWebsiteByID(websiteID); err != nil {
+		return nil, err
+	}
+	return dbService.GetUptimeHistory(websiteID, limit)
+}
+
+// SLATarget returns the configured uptime percentage SLA reports measure
+// websites against
+func (s *Service) SLATarget() float64 {
+	return s.slaTarget
+}
+
+// GetSLAReport builds the SLA report of a month, measured against target or
+// the configured target when target is 0
+func (s *Service) GetSLAReport(month time.Time, target float64) (*models.SLAReport, error) {
+	if target == 0 {
+		target = s.slaTarget
+	}
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetSLAReport(month, target, time.Now())
+}
+
+// GetAlertHistory retrieves a website's most recent alert delivery attempts
+func (s *Service) GetAlertHistory(websiteID int, limit int) ([]models.AlertRecord, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetAlertHistory(websiteID, limit)
+}
+
+// GetMaintenanceWindows retrieves every maintenance window
+func (s *Service) GetMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetMaintenanceWindows()
+}
+
+// GetWebsiteMaintenanceWindows retrieves the maintenance windows covering a
+// website, including global windows
+func (s *Service) GetWebsiteMaintenanceWindows(websiteID int) ([]models.MaintenanceWindow, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetWebsiteMaintenanceWindows(websiteID)
+}
+
+// CreateMaintenanceWindow stores a new maintenance window
+func (s *Service) CreateMaintenanceWindow(window models.MaintenanceWindow) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.CreateMaintenanceWindow(window)
+}
+
+// DeleteMaintenanceWindow removes a maintenance window
+func (s *Service) DeleteMaintenanceWindow(windowID int) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.DeleteMaintenanceWindow(windowID)
+}
+
+// GetStatusPages retrieves every status page with its components
+func (s *Service) GetStatusPages() ([]models.StatusPage, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetStatusPages()
+}
+
+// CreateStatusPage stores a new status page
+func (s *Service) CreateStatusPage(page models.StatusPage) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.CreateStatusPage(page)
+}
+
+// DeleteStatusPage removes a status page and its components
+func (s *Service) DeleteStatusPage(pageID int) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.DeleteStatusPage(pageID)
+}
+
+// AddStatusComponent appends a component to a status page
+func (s *Service) AddStatusComponent(component models.StatusComponent) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.AddStatusComponent(component)
+}
+
+// DeleteStatusComponent removes a component from its status page
+func (s *Service) DeleteStatusComponent(componentID int) error {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.DeleteStatusComponent(componentID)
+}
+
+// GetPublicStatusPage assembles what the status page with a slug shows
+func (s *Service) GetPublicStatusPage(slug string) (*models.PublicStatusPage, error) {
+	dbService := database.NewDatabaseService(s.db)
+	return dbService.GetPublicStatusPage(slug, time.Now())
+}
+
 // GetWebsiteDetailData retrieves all data needed for the detailed website view
 func (s *Service) GetWebsiteDetailData(websiteID int) (*models.WebsiteDetailData, error) {
 	dbService := database.NewDatabaseService(s.db)
@@ -110,11 +638,17 @@ func (s *Service) GetWebsiteDetailData(websiteID int) (*models.WebsiteDetailData
 		return nil, err
 	}
 
-	// Get incidents
+	// Get incidents with their timelines
 	incidents, err := dbService.GetIncidents(websiteID, 10)
 	if err != nil {
 		return nil, err
 	}
+	for i := range incidents {
+		incidents[i].Timeline, err = dbService.GetIncidentTimeline(incidents[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Get average response time
 	avgResponse, err := dbService.GetAverageResponseTime(websiteID, 24*30) // 30 days
@@ -122,12 +656,65 @@ func (s *Service) GetWebsiteDetailData(websiteID int) (*models.WebsiteDetailData
 		return nil, err
 	}
 
+	// Get the average timing breakdown of the last day
+	avgTiming, err := dbService.GetAverageTiming(websiteID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the channels the website can alert
+	channels, err := dbService.GetNotificationChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the most recent alert delivery attempts
+	alertHistory, err := dbService.GetAlertHistory(websiteID, database.AlertHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the maintenance windows covering the website
+	maintenance, err := dbService.GetWebsiteMaintenanceWindows(websiteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the latest results from other locations, leaving out probes that
+	// stopped reporting like the quorum does
+	locations, err := dbService.GetProbeResults(websiteID, s.monitor.ProbeResultsSince(*website, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the history of content changes
+	snapshots, err := dbService.GetContentSnapshots(websiteID, database.ContentSnapshotLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the registration of the website's domain, if it has been looked up
+	var domain *models.Domain
+	if name, ok := website.Domain(); ok {
+		domain, err = dbService.GetDomain(name)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
 	return &models.WebsiteDetailData{
-		Website:     *website,
-		LastStatus:  lastStatus,
-		UptimeStats: uptimeStats,
-		Incidents:   incidents,
-		AvgResponse: avgResponse,
+		Website:      *website,
+		LastStatus:   lastStatus,
+		UptimeStats:  uptimeStats,
+		Incidents:    incidents,
+		AvgResponse:  avgResponse,
+		AvgTiming:    avgTiming,
+		Channels:     channels,
+		AlertHistory: alertHistory,
+		Maintenance:  maintenance,
+		Locations:    locations,
+		Snapshots:    snapshots,
+		Domain:       domain,
 	}, nil
 }
 
@@ -135,6 +722,12 @@ func (s *Service) GetWebsiteDetailData(websiteID int) (*models.WebsiteDetailData
 func (s *Service) getUptimeStats(websiteID int) ([]models.UptimeStats, error) {
 	dbService := database.NewDatabaseService(s.db)
 
+	// Maintenance doesn't count as downtime
+	windows, err := dbService.GetWebsiteMaintenanceWindows(websiteID)
+	if err != nil {
+		return nil, err
+	}
+
 	periods := []struct {
 		hours int
 		label string
@@ -145,6 +738,12 @@ func (s *Service) getUptimeStats(websiteID int) ([]models.UptimeStats, error) {
 		{24 * 365, "365d"},
 	}
 
+	// The incidents are counted per period from one list
+	incidents, err := dbService.GetIncidents(websiteID, 100)
+	if err != nil {
+		return nil, err
+	}
+
 	var stats []models.UptimeStats
 	for _, period := range periods {
 		percentage, upChecks, downChecks, err := dbService.GetUptimePercentage(websiteID, period.hours)
@@ -152,19 +751,13 @@ func (s *Service) getUptimeStats(websiteID int) ([]models.UptimeStats, error) {
 			return nil, err
 		}
 
-		// Get incident count for this period
-		incidents, err := dbService.GetIncidents(websiteID, 100) // Get more incidents to count
-		if err != nil {
-			return nil, err
-		}
-
 		// Count incidents in this period
 		incidentCount := 0
 		var totalDowntime time.Duration
 		for _, incident := range incidents {
 			if time.Since(incident.StartedAt) <= time.Duration(period.hours)*time.Hour {
 				incidentCount++
-				totalDowntime += incident.Duration
+				totalDowntime += incident.DowntimeExcluding(windows)
 			}
 		}
 
@@ -0,0 +1,355 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// Paths of the probe API on the main Ark
+const (
+	probeRegisterPath = "/api/v1/uptime/probes/register"
+	probeChecksPath   = "/api/v1/uptime/probes/checks"
+	probeResultsPath  = "/api/v1/uptime/probes/results"
+)
+
+// maxProbeResponseBytes bounds the responses an agent reads from the main Ark
+const maxProbeResponseBytes = 8 << 20
+
+// AgentConfig configures a probe agent
+type AgentConfig struct {
+	// Server is the base URL of the main Ark, e.g. https://ark.example.com
+	Server string
+
+	// Token is the registration token configured on the main Ark
+	Token string
+
+	// Name is the location the agent checks from, e.g. eu-west
+	Name string
+
+	// SyncInterval controls how often the agent fetches its assigned
+	// checks, and how long it waits before retrying a failed registration
+	SyncInterval time.Duration
+
+	// MaxWorkers bounds the number of checks running at the same time
+	MaxWorkers int
+
+	// CheckTimeout bounds how long a single check may take
+	CheckTimeout time.Duration
+
+	// RequestTimeout bounds each request to the main Ark
+	RequestTimeout time.Duration
+}
+
+// DefaultAgentConfig returns the default probe agent configuration
+func DefaultAgentConfig() AgentConfig {
+	return AgentConfig{
+		SyncInterval:   time.Minute,
+		MaxWorkers:     5,
+		CheckTimeout:   10 * time.Second,
+		RequestTimeout: 30 * time.Second,
+	}
+}
+
+// Agent checks the websites a main Ark assigns it from its own location
+// and pushes the results back, so the main Ark can require several
+// locations to agree before it declares a website down. It registers with
+// the main Ark's registration token and authenticates everything after
+// that with the key it gets back.
+type Agent struct {
+	logger  *slog.Logger
+	config  AgentConfig
+	client  *http.Client
+	monitor *Monitor
+
+	// key authenticates the agent once it has registered
+	key   string
+	keyMu sync.Mutex
+}
+
+// NewAgent creates a probe agent, filling unset config with defaults
+func NewAgent(logger *slog.Logger, config AgentConfig) *Agent {
+	defaults := DefaultAgentConfig()
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = defaults.SyncInterval
+	}
+	if config.MaxWorkers <= 0 {
+		config.MaxWorkers = defaults.MaxWorkers
+	}
+	if config.CheckTimeout <= 0 {
+		config.CheckTimeout = defaults.CheckTimeout
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = defaults.RequestTimeout
+	}
+	config.Server = strings.TrimRight(config.Server, "/")
+
+	// The agent only borrows the monitor's checkers and schedule, it never
+	// alerts, so it needs no mailer
+	monitor := New(logger, nil, MonitorConfig{
+		ResyncInterval: config.SyncInterval,
+		MaxWorkers:     config.MaxWorkers,
+		CheckTimeout:   config.CheckTimeout,
+	})
+
+	return &Agent{
+		logger:  logger.With("probe", config.Name),
+		config:  config,
+		client:  &http.Client{Timeout: config.RequestTimeout},
+		monitor: monitor,
+	}
+}
+
+// RegisterChecker adds or replaces the checker the agent uses for a check
+// type. It must be called before Run.
+func (a *Agent) RegisterChecker(checkType string, checker Checker) {
+	a.monitor.RegisterChecker(checkType, checker)
+}
+
+// Run registers the agent and checks its assigned websites on their
+// schedule until ctx is cancelled. Registration is retried while the main
+// Ark can't be reached, but a rejected token is returned as an error.
+func (a *Agent) Run(ctx context.Context) error {
+	for {
+		err := a.register(ctx)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, models.ErrProbeUnauthorized) {
+			return err
+		}
+		a.logger.Error("Failed to register probe, retrying", "error", err, "delay", a.config.SyncInterval)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(a.config.SyncInterval):
+		}
+	}
+
+	schedule := a.monitor.schedule
+	a.sync(ctx)
+
+	syncTicker := time.NewTicker(a.config.SyncInterval)
+	defer syncTicker.Stop()
+
+	timer := time.NewTimer(schedule.untilNext(time.Now(), a.config.SyncInterval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.logger.Info("Probe stopped")
+			return nil
+		case <-syncTicker.C:
+			a.sync(ctx)
+		case <-timer.C:
+			if err := a.checkAndReport(ctx, schedule.due(time.Now())); err != nil {
+				a.logger.Error("Failed to report probe results", "error", err)
+			}
+		}
+
+		timer.Reset(schedule.untilNext(time.Now(), a.config.SyncInterval))
+	}
+}
+
+// RunOnce checks every assigned website straight away and reports the
+// results, registering first if the agent hasn't yet
+func (a *Agent) RunOnce(ctx context.Context) error {
+	if a.currentKey() == "" {
+		if err := a.register(ctx); err != nil {
+			return err
+		}
+	}
+
+	websites, err := a.fetchChecks(ctx)
+	if err != nil {
+		return err
+	}
+	return a.checkAndReport(ctx, websites)
+}
+
+// register registers the agent under its name and keeps the key it gets.
+// An agent registering again sends its current key, which the main Ark
+// requires before it replaces the key of an existing probe.
+func (a *Agent) register(ctx context.Context) error {
+	var registration models.ProbeRegistration
+	payload := map[string]string{"name": a.config.Name}
+	if key := a.currentKey(); key != "" {
+		payload["key"] = key
+	}
+	if err := a.call(ctx, http.MethodPost, probeRegisterPath, a.config.Token, payload, &registration); err != nil {
+		return err
+	}
+	if registration.Key == "" {
+		return fmt.Errorf("registration returned no probe key")
+	}
+
+	a.keyMu.Lock()
+	a.key = registration.Key
+	a.keyMu.Unlock()
+
+	a.logger.Info("Probe registered", "server", a.config.Server, "probe_id", registration.ID)
+	return nil
+}
+
+// sync reconciles the schedule with the checks assigned to the agent. The
+// current schedule is kept when they can't be fetched.
+func (a *Agent) sync(ctx context.Context) {
+	websites, err := a.fetchChecks(ctx)
+	if err != nil {
+		a.logger.Error("Failed to get probe checks", "error", err)
+		return
+	}
+	a.monitor.schedule.sync(websites, time.Now())
+}
+
+// fetchChecks retrieves the checks assigned to the agent as websites
+func (a *Agent) fetchChecks(ctx context.Context) ([]models.Website, error) {
+	var checks []models.ProbeCheck
+	err := a.authorized(ctx, func(key string) error {
+		return a.call(ctx, http.MethodGet, probeChecksPath, key, nil, &checks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	websites := make([]models.Website, 0, len(checks))
+	for _, check := range checks {
+		websites = append(websites, check.Website())
+	}
+	return websites, nil
+}
+
+// checkAndReport checks websites at once, running at most MaxWorkers
+// checks at the same time, and pushes the results to the main Ark
+func (a *Agent) checkAndReport(ctx context.Context, websites []models.Website) error {
+	if len(websites) == 0 {
+		return nil
+	}
+
+	results := make([]models.ProbeResult, len(websites))
+	slots := make(chan struct{}, a.config.MaxWorkers)
+	var wg sync.WaitGroup
+
+	for i, website := range websites {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result := a.monitor.checkWithRetries(ctx, website)
+			results[i] = models.ProbeResult{
+				WebsiteID:    website.ID,
+				IsUp:         result.IsUp,
+				StatusCode:   result.StatusCode,
+				ResponseTime: result.ResponseTime,
+				Error:        result.Error,
+				CheckedAt:    time.Now(),
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Don't report failures caused by the agent shutting down
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	payload := map[string]any{"results": results}
+	return a.authorized(ctx, func(key string) error {
+		return a.call(ctx, http.MethodPost, probeResultsPath, key, payload, nil)
+	})
+}
+
+// authorized calls fn with the agent's key, registering again and retrying
+// once if the main Ark no longer accepts the key, e.g. because the probe
+// was deleted
+func (a *Agent) authorized(ctx context.Context, fn func(key string) error) error {
+	err := fn(a.currentKey())
+	if !errors.Is(err, models.ErrProbeUnauthorized) {
+		return err
+	}
+
+	a.logger.Warn("Probe key rejected, registering again")
+	if err := a.register(ctx); err != nil {
+		return err
+	}
+	return fn(a.currentKey())
+}
+
+// currentKey returns the key the agent authenticates with
+func (a *Agent) currentKey() string {
+	a.keyMu.Lock()
+	defer a.keyMu.Unlock()
+
+	return a.key
+}
+
+// call sends a request to the probe API and decodes the data of its
+// response envelope into out. Unauthorized responses are returned as
+// models.ErrProbeUnauthorized.
+func (a *Agent) call(ctx context.Context, method, path, token string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.config.Server+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", a.config.Server, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return models.ErrProbeUnauthorized
+	}
+
+	var envelope struct {
+		Data  json.RawMessage `json:"data"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxProbeResponseBytes)).Decode(&envelope); err != nil {
+		return fmt.Errorf("unexpected response with status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if envelope.Error != nil {
+			return fmt.Errorf("request rejected with status %d: %s", resp.StatusCode, envelope.Error.Message)
+		}
+		return fmt.Errorf("request rejected with status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package monitor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// alertEvaluationInterval controls how often alert policies are evaluated
+// for reminders, escalations and deferred alerts
+const alertEvaluationInterval = time.Minute
+
+// alertLoop periodically applies the websites' alert policies
+func (m *Monitor) alertLoop(ctx context.Context, db Database) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(alertEvaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.processAlerts(db, time.Now())
+		}
+	}
+}
+
+// processAlerts releases alerts deferred by quiet hours that have ended,
+// escalates unacknowledged incidents and reminds channels of websites that
+// are still down
+func (m *Monitor) processAlerts(db Database, now time.Time) {
+	websites, err := db.GetActiveWebsites()
+	if err != nil {
+		m.logger.Error("Failed to get active websites", "error", err)
+		return
+	}
+
+	m.releaseDeferredAlerts(websites, db, now)
+
+	for _, website := range websites {
+		incident, err := db.GetLatestIncident(website.ID)
+		if err != nil {
+			m.logger.Error("Failed to get latest incident", "website_id", website.ID, "error", err)
+			continue
+		}
+		if incident == nil || incident.IsResolved() || incident.AcknowledgedAt != nil {
+			continue
+		}
+
+		// Maintenance suppresses reminders and escalations
+		if m.activeMaintenance(website, db, now) != nil {
+			continue
+		}
+
+		m.escalate(website, *incident, db, now)
+		m.remind(website, *incident, db, now)
+	}
+}
+
+// releaseDeferredAlerts delivers alerts held back by quiet hours once the
+// website's quiet hours have ended
+func (m *Monitor) releaseDeferredAlerts(websites []models.Website, db Database, now time.Time) {
+	deferred, err := db.GetDeferredAlerts()
+	if err != nil {
+		m.logger.Error("Failed to get deferred alerts", "error", err)
+		return
+	}
+	if len(deferred) == 0 {
+		return
+	}
+
+	byID := make(map[int]models.Website, len(websites))
+	for _, website := range websites {
+		byID[website.ID] = website
+	}
+
+	for _, record := range deferred {
+		website, ok := byID[record.WebsiteID]
+		if !ok || website.Alerts.InQuietHours(now) {
+			continue
+		}
+
+		channel := m.defaultChannel()
+		if record.ChannelID > 0 {
+			stored, err := db.GetNotificationChannel(record.ChannelID)
+			if errors.Is(err, sql.ErrNoRows) {
+				// The channel was deleted while the alert waited
+				if err := db.SetAlertOutcome(record.ID, models.AlertFailed); err != nil {
+					m.logger.Error("Failed to update deferred alert", "alert_id", record.ID, "error", err)
+				}
+				continue
+			}
+			if err != nil {
+				m.logger.Error("Failed to get notification channel", "channel_id", record.ChannelID, "error", err)
+				continue
+			}
+			channel = *stored
+		}
+
+		notification := Notification{
+			Website:   website,
+			Type:      record.Type,
+			Reason:    record.Reason,
+			Timestamp: now,
+		}
+		m.deliver(channel, notification, record.IncidentID, db)
+
+		if err := db.SetAlertOutcome(record.ID, models.AlertReleased); err != nil {
+			m.logger.Error("Failed to update deferred alert", "alert_id", record.ID, "error", err)
+		}
+	}
+}
+
+// escalate alerts the website's escalation contact once an incident has
+// stayed unacknowledged for longer than the policy allows. Escalations are
+// critical, so quiet hours don't apply, and a failed escalation is retried
+// on the next evaluation.
+func (m *Monitor) escalate(website models.Website, incident models.Incident, db Database, now time.Time) {
+	policy := website.Alerts
+	if policy.EscalationChannelID == 0 || policy.EscalateAfter <= 0 {
+		return
+	}
+	if now.Sub(incident.StartedAt) < time.Duration(policy.EscalateAfter)*time.Minute {
+		return
+	}
+
+	escalated, err := db.GetLastAlertSent(incident.ID, models.AlertEscalation)
+	if err != nil {
+		m.logger.Error("Failed to get last escalation", "incident_id", incident.ID, "error", err)
+		return
+	}
+	if escalated != nil {
+		return
+	}
+
+	channel, err := db.GetNotificationChannel(policy.EscalationChannelID)
+	if err != nil {
+		m.logger.Error("Failed to get escalation channel", "website_id", website.ID, "channel_id", policy.EscalationChannelID, "error", err)
+		return
+	}
+
+	notification := Notification{
+		Website:   website,
+		Type:      models.AlertEscalation,
+		Reason:    incident.Cause,
+		Timestamp: now,
+	}
+	if m.deliver(*channel, notification, incident.ID, db) == nil {
+		m.logger.Info("Escalated incident", "website_id", website.ID, "incident_id", incident.ID, "channel", channel.Name)
+	}
+}
+
+// remind re-notifies the website's channels every re-notify interval while
+// an incident stays open. Reminders are skipped rather than deferred during
+// quiet hours, so a single reminder follows once they end.
+func (m *Monitor) remind(website models.Website, incident models.Incident, db Database, now time.Time) {
+	policy := website.Alerts
+	if policy.RenotifyInterval <= 0 || policy.InQuietHours(now) {
+		return
+	}
+
+	last, err := db.GetLastAlertSent(incident.ID, models.AlertDown, models.AlertReminder)
+	if err != nil {
+		m.logger.Error("Failed to get last alert", "incident_id", incident.ID, "error", err)
+		return
+	}
+	since := incident.StartedAt
+	if last != nil && last.After(since) {
+		since = *last
+	}
+	if now.Sub(since) < time.Duration(policy.RenotifyInterval)*time.Minute {
+		return
+	}
+
+	notification := Notification{
+		Website:   website,
+		Type:      models.AlertReminder,
+		Reason:    incident.Cause,
+		Timestamp: now,
+	}
+	if m.notify(notification, incident.ID, db) {
+		m.logger.Info("Sent reminder alert", "website_id", website.ID, "incident_id", incident.ID)
+	}
+}
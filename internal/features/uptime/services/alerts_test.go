@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"net/http"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestQuietHoursDeferNonCriticalAlerts(t *testing.T) {
+	webhook, requests := newStandIn(t, http.StatusOK, "")
+
+	website := models.Website{
+		ID:   1,
+		Name: "Example",
+		URL:  "https://example.com",
+		Alerts: models.AlertPolicy{
+			QuietHoursStart:    "22:00",
+			QuietHoursEnd:      "06:00",
+			QuietHoursTimezone: "UTC",
+		},
+	}
+	db := &fakeDatabase{
+		websites: []models.Website{website},
+		channels: map[int][]models.NotificationChannel{
+			1: {{ID: 3, Name: "Webhook", Type: models.ChannelWebhook, URL: webhook.URL}},
+		},
+	}
+	m := newTestMonitor(MonitorConfig{})
+
+	night := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+
+	// Down alerts are critical and delivered straight away
+	if !m.notify(Notification{Website: website, Type: models.AlertDown, Timestamp: night}, 1, db) {
+		t.Fatalf("Expected the down alert to be delivered during quiet hours")
+	}
+
+	if m.notify(Notification{Website: website, Type: models.AlertRecovery, Timestamp: night.Add(time.Hour)}, 1, db) {
+		t.Fatalf("Expected the recovery alert to be deferred")
+	}
+	if len(requests()) != 1 || len(db.alertsWith(models.AlertDeferred)) != 1 {
+		t.Fatalf("Expected one delivery and one deferred alert, got %d and %+v", len(requests()), db.alerts)
+	}
+
+	m.processAlerts(db, night.Add(3*time.Hour))
+	if len(requests()) != 1 {
+		t.Fatalf("Expected the deferred alert to wait for the end of quiet hours")
+	}
+
+	m.processAlerts(db, night.Add(8*time.Hour))
+	if len(requests()) != 2 {
+		t.Fatalf("Expected the deferred alert to be released")
+	}
+	if len(db.alertsWith(models.AlertDeferred)) != 0 || len(db.alertsWith(models.AlertReleased)) != 1 {
+		t.Errorf("Expected the deferred alert to be marked released, got %+v", db.alerts)
+	}
+	sent := db.alertsWith(models.AlertSent)
+	if len(sent) != 2 || sent[1].Type != models.AlertRecovery || sent[1].ChannelID != 3 {
+		t.Errorf("Expected the released recovery alert to be recorded, got %+v", sent)
+	}
+}
+
+func TestRemindersAndEscalation(t *testing.T) {
+	team, teamRequests := newStandIn(t, http.StatusOK, "")
+	manager, managerRequests := newStandIn(t, http.StatusOK, "")
+
+	now := time.Now()
+	website := models.Website{
+		ID:   1,
+		Name: "Example",
+		URL:  "https://example.com",
+		Alerts: models.AlertPolicy{
+			RenotifyInterval:    10,
+			EscalationChannelID: 9,
+			EscalateAfter:       20,
+		},
+	}
+	db := &fakeDatabase{
+		websites: []models.Website{website},
+		channels: map[int][]models.NotificationChannel{
+			1: {{ID: 3, Name: "Team", Type: models.ChannelWebhook, URL: team.URL}},
+		},
+		contacts: []models.NotificationChannel{
+			{ID: 9, Name: "Manager", Type: models.ChannelWebhook, URL: manager.URL},
+		},
+		incidents: []models.Incident{{
+			ID:        1,
+			WebsiteID: 1,
+			Status:    models.IncidentOpen,
+			Cause:     "HTTP 503",
+			StartedAt: now.Add(-15 * time.Minute),
+		}},
+		alerts: []models.AlertRecord{{
+			ID:         1,
+			WebsiteID:  1,
+			IncidentID: 1,
+			Type:       models.AlertDown,
+			ChannelID:  3,
+			Outcome:    models.AlertSent,
+			SentAt:     now.Add(-15 * time.Minute),
+		}},
+	}
+	m := newTestMonitor(MonitorConfig{})
+
+	// Fifteen minutes in: due a reminder but not an escalation
+	m.processAlerts(db, now)
+	if len(teamRequests()) != 1 || len(managerRequests()) != 0 {
+		t.Fatalf("Expected a reminder only, got %d team and %d manager requests", len(teamRequests()), len(managerRequests()))
+	}
+
+	// The reminder resets the re-notify interval
+	m.processAlerts(db, now.Add(time.Minute))
+	if len(teamRequests()) != 1 {
+		t.Fatalf("Expected no reminder within the re-notify interval")
+	}
+
+	// Twenty six minutes in: reminded again and escalated once
+	m.processAlerts(db, now.Add(11*time.Minute))
+	m.processAlerts(db, now.Add(12*time.Minute))
+	if len(teamRequests()) != 2 || len(managerRequests()) != 1 {
+		t.Fatalf("Expected a second reminder and a single escalation, got %d team and %d manager requests", len(teamRequests()), len(managerRequests()))
+	}
+
+	// Acknowledging the incident stops reminders and escalations
+	db.mu.Lock()
+	acknowledgedAt := now
+	db.incidents[0].AcknowledgedAt = &acknowledgedAt
+	db.mu.Unlock()
+
+	m.processAlerts(db, now.Add(time.Hour))
+	if len(teamRequests()) != 2 || len(managerRequests()) != 1 {
+		t.Errorf("Expected no alerts for an acknowledged incident, got %d team and %d manager requests", len(teamRequests()), len(managerRequests()))
+	}
+
+	for _, record := range db.alertsWith(models.AlertSent) {
+		if record.IncidentID != 1 {
+			t.Errorf("Expected alerts to be linked to the incident, got %+v", record)
+		}
+	}
+}
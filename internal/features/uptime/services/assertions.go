@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// maxBodyBytes caps how much of a response body is read for body assertions
+const maxBodyBytes = 1 << 20
+
+// evaluateAssertions returns a description of the first assertion the
+// response fails, or an empty string when it satisfies them all
+func evaluateAssertions(a models.Assertions, resp *http.Response, body []byte, responseTime time.Duration) string {
+	if !a.AcceptsStatus(resp.StatusCode) {
+		return fmt.Sprintf("unexpected status code %d (expected %s)", resp.StatusCode, a.AcceptedStatusCodes())
+	}
+
+	for name, expected := range a.Headers {
+		values := resp.Header.Values(name)
+		if len(values) == 0 {
+			return fmt.Sprintf("missing response header %s", name)
+		}
+		if expected != "" && !containsFold(values, expected) {
+			return fmt.Sprintf("response header %s is %q, expected %q", name, strings.Join(values, ", "), expected)
+		}
+	}
+
+	if a.BodyContains != "" {
+		found, err := bodyMatches(body, a.BodyContains, a.BodyRegex)
+		if err != nil {
+			return err.Error()
+		}
+		if !found {
+			return fmt.Sprintf("response body does not contain %q", a.BodyContains)
+		}
+	}
+
+	if a.BodyNotContains != "" {
+		found, err := bodyMatches(body, a.BodyNotContains, a.BodyRegex)
+		if err != nil {
+			return err.Error()
+		}
+		if found {
+			return fmt.Sprintf("response body contains forbidden %q", a.BodyNotContains)
+		}
+	}
+
+	if a.MaxResponseTime > 0 && responseTime > time.Duration(a.MaxResponseTime)*time.Millisecond {
+		return fmt.Sprintf("response time %dms exceeds limit of %dms", responseTime.Milliseconds(), a.MaxResponseTime)
+	}
+
+	return ""
+}
+
+// bodyMatches reports whether body contains pattern, either as a substring
+// or as a regular expression
+func bodyMatches(body []byte, pattern string, isRegex bool) (bool, error) {
+	if !isRegex {
+		return strings.Contains(string(body), pattern), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Errorf("invalid body pattern %q: %w", pattern, err)
+	}
+	return re.Match(body), nil
+}
+
+func containsFold(values []string, expected string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), expected) {
+			return true
+		}
+	}
+	return false
+}
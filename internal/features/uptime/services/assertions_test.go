@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestCheckHTTPAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/redirect":
+			http.Redirect(w, r, "/no-content", http.StatusMovedPermanently)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status": "ok", "version": "1.2.3"}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		assertions models.Assertions
+		wantReason string
+	}{
+		{name: "default accepts 204", path: "/no-content"},
+		{name: "default accepts followed redirect", path: "/redirect"},
+		{name: "default rejects 500", path: "/error", wantReason: "unexpected status code 500"},
+		{name: "explicit range", path: "/error", assertions: models.Assertions{StatusCodes: "200-299,500"}},
+		{name: "body contains", path: "/", assertions: models.Assertions{BodyContains: `"status": "ok"`}},
+		{name: "body missing keyword", path: "/", assertions: models.Assertions{BodyContains: "healthy"}, wantReason: "does not contain"},
+		{name: "forbidden keyword", path: "/", assertions: models.Assertions{BodyNotContains: "version"}, wantReason: "contains forbidden"},
+		{name: "body regex", path: "/", assertions: models.Assertions{BodyContains: `"version": "\d+\.\d+`, BodyRegex: true}},
+		{name: "required header value", path: "/", assertions: models.Assertions{Headers: map[string]string{"Content-Type": "application/json"}}},
+		{name: "missing header", path: "/", assertions: models.Assertions{Headers: map[string]string{"X-Health": ""}}, wantReason: "missing response header X-Health"},
+	}
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := m.check(context.Background(), models.Website{URL: server.URL + tt.path, Assertions: tt.assertions})
+
+			if tt.wantReason == "" {
+				if !result.IsUp {
+					t.Fatalf("Expected website to be up, got reason %q", result.Error)
+				}
+				return
+			}
+
+			if result.IsUp {
+				t.Fatalf("Expected website to be down with reason containing %q", tt.wantReason)
+			}
+			if !strings.Contains(result.Error, tt.wantReason) {
+				t.Errorf("Expected reason containing %q, got %q", tt.wantReason, result.Error)
+			}
+		})
+	}
+}
+
+func TestAssertionsValidate(t *testing.T) {
+	invalid := []models.Assertions{
+		{StatusCodes: "abc"},
+		{StatusCodes: "299-200"},
+		{StatusCodes: "700"},
+		{BodyContains: "(", BodyRegex: true},
+	}
+	for _, assertions := range invalid {
+		if err := assertions.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", assertions)
+		}
+	}
+
+	if err := (models.Assertions{StatusCodes: "200-299, 301"}).Validate(); err != nil {
+		t.Errorf("Expected valid status codes, got %v", err)
+	}
+}
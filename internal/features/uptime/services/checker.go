@@ -0,0 +1,47 @@
+package monitor
+
+import (
+	"context"
+	"the-ark/internal/features/uptime/models"
+)
+
+// Result is the outcome of a single website check
+type Result struct {
+	// StatusCode is the HTTP status code, or 0 for checks that aren't HTTP
+	StatusCode   int
+	ResponseTime int64
+	IsUp         bool
+	Error        string
+
+	// Attempts is how many times the check ran, including retries
+	Attempts int
+
+	// Timing breaks down the response time of HTTP checks
+	Timing *models.CheckTiming
+
+	// Outvoted marks a failure too few locations agreed with to take the
+	// website down. The website counts as up, but degraded.
+	Outvoted bool
+
+	// Content is the normalized body of websites with content monitoring,
+	// or nil when it wasn't read
+	Content *string
+
+	// ContentError explains why the content couldn't be normalized. It
+	// doesn't affect the check, the snapshot is skipped.
+	ContentError string
+}
+
+// Checker checks websites of one check type. Implementations must honour
+// ctx cancellation and report failures in the Result rather than panicking.
+type Checker interface {
+	Check(ctx context.Context, website models.Website) Result
+}
+
+// CheckerFunc adapts a function to the Checker interface
+type CheckerFunc func(ctx context.Context, website models.Website) Result
+
+// Check calls f(ctx, website)
+func (f CheckerFunc) Check(ctx context.Context, website models.Website) Result {
+	return f(ctx, website)
+}
@@ -0,0 +1,174 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestTCPChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// Reserve a port and release it so nothing is listening there
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 2 * time.Second})
+
+	open := models.Website{CheckType: models.CheckTypeTCP, URL: listener.Addr().String()}
+	if result := m.check(context.Background(), open); !result.IsUp {
+		t.Errorf("Expected open port to be up, got %q", result.Error)
+	}
+
+	refused := models.Website{CheckType: models.CheckTypeTCP, URL: closedAddr}
+	if result := m.check(context.Background(), refused); result.IsUp {
+		t.Errorf("Expected closed port to be down")
+	}
+}
+
+func TestTLSChecker(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	target := strings.TrimPrefix(server.URL, "https://")
+	website := models.Website{CheckType: models.CheckTypeTLS, URL: target}
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 2 * time.Second})
+
+	// The test certificate isn't signed by a system root
+	if result := m.check(context.Background(), website); result.IsUp || !strings.Contains(result.Error, "TLS handshake failed") {
+		t.Errorf("Expected untrusted certificate to fail, got %+v", result)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	m.RegisterChecker(models.CheckTypeTLS, &tlsChecker{dialer: &net.Dialer{}, rootCAs: roots})
+
+	if result := m.check(context.Background(), website); !result.IsUp {
+		t.Errorf("Expected trusted certificate to pass, got %q", result.Error)
+	}
+}
+
+func TestHTTPCheckerTiming(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		w.Write([]byte("first half"))
+		w.(http.Flusher).Flush()
+		time.Sleep(30 * time.Millisecond)
+		w.Write([]byte("second half"))
+	}))
+	// Slow the handshake down so it shows up in whole milliseconds
+	server.TLS = &tls.Config{GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	}}
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	checker := newHTTPChecker(2 * time.Second)
+	checker.client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: roots}
+
+	website := models.Website{URL: server.URL, Request: models.RequestOptions{FollowRedirects: true}}
+	result := checker.Check(context.Background(), website)
+	if !result.IsUp || result.Timing == nil {
+		t.Fatalf("Expected a timed successful check, got %+v", result)
+	}
+	timing := *result.Timing
+	if timing.DNS != 0 {
+		t.Errorf("Expected no DNS lookup for an IP address, got %d ms", timing.DNS)
+	}
+	if timing.TLS < 20 || timing.TTFB < 30 || timing.Transfer < 30 {
+		t.Errorf("Expected the slow handshake, response and body in their phases, got %+v", timing)
+	}
+
+	// Each check opens a new connection, so the handshake is timed again
+	result = checker.Check(context.Background(), website)
+	if result.Timing == nil || result.Timing.TLS < 20 || result.Timing.TTFB < 30 {
+		t.Errorf("Expected the handshake timed on the second check, got %+v", result.Timing)
+	}
+}
+
+func TestDNSChecker(t *testing.T) {
+	tests := []struct {
+		name   string
+		dns    models.DNSOptions
+		wantUp bool
+	}{
+		{name: "resolves", dns: models.DNSOptions{}, wantUp: true},
+		{name: "expected address", dns: models.DNSOptions{RecordType: models.DNSRecordA, Expected: "127.0.0.1"}, wantUp: true},
+		{name: "unexpected address", dns: models.DNSOptions{RecordType: models.DNSRecordA, Expected: "192.0.2.1"}},
+	}
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 2 * time.Second})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			website := models.Website{CheckType: models.CheckTypeDNS, URL: "localhost", DNS: tt.dns}
+			result := m.check(context.Background(), website)
+			if result.IsUp != tt.wantUp {
+				t.Errorf("Expected up=%v, got %+v", tt.wantUp, result)
+			}
+		})
+	}
+}
+
+func TestUnsupportedCheckType(t *testing.T) {
+	m := newTestMonitor(MonitorConfig{})
+	result := m.check(context.Background(), models.Website{CheckType: "icmp", URL: "localhost"})
+	if result.IsUp || !strings.Contains(result.Error, "unsupported check type") {
+		t.Errorf("Expected unsupported check type to fail, got %+v", result)
+	}
+}
+
+func TestWebsiteValidate(t *testing.T) {
+	valid := []models.Website{
+		{URL: "https://example.com"},
+		{CheckType: models.CheckTypeTCP, URL: "db.example.com:5432"},
+		{CheckType: models.CheckTypeTLS, URL: "example.com"},
+		{CheckType: models.CheckTypeDNS, URL: "example.com", DNS: models.DNSOptions{RecordType: "mx"}},
+	}
+	for _, website := range valid {
+		if err := website.Validate(); err != nil {
+			t.Errorf("Expected %s %q to be valid, got %v", website.Type(), website.URL, err)
+		}
+	}
+
+	invalid := []models.Website{
+		{URL: "example.com"},
+		{CheckType: models.CheckTypeTCP, URL: "db.example.com"},
+		{CheckType: models.CheckTypeTCP, URL: "db.example.com:99999"},
+		{CheckType: models.CheckTypeDNS, URL: "https://example.com"},
+		{CheckType: models.CheckTypeDNS, URL: "example.com", DNS: models.DNSOptions{RecordType: "SRV"}},
+		{CheckType: "icmp", URL: "example.com"},
+	}
+	for _, website := range invalid {
+		if err := website.Validate(); err == nil {
+			t.Errorf("Expected %s %q to be invalid", website.Type(), website.URL)
+		}
+	}
+}
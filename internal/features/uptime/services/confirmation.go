@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// checkWithRetries checks a website, retrying failures with exponential
+// backoff as configured for the website. Each attempt gets the full check
+// timeout.
+func (m *Monitor) checkWithRetries(ctx context.Context, website models.Website) Result {
+	var result Result
+	for attempt := 1; ; attempt++ {
+		result = m.checkOnce(ctx, website)
+		result.Attempts = attempt
+
+		if result.IsUp || attempt > website.Confirmation.Retries {
+			break
+		}
+
+		delay := website.Confirmation.Backoff(attempt)
+		m.logger.Warn("Website check failed, retrying", "url", website.URL, "attempt", attempt, "delay", delay, "error", result.Error)
+
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(delay):
+		}
+	}
+
+	if !result.IsUp && result.Attempts > 1 {
+		result.Error = fmt.Sprintf("%s (failed %d attempts)", result.Error, result.Attempts)
+	}
+	return result
+}
+
+// checkOnce runs a single check attempt bounded by the check timeout
+func (m *Monitor) checkOnce(ctx context.Context, website models.Website) Result {
+	ctx, cancel := context.WithTimeout(ctx, m.config.CheckTimeout)
+	defer cancel()
+
+	return m.check(ctx, website)
+}
+
+// confirmedState returns a website's confirmed state, up or down, and how
+// many pending checks have disagreed with it since. The state is empty for
+// a website without confirmed checks.
+func (m *Monitor) confirmedState(website models.Website, db Database) (string, int, error) {
+	statuses, err := db.GetRecentStatuses(website.ID, models.MaxConfirmationCount+1)
+	if err != nil {
+		return "", 0, err
+	}
+
+	streak := 0
+	for _, status := range statuses {
+		if status != models.StatusPending {
+			if models.IsUpStatus(status) {
+				return models.StatusUp, streak, nil
+			}
+			return models.StatusDown, streak, nil
+		}
+		streak++
+	}
+	return "", streak, nil
+}
+
+// confirmStatus decides the status to store for a check result. A result
+// that disagrees with the confirmed state is pending until enough
+// consecutive checks agree with it. Up results that needed retries or were
+// outvoted by other locations are degraded.
+func confirmStatus(c models.Confirmation, result Result, confirmed string, streak int) string {
+	observed := models.StatusDown
+	if result.IsUp {
+		observed = models.StatusUp
+		if result.Attempts > 1 || result.Outvoted {
+			observed = models.StatusDegraded
+		}
+	}
+
+	currentlyUp := confirmed == models.StatusUp
+	if confirmed == "" || result.IsUp == currentlyUp {
+		return observed
+	}
+
+	// This check plus the pending ones before it
+	if streak+1 >= c.Threshold(currentlyUp) {
+		return observed
+	}
+	return models.StatusPending
+}
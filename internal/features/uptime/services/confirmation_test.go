@@ -0,0 +1,131 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestRetriesMarkFlakyWebsiteDegraded(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first two requests
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	website := models.Website{
+		ID:           1,
+		URL:          server.URL,
+		Confirmation: models.Confirmation{Retries: 2, RetryDelay: 1},
+	}
+
+	m.CheckWebsite(context.Background(), website, db)
+
+	checks := db.checksFor(1)
+	if len(checks) != 1 || checks[0].Status != models.StatusDegraded {
+		t.Fatalf("Expected a single degraded check, got %+v", checks)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", requests.Load())
+	}
+	if len(db.incidents) != 0 {
+		t.Errorf("Expected no incident for a degraded check, got %+v", db.incidents)
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	website := models.Website{URL: server.URL, Confirmation: models.Confirmation{Retries: 1, RetryDelay: 1}}
+
+	result := m.checkWithRetries(context.Background(), website)
+	if result.IsUp || result.Attempts != 2 || requests.Load() != 2 {
+		t.Errorf("Expected two failed attempts, got %+v after %d requests", result, requests.Load())
+	}
+}
+
+func TestConsecutiveChecksConfirmStateChanges(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	website := models.Website{
+		ID:           1,
+		URL:          server.URL,
+		Confirmation: models.Confirmation{FailureThreshold: 3, RecoveryThreshold: 2},
+	}
+
+	steps := []struct {
+		healthy bool
+		want    string
+	}{
+		{true, models.StatusUp},
+		{false, models.StatusPending},
+		{true, models.StatusUp}, // A pass resets the failure streak
+		{false, models.StatusPending},
+		{false, models.StatusPending},
+		{false, models.StatusDown},
+		{true, models.StatusPending},
+		{true, models.StatusUp},
+	}
+
+	for i, step := range steps {
+		healthy.Store(step.healthy)
+		m.CheckWebsite(context.Background(), website, db)
+
+		checks := db.checksFor(1)
+		if got := checks[len(checks)-1].Status; got != step.want {
+			t.Fatalf("Step %d: expected %s, got %s", i, step.want, got)
+		}
+
+		// The incident only opens once the failure is confirmed
+		wantIncidents := 0
+		if i >= 5 {
+			wantIncidents = 1
+		}
+		if len(db.incidents) != wantIncidents {
+			t.Fatalf("Step %d: expected %d incidents, got %d", i, wantIncidents, len(db.incidents))
+		}
+	}
+
+	if !db.incidents[0].IsResolved() {
+		t.Errorf("Expected the incident to be resolved after confirmed recovery")
+	}
+}
+
+func TestConfirmationBackoff(t *testing.T) {
+	c := models.Confirmation{RetryDelay: 500}
+
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}
+	for i, delay := range want {
+		if got := c.Backoff(i + 1); got != delay {
+			t.Errorf("Retry %d: expected %v, got %v", i+1, delay, got)
+		}
+	}
+
+	if got := c.Backoff(20); got != 30*time.Second {
+		t.Errorf("Expected backoff to be capped at 30s, got %v", got)
+	}
+}
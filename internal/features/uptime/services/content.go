@@ -0,0 +1,184 @@
+package monitor
+
+import (
+	"bytes"
+	"net/http"
+	"regexp"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skippedElements hold no visible text worth comparing
+var skippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Iframe:   true,
+}
+
+// inlineElements don't start a new line of text
+var inlineElements = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Bdi: true, atom.Bdo: true,
+	atom.Cite: true, atom.Code: true, atom.Data: true, atom.Em: true, atom.I: true,
+	atom.Kbd: true, atom.Label: true, atom.Mark: true, atom.Q: true, atom.S: true,
+	atom.Samp: true, atom.Small: true, atom.Span: true, atom.Strong: true, atom.Sub: true,
+	atom.Sup: true, atom.Time: true, atom.U: true, atom.Var: true,
+}
+
+// normalizeContent reduces a response body to the text compared between
+// checks: the visible text of HTML pages, or the body of other responses,
+// without ignored elements and patterns, one trimmed line per block of text
+func normalizeContent(body []byte, contentType string, options models.ContentOptions) (string, error) {
+	selectors, err := options.Selectors()
+	if err != nil {
+		return "", err
+	}
+	patterns, err := options.Patterns()
+	if err != nil {
+		return "", err
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	text := string(body)
+	if strings.Contains(contentType, "html") {
+		text, err = htmlText(body, selectors)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return normalizeLines(text, patterns), nil
+}
+
+// htmlText extracts the visible text of an HTML document, leaving out
+// elements matching any of the selectors
+func htmlText(body []byte, selectors []models.Selector) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.CommentNode:
+			return
+		case html.TextNode:
+			text.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if skippedElements[n.DataAtom] || ignoredElement(n, selectors) {
+				return
+			}
+		}
+
+		block := n.Type == html.ElementNode && !inlineElements[n.DataAtom]
+		if block {
+			text.WriteByte('\n')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if block {
+			text.WriteByte('\n')
+		}
+	}
+	walk(doc)
+
+	return text.String(), nil
+}
+
+// ignoredElement reports whether an element matches any of the selectors
+func ignoredElement(n *html.Node, selectors []models.Selector) bool {
+	if len(selectors) == 0 {
+		return false
+	}
+
+	var id, class string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "id":
+			id = attr.Val
+		case "class":
+			class = attr.Val
+		}
+	}
+	for _, selector := range selectors {
+		if selector.Matches(n.Data, id, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLines removes the ignored patterns from text, collapses
+// whitespace within each line and drops empty lines
+func normalizeLines(text string, patterns []*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		text = pattern.ReplaceAllString(text, "")
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trackContent stores a snapshot of a website's content whenever it
+// changes, and alerts on every change after the first snapshot. Changes
+// during maintenance update the snapshot without alerting.
+func (m *Monitor) trackContent(website models.Website, content string, maintenance bool, db Database) {
+	latest, err := db.GetLatestContentSnapshot(website.ID)
+	if err != nil {
+		m.logger.Error("Failed to get latest content snapshot", "website_id", website.ID, "error", err)
+		return
+	}
+
+	hash := models.ContentHash(content)
+	if latest != nil && latest.Hash == hash {
+		return
+	}
+
+	snapshot := models.ContentSnapshot{
+		WebsiteID:  website.ID,
+		Hash:       hash,
+		Content:    content,
+		CapturedAt: time.Now(),
+	}
+	var diff models.ContentDiff
+	if latest != nil {
+		diff = models.DiffContent(latest.Content, content)
+		snapshot.Diff = diff.Text(0)
+	}
+	if err := db.StoreContentSnapshot(snapshot); err != nil {
+		m.logger.Error("Failed to store content snapshot", "website_id", website.ID, "error", err)
+		return
+	}
+
+	if latest == nil || maintenance {
+		return
+	}
+	m.logger.Warn("Website content changed", "url", website.URL, "added", diff.Added, "removed", diff.Removed)
+
+	// Content changes aren't part of an incident
+	notification := Notification{
+		Website:   website,
+		Type:      models.AlertContentChanged,
+		Reason:    diff.Summary() + "\n" + diff.Text(models.MaxAlertDiffLines),
+		Timestamp: snapshot.CapturedAt,
+	}
+	if m.notify(notification, 0, db) {
+		m.logger.Info("Sent alert", "website_id", website.ID, "url", website.URL, "type", models.AlertContentChanged)
+	}
+}
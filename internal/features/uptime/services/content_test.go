@@ -0,0 +1,138 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestNormalizeContent(t *testing.T) {
+	body := []byte(`<html><head><title>Shop</title><style>body { color: red }</style></head>
+<body>
+	<h1>Welcome   to the <b>shop</b></h1>
+	<div id="clock">12:00:01</div>
+	<p class="ad banner">Buy now</p>
+	<script>track()</script>
+	<p>Updated 2024-05-01</p>
+</body></html>`)
+	options := models.ContentOptions{
+		Enabled:         true,
+		IgnoreSelectors: []string{"#clock", "p.ad"},
+		IgnorePatterns:  []string{`\d{4}-\d{2}-\d{2}`},
+	}
+
+	content, err := normalizeContent(body, "text/html; charset=utf-8", options)
+	if err != nil {
+		t.Fatalf("Failed to normalize content: %v", err)
+	}
+	if want := "Shop\nWelcome to the shop\nUpdated"; content != want {
+		t.Errorf("Expected %q, got %q", want, content)
+	}
+
+	// Other responses are compared as they are
+	content, err = normalizeContent([]byte(`{"version": "1.2"}`+"\n\n"), "application/json", options)
+	if err != nil || content != `{"version": "1.2"}` {
+		t.Errorf("Expected the JSON body, got %q, %v", content, err)
+	}
+}
+
+func TestContentChangeAlerts(t *testing.T) {
+	var defaced atomic.Bool
+	var visits atomic.Int64
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		visit := visits.Add(1)
+		headline := "Welcome to Example"
+		if defaced.Load() {
+			headline = "Hacked!"
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body><h1>%s</h1><span id="clock">%d</span><p>Visited on %s</p></body></html>`,
+			headline, visit, time.Now().Add(time.Duration(visit)*24*time.Hour).Format("2006-01-02"))
+	}))
+	defer target.Close()
+
+	webhook, requests := newStandIn(t, http.StatusOK, "")
+
+	website := models.Website{
+		ID:   1,
+		Name: "Example",
+		URL:  target.URL,
+		Content: models.ContentOptions{
+			Enabled:         true,
+			IgnoreSelectors: []string{"#clock"},
+			IgnorePatterns:  []string{`\d{4}-\d{2}-\d{2}`},
+		},
+	}
+	db := &fakeDatabase{
+		channels: map[int][]models.NotificationChannel{1: {{Name: "Webhook", Type: models.ChannelWebhook, URL: webhook.URL}}},
+	}
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+
+	// The first snapshot is stored quietly, and the ignored parts of the
+	// page changing doesn't count as a change
+	m.CheckWebsite(context.Background(), website, db)
+	m.CheckWebsite(context.Background(), website, db)
+	if len(db.snapshots) != 1 || len(requests()) != 0 {
+		t.Fatalf("Expected a single snapshot and no alert, got %d snapshots and %d requests", len(db.snapshots), len(requests()))
+	}
+	if db.snapshots[0].Diff != "" || db.snapshots[0].Content != "Welcome to Example\nVisited on" {
+		t.Errorf("Unexpected first snapshot %+v", db.snapshots[0])
+	}
+
+	defaced.Store(true)
+	m.CheckWebsite(context.Background(), website, db)
+	if len(db.snapshots) != 2 {
+		t.Fatalf("Expected a snapshot of the changed content, got %d", len(db.snapshots))
+	}
+	if diff := db.snapshots[1].Diff; diff != "- Welcome to Example\n+ Hacked!\n  Visited on" {
+		t.Errorf("Unexpected diff %q", diff)
+	}
+
+	sent := requests()
+	if len(sent) != 1 || !strings.Contains(sent[0].Body, "Hacked!") {
+		t.Fatalf("Expected a content change alert with the diff, got %+v", sent)
+	}
+	if len(db.alerts) != 1 || db.alerts[0].Type != models.AlertContentChanged {
+		t.Errorf("Expected a content_changed alert recorded, got %+v", db.alerts)
+	}
+	if len(db.incidents) != 0 {
+		t.Errorf("Expected no incident for a content change, got %+v", db.incidents)
+	}
+}
+
+func TestUnreadableContentKeepsWebsiteUp(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "Welcome to Example")
+	}))
+	defer target.Close()
+
+	website := models.Website{
+		ID:   1,
+		Name: "Example",
+		URL:  target.URL,
+		Content: models.ContentOptions{
+			Enabled:        true,
+			IgnorePatterns: []string{`(unclosed`},
+		},
+	}
+	db := &fakeDatabase{}
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	m.CheckWebsite(context.Background(), website, db)
+
+	checks := db.checksFor(1)
+	if len(checks) != 1 || checks[0].Status != models.StatusUp {
+		t.Fatalf("Expected the website to stay up, got %+v", checks)
+	}
+	if len(db.snapshots) != 0 {
+		t.Errorf("Expected the snapshot to be skipped, got %+v", db.snapshots)
+	}
+}
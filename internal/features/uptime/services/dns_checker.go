@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// dnsChecker reports a website as up when its hostname resolves, and when
+// configured, when the expected value is among the resolved records
+type dnsChecker struct {
+	resolver *net.Resolver
+}
+
+func (c *dnsChecker) Check(ctx context.Context, website models.Website) Result {
+	recordType := website.DNS.Type()
+
+	start := time.Now()
+	values, err := c.lookup(ctx, website.URL, recordType)
+	result := Result{ResponseTime: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = fmt.Sprintf("%s lookup failed: %v", recordType, err)
+		return result
+	}
+
+	if len(values) == 0 {
+		result.Error = fmt.Sprintf("no %s records found for %s", recordType, website.URL)
+		return result
+	}
+
+	if expected := website.DNS.Expected; expected != "" {
+		found := false
+		for _, value := range values {
+			if normalizeDNSValue(value) == normalizeDNSValue(expected) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result.Error = fmt.Sprintf("%s records for %s are %s, expected %s", recordType, website.URL, strings.Join(values, ", "), expected)
+			return result
+		}
+	}
+
+	result.IsUp = true
+	return result
+}
+
+// lookup resolves the records of one type for a hostname
+func (c *dnsChecker) lookup(ctx context.Context, host, recordType string) ([]string, error) {
+	switch recordType {
+	case models.DNSRecordA, models.DNSRecordAAAA:
+		network := "ip4"
+		if recordType == models.DNSRecordAAAA {
+			network = "ip6"
+		}
+		ips, err := c.resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, len(ips))
+		for i, ip := range ips {
+			values[i] = ip.String()
+		}
+		return values, nil
+	case models.DNSRecordCNAME:
+		cname, err := c.resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+	case models.DNSRecordMX:
+		records, err := c.resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, len(records))
+		for i, record := range records {
+			values[i] = record.Host
+		}
+		return values, nil
+	case models.DNSRecordNS:
+		records, err := c.resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, len(records))
+		for i, record := range records {
+			values[i] = record.Host
+		}
+		return values, nil
+	case models.DNSRecordTXT:
+		return c.resolver.LookupTXT(ctx, host)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+}
+
+// normalizeDNSValue makes "Mail.Example.com." and "mail.example.com" compare equal
+func normalizeDNSValue(value string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(value), "."))
+}
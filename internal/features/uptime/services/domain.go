@@ -0,0 +1,203 @@
+package monitor
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// domainTemplate is the mailer template used for domain expiry warnings
+const domainTemplate = "domain_expiry_warning.tmpl"
+
+// domainExpiryLayout formats the expiry date warnings are keyed by
+const domainExpiryLayout = "2006-01-02"
+
+// DomainConfig controls domain registration expiry tracking
+type DomainConfig struct {
+	// Recipient receives expiry warnings. Domains are still looked up
+	// without one.
+	Recipient string
+
+	// Thresholds are the days before expiry warnings are sent at
+	Thresholds []int
+
+	// RDAPURL is the RDAP service domains are looked up through
+	RDAPURL string
+
+	// Interval is how often every domain is looked up
+	Interval time.Duration
+
+	// Timeout bounds each lookup
+	Timeout time.Duration
+}
+
+// DefaultDomainConfig returns the default domain tracking configuration
+func DefaultDomainConfig() DomainConfig {
+	return DomainConfig{
+		Thresholds: models.DefaultDomainExpiryThresholds,
+		RDAPURL:    DefaultRDAPURL,
+		Interval:   24 * time.Hour,
+		Timeout:    15 * time.Second,
+	}
+}
+
+// DomainDatabase is the storage the domain tracker works on
+type DomainDatabase interface {
+	GetWebsites() ([]models.Website, error)
+	SaveDomain(domain models.Domain) (*models.Domain, error)
+	DeleteDomainsExcept(names []string) (int64, error)
+	HasDomainAlert(domainID int, expiresOn string, threshold int) (bool, error)
+	RecordDomainAlert(domainID int, expiresOn string, threshold int, at time.Time) error
+}
+
+// DomainExpiryWarning is the data rendered into domain expiry warning
+// emails
+type DomainExpiryWarning struct {
+	Domain    string
+	Registrar string
+	ExpiresAt string
+	DaysLeft  int
+	Expired   bool
+	Websites  []string
+	Timestamp string
+}
+
+// DomainTracker periodically looks up the registration of every distinct
+// registrable domain the websites are on, and warns as registrations
+// approach expiry
+type DomainTracker struct {
+	logger *slog.Logger
+	mailer Mailer
+	rdap   *RDAPClient
+	config DomainConfig
+	job    periodic
+}
+
+// NewDomainTracker creates a domain tracker, filling unset config with
+// defaults
+func NewDomainTracker(logger *slog.Logger, mailer Mailer, config DomainConfig) *DomainTracker {
+	defaults := DefaultDomainConfig()
+	if len(config.Thresholds) == 0 {
+		config.Thresholds = defaults.Thresholds
+	}
+	if config.RDAPURL == "" {
+		config.RDAPURL = defaults.RDAPURL
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	return &DomainTracker{
+		logger: logger,
+		mailer: mailer,
+		rdap:   NewRDAPClient(config.RDAPURL, config.Timeout),
+		config: config,
+	}
+}
+
+// Start looks up every domain straight away and then every interval until
+// stopped
+func (d *DomainTracker) Start(ctx context.Context, db DomainDatabase) {
+	d.job.runEvery(ctx, d.config.Interval, func(ctx context.Context) { d.Run(ctx, db, time.Now()) })
+}
+
+// Stop stops the domain tracker, cancelling running lookups
+func (d *DomainTracker) Stop(ctx context.Context) error {
+	return d.job.stop(ctx)
+}
+
+// Run looks up the registration of each domain the websites are on, forgets
+// domains no website is on any more and sends the expiry warnings due
+func (d *DomainTracker) Run(ctx context.Context, db DomainDatabase, now time.Time) {
+	websites, err := db.GetWebsites()
+	if err != nil {
+		d.logger.Error("Failed to get websites for domain lookups", "error", err)
+		return
+	}
+
+	// Websites on the same domain share a lookup
+	domains := make(map[string][]string)
+	for _, website := range websites {
+		if name, ok := website.Domain(); ok {
+			domains[name] = append(domains[name], website.Name)
+		}
+	}
+	names := make([]string, 0, len(domains))
+	for name := range domains {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	if deleted, err := db.DeleteDomainsExcept(names); err != nil {
+		d.logger.Error("Failed to delete unused domains", "error", err)
+	} else if deleted > 0 {
+		d.logger.Info("Deleted unused domains", "domains", deleted)
+	}
+
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return
+		}
+
+		domain, err := d.rdap.Lookup(ctx, name)
+		if err != nil {
+			// A lookup cut short by shutdown says nothing about the domain
+			if ctx.Err() != nil {
+				return
+			}
+			d.logger.Warn("Domain lookup failed", "domain", name, "error", err)
+			domain = &models.Domain{Name: name, Error: err.Error()}
+		}
+		domain.CheckedAt = &now
+
+		saved, err := db.SaveDomain(*domain)
+		if err != nil {
+			d.logger.Error("Failed to save domain", "domain", name, "error", err)
+			continue
+		}
+		d.warnExpiry(db, *saved, domains[name], now)
+	}
+}
+
+// warnExpiry emails a warning when a domain's registration crosses an expiry
+// threshold. Each threshold is sent once per expiry date, so a renewed
+// domain starts over, and only the most urgent threshold crossed is sent.
+func (d *DomainTracker) warnExpiry(db DomainDatabase, domain models.Domain, websites []string, now time.Time) {
+	threshold, due := domain.DueThreshold(d.config.Thresholds, now)
+	if !due || d.config.Recipient == "" {
+		return
+	}
+
+	expiresOn := domain.ExpiresAt.UTC().Format(domainExpiryLayout)
+	sent, err := db.HasDomainAlert(domain.ID, expiresOn, threshold)
+	if err != nil {
+		d.logger.Error("Failed to check domain expiry alerts", "domain", domain.Name, "error", err)
+		return
+	}
+	if sent {
+		return
+	}
+
+	warning := DomainExpiryWarning{
+		Domain:    domain.Name,
+		Registrar: domain.Registrar,
+		ExpiresAt: domain.ExpiresAt.UTC().Format("Jan 2, 2006 15:04 MST"),
+		DaysLeft:  domain.DaysLeft(now),
+		Expired:   domain.IsExpired(now),
+		Websites:  websites,
+		Timestamp: now.Format(time.RFC1123),
+	}
+	if err := d.mailer.Send(d.config.Recipient, domainTemplate, warning); err != nil {
+		d.logger.Error("Failed to send domain expiry warning", "domain", domain.Name, "error", err)
+		return
+	}
+
+	if err := db.RecordDomainAlert(domain.ID, expiresOn, threshold, now); err != nil {
+		d.logger.Error("Failed to record domain expiry alert", "domain", domain.Name, "error", err)
+	}
+	d.logger.Info("Sent domain expiry warning", "domain", domain.Name, "days_left", warning.DaysLeft)
+}
@@ -0,0 +1,210 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/internal/server/services/mailer"
+	"time"
+)
+
+// fakeDomainDatabase keeps domains and expiry warnings in memory
+type fakeDomainDatabase struct {
+	websites []models.Website
+	domains  map[string]models.Domain
+	alerts   map[string]bool
+}
+
+func (d *fakeDomainDatabase) GetWebsites() ([]models.Website, error) {
+	return d.websites, nil
+}
+
+func (d *fakeDomainDatabase) SaveDomain(domain models.Domain) (*models.Domain, error) {
+	if d.domains == nil {
+		d.domains = make(map[string]models.Domain)
+	}
+	saved, ok := d.domains[domain.Name]
+	if !ok {
+		saved = models.Domain{ID: len(d.domains) + 1, Name: domain.Name}
+	}
+	saved.CheckedAt, saved.Error = domain.CheckedAt, domain.Error
+	if domain.Error == "" {
+		saved.Registrar, saved.ExpiresAt = domain.Registrar, domain.ExpiresAt
+	}
+	d.domains[domain.Name] = saved
+	return &saved, nil
+}
+
+func (d *fakeDomainDatabase) DeleteDomainsExcept(names []string) (int64, error) {
+	var deleted int64
+	for name := range d.domains {
+		if !slices.Contains(names, name) {
+			delete(d.domains, name)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (d *fakeDomainDatabase) HasDomainAlert(domainID int, expiresOn string, threshold int) (bool, error) {
+	return d.alerts[fmt.Sprint(domainID, expiresOn, threshold)], nil
+}
+
+func (d *fakeDomainDatabase) RecordDomainAlert(domainID int, expiresOn string, threshold int, at time.Time) error {
+	if d.alerts == nil {
+		d.alerts = make(map[string]bool)
+	}
+	d.alerts[fmt.Sprint(domainID, expiresOn, threshold)] = true
+	return nil
+}
+
+// newRDAPStandIn serves RDAP domain responses for the domains expiring at
+// the given times, and 404s for any other domain
+func newRDAPStandIn(t *testing.T, expiries map[string]time.Time) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var lookups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/domain/")
+		mu.Lock()
+		lookups = append(lookups, name)
+		mu.Unlock()
+
+		expiresAt, ok := expiries[name]
+		if !ok {
+			http.Error(w, `{"errorCode":404,"title":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprintf(w, `{
+			"objectClassName": "domain",
+			"ldhName": %q,
+			"events": [
+				{"eventAction": "registration", "eventDate": "2001-05-01T00:00:00Z"},
+				{"eventAction": "expiration", "eventDate": %q}
+			],
+			"entities": [
+				{"objectClassName": "entity", "handle": "9999", "roles": ["registrant"]},
+				{"objectClassName": "entity", "handle": "292", "roles": ["registrar"],
+				 "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]}
+			]
+		}`, strings.ToUpper(name), expiresAt.Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), lookups...)
+	}
+}
+
+func TestRDAPLookup(t *testing.T) {
+	expiresAt := time.Date(2027, time.March, 14, 9, 30, 0, 0, time.UTC)
+	rdap, _ := newRDAPStandIn(t, map[string]time.Time{"example.com": expiresAt})
+	client := NewRDAPClient(rdap.URL+"/", 5*time.Second)
+
+	domain, err := client.Lookup(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Failed to look up domain: %v", err)
+	}
+	if domain.Registrar != "Example Registrar, Inc." || domain.ExpiresAt == nil || !domain.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Unexpected registration %+v", domain)
+	}
+
+	if _, err := client.Lookup(context.Background(), "example.org"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected an unknown domain to fail, got %v", err)
+	}
+}
+
+func TestDomainTrackerWarnsBeforeExpiry(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	rdap, lookups := newRDAPStandIn(t, map[string]time.Time{"example.com": now.Add(10 * 24 * time.Hour)})
+	mail, emails := newStandIn(t, http.StatusOK, `{"data":{}}`)
+
+	tracker := NewDomainTracker(
+		slog.New(slog.DiscardHandler),
+		mailer.New("key", "alerts@example.com").WithEndpoint(mail.URL),
+		DomainConfig{Recipient: "ops@example.com", RDAPURL: rdap.URL},
+	)
+
+	db := &fakeDomainDatabase{
+		websites: []models.Website{
+			{Name: "Shop", URL: "https://www.example.com"},
+			{Name: "API", URL: "https://api.example.com/health"},
+			{Name: "Mail", URL: "mail.example.org:25", CheckType: models.CheckTypeTCP},
+			{Name: "Router", URL: "http://192.168.1.1"},
+		},
+		domains: map[string]models.Domain{"old-example.com": {ID: 9, Name: "old-example.com"}},
+	}
+
+	tracker.Run(context.Background(), db, now)
+	tracker.Run(context.Background(), db, now.Add(time.Hour))
+
+	if got := lookups(); !slices.Equal(got, []string{"example.com", "example.org", "example.com", "example.org"}) {
+		t.Errorf("Expected each distinct domain looked up once per run, got %v", got)
+	}
+	if _, ok := db.domains["old-example.com"]; ok {
+		t.Error("Expected the domain no website is on to be deleted")
+	}
+	if domain := db.domains["example.org"]; domain.Error == "" || domain.ExpiresAt != nil {
+		t.Errorf("Expected the failed lookup to be recorded, got %+v", domain)
+	}
+	if domain := db.domains["example.com"]; domain.Registrar != "Example Registrar, Inc." || domain.DaysLeft(now) != 10 {
+		t.Errorf("Unexpected domain %+v", domain)
+	}
+
+	sent := emails()
+	if len(sent) != 1 {
+		t.Fatalf("Expected a single warning, got %d emails", len(sent))
+	}
+	var payload mailer.SMTP2GORequest
+	if err := json.Unmarshal([]byte(sent[0].Body), &payload); err != nil {
+		t.Fatalf("Failed to decode email: %v", err)
+	}
+	if len(payload.To) != 1 || payload.To[0] != "ops@example.com" || !strings.Contains(payload.Subject, "[EXPIRING] example.com") {
+		t.Errorf("Unexpected email %+v", payload)
+	}
+	for _, want := range []string{"in 10 day(s)", "Example Registrar, Inc.", "Shop, API"} {
+		if !strings.Contains(payload.TextBody, want) {
+			t.Errorf("Expected the warning to contain %q, got:\n%s", want, payload.TextBody)
+		}
+	}
+
+	// Crossing the next threshold sends another warning
+	tracker.Run(context.Background(), db, now.Add(5*24*time.Hour))
+	if len(emails()) != 2 {
+		t.Errorf("Expected a warning at the 7 day threshold, got %d emails", len(emails()))
+	}
+}
+
+func TestDomainTrackerCancelledLookup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rdap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Shut down while the lookup is in flight
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer rdap.Close()
+
+	tracker := NewDomainTracker(slog.New(slog.DiscardHandler), mailer.Mailer{}, DomainConfig{RDAPURL: rdap.URL})
+	db := &fakeDomainDatabase{
+		websites: []models.Website{{Name: "Shop", URL: "https://www.example.com"}},
+		domains:  map[string]models.Domain{"example.com": {ID: 1, Name: "example.com"}},
+	}
+
+	tracker.Run(ctx, db, time.Now())
+
+	if domain := db.domains["example.com"]; domain.Error != "" || domain.CheckedAt != nil {
+		t.Errorf("Expected the cancelled lookup not to be saved, got %+v", domain)
+	}
+}
@@ -0,0 +1,26 @@
+package monitor
+
+import (
+	"context"
+	"the-ark/internal/features/uptime/models"
+)
+
+// alertTemplate is the mailer template used for website alerts
+const alertTemplate = "website_status_alert.tmpl"
+
+// emailNotifier sends the website status alert email to the channel's
+// recipient
+type emailNotifier struct {
+	mailer Mailer
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, channel models.NotificationChannel, notification Notification) error {
+	alertData := map[string]interface{}{
+		"WebsiteName": notification.Website.Name,
+		"WebsiteURL":  notification.Website.URL,
+		"AlertType":   notification.Type,
+		"Reason":      notification.Reason,
+		"Timestamp":   notification.Timestamp.Format("2006-01-02 15:04:05"),
+	}
+	return n.mailer.Send(channel.Recipient, alertTemplate, alertData)
+}
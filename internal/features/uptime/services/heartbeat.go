@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"fmt"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// heartbeatEvaluationInterval caps how long a missed heartbeat can go
+// unnoticed, whatever its expected period
+const heartbeatEvaluationInterval = time.Minute
+
+// RecordHeartbeat stores a ping from a heartbeat website and alerts on a
+// change of status. A failed ping is recorded as a down check carrying the
+// message the job reported.
+func (m *Monitor) RecordHeartbeat(website models.Website, isUp bool, message string, db Database) {
+	if err := db.RecordHeartbeat(website.ID, time.Now()); err != nil {
+		m.logger.Error("Failed to record heartbeat", "website_id", website.ID, "error", err)
+		return
+	}
+
+	if !isUp && message == "" {
+		message = "job reported a failure"
+	}
+
+	m.record(website, Result{IsUp: isUp, Error: message}, db)
+}
+
+// checkHeartbeat reports a heartbeat website as down once no ping has
+// arrived within its period plus grace period. It returns false when there
+// is nothing to record, either because the heartbeat isn't overdue or the
+// missed period has already been recorded.
+func (m *Monitor) checkHeartbeat(website models.Website, lastStatus *models.WebsiteStatus, db Database, now time.Time) (Result, bool) {
+	lastPing, err := db.GetLastHeartbeat(website.ID)
+	if err != nil {
+		m.logger.Error("Failed to get last heartbeat", "website_id", website.ID, "error", err)
+		return Result{}, false
+	}
+
+	// A website that has never pinged gets one period from creation, and a
+	// resumed one a period from the resume
+	since := website.CreatedAt
+	if lastPing != nil {
+		since = *lastPing
+	}
+	resumed := website.ResumedAt != nil && website.ResumedAt.After(since)
+	if resumed {
+		since = *website.ResumedAt
+	}
+
+	period := m.schedule.intervalFor(website)
+	deadline := since.Add(period + time.Duration(website.GracePeriod)*time.Second)
+	if now.Before(deadline) {
+		return Result{}, false
+	}
+
+	// Record at most one missed heartbeat per period
+	if lastStatus != nil && !models.IsUpStatus(lastStatus.Status) && now.Sub(lastStatus.CheckedAt) < period {
+		return Result{}, false
+	}
+
+	if resumed {
+		return Result{Error: fmt.Sprintf("no ping received since monitoring resumed at %s", since.Format("2006-01-02 15:04:05"))}, true
+	}
+	if lastPing == nil {
+		return Result{Error: fmt.Sprintf("no ping received since the monitor was created at %s", since.Format("2006-01-02 15:04:05"))}, true
+	}
+	return Result{Error: fmt.Sprintf("no ping received since %s", since.Format("2006-01-02 15:04:05"))}, true
+}
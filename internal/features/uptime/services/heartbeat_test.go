@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestHeartbeatMissedAndRecovered(t *testing.T) {
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+
+	created := time.Now().Add(-2 * time.Hour)
+	website := models.Website{
+		ID:             1,
+		CheckType:      models.CheckTypeHeartbeat,
+		CheckInterval:  3600,
+		GracePeriod:    600,
+		HeartbeatToken: "token",
+		CreatedAt:      created,
+	}
+
+	// A ping within the period keeps the website up without storing anything
+	db.RecordHeartbeat(website.ID, time.Now().Add(-30*time.Minute))
+	if _, missed := m.checkHeartbeat(website, nil, db, time.Now()); missed {
+		t.Fatalf("Expected recent heartbeat not to be missed")
+	}
+
+	// Late, but still within the grace period
+	db.RecordHeartbeat(website.ID, time.Now().Add(-65*time.Minute))
+	if _, missed := m.checkHeartbeat(website, nil, db, time.Now()); missed {
+		t.Fatalf("Expected heartbeat within the grace period not to be missed")
+	}
+
+	// Past the period and grace period
+	db.RecordHeartbeat(website.ID, time.Now().Add(-75*time.Minute))
+	m.CheckWebsite(t.Context(), website, db)
+	checks := db.checksFor(website.ID)
+	if len(checks) != 1 || checks[0].Status != "down" || !strings.Contains(checks[0].Error, "no ping received") {
+		t.Fatalf("Expected a missed heartbeat to be recorded as down, got %+v", checks)
+	}
+
+	// Evaluating again within the same period doesn't record it twice
+	m.CheckWebsite(t.Context(), website, db)
+	if checks := db.checksFor(website.ID); len(checks) != 1 {
+		t.Fatalf("Expected one down check per missed period, got %d", len(checks))
+	}
+
+	// A ping brings the website back up
+	m.RecordHeartbeat(website, true, "", db)
+	checks = db.checksFor(website.ID)
+	if len(checks) != 2 || checks[1].Status != "up" {
+		t.Fatalf("Expected ping to record an up check, got %+v", checks)
+	}
+	if _, missed := m.checkHeartbeat(website, &checks[1], db, time.Now()); missed {
+		t.Errorf("Expected heartbeat to be on time after a ping")
+	}
+}
+
+func TestHeartbeatFailurePing(t *testing.T) {
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+	website := models.Website{ID: 1, CheckType: models.CheckTypeHeartbeat, HeartbeatToken: "token", CreatedAt: time.Now()}
+
+	m.RecordHeartbeat(website, false, "", db)
+
+	checks := db.checksFor(website.ID)
+	if len(checks) != 1 || checks[0].Status != "down" || checks[0].Error != "job reported a failure" {
+		t.Fatalf("Expected failure ping to record a down check, got %+v", checks)
+	}
+	if _, ok := db.heartbeats[website.ID]; !ok {
+		t.Errorf("Expected failure ping to count as a heartbeat")
+	}
+}
+
+func TestHeartbeatNeverPinged(t *testing.T) {
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+	website := models.Website{
+		ID:            1,
+		CheckType:     models.CheckTypeHeartbeat,
+		CheckInterval: 60,
+		CreatedAt:     time.Now().Add(-5 * time.Minute),
+	}
+
+	result, missed := m.checkHeartbeat(website, nil, db, time.Now())
+	if !missed || result.IsUp || !strings.Contains(result.Error, "since the monitor was created") {
+		t.Errorf("Expected a website that never pinged to be down, got %+v", result)
+	}
+}
+
+func TestHeartbeatEvaluatedEveryMinute(t *testing.T) {
+	s := newSchedule(5*time.Minute, 0.1)
+	daily := models.Website{CheckType: models.CheckTypeHeartbeat, CheckInterval: 86400}
+	if got := s.runInterval(daily); got != heartbeatEvaluationInterval {
+		t.Errorf("Expected daily heartbeat to be evaluated every %v, got %v", heartbeatEvaluationInterval, got)
+	}
+	if got := s.intervalFor(daily); got != 24*time.Hour {
+		t.Errorf("Expected heartbeat period of 24h, got %v", got)
+	}
+}
+
+func TestHeartbeatResumed(t *testing.T) {
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+
+	resumed := time.Now().Add(-10 * time.Minute)
+	website := models.Website{
+		ID:             1,
+		CheckType:      models.CheckTypeHeartbeat,
+		CheckInterval:  3600,
+		HeartbeatToken: "token",
+		CreatedAt:      time.Now().Add(-48 * time.Hour),
+		ResumedAt:      &resumed,
+	}
+
+	// The last ping is from before the pause, but the resume starts a new
+	// period
+	db.RecordHeartbeat(website.ID, time.Now().Add(-24*time.Hour))
+	if _, missed := m.checkHeartbeat(website, nil, db, time.Now()); missed {
+		t.Fatalf("Expected a resumed heartbeat not to be missed straight away")
+	}
+
+	result, missed := m.checkHeartbeat(website, nil, db, resumed.Add(61*time.Minute))
+	if !missed || !strings.Contains(result.Error, "since monitoring resumed") {
+		t.Errorf("Expected a heartbeat missed a period after the resume, got %+v, %v", result, missed)
+	}
+}
@@ -0,0 +1,218 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// httpChecker requests a website and evaluates its assertions
+type httpChecker struct {
+	client *http.Client
+
+	// noRedirectClient returns redirect responses as-is for websites that
+	// must not follow them
+	noRedirectClient *http.Client
+}
+
+func newHTTPChecker(timeout time.Duration) *httpChecker {
+	// Every check opens a new connection, so DNS, connect and TLS are timed
+	// each time and certificate or DNS changes are seen straight away
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
+
+	return &httpChecker{
+		client: &http.Client{Transport: transport, Timeout: timeout},
+		noRedirectClient: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (c *httpChecker) Check(ctx context.Context, website models.Website) Result {
+	req, err := buildRequest(ctx, website)
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+
+	client := c.client
+	if !website.Request.FollowRedirects {
+		client = c.noRedirectClient
+	}
+
+	trace := &timingTrace{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+
+	result := Result{ResponseTime: elapsed.Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+
+	// The body is always read so the transfer phase can be timed, but only
+	// kept for body assertions and content monitoring
+	var body []byte
+	if website.Assertions.HasBodyAssertions() || website.Content.Enabled {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to read response body: %v", err)
+			return result
+		}
+	} else {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
+	}
+	result.Timing = trace.finish(time.Now())
+
+	result.Error = evaluateAssertions(website.Assertions, resp, body, elapsed)
+	result.IsUp = result.Error == ""
+
+	// Only the content of working pages is compared, not error pages
+	if website.Content.Enabled && result.IsUp {
+		content, err := normalizeContent(body, resp.Header.Get("Content-Type"), website.Content)
+		if err != nil {
+			result.ContentError = err.Error()
+		} else {
+			result.Content = &content
+		}
+	}
+	return result
+}
+
+// timingTrace times the phases of a request through httptrace hooks. Hooks
+// may run on other goroutines, e.g. when dialing several addresses at once.
+type timingTrace struct {
+	mu sync.Mutex
+
+	dnsStart     time.Time
+	connectStart map[string]time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+
+	dns, connect, tls, ttfb time.Duration
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dns += time.Since(t.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.connectStart == nil {
+				t.connectStart = make(map[string]time.Time)
+			}
+			t.connectStart[network+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Only the dial that won counts
+			if err == nil {
+				t.connect += time.Since(t.connectStart[network+addr])
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tls += time.Since(t.tlsStart)
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.wroteRequest = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.firstByte = time.Now()
+			t.ttfb += t.firstByte.Sub(t.wroteRequest)
+		},
+	}
+}
+
+// finish returns the timing of a request whose body was read by end
+func (t *timingTrace) finish(end time.Time) *models.CheckTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timing := &models.CheckTiming{
+		DNS:     t.dns.Milliseconds(),
+		Connect: t.connect.Milliseconds(),
+		TLS:     t.tls.Milliseconds(),
+		TTFB:    t.ttfb.Milliseconds(),
+	}
+	if !t.firstByte.IsZero() {
+		timing.Transfer = end.Sub(t.firstByte).Milliseconds()
+	}
+	return timing
+}
+
+// buildRequest builds the HTTP request described by a website's request options
+func buildRequest(ctx context.Context, website models.Website) (*http.Request, error) {
+	options := website.Request
+
+	var body io.Reader
+	if options.Body != "" {
+		body = strings.NewReader(options.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, options.RequestMethod(), website.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	for name, value := range options.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if options.Body != "" && req.Header.Get("Content-Type") == "" && json.Valid([]byte(options.Body)) {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	userAgent := options.UserAgent
+	if userAgent == "" {
+		userAgent = models.DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	switch options.AuthType {
+	case models.AuthBasic:
+		req.SetBasicAuth(options.AuthUsername, options.AuthSecret)
+	case models.AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+options.AuthSecret)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,131 @@
+package monitor
+
+import (
+	"fmt"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// latencyBreach checks a passing result against the website's latency
+// limits, returning why it breaches them or an empty string if it doesn't
+func (m *Monitor) latencyBreach(website models.Website, result Result, db Database) string {
+	policy := website.Alerts
+	if !policy.HasLatencyLimits() || website.Type() == models.CheckTypeHeartbeat {
+		return ""
+	}
+
+	var baseline int64
+	if policy.LatencyDeviation > 0 {
+		median, samples, err := db.GetLatencyBaseline(website.ID, time.Now().Add(-models.LatencyBaselineWindow))
+		if err != nil {
+			m.logger.Error("Failed to get latency baseline", "website_id", website.ID, "error", err)
+		} else if samples >= models.MinBaselineSamples {
+			baseline = median
+		}
+	}
+	return policy.LatencyBreach(result.ResponseTime, baseline)
+}
+
+// trackLatency marks a website degraded once enough consecutive checks
+// breach its latency limits, and back to normal once enough are within them
+// again, alerting on both changes. The failure and recovery thresholds
+// confirm the changes like they do for going down and recovering. Going
+// down ends the degraded state without an alert, as the down alert
+// supersedes it.
+func (m *Monitor) trackLatency(website models.Website, status, breach string, db Database) {
+	var streak int
+	if status == models.StatusDown {
+		m.resetLatencyStreak(website.ID)
+	} else {
+		streak = m.latencyStreak(website.ID, breach != "")
+	}
+
+	degradedSince, err := db.GetDegradedSince(website.ID)
+	if err != nil {
+		m.logger.Error("Failed to get degraded state", "website_id", website.ID, "error", err)
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case status == models.StatusDown:
+		if degradedSince != nil {
+			m.setDegradedSince(website, nil, db)
+		}
+
+	case breach != "" && degradedSince == nil:
+		if streak < website.Confirmation.Threshold(true) {
+			return
+		}
+		if m.setDegradedSince(website, &now, db) {
+			m.sendLatencyAlert(website, models.AlertDegraded, breach, db)
+		}
+
+	case breach == "" && degradedSince != nil:
+		if streak < website.Confirmation.Threshold(false) {
+			return
+		}
+		if m.setDegradedSince(website, nil, db) {
+			reason := fmt.Sprintf("degraded for %s", now.Sub(*degradedSince).Round(time.Second))
+			m.sendLatencyAlert(website, models.AlertDegradedRecovery, reason, db)
+		}
+	}
+}
+
+// latencyStreak adds a check to the website's run of consecutive checks
+// that breached its latency limits, or that were within them, and returns
+// the length of the run. Stored statuses can't tell the runs apart, as
+// retried passes and outvoted failures are degraded too, so the runs are
+// kept in memory and start over when the monitor restarts.
+func (m *Monitor) latencyStreak(websiteID int, breached bool) int {
+	m.latencyMu.Lock()
+	defer m.latencyMu.Unlock()
+
+	// Breaching runs are positive and runs within the limits negative
+	streak := m.latencyStreaks[websiteID]
+	switch {
+	case breached && streak > 0:
+		streak++
+	case breached:
+		streak = 1
+	case streak < 0:
+		streak--
+	default:
+		streak = -1
+	}
+	m.latencyStreaks[websiteID] = streak
+	return max(streak, -streak)
+}
+
+// resetLatencyStreak forgets a website's run of checks, for when it goes
+// down
+func (m *Monitor) resetLatencyStreak(websiteID int) {
+	m.latencyMu.Lock()
+	defer m.latencyMu.Unlock()
+
+	delete(m.latencyStreaks, websiteID)
+}
+
+// setDegradedSince stores a website's degraded state, reporting whether it
+// was stored
+func (m *Monitor) setDegradedSince(website models.Website, since *time.Time, db Database) bool {
+	if err := db.SetDegradedSince(website.ID, since); err != nil {
+		m.logger.Error("Failed to set degraded state", "website_id", website.ID, "error", err)
+		return false
+	}
+	return true
+}
+
+// sendLatencyAlert notifies the website's channels that it became degraded
+// or recovered from it. Latency alerts aren't tied to an incident.
+func (m *Monitor) sendLatencyAlert(website models.Website, alertType, reason string, db Database) {
+	notification := Notification{
+		Website:   website,
+		Type:      alertType,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+	if m.notify(notification, 0, db) {
+		m.logger.Info("Sent alert", "website_id", website.ID, "url", website.URL, "type", alertType)
+	}
+}
@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"net/http"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+)
+
+func TestLatencyThresholdDegradesWebsite(t *testing.T) {
+	webhook, requests := newStandIn(t, http.StatusOK, "")
+
+	website := models.Website{
+		ID:           1,
+		Name:         "Example",
+		URL:          "https://example.com",
+		Confirmation: models.Confirmation{FailureThreshold: 2, RecoveryThreshold: 2},
+		Alerts:       models.AlertPolicy{LatencyThreshold: 1000},
+	}
+	db := &fakeDatabase{
+		channels: map[int][]models.NotificationChannel{
+			1: {{ID: 3, Name: "Webhook", Type: models.ChannelWebhook, URL: webhook.URL}},
+		},
+	}
+	m := newTestMonitor(MonitorConfig{})
+
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200}, db)
+
+	// One slow check is degraded, but doesn't confirm the change yet
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 8000}, db)
+	checks := db.checksFor(1)
+	if last := checks[len(checks)-1]; last.Status != models.StatusDegraded || last.Error == "" {
+		t.Fatalf("Expected a degraded check explaining the breach, got %+v", last)
+	}
+	if len(requests()) != 0 {
+		t.Fatalf("Expected no alert before the failure threshold")
+	}
+
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 8000}, db)
+	sent := db.alertsWith(models.AlertSent)
+	if len(sent) != 1 || sent[0].Type != models.AlertDegraded || sent[0].IncidentID != 0 {
+		t.Fatalf("Expected a degraded alert, got %+v", sent)
+	}
+	if since, _ := db.GetDegradedSince(1); since == nil {
+		t.Errorf("Expected the website to be marked degraded")
+	}
+	if len(db.incidents) != 0 {
+		t.Errorf("Expected no incident for a slow website, got %+v", db.incidents)
+	}
+
+	// Staying slow doesn't alert again
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 8000}, db)
+	if len(requests()) != 1 {
+		t.Errorf("Expected a single degraded alert, got %d requests", len(requests()))
+	}
+
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200}, db)
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200}, db)
+	sent = db.alertsWith(models.AlertSent)
+	if len(sent) != 2 || sent[1].Type != models.AlertDegradedRecovery {
+		t.Fatalf("Expected a recovery notice, got %+v", sent)
+	}
+	if since, _ := db.GetDegradedSince(1); since != nil {
+		t.Errorf("Expected the degraded state to be cleared")
+	}
+}
+
+func TestLatencyDeviationFromBaseline(t *testing.T) {
+	website := models.Website{ID: 1, Alerts: models.AlertPolicy{LatencyDeviation: 100}}
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+
+	// Without enough history there is no baseline to deviate from
+	if breach := m.latencyBreach(website, Result{IsUp: true, ResponseTime: 5000}, db); breach != "" {
+		t.Errorf("Expected no breach without a baseline, got %q", breach)
+	}
+
+	for range models.MinBaselineSamples {
+		m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200}, db)
+	}
+	if breach := m.latencyBreach(website, Result{IsUp: true, ResponseTime: 350}, db); breach != "" {
+		t.Errorf("Expected 350 ms to be within 100%% of a 200 ms baseline, got %q", breach)
+	}
+
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 500}, db)
+	checks := db.checksFor(1)
+	if last := checks[len(checks)-1]; last.Status != models.StatusDegraded {
+		t.Errorf("Expected 500 ms to be degraded against a 200 ms baseline, got %+v", last)
+	}
+	if since, _ := db.GetDegradedSince(1); since == nil {
+		t.Errorf("Expected a single slow check to degrade the website by default")
+	}
+}
+
+func TestGoingDownClearsDegradedState(t *testing.T) {
+	website := models.Website{ID: 1, Alerts: models.AlertPolicy{LatencyThreshold: 1000}}
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200}, db)
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 5000}, db)
+	if since, _ := db.GetDegradedSince(1); since == nil {
+		t.Fatalf("Expected the website to be degraded")
+	}
+
+	m.record(website, Result{StatusCode: 503, Error: "unexpected status code 503"}, db)
+	if since, _ := db.GetDegradedSince(1); since != nil {
+		t.Errorf("Expected going down to clear the degraded state")
+	}
+}
+
+func TestOnlyLatencyBreachesDegradeWebsite(t *testing.T) {
+	website := models.Website{
+		ID:           1,
+		Confirmation: models.Confirmation{FailureThreshold: 2, RecoveryThreshold: 2},
+		Alerts:       models.AlertPolicy{LatencyThreshold: 1000},
+	}
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+
+	// A pass after a retry and an outvoted failure are stored degraded, but
+	// aren't slow
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200, Attempts: 2}, db)
+	m.record(website, Result{IsUp: true, Outvoted: true, Error: "connection refused"}, db)
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 5000}, db)
+	if since, _ := db.GetDegradedSince(1); since != nil {
+		t.Fatalf("Expected a single latency breach not to confirm the degraded state")
+	}
+
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 5000}, db)
+	if since, _ := db.GetDegradedSince(1); since == nil {
+		t.Fatalf("Expected two latency breaches in a row to degrade the website")
+	}
+
+	// Passes after a retry are within the limits and count towards recovery
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200, Attempts: 2}, db)
+	m.record(website, Result{IsUp: true, StatusCode: 200, ResponseTime: 200}, db)
+	if since, _ := db.GetDegradedSince(1); since != nil {
+		t.Errorf("Expected two checks within the limits to clear the degraded state")
+	}
+}
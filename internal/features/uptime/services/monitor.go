@@ -2,9 +2,12 @@ package monitor
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"sync"
 	"the-ark/internal/features/uptime/models"
-	"the-ark/internal/server/services/mailer"
 	"time"
 
 	"log/slog"
@@ -16,196 +19,566 @@ type WebsiteEntry struct {
 	name string
 }
 
+// maxQueuedBatches bounds the batches of manual checks waiting for the
+// monitoring loop
+const maxQueuedBatches = 16
+
 type Monitor struct {
-	logger *slog.Logger
-	mailer mailer.Mailer
-	config MonitorConfig
+	logger    *slog.Logger
+	config    MonitorConfig
+	checkers  map[string]Checker
+	notifiers map[string]Notifier
+	events    Publisher
+	schedule  *schedule
+	reload    chan struct{}
+	jobs      chan models.Website
+	queued    chan []models.Website
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+
+	// inFlight tracks websites currently being checked so a slow check is
+	// never queued twice
+	inFlight   map[int]bool
+	inFlightMu sync.Mutex
+
+	// latencyStreaks tracks each website's run of checks breaching or
+	// within its latency limits
+	latencyStreaks map[int]int
+	latencyMu      sync.Mutex
 }
 
 type MonitorConfig struct {
 	AlertRecipient string
+
+	// DefaultInterval is used for websites without a check interval
+	DefaultInterval time.Duration
+
+	// ResyncInterval controls how often the schedule is reconciled with the
+	// database, in case websites change without a Reload call
+	ResyncInterval time.Duration
+
+	// JitterFraction spreads checks across a window of this fraction of
+	// each website's interval so they don't all fire together
+	JitterFraction float64
+
+	// MaxWorkers bounds the number of checks running at the same time
+	MaxWorkers int
+
+	// CheckTimeout bounds how long a single check may take
+	CheckTimeout time.Duration
+
+	// Quorum is how many locations, counting this one, must see a website
+	// fail before it is down. It is capped at the number of locations with
+	// recent results, so without probe agents one failure is enough.
+	Quorum int
+}
+
+// DefaultMonitorConfig returns the default scheduling configuration
+func DefaultMonitorConfig() MonitorConfig {
+	return MonitorConfig{
+		DefaultInterval: 5 * time.Minute,
+		ResyncInterval:  time.Minute,
+		JitterFraction:  0.1,
+		MaxWorkers:      5,
+		CheckTimeout:    10 * time.Second,
+		Quorum:          2,
+	}
+}
+
+// Publisher publishes live events for dashboards, e.g. core.EventBus
+type Publisher interface {
+	Publish(topic string, data any)
 }
 
 // Database interface for monitoring operations
 type Database interface {
 	GetActiveWebsites() ([]models.Website, error)
 	GetLastWebsiteStatus(websiteID int) (*models.WebsiteStatus, error)
-	StoreUptimeCheck(websiteID int, statusCode int, responseTime int64, isUp bool, errorMsg string) error
-	ShouldSendAlert(websiteID int, alertType string) (bool, error)
-	RecordAlertSent(websiteID int, alertType string) error
+	StoreUptimeCheck(websiteID int, statusCode int, responseTime int64, status string, errorMsg string, maintenance bool, timing *models.CheckTiming) error
+	GetRecentStatuses(websiteID int, limit int) ([]string, error)
+	RecordHeartbeat(websiteID int, at time.Time) error
+	GetLastHeartbeat(websiteID int) (*time.Time, error)
+	OpenIncident(websiteID int, cause string) error
+	ResolveIncident(websiteID int) error
+	GetLatestIncident(websiteID int) (*models.Incident, error)
+	GetWebsiteChannels(websiteID int) ([]models.NotificationChannel, error)
+	GetNotificationChannel(channelID int) (*models.NotificationChannel, error)
+	RecordAlert(record models.AlertRecord) error
+	GetLastAlertSent(incidentID int, alertTypes ...string) (*time.Time, error)
+	GetDeferredAlerts() ([]models.AlertRecord, error)
+	SetAlertOutcome(alertID int, outcome string) error
+	GetWebsiteMaintenanceWindows(websiteID int) ([]models.MaintenanceWindow, error)
+	GetLatencyBaseline(websiteID int, since time.Time) (int64, int, error)
+	GetDegradedSince(websiteID int) (*time.Time, error)
+	SetDegradedSince(websiteID int, since *time.Time) error
+	GetProbeResults(websiteID int, since time.Time) ([]models.ProbeResult, error)
+	GetLatestContentSnapshot(websiteID int) (*models.ContentSnapshot, error)
+	StoreContentSnapshot(snapshot models.ContentSnapshot) error
 }
 
-func New(logger *slog.Logger, mailer mailer.Mailer, config MonitorConfig) *Monitor {
+func New(logger *slog.Logger, mailer Mailer, config MonitorConfig) *Monitor {
+	defaults := DefaultMonitorConfig()
+	if config.DefaultInterval <= 0 {
+		config.DefaultInterval = defaults.DefaultInterval
+	}
+	if config.ResyncInterval <= 0 {
+		config.ResyncInterval = defaults.ResyncInterval
+	}
+	if config.JitterFraction <= 0 {
+		config.JitterFraction = defaults.JitterFraction
+	}
+	if config.MaxWorkers <= 0 {
+		config.MaxWorkers = defaults.MaxWorkers
+	}
+	if config.CheckTimeout <= 0 {
+		config.CheckTimeout = defaults.CheckTimeout
+	}
+	if config.Quorum <= 0 {
+		config.Quorum = defaults.Quorum
+	}
+
+	dialer := &net.Dialer{Timeout: config.CheckTimeout}
+	notifyClient := &http.Client{Timeout: notifyTimeout}
+
 	return &Monitor{
 		logger: logger,
-		mailer: mailer,
 		config: config,
+		checkers: map[string]Checker{
+			models.CheckTypeHTTP: newHTTPChecker(config.CheckTimeout),
+			models.CheckTypeTCP:  &tcpChecker{dialer: dialer},
+			models.CheckTypeTLS:  &tlsChecker{dialer: dialer},
+			models.CheckTypeDNS:  &dnsChecker{resolver: net.DefaultResolver},
+		},
+		notifiers: map[string]Notifier{
+			models.ChannelEmail:   &emailNotifier{mailer: mailer},
+			models.ChannelWebhook: &webhookNotifier{client: notifyClient},
+			models.ChannelSlack:   &chatNotifier{client: notifyClient, field: "text"},
+			models.ChannelDiscord: &chatNotifier{client: notifyClient, field: "content"},
+			models.ChannelNtfy:    &ntfyNotifier{client: notifyClient},
+			models.ChannelGotify:  &gotifyNotifier{client: notifyClient},
+		},
+		schedule: newSchedule(config.DefaultInterval, config.JitterFraction),
+		reload:   make(chan struct{}, 1),
+		jobs:     make(chan models.Website, config.MaxWorkers),
+		queued:   make(chan []models.Website, maxQueuedBatches),
+		inFlight: make(map[int]bool),
+
+		latencyStreaks: make(map[int]int),
+	}
+}
+
+// RegisterChecker adds or replaces the checker used for a check type. It
+// must be called before Start.
+func (m *Monitor) RegisterChecker(checkType string, checker Checker) {
+	m.checkers[checkType] = checker
+}
+
+// SetPublisher sets where the monitor publishes check results, status
+// changes and incidents as they happen. It must be called before Start.
+func (m *Monitor) SetPublisher(events Publisher) {
+	m.events = events
+}
+
+// publish sends a live event if the monitor has a publisher
+func (m *Monitor) publish(topic string, data any) {
+	if m.events != nil {
+		m.events.Publish(topic, data)
 	}
 }
 
 // Start monitoring in a goroutine
 func (m *Monitor) Start(ctx context.Context, db Database) {
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	// Start workers
+	for i := 0; i < m.config.MaxWorkers; i++ {
+		m.wg.Add(1)
+		go m.worker(ctx, db)
+	}
+
+	m.wg.Add(1)
 	go m.run(ctx, db)
+
+	m.wg.Add(1)
+	go m.alertLoop(ctx, db)
+}
+
+// Stop cancels in-flight checks and waits for the workers to exit
+func (m *Monitor) Stop(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reload asks the monitoring loop to re-read the website list, so added,
+// removed and edited websites are picked up without waiting for a resync
+func (m *Monitor) Reload() {
+	select {
+	case m.reload <- struct{}{}:
+	default:
+		// A reload is already pending
+	}
 }
 
 // Run the monitoring loop
 func (m *Monitor) run(ctx context.Context, db Database) {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
+	defer m.wg.Done()
 
-	// Do initial check
-	m.checkAllWebsites(db)
+	resync := time.NewTicker(m.config.ResyncInterval)
+	defer resync.Stop()
+
+	m.syncSchedule(db)
+
+	timer := time.NewTimer(m.schedule.untilNext(time.Now(), m.config.ResyncInterval))
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			m.logger.Info("Monitoring stopped")
 			return
-		case <-ticker.C:
-			m.checkAllWebsites(db)
+		case <-m.reload:
+			m.syncSchedule(db)
+		case <-resync.C:
+			m.syncSchedule(db)
+		case batch := <-m.queued:
+			m.enqueue(ctx, batch)
+		case <-timer.C:
+			m.checkDueWebsites(ctx)
 		}
+
+		timer.Reset(m.schedule.untilNext(time.Now(), m.config.ResyncInterval))
 	}
 }
 
-// Check all websites and store results
-func (m *Monitor) checkAllWebsites(db Database) {
+// Reconcile the schedule with the websites in the database
+func (m *Monitor) syncSchedule(db Database) {
 	websites, err := db.GetActiveWebsites()
 	if err != nil {
 		m.logger.Error("Failed to get active websites", "error", err)
 		return
 	}
 
-	for _, website := range websites {
-		m.CheckWebsite(website, db)
+	m.schedule.sync(websites, time.Now())
+}
+
+// Queue the websites that are due for the worker pool
+func (m *Monitor) checkDueWebsites(ctx context.Context) {
+	var due []models.Website
+	for _, website := range m.schedule.due(time.Now()) {
+		if !m.markInFlight(website.ID) {
+			m.logger.Warn("Skipping check, previous check still running", "website_id", website.ID, "url", website.URL)
+			continue
+		}
+		due = append(due, website)
 	}
+	m.enqueue(ctx, due)
 }
 
-// Check a single website
-func (m *Monitor) CheckWebsite(website models.Website, db Database) {
-	start := time.Now()
-	resp, err := http.Get(website.URL)
-	responseTime := time.Since(start).Milliseconds()
+// enqueue hands websites already marked in flight to the worker pool
+func (m *Monitor) enqueue(ctx context.Context, websites []models.Website) {
+	for i, website := range websites {
+		select {
+		case m.jobs <- website:
+		case <-ctx.Done():
+			for _, skipped := range websites[i:] {
+				m.clearInFlight(skipped.ID)
+			}
+			return
+		}
+	}
+}
 
-	var statusCode int
-	var isUp bool
-	var errorMsg string
+// Queue checks websites on the worker pool in the background, skipping
+// websites already being checked, and returns how many it queued. Checks
+// queued before the monitor starts run once it does.
+func (m *Monitor) Queue(websites []models.Website) int {
+	var batch []models.Website
+	for _, website := range websites {
+		if m.markInFlight(website.ID) {
+			batch = append(batch, website)
+		}
+	}
+	if len(batch) == 0 {
+		return 0
+	}
 
-	if err != nil {
-		errorMsg = err.Error()
-		isUp = false
-		m.logger.Error("Website check failed", "url", website.URL, "error", err)
-	} else {
-		defer resp.Body.Close()
-		statusCode = resp.StatusCode
-		isUp = resp.StatusCode == http.StatusOK
+	select {
+	case m.queued <- batch:
+		return len(batch)
+	default:
+		// Too many batches are waiting, so leave these to the schedule
+		for _, website := range batch {
+			m.clearInFlight(website.ID)
+		}
+		return 0
 	}
+}
 
-	// Store the check result
-	err = db.StoreUptimeCheck(website.ID, statusCode, responseTime, isUp, errorMsg)
-	if err != nil {
-		m.logger.Error("Failed to store uptime check", "website_id", website.ID, "error", err)
-		return
+// worker processes queued website checks
+func (m *Monitor) worker(ctx context.Context, db Database) {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case website := <-m.jobs:
+			m.CheckWebsite(ctx, website, db)
+			m.clearInFlight(website.ID)
+		}
 	}
+}
 
-	// Check if we need to send an alert
-	m.handleStatusChange(website, isUp, db)
+// markInFlight records that a website is being checked, returning false if
+// a check is already running
+func (m *Monitor) markInFlight(websiteID int) bool {
+	m.inFlightMu.Lock()
+	defer m.inFlightMu.Unlock()
+
+	if m.inFlight[websiteID] {
+		return false
+	}
+	m.inFlight[websiteID] = true
+	return true
 }
 
-// Handle status changes and send alerts if needed
-func (m *Monitor) handleStatusChange(website models.Website, currentIsUp bool, db Database) {
-	// Get the previous status
-	lastStatus, err := db.GetLastWebsiteStatus(website.ID)
-	if err != nil {
-		m.logger.Error("Failed to get last website status", "website_id", website.ID, "error", err)
+func (m *Monitor) clearInFlight(websiteID int) {
+	m.inFlightMu.Lock()
+	defer m.inFlightMu.Unlock()
+
+	delete(m.inFlight, websiteID)
+}
+
+// Check a single website
+func (m *Monitor) CheckWebsite(ctx context.Context, website models.Website, db Database) {
+	// Heartbeats are pushed to the monitor, so there is only something to
+	// record when one is overdue
+	if website.Type() == models.CheckTypeHeartbeat {
+		lastStatus, err := db.GetLastWebsiteStatus(website.ID)
+		if err != nil {
+			m.logger.Error("Failed to get last website status", "website_id", website.ID, "error", err)
+		}
+		if result, missed := m.checkHeartbeat(website, lastStatus, db, time.Now()); missed {
+			m.record(website, result, db)
+		}
 		return
 	}
 
-	// If this is the first check, don't send an alert
-	if lastStatus == nil {
+	result := m.checkWithRetries(ctx, website)
+
+	// Don't record a failure caused by the monitor shutting down
+	if errors.Is(ctx.Err(), context.Canceled) {
 		return
 	}
 
-	previousIsUp := lastStatus.Status == "up"
+	m.record(website, m.applyQuorum(website, result, db, time.Now()), db)
+}
 
-	// If status changed from up to down, send down alert
-	if previousIsUp && !currentIsUp {
-		m.sendDownAlert(website, db)
-	}
+// CheckWebsites checks a batch of websites at once, running at most
+// MaxWorkers checks at the same time, and returns when all are done.
+// Websites already being checked by the schedule are skipped.
+func (m *Monitor) CheckWebsites(ctx context.Context, websites []models.Website, db Database) {
+	slots := make(chan struct{}, m.config.MaxWorkers)
+	var wg sync.WaitGroup
 
-	// If status changed from down to up, send recovery alert
-	if !previousIsUp && currentIsUp {
-		m.sendRecoveryAlert(website, db)
+	for _, website := range websites {
+		if !m.markInFlight(website.ID) {
+			continue
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			defer m.clearInFlight(website.ID)
+
+			m.CheckWebsite(ctx, website, db)
+		}()
 	}
+	wg.Wait()
 }
 
-// Send alert when website goes down
-func (m *Monitor) sendDownAlert(website models.Website, db Database) {
-	// Check if we should send an alert (avoid spam)
-	shouldSend, err := db.ShouldSendAlert(website.ID, "down")
+// record stores a check result, confirming it against the website's recent
+// checks, and opens incidents and sends alerts when the confirmed state or
+// the latency state changes. Checks during maintenance are stored tagged as
+// maintenance and change nothing.
+func (m *Monitor) record(website models.Website, result Result, db Database) {
+	confirmed, streak, err := m.confirmedState(website, db)
 	if err != nil {
-		m.logger.Error("Failed to check if should send down alert", "website_id", website.ID, "error", err)
-		return
+		m.logger.Error("Failed to get recent website statuses", "website_id", website.ID, "error", err)
 	}
 
-	if !shouldSend {
-		return
+	status := confirmStatus(website.Confirmation, result, confirmed, streak)
+	switch status {
+	case models.StatusDown:
+		m.logger.Error("Website check failed", "url", website.URL, "error", result.Error)
+	case models.StatusPending, models.StatusDegraded:
+		m.logger.Warn("Website check unconfirmed", "url", website.URL, "status", status, "error", result.Error)
 	}
 
-	// Send the alert
-	alertData := map[string]interface{}{
-		"WebsiteName": website.Name,
-		"WebsiteURL":  website.URL,
-		"AlertType":   "down",
-		"Timestamp":   time.Now().Format("2006-01-02 15:04:05"),
+	// Checks of a website that is up are degraded when they're too slow.
+	// Outvoted failures have no response time worth comparing.
+	var breach string
+	if models.IsUpStatus(status) && !result.Outvoted {
+		if breach = m.latencyBreach(website, result, db); breach != "" {
+			m.logger.Warn("Website check slow", "url", website.URL, "reason", breach)
+			status = models.StatusDegraded
+			result.Error = breach
+		}
 	}
 
-	err = m.mailer.Send(m.config.AlertRecipient, "website_status_alert.tmpl", alertData)
+	maintenance := m.activeMaintenance(website, db, time.Now())
+
+	// Store the check result
+	err = db.StoreUptimeCheck(website.ID, result.StatusCode, result.ResponseTime, status, result.Error, maintenance != nil, result.Timing)
 	if err != nil {
-		m.logger.Error("Failed to send down alert", "website_id", website.ID, "error", err)
+		m.logger.Error("Failed to store uptime check", "website_id", website.ID, "error", err)
 		return
 	}
+	m.publish(models.EventCheck, models.CheckEvent{
+		WebsiteID:    website.ID,
+		Status:       status,
+		StatusCode:   result.StatusCode,
+		ResponseTime: result.ResponseTime,
+		Error:        result.Error,
+		Maintenance:  maintenance != nil,
+		CheckedAt:    time.Now(),
+	})
+
+	if result.ContentError != "" {
+		m.logger.Warn("Skipping content snapshot", "website_id", website.ID, "url", website.URL, "error", result.ContentError)
+	}
+	if result.Content != nil {
+		m.trackContent(website, *result.Content, maintenance != nil, db)
+	}
 
-	// Record that we sent the alert
-	err = db.RecordAlertSent(website.ID, "down")
-	if err != nil {
-		m.logger.Error("Failed to record down alert sent", "website_id", website.ID, "error", err)
+	if maintenance != nil {
+		m.logger.Info("Website checked during maintenance", "website_id", website.ID, "window", maintenance.Name, "status", status)
+		return
+	}
+
+	// Pending checks don't change the confirmed state
+	if status == models.StatusPending {
+		return
+	}
+
+	m.trackIncident(website, confirmed, status, result.Error, db)
+
+	if status != confirmed {
+		m.publish(models.EventStatus, models.StatusEvent{
+			WebsiteID: website.ID,
+			Previous:  confirmed,
+			Status:    status,
+			Reason:    result.Error,
+		})
+	}
+
+	// Check if we need to send an alert
+	m.handleStatusChange(website, confirmed, status, result.Error, db)
+
+	// Outvoted failures say nothing about the website's latency
+	if !result.Outvoted {
+		m.trackLatency(website, status, breach, db)
 	}
+}
 
-	m.logger.Info("Sent down alert", "website_id", website.ID, "url", website.URL)
+// trackIncident opens an incident when a website goes down, including on
+// its first check, and resolves it when the website recovers
+func (m *Monitor) trackIncident(website models.Website, previous, status, cause string, db Database) {
+	wasDown := previous == models.StatusDown
+	isDown := status == models.StatusDown
+
+	switch {
+	case isDown && !wasDown:
+		if err := db.OpenIncident(website.ID, cause); err != nil {
+			m.logger.Error("Failed to open incident", "website_id", website.ID, "error", err)
+			return
+		}
+		m.publish(models.EventIncident, models.IncidentChange{WebsiteID: website.ID, Action: models.IncidentEventOpened, Cause: cause})
+	case !isDown && wasDown:
+		if err := db.ResolveIncident(website.ID); err != nil {
+			m.logger.Error("Failed to resolve incident", "website_id", website.ID, "error", err)
+			return
+		}
+		m.publish(models.EventIncident, models.IncidentChange{WebsiteID: website.ID, Action: models.IncidentEventResolved})
+	}
 }
 
-// Send alert when website recovers
-func (m *Monitor) sendRecoveryAlert(website models.Website, db Database) {
-	// Check if we should send an alert (avoid spam)
-	shouldSend, err := db.ShouldSendAlert(website.ID, "recovery")
+// activeMaintenance returns the maintenance window covering a website at t,
+// or nil if there is none
+func (m *Monitor) activeMaintenance(website models.Website, db Database, t time.Time) *models.MaintenanceWindow {
+	windows, err := db.GetWebsiteMaintenanceWindows(website.ID)
 	if err != nil {
-		m.logger.Error("Failed to check if should send recovery alert", "website_id", website.ID, "error", err)
-		return
+		m.logger.Error("Failed to get maintenance windows", "website_id", website.ID, "error", err)
+		return nil
+	}
+	return models.ActiveMaintenance(windows, t)
+}
+
+// check runs the checker registered for the website's check type
+func (m *Monitor) check(ctx context.Context, website models.Website) Result {
+	checker, ok := m.checkers[website.Type()]
+	if !ok {
+		return Result{Error: fmt.Sprintf("unsupported check type %q", website.Type())}
 	}
+	return checker.Check(ctx, website)
+}
 
-	if !shouldSend {
+// Handle confirmed status changes and send alerts if needed
+func (m *Monitor) handleStatusChange(website models.Website, previous, status, reason string, db Database) {
+	// If this is the first check, don't send an alert
+	if previous == "" {
 		return
 	}
 
-	// Send the alert
-	alertData := map[string]interface{}{
-		"WebsiteName": website.Name,
-		"WebsiteURL":  website.URL,
-		"AlertType":   "recovery",
-		"Timestamp":   time.Now().Format("2006-01-02 15:04:05"),
+	previousIsUp := previous == models.StatusUp
+	isUp := models.IsUpStatus(status)
+
+	// If status changed from up to down, send down alert
+	if previousIsUp && !isUp {
+		m.sendAlert(website, models.AlertDown, reason, db)
 	}
 
-	err = m.mailer.Send(m.config.AlertRecipient, "website_status_alert.tmpl", alertData)
-	if err != nil {
-		m.logger.Error("Failed to send recovery alert", "website_id", website.ID, "error", err)
-		return
+	// If status changed from down to up, send recovery alert
+	if !previousIsUp && isUp {
+		m.sendAlert(website, models.AlertRecovery, "", db)
 	}
+}
 
-	// Record that we sent the alert
-	err = db.RecordAlertSent(website.ID, "recovery")
+// sendAlert notifies the website's channels of a state change, linking the
+// alert to the website's latest incident
+func (m *Monitor) sendAlert(website models.Website, alertType, reason string, db Database) {
+	incidentID := 0
+	incident, err := db.GetLatestIncident(website.ID)
 	if err != nil {
-		m.logger.Error("Failed to record recovery alert sent", "website_id", website.ID, "error", err)
+		m.logger.Error("Failed to get latest incident", "website_id", website.ID, "error", err)
+	} else if incident != nil {
+		incidentID = incident.ID
 	}
 
-	m.logger.Info("Sent recovery alert", "website_id", website.ID, "url", website.URL)
+	notification := Notification{
+		Website:   website,
+		Type:      alertType,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+	if m.notify(notification, incidentID, db) {
+		m.logger.Info("Sent alert", "website_id", website.ID, "url", website.URL, "type", alertType)
+	}
 }
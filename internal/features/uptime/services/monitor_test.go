@@ -0,0 +1,635 @@
+package monitor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"the-ark/internal/core"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/internal/server/services/mailer"
+	"time"
+)
+
+// fakeDatabase is an in-memory Database used by the monitor tests
+type fakeDatabase struct {
+	mu         sync.Mutex
+	websites   []models.Website
+	checks     []models.WebsiteStatus
+	heartbeats map[int]time.Time
+	incidents  []models.Incident
+	channels   map[int][]models.NotificationChannel
+	alerts     []models.AlertRecord
+	windows    []models.MaintenanceWindow
+	degraded   map[int]time.Time
+
+	// probes are the results probe agents reported
+	probes []models.ProbeResult
+
+	// snapshots are the stored content snapshots, oldest first
+	snapshots []models.ContentSnapshot
+
+	// contacts are channels not selected by any website, such as
+	// escalation contacts
+	contacts []models.NotificationChannel
+}
+
+func (d *fakeDatabase) GetActiveWebsites() ([]models.Website, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]models.Website(nil), d.websites...), nil
+}
+
+func (d *fakeDatabase) GetLastWebsiteStatus(websiteID int) (*models.WebsiteStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := len(d.checks) - 1; i >= 0; i-- {
+		if d.checks[i].WebsiteID == websiteID {
+			status := d.checks[i]
+			return &status, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *fakeDatabase) StoreUptimeCheck(websiteID int, statusCode int, responseTime int64, status string, errorMsg string, maintenance bool, timing *models.CheckTiming) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checks = append(d.checks, models.WebsiteStatus{
+		WebsiteID:    websiteID,
+		Status:       status,
+		StatusCode:   statusCode,
+		ResponseTime: responseTime,
+		Error:        errorMsg,
+		CheckedAt:    time.Now(),
+		Maintenance:  maintenance,
+		Timing:       timing,
+	})
+	return nil
+}
+
+func (d *fakeDatabase) GetRecentStatuses(websiteID int, limit int) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var statuses []string
+	for i := len(d.checks) - 1; i >= 0 && len(statuses) < limit; i-- {
+		if d.checks[i].WebsiteID == websiteID && !d.checks[i].Maintenance {
+			statuses = append(statuses, d.checks[i].Status)
+		}
+	}
+	return statuses, nil
+}
+
+func (d *fakeDatabase) RecordAlert(record models.AlertRecord) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	record.ID = len(d.alerts) + 1
+	if record.SentAt.IsZero() {
+		record.SentAt = time.Now()
+	}
+	d.alerts = append(d.alerts, record)
+	return nil
+}
+
+func (d *fakeDatabase) GetLastAlertSent(incidentID int, alertTypes ...string) (*time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var last *time.Time
+	for _, record := range d.alerts {
+		delivered := record.Outcome == models.AlertSent || record.Outcome == models.AlertReleased
+		if record.IncidentID != incidentID || !delivered || !slices.Contains(alertTypes, record.Type) {
+			continue
+		}
+		if last == nil || record.SentAt.After(*last) {
+			sentAt := record.SentAt
+			last = &sentAt
+		}
+	}
+	return last, nil
+}
+
+func (d *fakeDatabase) GetDeferredAlerts() ([]models.AlertRecord, error) {
+	return d.alertsWith(models.AlertDeferred), nil
+}
+
+func (d *fakeDatabase) SetAlertOutcome(alertID int, outcome string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.alerts[alertID-1].Outcome = outcome
+	return nil
+}
+
+// alertsWith returns the recorded alerts with an outcome
+func (d *fakeDatabase) alertsWith(outcome string) []models.AlertRecord {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var records []models.AlertRecord
+	for _, record := range d.alerts {
+		if record.Outcome == outcome {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (d *fakeDatabase) GetNotificationChannel(channelID int) (*models.NotificationChannel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	all := slices.Clone(d.contacts)
+	for _, channels := range d.channels {
+		all = append(all, channels...)
+	}
+	for _, channel := range all {
+		if channel.ID == channelID {
+			return &channel, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (d *fakeDatabase) GetWebsiteChannels(websiteID int) ([]models.NotificationChannel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.channels[websiteID], nil
+}
+
+func (d *fakeDatabase) RecordHeartbeat(websiteID int, at time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.heartbeats == nil {
+		d.heartbeats = make(map[int]time.Time)
+	}
+	d.heartbeats[websiteID] = at
+	return nil
+}
+
+func (d *fakeDatabase) GetLastHeartbeat(websiteID int) (*time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	at, ok := d.heartbeats[websiteID]
+	if !ok {
+		return nil, nil
+	}
+	return &at, nil
+}
+
+func (d *fakeDatabase) OpenIncident(websiteID int, cause string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, incident := range d.incidents {
+		if incident.WebsiteID == websiteID && !incident.IsResolved() {
+			return nil
+		}
+	}
+	d.incidents = append(d.incidents, models.Incident{
+		ID:        len(d.incidents) + 1,
+		WebsiteID: websiteID,
+		Status:    models.IncidentOpen,
+		Cause:     cause,
+		StartedAt: time.Now(),
+	})
+	return nil
+}
+
+func (d *fakeDatabase) GetWebsiteMaintenanceWindows(websiteID int) ([]models.MaintenanceWindow, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var windows []models.MaintenanceWindow
+	for _, window := range d.windows {
+		if window.IsGlobal() || window.WebsiteID == websiteID {
+			windows = append(windows, window)
+		}
+	}
+	return windows, nil
+}
+
+func (d *fakeDatabase) GetLatestIncident(websiteID int) (*models.Incident, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := len(d.incidents) - 1; i >= 0; i-- {
+		if d.incidents[i].WebsiteID == websiteID {
+			incident := d.incidents[i]
+			return &incident, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *fakeDatabase) ResolveIncident(websiteID int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range d.incidents {
+		if d.incidents[i].WebsiteID == websiteID && !d.incidents[i].IsResolved() {
+			now := time.Now()
+			d.incidents[i].Status = models.IncidentResolved
+			d.incidents[i].ResolvedAt = &now
+		}
+	}
+	return nil
+}
+
+func (d *fakeDatabase) GetLatencyBaseline(websiteID int, since time.Time) (int64, int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var latencies []int64
+	for _, check := range d.checks {
+		if check.WebsiteID == websiteID && check.Status == models.StatusUp && !check.Maintenance && !check.CheckedAt.Before(since) {
+			latencies = append(latencies, check.ResponseTime)
+		}
+	}
+	if len(latencies) == 0 {
+		return 0, 0, nil
+	}
+	slices.Sort(latencies)
+	return latencies[(len(latencies)-1)/2], len(latencies), nil
+}
+
+func (d *fakeDatabase) GetDegradedSince(websiteID int) (*time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	since, ok := d.degraded[websiteID]
+	if !ok {
+		return nil, nil
+	}
+	return &since, nil
+}
+
+func (d *fakeDatabase) SetDegradedSince(websiteID int, since *time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.degraded == nil {
+		d.degraded = make(map[int]time.Time)
+	}
+	if since == nil {
+		delete(d.degraded, websiteID)
+	} else {
+		d.degraded[websiteID] = *since
+	}
+	return nil
+}
+
+func (d *fakeDatabase) GetProbeResults(websiteID int, since time.Time) ([]models.ProbeResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var results []models.ProbeResult
+	for _, result := range d.probes {
+		if result.WebsiteID == websiteID && !result.CheckedAt.Before(since) {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func (d *fakeDatabase) GetLatestContentSnapshot(websiteID int) (*models.ContentSnapshot, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := len(d.snapshots) - 1; i >= 0; i-- {
+		if d.snapshots[i].WebsiteID == websiteID {
+			snapshot := d.snapshots[i]
+			return &snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *fakeDatabase) StoreContentSnapshot(snapshot models.ContentSnapshot) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	snapshot.ID = len(d.snapshots) + 1
+	d.snapshots = append(d.snapshots, snapshot)
+	return nil
+}
+
+func (d *fakeDatabase) checksFor(websiteID int) []models.WebsiteStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var checks []models.WebsiteStatus
+	for _, check := range d.checks {
+		if check.WebsiteID == websiteID {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+func newTestMonitor(config MonitorConfig) *Monitor {
+	return New(slog.New(slog.DiscardHandler), mailer.Mailer{}, config)
+}
+
+func TestCheckWebsiteTimesOut(t *testing.T) {
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hung.Close()
+
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	m.CheckWebsite(context.Background(), models.Website{ID: 1, URL: hung.URL}, db)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Expected check to time out quickly, took %v", elapsed)
+	}
+
+	checks := db.checksFor(1)
+	if len(checks) != 1 || checks[0].Status != "down" {
+		t.Fatalf("Expected a single down check, got %+v", checks)
+	}
+}
+
+func TestSlowWebsiteDoesNotBlockOthers(t *testing.T) {
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hung.Close()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	db := &fakeDatabase{websites: []models.Website{
+		{ID: 1, URL: hung.URL, CheckInterval: 60},
+		{ID: 2, URL: healthy.URL, CheckInterval: 60},
+	}}
+	m := newTestMonitor(MonitorConfig{MaxWorkers: 2, CheckTimeout: 5 * time.Second, JitterFraction: 0.001})
+	m.Start(context.Background(), db)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for len(db.checksFor(2)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if checks := db.checksFor(2); len(checks) == 0 || checks[0].Status != "up" {
+		t.Fatalf("Expected healthy website to be checked while another hangs, got %+v", checks)
+	}
+
+	// Shutdown cancels the hung request rather than waiting for its timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Stop(ctx); err != nil {
+		t.Fatalf("Expected monitor to stop cleanly, got %v", err)
+	}
+
+	if checks := db.checksFor(1); len(checks) != 0 {
+		t.Errorf("Expected cancelled check not to be stored, got %+v", checks)
+	}
+}
+
+func TestCheckWebsitesBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var websites []models.Website
+	for id := 1; id <= 5; id++ {
+		websites = append(websites, models.Website{ID: id, URL: server.URL})
+	}
+
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{MaxWorkers: 2})
+	m.CheckWebsites(context.Background(), websites, db)
+
+	for _, website := range websites {
+		if checks := db.checksFor(website.ID); len(checks) != 1 || checks[0].Status != models.StatusUp {
+			t.Errorf("Expected website %d to be checked once, got %+v", website.ID, checks)
+		}
+	}
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 checks at once, got %d", peak.Load())
+	}
+}
+
+func TestQueueChecksInBackground(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	websites := []models.Website{{ID: 1, URL: server.URL}, {ID: 2, URL: server.URL}}
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{MaxWorkers: 2, CheckTimeout: 5 * time.Second})
+	m.Start(context.Background(), db)
+	defer m.Stop(context.Background())
+
+	// Queueing returns while the checks are still running, and a website
+	// already being checked isn't queued again
+	if queued := m.Queue(websites); queued != 2 {
+		t.Fatalf("Expected 2 checks queued, got %d", queued)
+	}
+	if queued := m.Queue(websites[:1]); queued != 0 {
+		t.Errorf("Expected a website being checked to be skipped, got %d queued", queued)
+	}
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(db.checksFor(1)) == 0 || len(db.checksFor(2)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the queued checks to run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCheckHTTPRequestOptions(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		got, gotBody = r, string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+
+	website := models.Website{
+		URL: server.URL + "/health",
+		Request: models.RequestOptions{
+			Method:          http.MethodPost,
+			Headers:         map[string]string{"X-Environment": "production"},
+			Body:            `{"ping": true}`,
+			AuthType:        models.AuthBearer,
+			AuthSecret:      "s3cret",
+			UserAgent:       "health-checker/2.0",
+			FollowRedirects: true,
+		},
+	}
+	if result := m.check(context.Background(), website); !result.IsUp {
+		t.Fatalf("Expected check to pass, got %q", result.Error)
+	}
+
+	if got.Method != http.MethodPost || gotBody != `{"ping": true}` {
+		t.Errorf("Expected POST with JSON body, got %s %q", got.Method, gotBody)
+	}
+	if got.Header.Get("Authorization") != "Bearer s3cret" {
+		t.Errorf("Expected bearer token, got %q", got.Header.Get("Authorization"))
+	}
+	if got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", got.Header.Get("Content-Type"))
+	}
+	if got.UserAgent() != "health-checker/2.0" || got.Header.Get("X-Environment") != "production" {
+		t.Errorf("Expected custom headers, got %v", got.Header)
+	}
+
+	// Without redirects the 302 itself is evaluated against the assertions
+	noRedirect := models.Website{
+		URL:        server.URL + "/moved",
+		Request:    models.RequestOptions{FollowRedirects: false},
+		Assertions: models.Assertions{StatusCodes: "200"},
+	}
+	if result := m.check(context.Background(), noRedirect); result.IsUp || result.StatusCode != http.StatusFound {
+		t.Errorf("Expected unfollowed redirect to fail with 302, got %+v", result)
+	}
+
+	// Secrets are never echoed back in JSON
+	encoded, err := json.Marshal(website)
+	if err != nil {
+		t.Fatalf("Failed to marshal website: %v", err)
+	}
+	if strings.Contains(string(encoded), "s3cret") {
+		t.Errorf("Expected auth secret to be redacted, got %s", encoded)
+	}
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	website := models.Website{ID: 1, URL: server.URL}
+
+	m.CheckWebsite(context.Background(), website, db)
+	if len(db.incidents) != 0 {
+		t.Fatalf("Expected no incident while up, got %+v", db.incidents)
+	}
+
+	healthy.Store(false)
+	m.CheckWebsite(context.Background(), website, db)
+	m.CheckWebsite(context.Background(), website, db)
+	if len(db.incidents) != 1 || db.incidents[0].IsResolved() || !strings.Contains(db.incidents[0].Cause, "503") {
+		t.Fatalf("Expected a single open incident caused by the 503, got %+v", db.incidents)
+	}
+
+	healthy.Store(true)
+	m.CheckWebsite(context.Background(), website, db)
+	if len(db.incidents) != 1 || !db.incidents[0].IsResolved() {
+		t.Fatalf("Expected the incident to be resolved on recovery, got %+v", db.incidents)
+	}
+}
+
+func TestLiveEvents(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+
+	db := &fakeDatabase{}
+	m := newTestMonitor(MonitorConfig{})
+	m.RegisterChecker(models.CheckTypeHTTP, CheckerFunc(func(ctx context.Context, website models.Website) Result {
+		if healthy.Load() {
+			return Result{IsUp: true, StatusCode: 200}
+		}
+		return Result{Error: "connection refused"}
+	}))
+
+	bus := core.NewEventBus()
+	events, unsubscribe := bus.Subscribe("uptime")
+	defer unsubscribe()
+	m.SetPublisher(bus)
+
+	website := models.Website{ID: 1, URL: "https://example.com"}
+	m.CheckWebsite(context.Background(), website, db)
+	healthy.Store(false)
+	m.CheckWebsite(context.Background(), website, db)
+
+	var topics []string
+	for len(events) > 0 {
+		event := <-events
+		topics = append(topics, event.Topic)
+		if incident, ok := event.Data.(models.IncidentChange); ok && incident.Action != models.IncidentEventOpened {
+			t.Errorf("Expected the incident opened, got %+v", incident)
+		}
+	}
+
+	// The first check sets the status, the failure changes it and opens an
+	// incident
+	expected := []string{
+		models.EventCheck, models.EventStatus,
+		models.EventCheck, models.EventIncident, models.EventStatus,
+	}
+	if strings.Join(topics, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected events %v, got %v", expected, topics)
+	}
+}
+
+func TestMaintenanceSuppressesAlerts(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer target.Close()
+
+	webhook, requests := newStandIn(t, http.StatusOK, "")
+
+	start := time.Now().Add(-time.Minute)
+	end := start.Add(time.Hour)
+	website := models.Website{ID: 1, Name: "Example", URL: target.URL}
+	db := &fakeDatabase{
+		checks:   []models.WebsiteStatus{{WebsiteID: 1, Status: models.StatusUp}},
+		channels: map[int][]models.NotificationChannel{1: {{Name: "Webhook", Type: models.ChannelWebhook, URL: webhook.URL}}},
+		windows:  []models.MaintenanceWindow{{Name: "Deploy", StartsAt: &start, EndsAt: &end}},
+	}
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	m.CheckWebsite(context.Background(), website, db)
+
+	checks := db.checksFor(1)
+	if last := checks[len(checks)-1]; !last.Maintenance || last.Status != models.StatusDown {
+		t.Fatalf("Expected a down check tagged as maintenance, got %+v", last)
+	}
+	if len(db.incidents) != 0 || len(requests()) != 0 {
+		t.Fatalf("Expected no incident or alert during maintenance, got %+v and %d requests", db.incidents, len(requests()))
+	}
+
+	// Still down once maintenance ends, so the change from the state before
+	// maintenance is alerted
+	db.mu.Lock()
+	db.windows = nil
+	db.mu.Unlock()
+
+	m.CheckWebsite(context.Background(), website, db)
+	if len(db.incidents) != 1 || len(requests()) != 1 {
+		t.Errorf("Expected an incident and alert after maintenance, got %+v and %d requests", db.incidents, len(requests()))
+	}
+}
@@ -0,0 +1,224 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// notifyTimeout bounds how long delivering through a single channel may take
+const notifyTimeout = 10 * time.Second
+
+// Mailer sends templated emails
+type Mailer interface {
+	Send(recipient, templateFile string, data any) error
+}
+
+// Notification is an alert about a website delivered through a channel
+type Notification struct {
+	Website   models.Website
+	Type      string
+	Reason    string
+	Timestamp time.Time
+}
+
+// Title returns a one line summary of the notification
+func (n Notification) Title() string {
+	switch n.Type {
+	case models.AlertDown:
+		return fmt.Sprintf("[DOWN] %s", n.Website.Name)
+	case models.AlertRecovery:
+		return fmt.Sprintf("[RECOVERED] %s", n.Website.Name)
+	case models.AlertReminder:
+		return fmt.Sprintf("[STILL DOWN] %s", n.Website.Name)
+	case models.AlertEscalation:
+		return fmt.Sprintf("[ESCALATED] %s", n.Website.Name)
+	case models.AlertDegraded:
+		return fmt.Sprintf("[DEGRADED] %s", n.Website.Name)
+	case models.AlertDegradedRecovery:
+		return fmt.Sprintf("[RESPONSIVE] %s", n.Website.Name)
+	case models.AlertContentChanged:
+		return fmt.Sprintf("[CHANGED] %s", n.Website.Name)
+	default:
+		return fmt.Sprintf("[TEST] %s", n.Website.Name)
+	}
+}
+
+// Message returns the notification as plain text
+func (n Notification) Message() string {
+	var message string
+	switch n.Type {
+	case models.AlertDown:
+		message = fmt.Sprintf("%s (%s) is down", n.Website.Name, n.Website.URL)
+	case models.AlertRecovery:
+		message = fmt.Sprintf("%s (%s) has recovered", n.Website.Name, n.Website.URL)
+	case models.AlertReminder:
+		message = fmt.Sprintf("%s (%s) is still down", n.Website.Name, n.Website.URL)
+	case models.AlertEscalation:
+		message = fmt.Sprintf("%s (%s) is down and the incident is unacknowledged", n.Website.Name, n.Website.URL)
+	case models.AlertDegraded:
+		message = fmt.Sprintf("%s (%s) is responding slowly", n.Website.Name, n.Website.URL)
+	case models.AlertDegradedRecovery:
+		message = fmt.Sprintf("%s (%s) is responding normally again", n.Website.Name, n.Website.URL)
+	case models.AlertContentChanged:
+		message = fmt.Sprintf("The content of %s (%s) has changed", n.Website.Name, n.Website.URL)
+	default:
+		message = "This is a test notification from The Ark uptime monitor"
+	}
+	if n.Reason != "" {
+		message += ": " + n.Reason
+	}
+	return message
+}
+
+// Notifier delivers notifications through one type of channel
+type Notifier interface {
+	Notify(ctx context.Context, channel models.NotificationChannel, notification Notification) error
+}
+
+// RegisterNotifier sets the notifier used for a channel type, replacing any
+// existing one
+func (m *Monitor) RegisterNotifier(channelType string, notifier Notifier) {
+	m.notifiers[channelType] = notifier
+}
+
+// SendNotification delivers a notification through a single channel
+func (m *Monitor) SendNotification(ctx context.Context, channel models.NotificationChannel, notification Notification) error {
+	notifier, ok := m.notifiers[channel.Type]
+	if !ok {
+		return fmt.Errorf("unsupported channel type %q", channel.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	return notifier.Notify(ctx, channel, notification)
+}
+
+// alertChannels returns the channels a website alerts, falling back to
+// emailing the alert recipient when it has none
+func (m *Monitor) alertChannels(website models.Website, db Database) ([]models.NotificationChannel, error) {
+	channels, err := db.GetWebsiteChannels(website.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) == 0 && m.config.AlertRecipient != "" {
+		channels = []models.NotificationChannel{m.defaultChannel()}
+	}
+	return channels, nil
+}
+
+// defaultChannel emails the alert recipient. It has no ID as it isn't stored.
+func (m *Monitor) defaultChannel() models.NotificationChannel {
+	return models.NotificationChannel{
+		Name:      "Alert recipient",
+		Type:      models.ChannelEmail,
+		Recipient: m.config.AlertRecipient,
+	}
+}
+
+// deliver sends a notification through a single channel and records the
+// attempt and its outcome in the alert history
+func (m *Monitor) deliver(channel models.NotificationChannel, notification Notification, incidentID int, db Database) error {
+	record := models.AlertRecord{
+		WebsiteID:   notification.Website.ID,
+		IncidentID:  incidentID,
+		Type:        notification.Type,
+		ChannelID:   channel.ID,
+		ChannelName: channel.Name,
+		Outcome:     models.AlertSent,
+		Reason:      notification.Reason,
+		SentAt:      notification.Timestamp,
+	}
+
+	sendErr := m.SendNotification(context.Background(), channel, notification)
+	if sendErr != nil {
+		m.logger.Error("Failed to send notification", "website_id", notification.Website.ID, "channel", channel.Name, "type", notification.Type, "error", sendErr)
+		record.Outcome = models.AlertFailed
+		record.Error = sendErr.Error()
+	}
+
+	if err := db.RecordAlert(record); err != nil {
+		m.logger.Error("Failed to record alert", "website_id", notification.Website.ID, "channel", channel.Name, "error", err)
+	}
+	return sendErr
+}
+
+// notify delivers a notification through every channel the website alerts.
+// Non-critical alerts raised during the website's quiet hours are recorded
+// as deferred and delivered once the quiet hours end. It reports whether any
+// channel accepted the notification.
+func (m *Monitor) notify(notification Notification, incidentID int, db Database) bool {
+	website := notification.Website
+	channels, err := m.alertChannels(website, db)
+	if err != nil {
+		m.logger.Error("Failed to get notification channels", "website_id", website.ID, "error", err)
+		return false
+	}
+	if len(channels) == 0 {
+		m.logger.Warn("No notification channels for website", "website_id", website.ID)
+		return false
+	}
+
+	if !models.IsCriticalAlert(notification.Type) && website.Alerts.InQuietHours(notification.Timestamp) {
+		for _, channel := range channels {
+			err := db.RecordAlert(models.AlertRecord{
+				WebsiteID:   website.ID,
+				IncidentID:  incidentID,
+				Type:        notification.Type,
+				ChannelID:   channel.ID,
+				ChannelName: channel.Name,
+				Outcome:     models.AlertDeferred,
+				Reason:      notification.Reason,
+				SentAt:      notification.Timestamp,
+			})
+			if err != nil {
+				m.logger.Error("Failed to record deferred alert", "website_id", website.ID, "channel", channel.Name, "error", err)
+			}
+		}
+		m.logger.Info("Deferred alert during quiet hours", "website_id", website.ID, "type", notification.Type)
+		return false
+	}
+
+	delivered := false
+	for _, channel := range channels {
+		if m.deliver(channel, notification, incidentID, db) == nil {
+			delivered = true
+		}
+	}
+	return delivered
+}
+
+// newJSONRequest builds a POST request with payload encoded as JSON
+func newJSONRequest(ctx context.Context, url string, payload any) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// send performs a notification request, failing on non-2xx responses
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
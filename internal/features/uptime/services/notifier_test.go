@@ -0,0 +1,208 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/internal/server/services/mailer"
+	"time"
+)
+
+// capturedRequest is a request received by a notification stand-in
+type capturedRequest struct {
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+// newStandIn starts a server recording every request it receives
+func newStandIn(t *testing.T, status int, response string) (*httptest.Server, func() []capturedRequest) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, capturedRequest{Path: r.URL.Path, Header: r.Header.Clone(), Body: string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []capturedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]capturedRequest(nil), requests...)
+	}
+}
+
+func testNotification() Notification {
+	return Notification{
+		Website:   models.Website{ID: 7, Name: "Example", URL: "https://example.com"},
+		Type:      models.AlertDown,
+		Reason:    "HTTP 503",
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNotifiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel models.NotificationChannel
+		verify  func(t *testing.T, request capturedRequest)
+	}{
+		{
+			name:    "webhook",
+			channel: models.NotificationChannel{Type: models.ChannelWebhook, Token: "secret"},
+			verify: func(t *testing.T, request capturedRequest) {
+				var payload webhookPayload
+				if err := json.Unmarshal([]byte(request.Body), &payload); err != nil {
+					t.Fatalf("Failed to decode payload: %v", err)
+				}
+				if payload.Event != models.AlertDown || payload.Website.ID != 7 || payload.Reason != "HTTP 503" {
+					t.Errorf("Unexpected payload %+v", payload)
+				}
+				if got := request.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Expected bearer token, got %q", got)
+				}
+			},
+		},
+		{
+			name:    "slack",
+			channel: models.NotificationChannel{Type: models.ChannelSlack},
+			verify: func(t *testing.T, request capturedRequest) {
+				var payload map[string]string
+				json.Unmarshal([]byte(request.Body), &payload)
+				if !strings.Contains(payload["text"], "[DOWN] Example") {
+					t.Errorf("Expected Slack text, got %s", request.Body)
+				}
+			},
+		},
+		{
+			name:    "discord",
+			channel: models.NotificationChannel{Type: models.ChannelDiscord},
+			verify: func(t *testing.T, request capturedRequest) {
+				var payload map[string]string
+				json.Unmarshal([]byte(request.Body), &payload)
+				if !strings.Contains(payload["content"], "HTTP 503") {
+					t.Errorf("Expected Discord content, got %s", request.Body)
+				}
+			},
+		},
+		{
+			name:    "ntfy",
+			channel: models.NotificationChannel{Type: models.ChannelNtfy},
+			verify: func(t *testing.T, request capturedRequest) {
+				if request.Header.Get("Title") != "[DOWN] Example" || request.Header.Get("Priority") != "high" {
+					t.Errorf("Unexpected ntfy headers %v", request.Header)
+				}
+				if !strings.Contains(request.Body, "is down") {
+					t.Errorf("Expected plain text message, got %q", request.Body)
+				}
+			},
+		},
+		{
+			name:    "gotify",
+			channel: models.NotificationChannel{Type: models.ChannelGotify, Token: "app-token"},
+			verify: func(t *testing.T, request capturedRequest) {
+				var message gotifyMessage
+				json.Unmarshal([]byte(request.Body), &message)
+				if request.Path != "/message" || request.Header.Get("X-Gotify-Key") != "app-token" {
+					t.Errorf("Unexpected Gotify request to %s with headers %v", request.Path, request.Header)
+				}
+				if message.Priority != 8 || message.Title != "[DOWN] Example" {
+					t.Errorf("Unexpected Gotify message %+v", message)
+				}
+			},
+		},
+		{
+			name:    "email",
+			channel: models.NotificationChannel{Type: models.ChannelEmail, Recipient: "ops@example.com"},
+			verify: func(t *testing.T, request capturedRequest) {
+				var payload mailer.SMTP2GORequest
+				if err := json.Unmarshal([]byte(request.Body), &payload); err != nil {
+					t.Fatalf("Failed to decode email: %v", err)
+				}
+				if len(payload.To) != 1 || payload.To[0] != "ops@example.com" || !strings.Contains(payload.Subject, "[DOWN]") {
+					t.Errorf("Unexpected email %+v", payload)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newStandIn(t, http.StatusOK, `{"data":{}}`)
+			m := New(slog.New(slog.DiscardHandler), mailer.New("key", "alerts@example.com").WithEndpoint(server.URL), MonitorConfig{})
+
+			channel := tt.channel
+			channel.URL = server.URL
+
+			if err := m.SendNotification(context.Background(), channel, testNotification()); err != nil {
+				t.Fatalf("Failed to send notification: %v", err)
+			}
+
+			received := requests()
+			if len(received) != 1 {
+				t.Fatalf("Expected one request, got %d", len(received))
+			}
+			tt.verify(t, received[0])
+		})
+	}
+}
+
+func TestNotifierRejected(t *testing.T) {
+	server, _ := newStandIn(t, http.StatusForbidden, "")
+	m := newTestMonitor(MonitorConfig{})
+
+	channel := models.NotificationChannel{Type: models.ChannelWebhook, URL: server.URL}
+	err := m.SendNotification(context.Background(), channel, testNotification())
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected a rejected notification, got %v", err)
+	}
+}
+
+func TestDownAlertUsesWebsiteChannels(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer target.Close()
+
+	slack, slackRequests := newStandIn(t, http.StatusOK, "ok")
+	broken, _ := newStandIn(t, http.StatusInternalServerError, "")
+
+	website := models.Website{ID: 1, Name: "Example", URL: target.URL}
+	db := &fakeDatabase{
+		checks: []models.WebsiteStatus{{WebsiteID: 1, Status: models.StatusUp}},
+		channels: map[int][]models.NotificationChannel{
+			1: {
+				{Name: "Broken", Type: models.ChannelWebhook, URL: broken.URL},
+				{Name: "Slack", Type: models.ChannelSlack, URL: slack.URL},
+			},
+		},
+	}
+
+	m := newTestMonitor(MonitorConfig{CheckTimeout: 5 * time.Second})
+	m.CheckWebsite(context.Background(), website, db)
+
+	if len(slackRequests()) != 1 {
+		t.Fatalf("Expected the Slack channel to be notified")
+	}
+	// Every delivery attempt is recorded against the incident
+	sent, failed := db.alertsWith(models.AlertSent), db.alertsWith(models.AlertFailed)
+	if len(sent) != 1 || sent[0].ChannelName != "Slack" || sent[0].Type != models.AlertDown || sent[0].IncidentID != 1 {
+		t.Errorf("Expected a sent down alert through Slack, got %+v", sent)
+	}
+	if len(failed) != 1 || failed[0].ChannelName != "Broken" || !strings.Contains(failed[0].Error, "500") {
+		t.Errorf("Expected a failed alert through the broken webhook, got %+v", failed)
+	}
+}
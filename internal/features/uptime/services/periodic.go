@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// periodic runs a background job every interval, shared by the rollup,
+// reporter and domain tracker
+type periodic struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// runEvery calls fn straight away and then every interval until stopped or
+// ctx is cancelled. fn is passed a context cancelled by stop.
+func (p *periodic) runEvery(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		fn(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	}()
+}
+
+// stop cancels the job and waits for a running call to finish, giving up
+// when ctx is done
+func (p *periodic) stop(ctx context.Context) error {
+	if p.cancel == nil {
+		return nil
+	}
+	p.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPeriodic(t *testing.T) {
+	var job periodic
+	if err := job.stop(context.Background()); err != nil {
+		t.Fatalf("Stopping an unstarted job failed: %v", err)
+	}
+
+	var runs atomic.Int32
+	started := make(chan struct{})
+	job.runEvery(context.Background(), time.Hour, func(ctx context.Context) {
+		if runs.Add(1) == 1 {
+			close(started)
+		}
+	})
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Job didn't run straight away")
+	}
+
+	if err := job.stop(context.Background()); err != nil {
+		t.Fatalf("Failed to stop job: %v", err)
+	}
+	if err := job.stop(context.Background()); err != nil {
+		t.Fatalf("Stopping a stopped job failed: %v", err)
+	}
+	if got := runs.Load(); got != 1 {
+		t.Errorf("Expected 1 run, got %d", got)
+	}
+}
+
+func TestPeriodicStopTimeout(t *testing.T) {
+	var job periodic
+	release := make(chan struct{})
+	running := make(chan struct{})
+	job.runEvery(context.Background(), time.Hour, func(ctx context.Context) {
+		close(running)
+		<-release
+	})
+	<-running
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := job.stop(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded while the job runs, got %v", err)
+	}
+
+	close(release)
+	if err := job.stop(context.Background()); err != nil {
+		t.Errorf("Failed to stop job once released: %v", err)
+	}
+}
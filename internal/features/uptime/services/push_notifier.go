@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+)
+
+// ntfyNotifier publishes to an ntfy topic URL
+type ntfyNotifier struct {
+	client *http.Client
+}
+
+func (n *ntfyNotifier) Notify(ctx context.Context, channel models.NotificationChannel, notification Notification) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, strings.NewReader(notification.Message()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Title", notification.Title())
+	switch notification.Type {
+	case models.AlertDown:
+		req.Header.Set("Priority", "high")
+		req.Header.Set("Tags", "rotating_light")
+	case models.AlertDegraded:
+		req.Header.Set("Tags", "warning")
+	case models.AlertContentChanged:
+		req.Header.Set("Tags", "pencil2")
+	case models.AlertRecovery, models.AlertDegradedRecovery:
+		req.Header.Set("Tags", "white_check_mark")
+	}
+	if channel.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+channel.Token)
+	}
+
+	return send(n.client, req)
+}
+
+// gotifyMessage is the body of Gotify's create message API
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// gotifyNotifier creates messages on a Gotify server with an application
+// token
+type gotifyNotifier struct {
+	client *http.Client
+}
+
+func (n *gotifyNotifier) Notify(ctx context.Context, channel models.NotificationChannel, notification Notification) error {
+	message := gotifyMessage{
+		Title:    notification.Title(),
+		Message:  notification.Message(),
+		Priority: 5,
+	}
+	if notification.Type == models.AlertDown {
+		message.Priority = 8
+	}
+
+	req, err := newJSONRequest(ctx, strings.TrimSuffix(channel.URL, "/")+"/message", message)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Gotify-Key", channel.Token)
+	return send(n.client, req)
+}
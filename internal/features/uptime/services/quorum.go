@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"fmt"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// probeResultMaxAge is how many check intervals a probe's result counts
+// towards the quorum for. Older results are from probes that stopped
+// reporting.
+const probeResultMaxAge = 2
+
+// ProbeResultsSince returns when the oldest probe result that still counts
+// towards a website's quorum was reported
+func (m *Monitor) ProbeResultsSince(website models.Website, now time.Time) time.Time {
+	return now.Add(-probeResultMaxAge * m.schedule.intervalFor(website))
+}
+
+// applyQuorum weighs a local check result against the latest results the
+// probe agents reported for the website. The website is only down when at
+// least Quorum locations, counting this one, saw it fail, capped at the
+// number of locations that reported recently. A local failure the other
+// locations outvote is kept as an up but outvoted result, so a problem with
+// this server's own network doesn't take websites down.
+func (m *Monitor) applyQuorum(website models.Website, result Result, db Database, now time.Time) Result {
+	probes, err := db.GetProbeResults(website.ID, m.ProbeResultsSince(website, now))
+	if err != nil {
+		m.logger.Error("Failed to get probe results", "website_id", website.ID, "error", err)
+		return result
+	}
+	if len(probes) == 0 {
+		return result
+	}
+
+	locations := len(probes) + 1
+	quorum := min(m.config.Quorum, locations)
+
+	down := 0
+	if !result.IsUp {
+		down++
+	}
+	var probeFailure string
+	for _, probe := range probes {
+		if probe.IsUp {
+			continue
+		}
+		down++
+		if probeFailure == "" {
+			probeFailure = fmt.Sprintf("%s: %s", probe.Location, probe.Error)
+		}
+	}
+
+	switch {
+	case down >= quorum && result.IsUp:
+		result.IsUp = false
+		result.Error = fmt.Sprintf("down from %d of %d locations (%s)", down, locations, probeFailure)
+	case down >= quorum:
+		result.Error = fmt.Sprintf("%s (down from %d of %d locations)", result.Error, down, locations)
+	case !result.IsUp:
+		result.IsUp = true
+		result.Outvoted = true
+		result.Error = fmt.Sprintf("%s (down from %d of %d locations, %d needed)", result.Error, down, locations, quorum)
+	}
+	return result
+}
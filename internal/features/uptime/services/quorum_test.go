@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestQuorum(t *testing.T) {
+	now := time.Now()
+	up := func(location string) models.ProbeResult {
+		return models.ProbeResult{WebsiteID: 1, IsUp: true, Location: location, CheckedAt: now}
+	}
+	down := func(location string) models.ProbeResult {
+		return models.ProbeResult{WebsiteID: 1, Error: "timeout", Location: location, CheckedAt: now}
+	}
+
+	tests := []struct {
+		name     string
+		localUp  bool
+		probes   []models.ProbeResult
+		expected string
+		incident bool
+		message  string
+	}{
+		{"local failure without probes", false, nil, models.StatusDown, true, "connection refused"},
+		{"local failure outvoted", false, []models.ProbeResult{up("eu-west"), up("us-east")}, models.StatusDegraded, false, "down from 1 of 3 locations, 2 needed"},
+		{"local failure confirmed", false, []models.ProbeResult{up("eu-west"), down("us-east")}, models.StatusDown, true, "down from 2 of 3 locations"},
+		{"probes outvote local success", true, []models.ProbeResult{down("eu-west"), down("us-east")}, models.StatusDown, true, "down from 2 of 3 locations (eu-west: timeout)"},
+		{"single probe failure", true, []models.ProbeResult{down("eu-west"), up("us-east")}, models.StatusUp, false, ""},
+		{"stale probe results ignored", false, []models.ProbeResult{{WebsiteID: 1, IsUp: true, CheckedAt: now.Add(-time.Hour)}}, models.StatusDown, true, "connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDatabase{probes: tt.probes}
+			m := newTestMonitor(MonitorConfig{Quorum: 2})
+			m.RegisterChecker(models.CheckTypeHTTP, CheckerFunc(func(ctx context.Context, website models.Website) Result {
+				if tt.localUp {
+					return Result{IsUp: true, StatusCode: 200}
+				}
+				return Result{Error: "connection refused"}
+			}))
+
+			m.CheckWebsite(context.Background(), models.Website{ID: 1, URL: "https://example.com", CheckInterval: 60}, db)
+
+			checks := db.checksFor(1)
+			if len(checks) != 1 || checks[0].Status != tt.expected {
+				t.Fatalf("Expected a single %s check, got %+v", tt.expected, checks)
+			}
+			if (len(db.incidents) > 0) != tt.incident {
+				t.Errorf("Expected incident %v, got %+v", tt.incident, db.incidents)
+			}
+			if !strings.Contains(checks[0].Error, tt.message) {
+				t.Errorf("Expected the error to contain %q, got %q", tt.message, checks[0].Error)
+			}
+		})
+	}
+}
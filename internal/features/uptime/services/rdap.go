@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// DefaultRDAPURL is the RDAP service domains are looked up through. It
+// redirects each query to the registry responsible for the domain.
+const DefaultRDAPURL = "https://rdap.org"
+
+// maxRDAPResponse bounds how much of an RDAP response is read
+const maxRDAPResponse = 1 << 20
+
+// RDAPClient looks up domain registrations through RDAP, the successor to
+// WHOIS
+type RDAPClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewRDAPClient creates a client querying the RDAP service at baseURL,
+// falling back to DefaultRDAPURL when it is empty
+func NewRDAPClient(baseURL string, timeout time.Duration) *RDAPClient {
+	if baseURL == "" {
+		baseURL = DefaultRDAPURL
+	}
+	return &RDAPClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// rdapDomain holds the parts of an RDAP domain response that are used
+type rdapDomain struct {
+	Events []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Handle string            `json:"handle"`
+		Roles  []string          `json:"roles"`
+		VCard  []json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+}
+
+// Lookup retrieves a domain's registrar and expiry. Registries that don't
+// publish an expiry leave ExpiresAt unset.
+func (c *RDAPClient) Lookup(ctx context.Context, name string) (*models.Domain, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/domain/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RDAP lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("domain not found in RDAP")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("RDAP lookup failed: HTTP %d", resp.StatusCode)
+	}
+
+	var response rdapDomain
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRDAPResponse)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid RDAP response: %w", err)
+	}
+
+	domain := &models.Domain{Name: name}
+	for _, event := range response.Events {
+		if event.Action != "expiration" {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, event.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RDAP expiration date %q", event.Date)
+		}
+		domain.ExpiresAt = &expiresAt
+	}
+	for _, entity := range response.Entities {
+		for _, role := range entity.Roles {
+			if role == "registrar" {
+				domain.Registrar = vcardName(entity.VCard)
+				if domain.Registrar == "" {
+					domain.Registrar = entity.Handle
+				}
+			}
+		}
+	}
+	return domain, nil
+}
+
+// vcardName returns the formatted name of a jCard, an array of "vcard"
+// followed by properties such as ["fn", {}, "text", "Example Registrar"]
+func vcardName(vcard []json.RawMessage) string {
+	if len(vcard) < 2 {
+		return ""
+	}
+	var properties [][]any
+	if err := json.Unmarshal(vcard[1], &properties); err != nil {
+		return ""
+	}
+	for _, property := range properties {
+		if len(property) < 4 || property[0] != "fn" {
+			continue
+		}
+		if name, ok := property[3].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
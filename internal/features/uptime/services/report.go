@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"context"
+	"log/slog"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// reportTemplate is the mailer template used for monthly SLA reports
+const reportTemplate = "sla_report.tmpl"
+
+// ReportConfig controls the monthly SLA report email
+type ReportConfig struct {
+	// Recipient is who the report is emailed to. Reports aren't emailed
+	// without one.
+	Recipient string
+
+	// Target is the uptime percentage websites are reported against
+	Target float64
+
+	// Interval is how often the reporter checks whether last month's report
+	// is due
+	Interval time.Duration
+}
+
+// DefaultReportConfig returns the default report configuration
+func DefaultReportConfig() ReportConfig {
+	return ReportConfig{
+		Target:   models.DefaultSLATarget,
+		Interval: time.Hour,
+	}
+}
+
+// ReportDatabase is the storage the reporter works on
+type ReportDatabase interface {
+	GetSLAReport(month time.Time, target float64, now time.Time) (*models.SLAReport, error)
+	GetReportSent(month string) (*time.Time, error)
+	RecordReportSent(month, recipient string, at time.Time) error
+}
+
+// Reporter emails the SLA report of each calendar month once it has ended
+type Reporter struct {
+	logger *slog.Logger
+	mailer Mailer
+	config ReportConfig
+	job    periodic
+}
+
+// NewReporter creates a reporter, filling unset config with defaults
+func NewReporter(logger *slog.Logger, mailer Mailer, config ReportConfig) *Reporter {
+	defaults := DefaultReportConfig()
+	if models.ValidateSLATarget(config.Target) != nil {
+		config.Target = defaults.Target
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	return &Reporter{logger: logger, mailer: mailer, config: config}
+}
+
+// Start checks for a due report straight away and then every interval until
+// stopped
+func (r *Reporter) Start(ctx context.Context, db ReportDatabase) {
+	r.job.runEvery(ctx, r.config.Interval, func(context.Context) { r.Run(db, time.Now()) })
+}
+
+// Stop stops the reporter, waiting for a running report to finish
+func (r *Reporter) Stop(ctx context.Context) error {
+	return r.job.stop(ctx)
+}
+
+// Run emails the report of the month before now, unless it has already been
+// sent
+func (r *Reporter) Run(db ReportDatabase, now time.Time) {
+	if r.config.Recipient == "" {
+		return
+	}
+
+	month := models.ReportMonth(now).AddDate(0, -1, 0)
+	name := month.Format(models.ReportMonthLayout)
+
+	sent, err := db.GetReportSent(name)
+	if err != nil {
+		r.logger.Error("Failed to check SLA report delivery", "month", name, "error", err)
+		return
+	}
+	if sent != nil {
+		return
+	}
+
+	report, err := db.GetSLAReport(month, r.config.Target, now)
+	if err != nil {
+		r.logger.Error("Failed to build SLA report", "month", name, "error", err)
+		return
+	}
+	if len(report.Websites) == 0 {
+		return
+	}
+
+	if err := r.mailer.Send(r.config.Recipient, reportTemplate, report); err != nil {
+		r.logger.Error("Failed to send SLA report", "month", name, "error", err)
+		return
+	}
+	if err := db.RecordReportSent(name, r.config.Recipient, now); err != nil {
+		r.logger.Error("Failed to record SLA report delivery", "month", name, "error", err)
+		return
+	}
+	r.logger.Info("Sent SLA report", "month", name, "recipient", r.config.Recipient)
+}
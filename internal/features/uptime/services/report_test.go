@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"the-ark/internal/server/services/mailer"
+	"time"
+)
+
+// fakeReportDatabase serves a fixed report and records deliveries
+type fakeReportDatabase struct {
+	websites  []models.WebsiteSLA
+	requested []time.Time
+	sent      map[string]time.Time
+}
+
+func (d *fakeReportDatabase) GetSLAReport(month time.Time, target float64, now time.Time) (*models.SLAReport, error) {
+	d.requested = append(d.requested, month)
+	return &models.SLAReport{Month: month, Target: target, GeneratedAt: now, Websites: d.websites}, nil
+}
+
+func (d *fakeReportDatabase) GetReportSent(month string) (*time.Time, error) {
+	if at, ok := d.sent[month]; ok {
+		return &at, nil
+	}
+	return nil, nil
+}
+
+func (d *fakeReportDatabase) RecordReportSent(month, recipient string, at time.Time) error {
+	if d.sent == nil {
+		d.sent = make(map[string]time.Time)
+	}
+	d.sent[month] = at
+	return nil
+}
+
+func TestReporterSendsLastMonthOnce(t *testing.T) {
+	server, requests := newStandIn(t, http.StatusOK, `{"data":{}}`)
+	reporter := NewReporter(
+		slog.New(slog.DiscardHandler),
+		mailer.New("key", "alerts@example.com").WithEndpoint(server.URL),
+		ReportConfig{Recipient: "ops@example.com", Target: 99.5},
+	)
+
+	resolved := time.Date(2026, time.September, 3, 10, 30, 0, 0, time.UTC)
+	db := &fakeReportDatabase{websites: []models.WebsiteSLA{{
+		WebsiteID: 1,
+		Name:      "Example",
+		URL:       "https://example.com",
+		Uptime:    99.25,
+		Incidents: []models.ReportIncident{{ID: 4, Cause: "timeout", StartedAt: resolved.Add(-time.Hour), ResolvedAt: &resolved, DowntimeSeconds: 3600}},
+	}}}
+
+	now := time.Date(2026, time.October, 1, 0, 30, 0, 0, time.UTC)
+	reporter.Run(db, now)
+	reporter.Run(db, now.Add(time.Hour))
+
+	received := requests()
+	if len(received) != 1 {
+		t.Fatalf("Expected the report to be emailed once, got %d emails", len(received))
+	}
+	if len(db.requested) != 1 || !db.requested[0].Equal(time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected September's report, got %v", db.requested)
+	}
+	if _, ok := db.sent["2026-09"]; !ok {
+		t.Errorf("Expected the delivery to be recorded, got %v", db.sent)
+	}
+
+	var payload mailer.SMTP2GORequest
+	if err := json.Unmarshal([]byte(received[0].Body), &payload); err != nil {
+		t.Fatalf("Failed to decode email: %v", err)
+	}
+	if len(payload.To) != 1 || payload.To[0] != "ops@example.com" || !strings.Contains(payload.Subject, "September 2026") {
+		t.Errorf("Unexpected email %+v", payload)
+	}
+	for _, want := range []string{"Example", "99.250%", "99.5%", "1h 0m", "timeout"} {
+		if !strings.Contains(payload.TextBody, want) {
+			t.Errorf("Expected the report body to contain %q, got:\n%s", want, payload.TextBody)
+		}
+	}
+}
+
+func TestReporterSkipsWithoutRecipient(t *testing.T) {
+	server, requests := newStandIn(t, http.StatusOK, `{"data":{}}`)
+	reporter := NewReporter(slog.New(slog.DiscardHandler), mailer.New("key", "alerts@example.com").WithEndpoint(server.URL), ReportConfig{})
+
+	db := &fakeReportDatabase{websites: []models.WebsiteSLA{{WebsiteID: 1, Name: "Example"}}}
+	reporter.Run(db, time.Now())
+	if len(requests()) != 0 || len(db.requested) != 0 {
+		t.Errorf("Expected no report without a recipient")
+	}
+}
@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"context"
+	"log/slog"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// RollupConfig controls how check history is downsampled and pruned
+type RollupConfig struct {
+	// Interval is how often new checks are rolled up
+	Interval time.Duration
+
+	// RawRetention is how long raw checks are kept once rolled up
+	RawRetention time.Duration
+}
+
+// DefaultRollupConfig returns the default rollup configuration
+func DefaultRollupConfig() RollupConfig {
+	return RollupConfig{
+		Interval:     5 * time.Minute,
+		RawRetention: 30 * 24 * time.Hour,
+	}
+}
+
+// RollupDatabase is the storage the rollup job works on
+type RollupDatabase interface {
+	RollUpChecks(resolution string, now time.Time) (int, error)
+	PruneChecks(before time.Time) (int64, error)
+	PruneRollups(resolution string, before time.Time) (int64, error)
+}
+
+// Rollup periodically aggregates raw checks into hourly and daily rollups
+// and prunes raw checks and hourly rollups past their retention
+type Rollup struct {
+	logger *slog.Logger
+	config RollupConfig
+	job    periodic
+}
+
+// NewRollup creates a rollup job, filling unset config with defaults
+func NewRollup(logger *slog.Logger, config RollupConfig) *Rollup {
+	defaults := DefaultRollupConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RawRetention <= 0 {
+		config.RawRetention = defaults.RawRetention
+	}
+	return &Rollup{logger: logger, config: config}
+}
+
+// Start runs a rollup straight away and then every interval until stopped
+func (r *Rollup) Start(ctx context.Context, db RollupDatabase) {
+	r.job.runEvery(ctx, r.config.Interval, func(context.Context) { r.Run(db, time.Now()) })
+}
+
+// Stop stops the rollup job, waiting for a running rollup to finish
+func (r *Rollup) Stop(ctx context.Context) error {
+	return r.job.stop(ctx)
+}
+
+// Run rolls up the checks of every complete hour and day, then prunes what
+// has passed its retention
+func (r *Rollup) Run(db RollupDatabase, now time.Time) {
+	for _, resolution := range []string{models.ResolutionHour, models.ResolutionDay} {
+		written, err := db.RollUpChecks(resolution, now)
+		if err != nil {
+			r.logger.Error("Failed to roll up checks", "resolution", resolution, "error", err)
+			return
+		}
+		if written > 0 {
+			r.logger.Debug("Rolled up checks", "resolution", resolution, "rollups", written)
+		}
+	}
+
+	pruned, err := db.PruneChecks(now.Add(-r.config.RawRetention))
+	if err != nil {
+		r.logger.Error("Failed to prune checks", "error", err)
+		return
+	}
+	if pruned > 0 {
+		r.logger.Info("Pruned raw checks", "checks", pruned)
+	}
+
+	if _, err := db.PruneRollups(models.ResolutionHour, now.Add(-models.HourlyRollupRetention)); err != nil {
+		r.logger.Error("Failed to prune hourly rollups", "error", err)
+	}
+}
@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// fakeRollupDatabase records the rollup job's calls
+type fakeRollupDatabase struct {
+	rollUpErr   error
+	rolledUp    []string
+	prunedTo    time.Time
+	hourlyUntil time.Time
+}
+
+func (d *fakeRollupDatabase) RollUpChecks(resolution string, now time.Time) (int, error) {
+	if d.rollUpErr != nil {
+		return 0, d.rollUpErr
+	}
+	d.rolledUp = append(d.rolledUp, resolution)
+	return 1, nil
+}
+
+func (d *fakeRollupDatabase) PruneChecks(before time.Time) (int64, error) {
+	d.prunedTo = before
+	return 1, nil
+}
+
+func (d *fakeRollupDatabase) PruneRollups(resolution string, before time.Time) (int64, error) {
+	d.hourlyUntil = before
+	return 0, nil
+}
+
+func TestRollupRun(t *testing.T) {
+	now := time.Now()
+	rollup := NewRollup(slog.New(slog.DiscardHandler), RollupConfig{RawRetention: 7 * 24 * time.Hour})
+
+	db := &fakeRollupDatabase{}
+	rollup.Run(db, now)
+	if len(db.rolledUp) != 2 || db.rolledUp[0] != models.ResolutionHour || db.rolledUp[1] != models.ResolutionDay {
+		t.Errorf("Expected hourly then daily rollups, got %v", db.rolledUp)
+	}
+	if !db.prunedTo.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("Expected raw checks pruned to the retention, got %s", db.prunedTo)
+	}
+	if !db.hourlyUntil.Equal(now.Add(-models.HourlyRollupRetention)) {
+		t.Errorf("Expected hourly rollups pruned to their retention, got %s", db.hourlyUntil)
+	}
+
+	// Nothing is pruned if checks couldn't be rolled up
+	failing := &fakeRollupDatabase{rollUpErr: errors.New("database is locked")}
+	rollup.Run(failing, now)
+	if !failing.prunedTo.IsZero() || !failing.hourlyUntil.IsZero() {
+		t.Errorf("Expected nothing pruned after a failed rollup, got %+v", failing)
+	}
+}
@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// scheduledCheck tracks when a single website is next due to be checked
+type scheduledCheck struct {
+	website  models.Website
+	interval time.Duration
+	nextRun  time.Time
+}
+
+// schedule keeps a per-website run queue keyed by website ID
+type schedule struct {
+	mu              sync.Mutex
+	entries         map[int]*scheduledCheck
+	defaultInterval time.Duration
+	jitterFraction  float64
+	rand            *rand.Rand
+}
+
+func newSchedule(defaultInterval time.Duration, jitterFraction float64) *schedule {
+	return &schedule{
+		entries:         make(map[int]*scheduledCheck),
+		defaultInterval: defaultInterval,
+		jitterFraction:  jitterFraction,
+		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// sync reconciles the schedule with the current set of websites. New websites
+// are scheduled within their jitter window, removed websites are dropped and
+// websites whose interval changed are rescheduled from now.
+func (s *schedule) sync(websites []models.Website, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[int]bool, len(websites))
+	for _, website := range websites {
+		seen[website.ID] = true
+		interval := s.runInterval(website)
+
+		entry, exists := s.entries[website.ID]
+		if !exists {
+			s.entries[website.ID] = &scheduledCheck{
+				website:  website,
+				interval: interval,
+				nextRun:  now.Add(s.jitter(interval)),
+			}
+			continue
+		}
+
+		entry.website = website
+		if entry.interval != interval {
+			entry.interval = interval
+			entry.nextRun = now.Add(s.jitter(interval))
+		}
+	}
+
+	for id := range s.entries {
+		if !seen[id] {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// due returns the websites whose next run time has passed and schedules
+// their following run one interval (plus or minus jitter) from now
+func (s *schedule) due(now time.Time) []models.Website {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []*scheduledCheck
+	for _, entry := range s.entries {
+		if !entry.nextRun.After(now) {
+			entries = append(entries, entry)
+		}
+	}
+
+	// Check the most overdue websites first
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].nextRun.Before(entries[j].nextRun)
+	})
+
+	websites := make([]models.Website, 0, len(entries))
+	for _, entry := range entries {
+		websites = append(websites, entry.website)
+		offset := s.jitter(entry.interval) - s.jitterWindow(entry.interval)/2
+		entry.nextRun = now.Add(entry.interval + offset)
+	}
+
+	return websites
+}
+
+// untilNext returns how long to wait before the next website is due. An
+// empty schedule returns fallback.
+func (s *schedule) untilNext(now time.Time, fallback time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) == 0 {
+		return fallback
+	}
+
+	var next time.Time
+	for _, entry := range s.entries {
+		if next.IsZero() || entry.nextRun.Before(next) {
+			next = entry.nextRun
+		}
+	}
+
+	wait := next.Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// intervalFor returns the check interval for a website, falling back to the
+// default when none is configured
+func (s *schedule) intervalFor(website models.Website) time.Duration {
+	if website.CheckInterval <= 0 {
+		return s.defaultInterval
+	}
+	return time.Duration(website.CheckInterval) * time.Second
+}
+
+// runInterval returns how often a website is checked. Heartbeats are
+// evaluated more often than their period so a missed ping is noticed soon
+// after its grace period ends.
+func (s *schedule) runInterval(website models.Website) time.Duration {
+	interval := s.intervalFor(website)
+	if website.Type() == models.CheckTypeHeartbeat && interval > heartbeatEvaluationInterval {
+		return heartbeatEvaluationInterval
+	}
+	return interval
+}
+
+// jitterWindow returns the width of the random window used to spread checks
+func (s *schedule) jitterWindow(interval time.Duration) time.Duration {
+	return time.Duration(float64(interval) * s.jitterFraction)
+}
+
+// jitter returns a random offset within the jitter window for an interval
+func (s *schedule) jitter(interval time.Duration) time.Duration {
+	window := s.jitterWindow(interval)
+	if window <= 0 {
+		return 0
+	}
+	return time.Duration(s.rand.Int63n(int64(window)))
+}
@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"testing"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+func TestScheduleHonoursCheckInterval(t *testing.T) {
+	s := newSchedule(5*time.Minute, 0.1)
+	now := time.Now()
+
+	s.sync([]models.Website{
+		{ID: 1, Name: "fast", CheckInterval: 60},
+		{ID: 2, Name: "slow", CheckInterval: 300},
+	}, now)
+
+	// Both websites start within their jitter window
+	if due := s.due(now.Add(31 * time.Second)); len(due) != 2 {
+		t.Fatalf("Expected 2 websites due after initial jitter window, got %d", len(due))
+	}
+
+	// After one minute (plus jitter) only the fast website is due again
+	due := s.due(now.Add(31*time.Second + 66*time.Second))
+	if len(due) != 1 || due[0].ID != 1 {
+		t.Fatalf("Expected only website 1 to be due, got %+v", due)
+	}
+
+	// The slow website is due again once its five minute interval has passed
+	due = s.due(now.Add(31*time.Second + 316*time.Second))
+	found := false
+	for _, website := range due {
+		if website.ID == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected website 2 to be due after its interval, got %+v", due)
+	}
+}
+
+func TestScheduleSyncAddsRemovesAndEdits(t *testing.T) {
+	s := newSchedule(5*time.Minute, 0.1)
+	now := time.Now()
+
+	s.sync([]models.Website{{ID: 1, CheckInterval: 300}, {ID: 2, CheckInterval: 300}}, now)
+	s.due(now.Add(time.Minute))
+
+	// Remove website 2, shorten website 1's interval and add website 3
+	s.sync([]models.Website{{ID: 1, URL: "https://example.com", CheckInterval: 60}, {ID: 3, CheckInterval: 60}}, now.Add(time.Minute))
+
+	if _, exists := s.entries[2]; exists {
+		t.Error("Expected removed website to be dropped from the schedule")
+	}
+
+	due := s.due(now.Add(time.Minute + 7*time.Second))
+	if len(due) != 2 {
+		t.Fatalf("Expected edited and added websites to be due, got %+v", due)
+	}
+	for _, website := range due {
+		if website.ID == 1 && website.URL != "https://example.com" {
+			t.Errorf("Expected edited website to carry its new URL, got %q", website.URL)
+		}
+	}
+}
+
+func TestScheduleDefaultInterval(t *testing.T) {
+	s := newSchedule(2*time.Minute, 0.1)
+
+	if interval := s.intervalFor(models.Website{CheckInterval: 0}); interval != 2*time.Minute {
+		t.Errorf("Expected default interval for unset check interval, got %v", interval)
+	}
+
+	if wait := s.untilNext(time.Now(), time.Minute); wait != time.Minute {
+		t.Errorf("Expected fallback wait for an empty schedule, got %v", wait)
+	}
+}
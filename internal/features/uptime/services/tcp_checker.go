@@ -0,0 +1,33 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// tcpChecker reports a website as up when a TCP connection to its
+// host:port target can be opened
+type tcpChecker struct {
+	dialer *net.Dialer
+}
+
+func (c *tcpChecker) Check(ctx context.Context, website models.Website) Result {
+	host, port, err := models.SplitHostPort(website.URL, "")
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+
+	start := time.Now()
+	conn, err := c.dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	result := Result{ResponseTime: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	conn.Close()
+
+	result.IsUp = true
+	return result
+}
@@ -0,0 +1,47 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// tlsChecker reports a website as up when a TLS handshake with its target
+// succeeds and the certificate verifies for the target's hostname
+type tlsChecker struct {
+	dialer *net.Dialer
+
+	// rootCAs overrides the system roots, used by tests
+	rootCAs *x509.CertPool
+}
+
+func (c *tlsChecker) Check(ctx context.Context, website models.Website) Result {
+	host, port, err := models.SplitHostPort(website.URL, models.DefaultTLSPort)
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: c.dialer,
+		Config: &tls.Config{
+			ServerName: host,
+			RootCAs:    c.rootCAs,
+		},
+	}
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	result := Result{ResponseTime: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = fmt.Sprintf("TLS handshake failed: %v", err)
+		return result
+	}
+	conn.Close()
+
+	result.IsUp = true
+	return result
+}
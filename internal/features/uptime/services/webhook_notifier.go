@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"the-ark/internal/features/uptime/models"
+	"time"
+)
+
+// webhookPayload is the JSON body posted to generic webhooks
+type webhookPayload struct {
+	Event     string         `json:"event"`
+	Title     string         `json:"title"`
+	Message   string         `json:"message"`
+	Reason    string         `json:"reason,omitempty"`
+	Website   webhookWebsite `json:"website"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+type webhookWebsite struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// webhookNotifier posts a JSON description of the notification
+type webhookNotifier struct {
+	client *http.Client
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, channel models.NotificationChannel, notification Notification) error {
+	payload := webhookPayload{
+		Event:   notification.Type,
+		Title:   notification.Title(),
+		Message: notification.Message(),
+		Reason:  notification.Reason,
+		Website: webhookWebsite{
+			ID:   notification.Website.ID,
+			Name: notification.Website.Name,
+			URL:  notification.Website.URL,
+		},
+		Timestamp: notification.Timestamp,
+	}
+	req, err := newJSONRequest(ctx, channel.URL, payload)
+	if err != nil {
+		return err
+	}
+	if channel.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+channel.Token)
+	}
+	return send(n.client, req)
+}
+
+// chatNotifier posts to Slack- and Discord-compatible incoming webhooks,
+// which take the message text under a single field
+type chatNotifier struct {
+	client *http.Client
+
+	// field is "text" for Slack and "content" for Discord
+	field string
+}
+
+func (n *chatNotifier) Notify(ctx context.Context, channel models.NotificationChannel, notification Notification) error {
+	payload := map[string]string{
+		n.field: notification.Title() + "\n" + notification.Message(),
+	}
+	req, err := newJSONRequest(ctx, channel.URL, payload)
+	if err != nil {
+		return err
+	}
+	return send(n.client, req)
+}
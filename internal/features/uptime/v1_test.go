@@ -0,0 +1,60 @@
+package uptime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"the-ark/internal/features/uptime/models"
+)
+
+func TestV1HidesHeartbeatToken(t *testing.T) {
+	feature, server := newProbeTestServer(t, Config{})
+
+	token := "HEARTBEATSECRETTOKEN"
+	websiteID, err := feature.service.CreateWebsite(models.Website{
+		Name:           "Nightly backup",
+		URL:            models.HeartbeatPath(token),
+		CheckType:      models.CheckTypeHeartbeat,
+		HeartbeatToken: token,
+		CheckInterval:  86400,
+		IsActive:       true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create website: %v", err)
+	}
+
+	get := func(path string) string {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected 200 from %s, got %d: %s", path, resp.StatusCode, body)
+		}
+		return string(body)
+	}
+
+	if list := get("/api/v1/uptime/websites"); strings.Contains(list, token) {
+		t.Errorf("Expected the website list to leave out the ping token, got %s", list)
+	}
+
+	var detail struct {
+		Data struct {
+			URL     string `json:"url"`
+			PingURL string `json:"ping_url"`
+		} `json:"data"`
+	}
+	body := get(fmt.Sprintf("/api/v1/uptime/websites/%d", websiteID))
+	if err := json.Unmarshal([]byte(body), &detail); err != nil {
+		t.Fatalf("Failed to decode website: %v", err)
+	}
+	if detail.Data.URL != "" || detail.Data.PingURL != server.URL+models.HeartbeatPath(token) {
+		t.Errorf("Expected only an explicit ping URL, got %+v", detail.Data)
+	}
+}
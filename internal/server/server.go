@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"the-ark/internal/features/rss"
+	"the-ark/internal/features/ssl"
 	"the-ark/internal/features/uptime"
 	"the-ark/internal/server/handlers"
 	"the-ark/internal/server/services/mailer"
@@ -29,6 +30,7 @@ type Server struct {
 	mailer      mailer.Mailer
 	authService *auth.Service
 	registry    *core.Registry
+	events      *core.EventBus
 	server      *http.Server
 }
 
@@ -70,13 +72,27 @@ func New(logger *slog.Logger) *Server {
 	authService := auth.NewService(coreLogger, db, config)
 	registry := core.NewRegistry(coreLogger)
 
+	// Features publish live updates for dashboards on the event bus
+	events := core.NewEventBus()
+
 	// Initialize uptime feature if enabled
 	var uptimeFeature *uptime.Feature
 	if config.IsFeatureEnabled("uptime") {
 		uptimeConfig := uptime.Config{
-			AlertRecipient: config.Features.Uptime.AlertRecipient,
+			AlertRecipient:         config.Features.Uptime.AlertRecipient,
+			DefaultCheckInterval:   config.Features.Uptime.CheckInterval,
+			MaxConcurrentChecks:    config.Features.Uptime.MaxConcurrentChecks,
+			CheckTimeout:           config.Features.Uptime.CheckTimeout,
+			RawRetentionDays:       config.Features.Uptime.RawRetentionDays,
+			MaxWebsites:            config.Features.Uptime.MaxWebsites,
+			SLATarget:              config.Features.Uptime.SLATarget,
+			ReportRecipient:        config.Features.Uptime.ReportRecipient,
+			ProbeToken:             config.Features.Uptime.ProbeToken,
+			ProbeQuorum:            config.Features.Uptime.ProbeQuorum,
+			RDAPURL:                config.Features.Uptime.RDAPURL,
+			DomainExpiryThresholds: config.Features.Uptime.DomainExpiryThresholds,
 		}
-		uptimeFeature = uptime.NewFeature(logger, coreDB, mailer, uptimeConfig)
+		uptimeFeature = uptime.NewFeature(logger, coreDB, mailer, events, uptimeConfig)
 	}
 
 	srv := &Server{
@@ -87,6 +103,7 @@ func New(logger *slog.Logger) *Server {
 		mailer:      mailer,
 		authService: authService,
 		registry:    registry,
+		events:      events,
 	}
 
 	// Initialize database tables
@@ -113,13 +130,22 @@ func New(logger *slog.Logger) *Server {
 	var rssFeature *rss.Feature
 	if config.IsFeatureEnabled("rss") {
 		rssConfig := rss.NewConfig(config)
-		rssFeature = rss.NewFeature(coreLogger, coreDB, rssConfig)
+		rssFeature = rss.NewFeature(coreLogger, coreDB, events, rssConfig)
 		if err := registry.Register(rssFeature); err != nil {
 			logger.Error("Failed to register RSS feature", "error", err)
 			os.Exit(1)
 		}
 	}
 
+	// Initialize SSL tracker feature if enabled
+	if config.IsFeatureEnabled("ssl") {
+		sslFeature := ssl.NewFeature(coreLogger, coreDB, mailer, ssl.NewConfig(config))
+		if err := registry.Register(sslFeature); err != nil {
+			logger.Error("Failed to register SSL tracker feature", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// Setup routes
 	srv.setupRoutes()
 
@@ -151,6 +177,14 @@ func (s *Server) setupRoutes() {
 	// Static assets
 	mux.Get("/assets/*", handlers.StaticHandler)
 
+	// Public feature routes, e.g. heartbeat ping URLs called by cron jobs
+	routes := s.registry.GetAllRoutes()
+	for _, route := range routes {
+		if route.Public {
+			mux.Method(route.Method, route.Path, route.Handler)
+		}
+	}
+
 	// Protected routes (require authentication)
 	mux.Group(func(r chi.Router) {
 		r.Use(auth.RequireAuthentication)
@@ -158,10 +192,14 @@ func (s *Server) setupRoutes() {
 		// Portal dashboard (protected)
 		r.Get("/", portalHandler.DashboardHandler)
 
+		// Live updates from features as server-sent events
+		r.Get("/events", s.events.ServeHTTP)
+
 		// Feature routes - use the registry to get all feature routes
-		routes := s.registry.GetAllRoutes()
 		for _, route := range routes {
-			r.Method(route.Method, route.Path, route.Handler)
+			if !route.Public {
+				r.Method(route.Method, route.Path, route.Handler)
+			}
 		}
 
 		// Legacy API routes (for backward compatibility)
@@ -180,12 +218,6 @@ func (s *Server) setupRoutes() {
 			})
 		})
 
-		r.Route("/ssl", func(r chi.Router) {
-			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-				http.Error(w, "SSL certificate tracker coming soon", http.StatusNotImplemented)
-			})
-		})
-
 		r.Route("/logs", func(r chi.Router) {
 			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, "Log viewer coming soon", http.StatusNotImplemented)
@@ -13,10 +13,14 @@ import (
 //go:embed "templates"
 var templateFS embed.FS
 
+// defaultEndpoint is the SMTP2GO send email API
+const defaultEndpoint = "https://api.smtp2go.com/v3/email/send"
+
 type Mailer struct {
-	apiKey string
-	sender string
-	client *http.Client
+	apiKey   string
+	sender   string
+	endpoint string
+	client   *http.Client
 }
 
 // SMTP2GO API request structure
@@ -43,12 +47,20 @@ func New(apiKey, sender string) Mailer {
 	}
 
 	return Mailer{
-		apiKey: apiKey,
-		sender: sender,
-		client: client,
+		apiKey:   apiKey,
+		sender:   sender,
+		endpoint: defaultEndpoint,
+		client:   client,
 	}
 }
 
+// WithEndpoint returns a copy of the mailer that sends through an
+// SMTP2GO-compatible API at endpoint
+func (m Mailer) WithEndpoint(endpoint string) Mailer {
+	m.endpoint = endpoint
+	return m
+}
+
 func (m Mailer) Send(recipient, templateFile string, data any) error {
 	tmpl, err := template.New("email").ParseFS(templateFS, "templates/"+templateFile)
 	if err != nil {
@@ -108,7 +120,12 @@ func (m Mailer) Send(recipient, templateFile string, data any) error {
 }
 
 func (m Mailer) sendViaAPI(jsonData []byte) error {
-	req, err := http.NewRequest("POST", "https://api.smtp2go.com/v3/email/send", bytes.NewBuffer(jsonData))
+	endpoint := m.endpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
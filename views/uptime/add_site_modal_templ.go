@@ -9,11 +9,12 @@ import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
 import (
+	"the-ark/internal/features/uptime/models"
 	"the-ark/views/components/button"
 	"the-ark/views/components/card"
 )
 
-func AddSiteModal() templ.Component {
+func AddSiteModal(channels []models.NotificationChannel) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -88,11 +89,40 @@ func AddSiteModal() templ.Component {
 					}()
 				}
 				ctx = templ.InitializeContext(ctx)
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<!-- Debug info --> <div class=\"text-xs text-gray-500 mb-4\">Modal loaded successfully</div><form id=\"add-site-form\" class=\"space-y-4\" onsubmit=\"console.log('Form onsubmit fired'); return false;\"><!-- HTMX indicator --><div id=\"form-indicator\" class=\"htmx-indicator text-blue-600\">Submitting...</div><div><label for=\"name\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Site Name</label> <input type=\"text\" id=\"name\" name=\"name\" required class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"e.g., My Website\"></div><div><label for=\"url\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">URL</label> <input type=\"url\" id=\"url\" name=\"url\" required class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"https://example.com\"></div><div><label for=\"check_interval\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Check Interval (minutes)</label> <select id=\"check_interval\" name=\"check_interval\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"><option value=\"60\">1 minute</option> <option value=\"300\" selected>5 minutes</option> <option value=\"900\">15 minutes</option> <option value=\"1800\">30 minutes</option> <option value=\"3600\">1 hour</option></select></div><div class=\"flex space-x-3 pt-4\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<!-- Debug info --> <div class=\"text-xs text-gray-500 mb-4\">Modal loaded successfully</div><form id=\"add-site-form\" class=\"space-y-4\" onsubmit=\"console.log('Form onsubmit fired'); return false;\"><!-- HTMX indicator --><div id=\"form-indicator\" class=\"htmx-indicator text-blue-600\">Submitting...</div><div><label for=\"name\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Site Name</label> <input type=\"text\" id=\"name\" name=\"name\" required class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"e.g., My Website\"></div><div><label for=\"tags\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Tags</label> <input type=\"text\" id=\"tags\" name=\"tags\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"e.g., prod, client-x\"><p class=\"text-xs text-gray-500 dark:text-gray-400 mt-1\">Comma-separated. Websites are grouped and bulk-managed by tag.</p></div><div><label for=\"check_type\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Check Type</label> <select id=\"check_type\" name=\"check_type\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" onchange=\"document.querySelectorAll('#add-site-form [data-check-type]').forEach(f => { const on = f.dataset.checkType === this.value; f.disabled = !on; f.classList.toggle('hidden', !on); })\"><option value=\"http\" selected>HTTP/S</option> <option value=\"tcp\">TCP port</option> <option value=\"tls\">TLS handshake</option> <option value=\"dns\">DNS record</option> <option value=\"heartbeat\">Heartbeat (push)</option></select></div><fieldset data-check-type=\"http\" class=\"space-y-4\"><div><label for=\"url\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">URL</label> <input type=\"url\" id=\"url\" name=\"url\" required class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"https://example.com\"></div><details class=\"border border-gray-200 dark:border-gray-700 rounded-md p-3\"><summary class=\"text-sm font-medium text-gray-700 dark:text-gray-300 cursor-pointer\">Request</summary><div class=\"space-y-4 mt-3\"><div><label for=\"request_method\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Method</label> <select id=\"request_method\" name=\"request_method\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"><option value=\"GET\" selected>GET</option> <option value=\"HEAD\">HEAD</option> <option value=\"POST\">POST</option> <option value=\"PUT\">PUT</option> <option value=\"PATCH\">PATCH</option> <option value=\"DELETE\">DELETE</option> <option value=\"OPTIONS\">OPTIONS</option></select></div><div><label for=\"request_headers\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Request headers</label> <textarea id=\"request_headers\" name=\"request_headers\" rows=\"2\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"X-Environment: production\"></textarea></div><div><label for=\"request_body\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Request body</label> <textarea id=\"request_body\" name=\"request_body\" rows=\"3\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Var5 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				var templ_7745c5c3_Var5 string
+				templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(`{"ping": true}`)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/uptime/add_site_modal.templ`, Line: 142, Col: 40}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\"></textarea></div><div><label for=\"auth_type\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Authentication</label> <select id=\"auth_type\" name=\"auth_type\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"><option value=\"\" selected>None</option> <option value=\"basic\">Basic auth</option> <option value=\"bearer\">Bearer token</option></select></div><div><label for=\"auth_username\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Username (basic auth)</label> <input type=\"text\" id=\"auth_username\" name=\"auth_username\" autocomplete=\"off\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"></div><div><label for=\"auth_secret\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Password or token</label> <input type=\"password\" id=\"auth_secret\" name=\"auth_secret\" autocomplete=\"new-password\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"></div><div><label for=\"user_agent\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">User-Agent</label> <input type=\"text\" id=\"user_agent\" name=\"user_agent\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"The Ark Uptime Monitor/1.0\"></div><label class=\"flex items-center space-x-2 text-sm text-gray-700 dark:text-gray-300\"><input type=\"checkbox\" id=\"no_follow_redirects\" name=\"no_follow_redirects\" value=\"1\"> <span>Don't follow redirects</span></label></div></details> <details class=\"border border-gray-200 dark:border-gray-700 rounded-md p-3\"><summary class=\"text-sm font-medium text-gray-700 dark:text-gray-300 cursor-pointer\">Assertions</summary><div class=\"space-y-4 mt-3\"><div><label for=\"status_codes\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Accepted status codes</label> <input type=\"text\" id=\"status_codes\" name=\"status_codes\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"200-399\"></div><div><label for=\"body_contains\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Body must contain</label> <input type=\"text\" id=\"body_contains\" name=\"body_contains\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"e.g., OK\"></div><div><label for=\"body_not_contains\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Body must not contain</label> <input type=\"text\" id=\"body_not_contains\" name=\"body_not_contains\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"e.g., Internal Server Error\"></div><label class=\"flex items-center space-x-2 text-sm text-gray-700 dark:text-gray-300\"><input type=\"checkbox\" id=\"body_regex\" name=\"body_regex\" value=\"1\"> <span>Treat body patterns as regular expressions</span></label><div><label for=\"required_headers\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Required response headers</label> <textarea id=\"required_headers\" name=\"required_headers\" rows=\"2\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"Content-Type: application/json\"></textarea></div><div><label for=\"max_response_time\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Max response time (ms)</label> <input type=\"number\" id=\"max_response_time\" name=\"max_response_time\" min=\"0\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"e.g., 2000\"></div></div></details> <details class=\"border border-gray-200 dark:border-gray-700 rounded-md p-3\"><summary class=\"text-sm font-medium text-gray-700 dark:text-gray-300 cursor-pointer\">Content changes</summary><div class=\"space-y-4 mt-3\"><label class=\"flex items-center space-x-2 text-sm text-gray-700 dark:text-gray-300\"><input type=\"checkbox\" id=\"content_monitoring\" name=\"content_monitoring\" value=\"1\"> <span>Alert when the page content changes</span></label><div><label for=\"content_ignore_selectors\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Ignored elements</label> <textarea id=\"content_ignore_selectors\" name=\"content_ignore_selectors\" rows=\"2\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"One selector per line, e.g. #clock or .ad\"></textarea></div><div><label for=\"content_ignore_patterns\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Ignored text patterns</label> <textarea id=\"content_ignore_patterns\" name=\"content_ignore_patterns\" rows=\"2\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"One regular expression per line, e.g. \\d{4}-\\d{2}-\\d{2}\"></textarea></div></div></details></fieldset><fieldset data-check-type=\"tcp\" class=\"hidden space-y-4\" disabled><div><label for=\"tcp_host\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Host</label> <input type=\"text\" id=\"tcp_host\" name=\"tcp_host\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"db.example.com\"></div><div><label for=\"tcp_port\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Port</label> <input type=\"number\" id=\"tcp_port\" name=\"tcp_port\" min=\"1\" max=\"65535\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"5432\"></div><p class=\"text-xs text-gray-500 dark:text-gray-400\">Down when a TCP connection to the port is refused or times out.</p></fieldset><fieldset data-check-type=\"tls\" class=\"hidden space-y-4\" disabled><div><label for=\"tls_host\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Host</label> <input type=\"text\" id=\"tls_host\" name=\"tls_host\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"example.com\"></div><div><label for=\"tls_port\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Port</label> <input type=\"number\" id=\"tls_port\" name=\"tls_port\" min=\"1\" max=\"65535\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"443\"></div><p class=\"text-xs text-gray-500 dark:text-gray-400\">Down when the TLS handshake fails or the certificate is invalid for the host.</p></fieldset><fieldset data-check-type=\"dns\" class=\"hidden space-y-4\" disabled><div><label for=\"dns_host\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Hostname</label> <input type=\"text\" id=\"dns_host\" name=\"dns_host\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"example.com\"></div><div><label for=\"dns_record_type\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Record type</label> <select id=\"dns_record_type\" name=\"dns_record_type\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"><option value=\"A\" selected>A</option> <option value=\"AAAA\">AAAA</option> <option value=\"CNAME\">CNAME</option> <option value=\"MX\">MX</option> <option value=\"NS\">NS</option> <option value=\"TXT\">TXT</option></select></div><div><label for=\"dns_expected\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Expected value</label> <input type=\"text\" id=\"dns_expected\" name=\"dns_expected\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\" placeholder=\"e.g., 203.0.113.10\"></div><p class=\"text-xs text-gray-500 dark:text-gray-400\">Down when the record doesn't resolve, or doesn't include the expected value.</p></fieldset><fieldset data-check-type=\"heartbeat\" class=\"hidden space-y-4\" disabled><p class=\"text-sm text-gray-500 dark:text-gray-400\">A secret ping URL is created for the site. Your job calls it on every run, and the site goes down when no ping arrives within the check interval plus the grace period.</p><div><label for=\"grace_period\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Grace period (minutes)</label> <input type=\"number\" id=\"grace_period\" name=\"grace_period\" min=\"0\" value=\"5\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"></div></fieldset><div><label for=\"check_interval\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Check Interval (minutes)</label> <select id=\"check_interval\" name=\"check_interval\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"><option value=\"60\">1 minute</option> <option value=\"300\" selected>5 minutes</option> <option value=\"900\">15 minutes</option> <option value=\"1800\">30 minutes</option> <option value=\"3600\">1 hour</option> <option value=\"21600\">6 hours</option> <option value=\"86400\">1 day</option></select></div><details class=\"border border-gray-200 dark:border-gray-700 rounded-md p-3\"><summary class=\"text-sm font-medium text-gray-700 dark:text-gray-300 cursor-pointer\">Confirmation</summary><div class=\"space-y-4 mt-3\"><div class=\"grid grid-cols-2 gap-3\"><div><label for=\"retries\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Retries</label> <input type=\"number\" id=\"retries\" name=\"retries\" min=\"0\" max=\"10\" value=\"0\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"></div><div><label for=\"retry_delay\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Retry delay (seconds)</label> <input type=\"number\" id=\"retry_delay\" name=\"retry_delay\" min=\"0\" step=\"0.1\" value=\"1\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"></div><div><label for=\"failure_threshold\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Failures before down</label> <input type=\"number\" id=\"failure_threshold\" name=\"failure_threshold\" min=\"1\" max=\"20\" value=\"1\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"></div><div><label for=\"recovery_threshold\" class=\"block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1\">Passes before up</label> <input type=\"number\" id=\"recovery_threshold\" name=\"recovery_threshold\" min=\"1\" max=\"20\" value=\"1\" class=\"w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:bg-gray-700 dark:text-white\"></div></div><p class=\"text-xs text-gray-500 dark:text-gray-400\">A failed check is retried with a doubling delay, and only consecutive failed checks take the site down. Checks that only pass after a retry show as degraded.</p></div></details> <details class=\"border border-gray-200 dark:border-gray-700 rounded-md p-3\"><summary class=\"text-sm font-medium text-gray-700 dark:text-gray-300 cursor-pointer\">Notifications</summary><div class=\"mt-3\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ChannelCheckboxes(channels, models.Website{}).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</div><div class=\"mt-3 space-y-2\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = AlertPolicyFields(channels, models.AlertPolicy{}).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</div></details><div class=\"flex space-x-3 pt-4\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Var6 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 					templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 					templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 					if !templ_7745c5c3_IsBuffer {
@@ -104,7 +134,7 @@ func AddSiteModal() templ.Component {
 						}()
 					}
 					ctx = templ.InitializeContext(ctx)
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "Cancel")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "Cancel")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
@@ -118,11 +148,11 @@ func AddSiteModal() templ.Component {
 						"type":    "button",
 						"onclick": "document.getElementById('add-site-modal').innerHTML = ''",
 					},
-				}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var5), templ_7745c5c3_Buffer)
+				}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var6), templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Var6 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				templ_7745c5c3_Var7 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 					templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 					templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 					if !templ_7745c5c3_IsBuffer {
@@ -134,7 +164,7 @@ func AddSiteModal() templ.Component {
 						}()
 					}
 					ctx = templ.InitializeContext(ctx)
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "Add Site")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "Add Site")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
@@ -154,11 +184,11 @@ func AddSiteModal() templ.Component {
 						"hx-on::after-request": "if(event.detail.xhr.status === 200) { try { const response = JSON.parse(event.detail.xhr.responseText); if(response.success) { window.location.href = '/uptime'; } } catch(e) { console.error('Failed to parse response:', e); } }",
 						"onclick":              "console.log('Submit button clicked');",
 					},
-				}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var6), templ_7745c5c3_Buffer)
+				}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var7), templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</div></form>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</div></form>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -171,12 +201,12 @@ func AddSiteModal() templ.Component {
 			return nil
 		})
 		templ_7745c5c3_Err = card.Card(card.Props{
-			Class: "w-full max-w-md mx-4 border-gray-200 dark:border-gray-700 bg-white dark:bg-gray-800",
+			Class: "w-full max-w-md mx-4 max-h-[90vh] overflow-y-auto border-gray-200 dark:border-gray-700 bg-white dark:bg-gray-800",
 		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</div><script>\n\t\t// Debug HTMX\n\t\tconsole.log('Modal loaded, checking HTMX...');\n\t\tif (typeof htmx !== 'undefined') {\n\t\t\tconsole.log('HTMX is loaded');\n\t\t\t\n\t\t\t// Add event listener to form\n\t\t\tconst form = document.querySelector('form');\n\t\t\tconsole.log('Form found:', form);\n\t\t\t\n\t\t\tform.addEventListener('submit', function(e) {\n\t\t\t\tconsole.log('Form submit event fired');\n\t\t\t\tconsole.log('Form action:', form.action);\n\t\t\t\tconsole.log('Form method:', form.method);\n\t\t\t\tconsole.log('Form has hx-post:', form.hasAttribute('hx-post'));\n\t\t\t});\n\t\t\t\n\t\t\t// Listen for HTMX events\n\t\t\tdocument.body.addEventListener('htmx:beforeRequest', function(e) {\n\t\t\t\tconsole.log('HTMX beforeRequest:', e.detail);\n\t\t\t\tconsole.log('Request URL:', e.detail.requestConfig.path);\n\t\t\t});\n\t\t\t\n\t\t\tdocument.body.addEventListener('htmx:afterRequest', function(e) {\n\t\t\t\tconsole.log('HTMX afterRequest:', e.detail);\n\t\t\t\tconsole.log('Response status:', e.detail.xhr.status);\n\t\t\t});\n\t\t\t\n\t\t\tdocument.body.addEventListener('htmx:sendError', function(e) {\n\t\t\t\tconsole.log('HTMX sendError:', e.detail);\n\t\t\t});\n\t\t} else {\n\t\t\tconsole.log('HTMX is NOT loaded');\n\t\t}\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</div><script>\n\t\t// Debug HTMX\n\t\tconsole.log('Modal loaded, checking HTMX...');\n\t\tif (typeof htmx !== 'undefined') {\n\t\t\tconsole.log('HTMX is loaded');\n\t\t\t\n\t\t\t// Add event listener to form\n\t\t\tconst form = document.querySelector('form');\n\t\t\tconsole.log('Form found:', form);\n\t\t\t\n\t\t\tform.addEventListener('submit', function(e) {\n\t\t\t\tconsole.log('Form submit event fired');\n\t\t\t\tconsole.log('Form action:', form.action);\n\t\t\t\tconsole.log('Form method:', form.method);\n\t\t\t\tconsole.log('Form has hx-post:', form.hasAttribute('hx-post'));\n\t\t\t});\n\t\t\t\n\t\t\t// Listen for HTMX events\n\t\t\tdocument.body.addEventListener('htmx:beforeRequest', function(e) {\n\t\t\t\tconsole.log('HTMX beforeRequest:', e.detail);\n\t\t\t\tconsole.log('Request URL:', e.detail.requestConfig.path);\n\t\t\t});\n\t\t\t\n\t\t\tdocument.body.addEventListener('htmx:afterRequest', function(e) {\n\t\t\t\tconsole.log('HTMX afterRequest:', e.detail);\n\t\t\t\tconsole.log('Response status:', e.detail.xhr.status);\n\t\t\t});\n\t\t\t\n\t\t\tdocument.body.addEventListener('htmx:sendError', function(e) {\n\t\t\t\tconsole.log('HTMX sendError:', e.detail);\n\t\t\t});\n\t\t} else {\n\t\t\tconsole.log('HTMX is NOT loaded');\n\t\t}\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
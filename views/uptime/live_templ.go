@@ -0,0 +1,56 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.924
+package uptime
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "fmt"
+
+// LiveUpdatesScript keeps a page's live regions current as the monitor
+// publishes events. A live region is an element with an id, the ID of the
+// website it shows as data-live-website, and the URL of a page containing
+// its current version as data-live-url. Regions sharing a URL are refreshed
+// with a single request.
+func LiveUpdatesScript() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script>\n\t\t(function() {\n\t\t\tif (!window.EventSource) return;\n\n\t\t\t// URLs being fetched, and whether another event arrived meanwhile\n\t\t\tconst inFlight = new Map();\n\n\t\t\tfunction refresh(url) {\n\t\t\t\tif (inFlight.has(url)) {\n\t\t\t\t\tinFlight.set(url, true);\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tinFlight.set(url, false);\n\n\t\t\t\tfetch(url, { credentials: 'same-origin' })\n\t\t\t\t\t.then(function(resp) {\n\t\t\t\t\t\tif (!resp.ok) throw new Error('status ' + resp.status);\n\t\t\t\t\t\treturn resp.text();\n\t\t\t\t\t})\n\t\t\t\t\t.then(function(html) {\n\t\t\t\t\t\tconst doc = new DOMParser().parseFromString(html, 'text/html');\n\t\t\t\t\t\tdocument.querySelectorAll('[data-live-url]').forEach(function(region) {\n\t\t\t\t\t\t\tif (region.dataset.liveUrl !== url) return;\n\t\t\t\t\t\t\tconst updated = doc.getElementById(region.id);\n\t\t\t\t\t\t\tif (!updated) return;\n\t\t\t\t\t\t\tregion.replaceWith(updated);\n\t\t\t\t\t\t\thtmx.process(updated);\n\t\t\t\t\t\t});\n\t\t\t\t\t})\n\t\t\t\t\t.catch(function(err) { console.error('Failed to refresh ' + url + ':', err); })\n\t\t\t\t\t.finally(function() {\n\t\t\t\t\t\tconst again = inFlight.get(url);\n\t\t\t\t\t\tinFlight.delete(url);\n\t\t\t\t\t\tif (again) refresh(url);\n\t\t\t\t\t});\n\t\t\t}\n\n\t\t\tfunction onEvent(e) {\n\t\t\t\tconst websiteId = String(JSON.parse(e.data).website_id);\n\t\t\t\tconst urls = new Set();\n\t\t\t\tdocument.querySelectorAll('[data-live-website]').forEach(function(region) {\n\t\t\t\t\tif (region.dataset.liveWebsite === websiteId) urls.add(region.dataset.liveUrl);\n\t\t\t\t});\n\t\t\t\turls.forEach(refresh);\n\t\t\t}\n\n\t\t\tconst source = new EventSource('/events?topic=uptime');\n\t\t\t['uptime.check', 'uptime.status', 'uptime.incident'].forEach(function(topic) {\n\t\t\t\tsource.addEventListener(topic, onEvent);\n\t\t\t});\n\t\t})();\n\t</script>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// getLiveAttributes marks an element as a live region of a website,
+// refreshed from url
+func getLiveAttributes(websiteID int, url string) templ.Attributes {
+	return templ.Attributes{
+		"data-live-website": fmt.Sprint(websiteID),
+		"data-live-url":     url,
+	}
+}
+
+var _ = templruntime.GeneratedTemplate